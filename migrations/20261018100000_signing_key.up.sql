@@ -0,0 +1,8 @@
+create table golauth_signing_key
+(
+    id            varchar(255) PRIMARY KEY,
+    algorithm     varchar(20)  not null,
+    private_key   text         not null,
+    creation_date timestamptz  not null default current_timestamp,
+    retired_at    timestamptz
+);
@@ -0,0 +1 @@
+drop table golauth_refresh_token;
@@ -0,0 +1,17 @@
+create table golauth_refresh_token
+(
+    id            uuid PRIMARY KEY     DEFAULT gen_random_uuid(),
+    family_id     uuid        not null,
+    user_id       uuid        not null,
+    token_hash    varchar(64) not null,
+    expires_at    timestamptz not null,
+    used_at       timestamptz,
+    revoked_at    timestamptz,
+    creation_date timestamptz not null default current_timestamp
+);
+
+create unique index ui_golauth_refresh_token_hash
+    on golauth_refresh_token (token_hash);
+
+create index i_golauth_refresh_token_family
+    on golauth_refresh_token (family_id);
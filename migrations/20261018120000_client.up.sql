@@ -0,0 +1,16 @@
+create table golauth_client
+(
+    id            uuid PRIMARY KEY       DEFAULT gen_random_uuid(),
+    client_id     varchar(255)  not null,
+    client_secret varchar(1000) not null,
+    name          varchar(255)  not null,
+    grant_types   text[]        not null default '{}',
+    scopes        text[]        not null default '{}',
+    authorities   text[]        not null default '{}',
+    token_ttl     integer       not null default 0,
+    enabled       boolean       not null default true,
+    creation_date timestamptz   not null default current_timestamp
+);
+
+create unique index ui_golauth_client_client_id
+    on golauth_client (client_id);
@@ -0,0 +1,4 @@
+drop table golauth_authorization_code;
+
+alter table golauth_client
+    drop column redirect_uris;
@@ -0,0 +1,20 @@
+alter table golauth_client
+    add column redirect_uris text[] not null default '{}';
+
+create table golauth_authorization_code
+(
+    id                    uuid PRIMARY KEY       DEFAULT gen_random_uuid(),
+    code_hash             varchar(64)   not null,
+    client_id             varchar(255)  not null,
+    user_id               uuid          not null,
+    redirect_uri          varchar(2000) not null,
+    scope                 varchar(1000) not null default '',
+    code_challenge        varchar(128)  not null,
+    code_challenge_method varchar(10)   not null,
+    expires_at            timestamptz   not null,
+    used_at               timestamptz,
+    creation_date         timestamptz   not null default current_timestamp
+);
+
+create unique index ui_golauth_authorization_code_hash
+    on golauth_authorization_code (code_hash);
@@ -0,0 +1,9 @@
+create table golauth_revoked_token
+(
+    jti           varchar(64) PRIMARY KEY,
+    expires_at    timestamptz not null,
+    creation_date timestamptz not null default current_timestamp
+);
+
+create index i_golauth_revoked_token_expires_at
+    on golauth_revoked_token (expires_at);
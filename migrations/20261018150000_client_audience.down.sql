@@ -0,0 +1,2 @@
+alter table golauth_client
+    drop column audiences;
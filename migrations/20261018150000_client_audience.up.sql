@@ -0,0 +1,2 @@
+alter table golauth_client
+    add column audiences text[] not null default '{}';
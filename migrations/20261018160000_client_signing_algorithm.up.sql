@@ -0,0 +1,2 @@
+alter table golauth_client
+    add column signing_algorithm varchar(20) not null default '';
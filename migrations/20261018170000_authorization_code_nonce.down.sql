@@ -0,0 +1,2 @@
+alter table golauth_authorization_code
+    drop column nonce;
@@ -0,0 +1,2 @@
+alter table golauth_authorization_code
+    add column nonce varchar(255) not null default '';
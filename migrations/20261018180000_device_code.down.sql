@@ -0,0 +1 @@
+drop table golauth_device_code;
@@ -0,0 +1,22 @@
+create table golauth_device_code
+(
+    id               uuid PRIMARY KEY       DEFAULT gen_random_uuid(),
+    device_code_hash varchar(64)   not null,
+    user_code        varchar(16)   not null,
+    client_id        varchar(255)  not null,
+    scope            varchar(1000) not null default '',
+    user_id          uuid,
+    denied           boolean       not null default false,
+    poll_interval    integer       not null,
+    expires_at       timestamptz   not null,
+    last_polled_at   timestamptz,
+    approved_at      timestamptz,
+    used_at          timestamptz,
+    creation_date    timestamptz   not null default current_timestamp
+);
+
+create unique index ui_golauth_device_code_hash
+    on golauth_device_code (device_code_hash);
+
+create unique index ui_golauth_device_code_user_code
+    on golauth_device_code (user_code);
@@ -0,0 +1,9 @@
+create table golauth_revoked_subject
+(
+    subject    varchar(255) PRIMARY KEY,
+    revoked_at timestamptz  not null,
+    expires_at timestamptz  not null
+);
+
+create index i_golauth_revoked_subject_expires_at
+    on golauth_revoked_subject (expires_at);
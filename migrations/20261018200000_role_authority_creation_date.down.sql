@@ -0,0 +1,2 @@
+alter table golauth_role_authority
+    drop column creation_date;
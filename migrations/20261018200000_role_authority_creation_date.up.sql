@@ -0,0 +1,2 @@
+alter table golauth_role_authority
+    add column creation_date timestamptz not null default current_timestamp;
@@ -0,0 +1 @@
+drop table golauth_password_reset_token;
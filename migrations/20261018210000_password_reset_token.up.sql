@@ -0,0 +1,15 @@
+create table golauth_password_reset_token
+(
+    id            uuid PRIMARY KEY     DEFAULT gen_random_uuid(),
+    user_id       uuid        not null,
+    token_hash    varchar(64) not null,
+    expires_at    timestamptz not null,
+    used_at       timestamptz,
+    creation_date timestamptz not null default current_timestamp
+);
+
+create unique index ui_golauth_password_reset_token_hash
+    on golauth_password_reset_token (token_hash);
+
+create index i_golauth_password_reset_token_user
+    on golauth_password_reset_token (user_id);
@@ -0,0 +1,4 @@
+drop table golauth_email_verification_token;
+
+alter table golauth_user
+    drop column email_verified;
@@ -0,0 +1,23 @@
+alter table golauth_user
+    add column email_verified boolean not null default false;
+
+-- accounts created before verification existed keep signing in when it becomes required
+update golauth_user
+set email_verified = true;
+
+create table golauth_email_verification_token
+(
+    id            uuid PRIMARY KEY     DEFAULT gen_random_uuid(),
+    user_id       uuid         not null,
+    email         varchar(255) not null,
+    token_hash    varchar(64) not null,
+    expires_at    timestamptz not null,
+    used_at       timestamptz,
+    creation_date timestamptz not null default current_timestamp
+);
+
+create unique index ui_golauth_email_verification_token_hash
+    on golauth_email_verification_token (token_hash);
+
+create index i_golauth_email_verification_token_user
+    on golauth_email_verification_token (user_id);
@@ -0,0 +1,15 @@
+create table golauth_password_history
+(
+    id            uuid PRIMARY KEY      DEFAULT gen_random_uuid(),
+    user_id       uuid         not null,
+    password_hash varchar(1000) not null,
+    creation_date timestamptz  not null default current_timestamp
+);
+
+create index i_golauth_password_history_user
+    on golauth_password_history (user_id, creation_date);
+
+-- the current passwords start the history, so they cannot be chosen again once the history rule is enabled
+insert into golauth_password_history (user_id, password_hash)
+select id, password
+from golauth_user;
@@ -0,0 +1 @@
+drop table golauth_login_failure;
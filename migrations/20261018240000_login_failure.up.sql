@@ -0,0 +1,12 @@
+create table golauth_login_failure
+(
+    kind         varchar(20)  not null,
+    subject      varchar(255) not null,
+    failures     int          not null,
+    last_failure timestamptz  not null,
+    locked_until timestamptz,
+    PRIMARY KEY (kind, subject)
+);
+
+create index i_golauth_login_failure_locked_until
+    on golauth_login_failure (locked_until);
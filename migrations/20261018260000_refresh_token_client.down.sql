@@ -0,0 +1,2 @@
+alter table golauth_refresh_token
+    drop column client_id;
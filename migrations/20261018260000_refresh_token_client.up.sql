@@ -0,0 +1,2 @@
+alter table golauth_refresh_token
+    add column client_id varchar(255) not null default '';
@@ -0,0 +1 @@
+drop index ui_golauth_signing_key_active;
@@ -0,0 +1,12 @@
+update golauth_signing_key k
+set retired_at = current_timestamp
+where retired_at is null
+  and exists(select 1
+             from golauth_signing_key n
+             where n.algorithm = k.algorithm
+               and n.retired_at is null
+               and (n.creation_date, n.id) > (k.creation_date, k.id));
+
+create unique index ui_golauth_signing_key_active
+    on golauth_signing_key (algorithm)
+    where retired_at is null;
@@ -0,0 +1,36 @@
+//go:generate mockgen -source AddAuthority.go -destination mock/AddAuthority_mock.go -package mock
+package authority
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+)
+
+var ErrAuthorityAlreadyExists = errors.New("authority name already in use")
+
+type AddAuthority interface {
+	Execute(ctx context.Context, input *entity.Authority) (*entity.Authority, error)
+}
+
+func NewAddAuthority(repoFactory factory.RepositoryFactory) AddAuthority {
+	return addAuthority{repo: repoFactory.NewAuthorityRepository()}
+}
+
+type addAuthority struct {
+	repo repository.AuthorityRepository
+}
+
+func (uc addAuthority) Execute(ctx context.Context, input *entity.Authority) (*entity.Authority, error) {
+	input.Enabled = true
+	authority, err := uc.repo.Create(ctx, input)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrAuthorityAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return authority, nil
+}
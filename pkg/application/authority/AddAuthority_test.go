@@ -0,0 +1,75 @@
+package authority
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type AddAuthoritySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory         *factoryMock.MockRepositoryFactory
+	authorityRepository *repoMock.MockAuthorityRepository
+
+	ctx          context.Context
+	addAuthority AddAuthority
+}
+
+func TestAddAuthority(t *testing.T) {
+	suite.Run(t, new(AddAuthoritySuite))
+}
+
+func (s *AddAuthoritySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.authorityRepository = repoMock.NewMockAuthorityRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepository)
+
+	s.ctx = context.Background()
+	s.addAuthority = NewAddAuthority(s.repoFactory)
+}
+
+func (s *AddAuthoritySuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *AddAuthoritySuite) TestAddOk() {
+	input := &entity.Authority{Name: "ORDERS_READ", Description: "Read orders"}
+	s.authorityRepository.EXPECT().Create(s.ctx, &entity.Authority{Name: "ORDERS_READ", Description: "Read orders", Enabled: true}).
+		DoAndReturn(func(_ context.Context, a *entity.Authority) (*entity.Authority, error) {
+			a.ID = uuid.New()
+			return a, nil
+		}).Times(1)
+
+	output, err := s.addAuthority.Execute(s.ctx, input)
+	s.NoError(err)
+	s.NotEqual(uuid.Nil, output.ID)
+	s.True(output.Enabled)
+}
+
+func (s *AddAuthoritySuite) TestAddDuplicated() {
+	s.authorityRepository.EXPECT().Create(s.ctx, gomock.Any()).Return(nil, fmt.Errorf("could not create: %w", repository.ErrDuplicate)).Times(1)
+
+	_, err := s.addAuthority.Execute(s.ctx, &entity.Authority{Name: "ADMIN"})
+	s.ErrorIs(err, ErrAuthorityAlreadyExists)
+}
+
+func (s *AddAuthoritySuite) TestAddErr() {
+	s.authorityRepository.EXPECT().Create(s.ctx, gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+
+	_, err := s.addAuthority.Execute(s.ctx, &entity.Authority{Name: "ADMIN"})
+	s.EqualError(err, "connection refused")
+}
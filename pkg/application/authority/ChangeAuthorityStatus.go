@@ -0,0 +1,32 @@
+//go:generate mockgen -source ChangeAuthorityStatus.go -destination mock/ChangeAuthorityStatus_mock.go -package mock
+package authority
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+// ChangeAuthorityStatus enables or disables an authority. Disabled authorities are left out of new tokens.
+type ChangeAuthorityStatus interface {
+	Execute(ctx context.Context, id uuid.UUID, enabled bool) error
+}
+
+func NewChangeAuthorityStatus(repoFactory factory.RepositoryFactory) ChangeAuthorityStatus {
+	return changeAuthorityStatus{repo: repoFactory.NewAuthorityRepository()}
+}
+
+type changeAuthorityStatus struct {
+	repo repository.AuthorityRepository
+}
+
+func (uc changeAuthorityStatus) Execute(ctx context.Context, id uuid.UUID, enabled bool) error {
+	err := uc.repo.ChangeStatus(ctx, id, enabled)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrAuthorityNotFound, id)
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package authority
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type ChangeAuthorityStatusSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory         *factoryMock.MockRepositoryFactory
+	authorityRepository *repoMock.MockAuthorityRepository
+
+	ctx                   context.Context
+	changeAuthorityStatus ChangeAuthorityStatus
+}
+
+func TestChangeAuthorityStatus(t *testing.T) {
+	suite.Run(t, new(ChangeAuthorityStatusSuite))
+}
+
+func (s *ChangeAuthorityStatusSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.authorityRepository = repoMock.NewMockAuthorityRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepository)
+
+	s.ctx = context.Background()
+	s.changeAuthorityStatus = NewChangeAuthorityStatus(s.repoFactory)
+}
+
+func (s *ChangeAuthorityStatusSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ChangeAuthorityStatusSuite) TestChangeStatusOk() {
+	id := uuid.New()
+	s.authorityRepository.EXPECT().ChangeStatus(s.ctx, id, false).Return(nil).Times(1)
+
+	s.NoError(s.changeAuthorityStatus.Execute(s.ctx, id, false))
+}
+
+func (s *ChangeAuthorityStatusSuite) TestChangeStatusNotFound() {
+	id := uuid.New()
+	s.authorityRepository.EXPECT().ChangeStatus(s.ctx, id, true).Return(fmt.Errorf("could not change: %w", repository.ErrNotFound)).Times(1)
+
+	s.ErrorIs(s.changeAuthorityStatus.Execute(s.ctx, id, true), ErrAuthorityNotFound)
+}
+
+func (s *ChangeAuthorityStatusSuite) TestChangeStatusErr() {
+	id := uuid.New()
+	s.authorityRepository.EXPECT().ChangeStatus(s.ctx, id, true).Return(errors.New("connection refused")).Times(1)
+
+	s.EqualError(s.changeAuthorityStatus.Execute(s.ctx, id, true), "connection refused")
+}
@@ -0,0 +1,38 @@
+//go:generate mockgen -source EditAuthority.go -destination mock/EditAuthority_mock.go -package mock
+package authority
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+var ErrAuthorityNotFound = errors.New("authority not found")
+
+type EditAuthority interface {
+	Execute(ctx context.Context, id uuid.UUID, input *entity.Authority) error
+}
+
+func NewEditAuthority(repoFactory factory.RepositoryFactory) EditAuthority {
+	return editAuthority{repo: repoFactory.NewAuthorityRepository()}
+}
+
+type editAuthority struct {
+	repo repository.AuthorityRepository
+}
+
+func (uc editAuthority) Execute(ctx context.Context, id uuid.UUID, input *entity.Authority) error {
+	input.ID = id
+	err := uc.repo.Edit(ctx, input)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrAuthorityNotFound, id)
+	}
+	if errors.Is(err, repository.ErrDuplicate) {
+		return ErrAuthorityAlreadyExists
+	}
+	return err
+}
@@ -0,0 +1,72 @@
+package authority
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type EditAuthoritySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory         *factoryMock.MockRepositoryFactory
+	authorityRepository *repoMock.MockAuthorityRepository
+
+	ctx           context.Context
+	editAuthority EditAuthority
+}
+
+func TestEditAuthority(t *testing.T) {
+	suite.Run(t, new(EditAuthoritySuite))
+}
+
+func (s *EditAuthoritySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.authorityRepository = repoMock.NewMockAuthorityRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepository)
+
+	s.ctx = context.Background()
+	s.editAuthority = NewEditAuthority(s.repoFactory)
+}
+
+func (s *EditAuthoritySuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *EditAuthoritySuite) TestEditOk() {
+	id := uuid.New()
+	s.authorityRepository.EXPECT().Edit(s.ctx, &entity.Authority{ID: id, Name: "ORDERS_READ", Description: "Read orders"}).Return(nil).Times(1)
+
+	s.NoError(s.editAuthority.Execute(s.ctx, id, &entity.Authority{Name: "ORDERS_READ", Description: "Read orders"}))
+}
+
+func (s *EditAuthoritySuite) TestEditNotFound() {
+	s.authorityRepository.EXPECT().Edit(s.ctx, gomock.Any()).Return(fmt.Errorf("could not edit: %w", repository.ErrNotFound)).Times(1)
+
+	s.ErrorIs(s.editAuthority.Execute(s.ctx, uuid.New(), &entity.Authority{}), ErrAuthorityNotFound)
+}
+
+func (s *EditAuthoritySuite) TestEditDuplicated() {
+	s.authorityRepository.EXPECT().Edit(s.ctx, gomock.Any()).Return(fmt.Errorf("could not edit: %w", repository.ErrDuplicate)).Times(1)
+
+	s.ErrorIs(s.editAuthority.Execute(s.ctx, uuid.New(), &entity.Authority{Name: "ADMIN"}), ErrAuthorityAlreadyExists)
+}
+
+func (s *EditAuthoritySuite) TestEditErr() {
+	s.authorityRepository.EXPECT().Edit(s.ctx, gomock.Any()).Return(errors.New("connection refused")).Times(1)
+
+	s.EqualError(s.editAuthority.Execute(s.ctx, uuid.New(), &entity.Authority{}), "connection refused")
+}
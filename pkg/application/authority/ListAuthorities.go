@@ -0,0 +1,25 @@
+//go:generate mockgen -source ListAuthorities.go -destination mock/ListAuthorities_mock.go -package mock
+package authority
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+)
+
+type ListAuthorities interface {
+	Execute(ctx context.Context) ([]*entity.Authority, error)
+}
+
+func NewListAuthorities(repoFactory factory.RepositoryFactory) ListAuthorities {
+	return listAuthorities{repo: repoFactory.NewAuthorityRepository()}
+}
+
+type listAuthorities struct {
+	repo repository.AuthorityRepository
+}
+
+func (uc listAuthorities) Execute(ctx context.Context) ([]*entity.Authority, error) {
+	return uc.repo.FindAll(ctx)
+}
@@ -0,0 +1,61 @@
+package authority
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type ListAuthoritiesSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory         *factoryMock.MockRepositoryFactory
+	authorityRepository *repoMock.MockAuthorityRepository
+
+	ctx             context.Context
+	listAuthorities ListAuthorities
+}
+
+func TestListAuthorities(t *testing.T) {
+	suite.Run(t, new(ListAuthoritiesSuite))
+}
+
+func (s *ListAuthoritiesSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.authorityRepository = repoMock.NewMockAuthorityRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepository)
+
+	s.ctx = context.Background()
+	s.listAuthorities = NewListAuthorities(s.repoFactory)
+}
+
+func (s *ListAuthoritiesSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ListAuthoritiesSuite) TestListOk() {
+	authorities := []*entity.Authority{{ID: uuid.New(), Name: "ADMIN"}}
+	s.authorityRepository.EXPECT().FindAll(s.ctx).Return(authorities, nil).Times(1)
+
+	output, err := s.listAuthorities.Execute(s.ctx)
+	s.NoError(err)
+	s.Equal(authorities, output)
+}
+
+func (s *ListAuthoritiesSuite) TestListErr() {
+	s.authorityRepository.EXPECT().FindAll(s.ctx).Return(nil, errors.New("connection refused")).Times(1)
+
+	_, err := s.listAuthorities.Execute(s.ctx)
+	s.EqualError(err, "connection refused")
+}
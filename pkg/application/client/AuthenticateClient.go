@@ -0,0 +1,56 @@
+//go:generate mockgen -source AuthenticateClient.go -destination mock/AuthenticateClient_mock.go -package mock
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var ErrInvalidClient = errors.New("invalid client credentials")
+
+// AuthenticateClient checks the client credentials. Clients are kept in the database, so a client whose
+// signing_algorithm has no signing key in the key store is refused here, before any token is issued to it.
+type AuthenticateClient interface {
+	Execute(ctx context.Context, clientID string, secret string) (*entity.Client, error)
+}
+
+func NewAuthenticateClient(repo repository.ClientRepository, keyStore token.KeyStore) AuthenticateClient {
+	return authenticateClient{repo: repo, keyStore: keyStore}
+}
+
+type authenticateClient struct {
+	repo     repository.ClientRepository
+	keyStore token.KeyStore
+}
+
+func (uc authenticateClient) Execute(ctx context.Context, clientID string, secret string) (*entity.Client, error) {
+	if clientID == "" {
+		return nil, ErrInvalidClient
+	}
+	client, err := uc.repo.FindByClientID(ctx, clientID)
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+	if !client.Enabled {
+		return nil, ErrInvalidClient
+	}
+	if _, err = uc.keyStore.SigningKey(client.SigningAlgorithm); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidClient, err)
+	}
+	if client.IsPublic() {
+		if secret != "" {
+			return nil, ErrInvalidClient
+		}
+		return client, nil
+	}
+	err = bcrypt.CompareHashAndPassword([]byte(client.Secret), []byte(secret))
+	if err != nil {
+		return nil, ErrInvalidClient
+	}
+	return client, nil
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+type AuthenticateClientSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	ctx              context.Context
+	clientRepository *repoMock.MockClientRepository
+	keyStore         *tokenMock.MockKeyStore
+	authenticate     AuthenticateClient
+	client           *entity.Client
+}
+
+func TestAuthenticateClient(t *testing.T) {
+	suite.Run(t, new(AuthenticateClientSuite))
+}
+
+func (s *AuthenticateClientSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.clientRepository = repoMock.NewMockClientRepository(s.mockCtrl)
+	s.keyStore = tokenMock.NewMockKeyStore(s.mockCtrl)
+	s.keyStore.EXPECT().SigningKey("").Return(&entity.SigningKey{}, nil).AnyTimes()
+	s.authenticate = NewAuthenticateClient(s.clientRepository, s.keyStore)
+
+	secret, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.DefaultCost)
+	s.client = &entity.Client{ClientID: "service", Secret: string(secret), Enabled: true}
+}
+
+func (s *AuthenticateClientSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *AuthenticateClientSuite) TestAuthenticateOk() {
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "service").Return(s.client, nil).Times(1)
+
+	c, err := s.authenticate.Execute(s.ctx, "service", "secret")
+	s.NoError(err)
+	s.Equal(s.client, c)
+}
+
+func (s *AuthenticateClientSuite) TestAuthenticateWrongSecret() {
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "service").Return(s.client, nil).Times(1)
+
+	c, err := s.authenticate.Execute(s.ctx, "service", "wrong")
+	s.ErrorIs(err, ErrInvalidClient)
+	s.Nil(c)
+}
+
+func (s *AuthenticateClientSuite) TestAuthenticateDisabled() {
+	s.client.Enabled = false
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "service").Return(s.client, nil).Times(1)
+
+	_, err := s.authenticate.Execute(s.ctx, "service", "secret")
+	s.ErrorIs(err, ErrInvalidClient)
+}
+
+func (s *AuthenticateClientSuite) TestAuthenticateNotFound() {
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "unknown").Return(nil, fmt.Errorf("not found")).Times(1)
+
+	_, err := s.authenticate.Execute(s.ctx, "unknown", "secret")
+	s.ErrorIs(err, ErrInvalidClient)
+}
+
+func (s *AuthenticateClientSuite) TestAuthenticateEmptyClientID() {
+	_, err := s.authenticate.Execute(s.ctx, "", "secret")
+	s.ErrorIs(err, ErrInvalidClient)
+}
+
+func (s *AuthenticateClientSuite) TestAuthenticatePublicClient() {
+	public := &entity.Client{ClientID: "spa", Enabled: true}
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(public, nil).Times(1)
+
+	c, err := s.authenticate.Execute(s.ctx, "spa", "")
+	s.NoError(err)
+	s.Equal(public, c)
+}
+
+func (s *AuthenticateClientSuite) TestAuthenticatePublicClientWithSecret() {
+	public := &entity.Client{ClientID: "spa", Enabled: true}
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(public, nil).Times(1)
+
+	_, err := s.authenticate.Execute(s.ctx, "spa", "secret")
+	s.ErrorIs(err, ErrInvalidClient)
+}
+
+func (s *AuthenticateClientSuite) TestAuthenticateUnsupportedSigningAlgorithm() {
+	s.client.SigningAlgorithm = "ES384"
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "service").Return(s.client, nil).Times(1)
+	s.keyStore.EXPECT().SigningKey("ES384").Return(nil, token.ErrNoSigningKey).Times(1)
+
+	c, err := s.authenticate.Execute(s.ctx, "service", "secret")
+	s.ErrorIs(err, ErrInvalidClient)
+	s.ErrorIs(err, token.ErrNoSigningKey)
+	s.Nil(c)
+}
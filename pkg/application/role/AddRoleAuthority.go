@@ -0,0 +1,68 @@
+//go:generate mockgen -source AddRoleAuthority.go -destination mock/AddRoleAuthority_mock.go -package mock
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/authority"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRoleNotFound             = errors.New("role not found")
+	ErrAuthorityAlreadyAssigned = errors.New("authority already assigned to role")
+	ErrAuthorityNotAssigned     = errors.New("authority not assigned to role")
+)
+
+type AddRoleAuthority interface {
+	Execute(ctx context.Context, roleID uuid.UUID, authorityID uuid.UUID) error
+}
+
+func NewAddRoleAuthority(repoFactory factory.RepositoryFactory) AddRoleAuthority {
+	return addRoleAuthority{
+		roleRepository:          repoFactory.NewRoleRepository(),
+		authorityRepository:     repoFactory.NewAuthorityRepository(),
+		roleAuthorityRepository: repoFactory.NewRoleAuthorityRepository(),
+	}
+}
+
+type addRoleAuthority struct {
+	roleRepository          repository.RoleRepository
+	authorityRepository     repository.AuthorityRepository
+	roleAuthorityRepository repository.RoleAuthorityRepository
+}
+
+func (uc addRoleAuthority) Execute(ctx context.Context, roleID uuid.UUID, authorityID uuid.UUID) error {
+	err := requireRoleAndAuthority(ctx, uc.roleRepository, uc.authorityRepository, roleID, authorityID)
+	if err != nil {
+		return err
+	}
+	err = uc.roleAuthorityRepository.AddRoleAuthority(ctx, roleID, authorityID)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return fmt.Errorf("%w: %s", ErrAuthorityAlreadyAssigned, authorityID)
+	}
+	return err
+}
+
+// requireRoleAndAuthority fails with ErrRoleNotFound or authority.ErrAuthorityNotFound unless both exist.
+func requireRoleAndAuthority(ctx context.Context, roleRepository repository.RoleRepository, authorityRepository repository.AuthorityRepository,
+	roleID uuid.UUID, authorityID uuid.UUID) error {
+	exists, err := roleRepository.ExistsById(ctx, roleID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrRoleNotFound, roleID)
+	}
+	exists, err = authorityRepository.ExistsById(ctx, authorityID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", authority.ErrAuthorityNotFound, authorityID)
+	}
+	return nil
+}
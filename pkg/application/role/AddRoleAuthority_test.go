@@ -0,0 +1,93 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/authority"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type AddRoleAuthoritySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory             *factoryMock.MockRepositoryFactory
+	roleRepository          *repoMock.MockRoleRepository
+	authorityRepository     *repoMock.MockAuthorityRepository
+	roleAuthorityRepository *repoMock.MockRoleAuthorityRepository
+
+	ctx              context.Context
+	addRoleAuthority AddRoleAuthority
+}
+
+func TestAddRoleAuthority(t *testing.T) {
+	suite.Run(t, new(AddRoleAuthoritySuite))
+}
+
+func (s *AddRoleAuthoritySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.roleRepository = repoMock.NewMockRoleRepository(s.mockCtrl)
+	s.authorityRepository = repoMock.NewMockAuthorityRepository(s.mockCtrl)
+	s.roleAuthorityRepository = repoMock.NewMockRoleAuthorityRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewRoleRepository().AnyTimes().Return(s.roleRepository)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepository)
+	s.repoFactory.EXPECT().NewRoleAuthorityRepository().AnyTimes().Return(s.roleAuthorityRepository)
+
+	s.ctx = context.Background()
+	s.addRoleAuthority = NewAddRoleAuthority(s.repoFactory)
+}
+
+func (s *AddRoleAuthoritySuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *AddRoleAuthoritySuite) TestAddOk() {
+	roleId, authorityId := uuid.New(), uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.authorityRepository.EXPECT().ExistsById(s.ctx, authorityId).Return(true, nil).Times(1)
+	s.roleAuthorityRepository.EXPECT().AddRoleAuthority(s.ctx, roleId, authorityId).Return(nil).Times(1)
+
+	s.NoError(s.addRoleAuthority.Execute(s.ctx, roleId, authorityId))
+}
+
+func (s *AddRoleAuthoritySuite) TestAddRoleNotFound() {
+	roleId := uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(false, nil).Times(1)
+
+	s.ErrorIs(s.addRoleAuthority.Execute(s.ctx, roleId, uuid.New()), ErrRoleNotFound)
+}
+
+func (s *AddRoleAuthoritySuite) TestAddAuthorityNotFound() {
+	roleId, authorityId := uuid.New(), uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.authorityRepository.EXPECT().ExistsById(s.ctx, authorityId).Return(false, nil).Times(1)
+
+	s.ErrorIs(s.addRoleAuthority.Execute(s.ctx, roleId, authorityId), authority.ErrAuthorityNotFound)
+}
+
+func (s *AddRoleAuthoritySuite) TestAddAlreadyAssigned() {
+	roleId, authorityId := uuid.New(), uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.authorityRepository.EXPECT().ExistsById(s.ctx, authorityId).Return(true, nil).Times(1)
+	s.roleAuthorityRepository.EXPECT().AddRoleAuthority(s.ctx, roleId, authorityId).Return(fmt.Errorf("could not add: %w", repository.ErrDuplicate)).Times(1)
+
+	s.ErrorIs(s.addRoleAuthority.Execute(s.ctx, roleId, authorityId), ErrAuthorityAlreadyAssigned)
+}
+
+func (s *AddRoleAuthoritySuite) TestAddExistsErr() {
+	roleId := uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(false, errors.New("connection refused")).Times(1)
+
+	s.EqualError(s.addRoleAuthority.Execute(s.ctx, roleId, uuid.New()), "connection refused")
+}
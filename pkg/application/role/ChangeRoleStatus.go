@@ -4,23 +4,26 @@ package role
 import (
 	"context"
 	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
 	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/google/uuid"
 )
 
 type ChangeRoleStatus interface {
-	Execute(ctx context.Context, id uuid.UUID, enabled bool) error
+	Execute(ctx context.Context, id uuid.UUID, enabled bool, revokeTokens bool) error
 }
 
 type changeRoleStatus struct {
-	repo repository.RoleRepository
+	repo             repository.RoleRepository
+	userRoleRepo     repository.UserRoleRepository
+	revokeUserTokens token.RevokeUserTokens
 }
 
-func NewChangeRoleStatus(repo repository.RoleRepository) ChangeRoleStatus {
-	return changeRoleStatus{repo: repo}
+func NewChangeRoleStatus(repo repository.RoleRepository, userRoleRepo repository.UserRoleRepository, revokeUserTokens token.RevokeUserTokens) ChangeRoleStatus {
+	return changeRoleStatus{repo: repo, userRoleRepo: userRoleRepo, revokeUserTokens: revokeUserTokens}
 }
 
-func (uc changeRoleStatus) Execute(ctx context.Context, id uuid.UUID, enabled bool) error {
+func (uc changeRoleStatus) Execute(ctx context.Context, id uuid.UUID, enabled bool, revokeTokens bool) error {
 	exists, err := uc.repo.ExistsById(ctx, id)
 	if err != nil {
 		return err
@@ -28,5 +31,16 @@ func (uc changeRoleStatus) Execute(ctx context.Context, id uuid.UUID, enabled bo
 	if !exists {
 		return fmt.Errorf("role with id %s does not exists", id)
 	}
-	return uc.repo.ChangeStatus(ctx, id, enabled)
+	err = uc.repo.ChangeStatus(ctx, id, enabled)
+	if err != nil {
+		return err
+	}
+	if enabled || !revokeTokens {
+		return nil
+	}
+	userIDs, err := uc.userRoleRepo.FindUserIDsByRoleID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return uc.revokeUserTokens.Execute(ctx, userIDs...)
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
 	"github.com/golauth/golauth/pkg/domain/repository/mock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -18,6 +19,8 @@ type ChangeRoleStatusSuite struct {
 	mockCtrl         *gomock.Controller
 	ctx              context.Context
 	repo             *mock.MockRoleRepository
+	userRoleRepo     *mock.MockUserRoleRepository
+	revokeUserTokens *tokenMock.MockRevokeUserTokens
 	changeRoleStatus ChangeRoleStatus
 }
 
@@ -30,7 +33,9 @@ func (s *ChangeRoleStatusSuite) SetupTest() {
 	s.mockCtrl = gomock.NewController(s.T())
 	s.ctx = context.Background()
 	s.repo = mock.NewMockRoleRepository(s.mockCtrl)
-	s.changeRoleStatus = NewChangeRoleStatus(s.repo)
+	s.userRoleRepo = mock.NewMockUserRoleRepository(s.mockCtrl)
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+	s.changeRoleStatus = NewChangeRoleStatus(s.repo, s.userRoleRepo, s.revokeUserTokens)
 }
 
 func (s *ChangeRoleStatusSuite) TearDownTest() {
@@ -41,7 +46,7 @@ func (s *ChangeRoleStatusSuite) TestChangeStatusOk() {
 	roleId := uuid.New()
 	s.repo.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
 	s.repo.EXPECT().ChangeStatus(s.ctx, roleId, false).Return(nil).Times(1)
-	err := s.changeRoleStatus.Execute(s.ctx, roleId, false)
+	err := s.changeRoleStatus.Execute(s.ctx, roleId, false, false)
 	s.NoError(err)
 }
 
@@ -49,7 +54,7 @@ func (s *ChangeRoleStatusSuite) TestChangeStatusIdNotExists() {
 	roleId := uuid.New()
 	errMessage := fmt.Sprintf("role with id %s does not exists", roleId)
 	s.repo.EXPECT().ExistsById(s.ctx, roleId).Return(false, nil).Times(1)
-	err := s.changeRoleStatus.Execute(s.ctx, roleId, false)
+	err := s.changeRoleStatus.Execute(s.ctx, roleId, false, false)
 	s.Error(err)
 	s.EqualError(err, errMessage)
 }
@@ -58,7 +63,35 @@ func (s *ChangeRoleStatusSuite) TestChangeStatusExistsErr() {
 	errMessage := "could not check if id exists"
 	roleId := uuid.New()
 	s.repo.EXPECT().ExistsById(s.ctx, roleId).Return(false, errors.New(errMessage)).Times(1)
-	err := s.changeRoleStatus.Execute(s.ctx, roleId, false)
+	err := s.changeRoleStatus.Execute(s.ctx, roleId, false, false)
 	s.Error(err)
 	s.EqualError(err, errMessage)
 }
+
+func (s *ChangeRoleStatusSuite) TestChangeStatusRevokeTokens() {
+	roleId := uuid.New()
+	userIDs := []uuid.UUID{uuid.New(), uuid.New()}
+	s.repo.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.repo.EXPECT().ChangeStatus(s.ctx, roleId, false).Return(nil).Times(1)
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(s.ctx, roleId).Return(userIDs, nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, userIDs[0], userIDs[1]).Return(nil).Times(1)
+	err := s.changeRoleStatus.Execute(s.ctx, roleId, false, true)
+	s.NoError(err)
+}
+
+func (s *ChangeRoleStatusSuite) TestEnableDoesNotRevokeTokens() {
+	roleId := uuid.New()
+	s.repo.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.repo.EXPECT().ChangeStatus(s.ctx, roleId, true).Return(nil).Times(1)
+	err := s.changeRoleStatus.Execute(s.ctx, roleId, true, true)
+	s.NoError(err)
+}
+
+func (s *ChangeRoleStatusSuite) TestChangeStatusFindUsersErr() {
+	roleId := uuid.New()
+	s.repo.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.repo.EXPECT().ChangeStatus(s.ctx, roleId, false).Return(nil).Times(1)
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(s.ctx, roleId).Return(nil, errors.New("connection refused")).Times(1)
+	err := s.changeRoleStatus.Execute(s.ctx, roleId, false, true)
+	s.EqualError(err, "connection refused")
+}
@@ -0,0 +1,50 @@
+//go:generate mockgen -source DeleteRole.go -destination mock/DeleteRole_mock.go -package mock
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+var ErrRoleInUse = errors.New("role is assigned to users")
+
+// DeleteRole removes a role with its authorities. A role still held by users is only removed when cascade
+// is set, in which case it is taken away from them and their access tokens are revoked.
+type DeleteRole interface {
+	Execute(ctx context.Context, id uuid.UUID, cascade bool) error
+}
+
+func NewDeleteRole(repo repository.RoleRepository, userRoleRepo repository.UserRoleRepository, revokeUserTokens token.RevokeUserTokens) DeleteRole {
+	return deleteRole{repo: repo, userRoleRepo: userRoleRepo, revokeUserTokens: revokeUserTokens}
+}
+
+type deleteRole struct {
+	repo             repository.RoleRepository
+	userRoleRepo     repository.UserRoleRepository
+	revokeUserTokens token.RevokeUserTokens
+}
+
+func (uc deleteRole) Execute(ctx context.Context, id uuid.UUID, cascade bool) error {
+	userIDs, err := uc.userRoleRepo.FindUserIDsByRoleID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(userIDs) > 0 && !cascade {
+		return fmt.Errorf("%w: %d users", ErrRoleInUse, len(userIDs))
+	}
+	err = uc.repo.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrRoleNotFound, id)
+	}
+	if err != nil {
+		return err
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return uc.revokeUserTokens.Execute(ctx, userIDs...)
+}
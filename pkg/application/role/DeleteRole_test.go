@@ -0,0 +1,88 @@
+package role
+
+import (
+	"context"
+	"errors"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type DeleteRoleSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl         *gomock.Controller
+	ctx              context.Context
+	repo             *mock.MockRoleRepository
+	userRoleRepo     *mock.MockUserRoleRepository
+	revokeUserTokens *tokenMock.MockRevokeUserTokens
+	deleteRole       DeleteRole
+}
+
+func TestDeleteRole(t *testing.T) {
+	suite.Run(t, new(DeleteRoleSuite))
+}
+
+func (s *DeleteRoleSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.repo = mock.NewMockRoleRepository(s.mockCtrl)
+	s.userRoleRepo = mock.NewMockUserRoleRepository(s.mockCtrl)
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+	s.deleteRole = NewDeleteRole(s.repo, s.userRoleRepo, s.revokeUserTokens)
+}
+
+func (s *DeleteRoleSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *DeleteRoleSuite) TestDeleteUnusedRole() {
+	roleId := uuid.New()
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(s.ctx, roleId).Return(nil, nil).Times(1)
+	s.repo.EXPECT().Delete(s.ctx, roleId).Return(nil).Times(1)
+
+	err := s.deleteRole.Execute(s.ctx, roleId, false)
+	s.NoError(err)
+}
+
+func (s *DeleteRoleSuite) TestDeleteRoleInUse() {
+	roleId := uuid.New()
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(s.ctx, roleId).Return([]uuid.UUID{uuid.New()}, nil).Times(1)
+
+	err := s.deleteRole.Execute(s.ctx, roleId, false)
+	s.ErrorIs(err, ErrRoleInUse)
+}
+
+func (s *DeleteRoleSuite) TestDeleteRoleInUseCascade() {
+	roleId := uuid.New()
+	userIds := []uuid.UUID{uuid.New(), uuid.New()}
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(s.ctx, roleId).Return(userIds, nil).Times(1)
+	s.repo.EXPECT().Delete(s.ctx, roleId).Return(nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, userIds[0], userIds[1]).Return(nil).Times(1)
+
+	err := s.deleteRole.Execute(s.ctx, roleId, true)
+	s.NoError(err)
+}
+
+func (s *DeleteRoleSuite) TestDeleteRoleNotFound() {
+	roleId := uuid.New()
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(s.ctx, roleId).Return(nil, nil).Times(1)
+	s.repo.EXPECT().Delete(s.ctx, roleId).Return(repository.ErrNotFound).Times(1)
+
+	err := s.deleteRole.Execute(s.ctx, roleId, true)
+	s.ErrorIs(err, ErrRoleNotFound)
+}
+
+func (s *DeleteRoleSuite) TestDeleteRoleFindUsersError() {
+	roleId := uuid.New()
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(s.ctx, roleId).Return(nil, errors.New("db down")).Times(1)
+
+	err := s.deleteRole.Execute(s.ctx, roleId, true)
+	s.EqualError(err, "db down")
+}
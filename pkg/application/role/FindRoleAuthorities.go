@@ -0,0 +1,38 @@
+//go:generate mockgen -source FindRoleAuthorities.go -destination mock/FindRoleAuthorities_mock.go -package mock
+package role
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+type FindRoleAuthorities interface {
+	Execute(ctx context.Context, roleID uuid.UUID) ([]*entity.Authority, error)
+}
+
+func NewFindRoleAuthorities(repoFactory factory.RepositoryFactory) FindRoleAuthorities {
+	return findRoleAuthorities{
+		roleRepository:          repoFactory.NewRoleRepository(),
+		roleAuthorityRepository: repoFactory.NewRoleAuthorityRepository(),
+	}
+}
+
+type findRoleAuthorities struct {
+	roleRepository          repository.RoleRepository
+	roleAuthorityRepository repository.RoleAuthorityRepository
+}
+
+func (uc findRoleAuthorities) Execute(ctx context.Context, roleID uuid.UUID) ([]*entity.Authority, error) {
+	exists, err := uc.roleRepository.ExistsById(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrRoleNotFound, roleID)
+	}
+	return uc.roleAuthorityRepository.FindAuthoritiesByRoleID(ctx, roleID)
+}
@@ -0,0 +1,78 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type FindRoleAuthoritiesSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory             *factoryMock.MockRepositoryFactory
+	roleRepository          *repoMock.MockRoleRepository
+	authorityRepository     *repoMock.MockAuthorityRepository
+	roleAuthorityRepository *repoMock.MockRoleAuthorityRepository
+
+	ctx                 context.Context
+	findRoleAuthorities FindRoleAuthorities
+}
+
+func TestFindRoleAuthorities(t *testing.T) {
+	suite.Run(t, new(FindRoleAuthoritiesSuite))
+}
+
+func (s *FindRoleAuthoritiesSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.roleRepository = repoMock.NewMockRoleRepository(s.mockCtrl)
+	s.authorityRepository = repoMock.NewMockAuthorityRepository(s.mockCtrl)
+	s.roleAuthorityRepository = repoMock.NewMockRoleAuthorityRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewRoleRepository().AnyTimes().Return(s.roleRepository)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepository)
+	s.repoFactory.EXPECT().NewRoleAuthorityRepository().AnyTimes().Return(s.roleAuthorityRepository)
+
+	s.ctx = context.Background()
+	s.findRoleAuthorities = NewFindRoleAuthorities(s.repoFactory)
+}
+
+func (s *FindRoleAuthoritiesSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *FindRoleAuthoritiesSuite) TestFindOk() {
+	roleId := uuid.New()
+	authorities := []*entity.Authority{{ID: uuid.New(), Name: "ADMIN"}}
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.roleAuthorityRepository.EXPECT().FindAuthoritiesByRoleID(s.ctx, roleId).Return(authorities, nil).Times(1)
+
+	output, err := s.findRoleAuthorities.Execute(s.ctx, roleId)
+	s.NoError(err)
+	s.Equal(authorities, output)
+}
+
+func (s *FindRoleAuthoritiesSuite) TestFindRoleNotFound() {
+	roleId := uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(false, nil).Times(1)
+
+	_, err := s.findRoleAuthorities.Execute(s.ctx, roleId)
+	s.ErrorIs(err, ErrRoleNotFound)
+}
+
+func (s *FindRoleAuthoritiesSuite) TestFindErr() {
+	roleId := uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(false, errors.New("connection refused")).Times(1)
+
+	_, err := s.findRoleAuthorities.Execute(s.ctx, roleId)
+	s.EqualError(err, "connection refused")
+}
@@ -0,0 +1,25 @@
+//go:generate mockgen -source FindRoleUsage.go -destination mock/FindRoleUsage_mock.go -package mock
+package role
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+)
+
+// FindRoleUsage reports, for every role, how many users hold it and which authorities it grants.
+type FindRoleUsage interface {
+	Execute(ctx context.Context) ([]*entity.RoleUsage, error)
+}
+
+func NewFindRoleUsage(repo repository.RoleRepository) FindRoleUsage {
+	return findRoleUsage{repo: repo}
+}
+
+type findRoleUsage struct {
+	repo repository.RoleRepository
+}
+
+func (uc findRoleUsage) Execute(ctx context.Context) ([]*entity.RoleUsage, error) {
+	return uc.repo.FindUsage(ctx)
+}
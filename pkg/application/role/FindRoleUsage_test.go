@@ -0,0 +1,54 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type FindRoleUsageSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl      *gomock.Controller
+	ctx           context.Context
+	repo          *mock.MockRoleRepository
+	findRoleUsage FindRoleUsage
+}
+
+func TestFindRoleUsage(t *testing.T) {
+	suite.Run(t, new(FindRoleUsageSuite))
+}
+
+func (s *FindRoleUsageSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.repo = mock.NewMockRoleRepository(s.mockCtrl)
+	s.findRoleUsage = NewFindRoleUsage(s.repo)
+}
+
+func (s *FindRoleUsageSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *FindRoleUsageSuite) TestFindUsageOk() {
+	usage := []*entity.RoleUsage{{Role: entity.Role{Name: "ADMIN"}, Users: 2, Authorities: []string{"ADMIN"}}}
+	s.repo.EXPECT().FindUsage(s.ctx).Return(usage, nil).Times(1)
+
+	result, err := s.findRoleUsage.Execute(s.ctx)
+	s.NoError(err)
+	s.Equal(usage, result)
+}
+
+func (s *FindRoleUsageSuite) TestFindUsageError() {
+	s.repo.EXPECT().FindUsage(s.ctx).Return(nil, errors.New("db down")).Times(1)
+
+	result, err := s.findRoleUsage.Execute(s.ctx)
+	s.Nil(result)
+	s.EqualError(err, "db down")
+}
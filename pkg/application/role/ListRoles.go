@@ -0,0 +1,67 @@
+//go:generate mockgen -source ListRoles.go -destination mock/ListRoles_mock.go -package mock
+package role
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidSort = errors.New("invalid sort field")
+	ErrInvalidPage = errors.New("invalid page or size")
+)
+
+var sortFields = map[string]bool{
+	repository.RoleSortName:         true,
+	repository.RoleSortCreationDate: true,
+}
+
+type RolePage struct {
+	Roles []*entity.Role
+	Page  int
+	Size  int
+	Total int
+}
+
+// ListRoles returns the zero-based page of roles matching filter. A zero size falls back to
+// DefaultPageSize and an empty sort orders by name.
+type ListRoles interface {
+	Execute(ctx context.Context, filter repository.RoleFilter, page int, size int) (*RolePage, error)
+}
+
+func NewListRoles(repo repository.RoleRepository) ListRoles {
+	return listRoles{repo: repo}
+}
+
+type listRoles struct {
+	repo repository.RoleRepository
+}
+
+func (uc listRoles) Execute(ctx context.Context, filter repository.RoleFilter, page int, size int) (*RolePage, error) {
+	if filter.Sort == "" {
+		filter.Sort = repository.RoleSortName
+	}
+	if !sortFields[filter.Sort] {
+		return nil, ErrInvalidSort
+	}
+	if size == 0 {
+		size = DefaultPageSize
+	}
+	if page < 0 || size < 0 || size > MaxPageSize {
+		return nil, ErrInvalidPage
+	}
+	filter.Offset = page * size
+	filter.Limit = size
+	roles, total, err := uc.repo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &RolePage{Roles: roles, Page: page, Size: size, Total: total}, nil
+}
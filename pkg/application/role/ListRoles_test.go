@@ -0,0 +1,84 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type ListRolesSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	roleRepository *mock.MockRoleRepository
+
+	ctx       context.Context
+	listRoles ListRoles
+}
+
+func TestListRoles(t *testing.T) {
+	suite.Run(t, new(ListRolesSuite))
+}
+
+func (s *ListRolesSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.roleRepository = mock.NewMockRoleRepository(s.mockCtrl)
+
+	s.ctx = context.Background()
+	s.listRoles = NewListRoles(s.roleRepository)
+}
+
+func (s *ListRolesSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ListRolesSuite) TestListDefaults() {
+	roles := []*entity.Role{{Name: "ADMIN"}}
+	expected := repository.RoleFilter{Sort: repository.RoleSortName, Limit: DefaultPageSize}
+	s.roleRepository.EXPECT().FindAll(s.ctx, expected).Return(roles, 1, nil).Times(1)
+
+	page, err := s.listRoles.Execute(s.ctx, repository.RoleFilter{}, 0, 0)
+	s.NoError(err)
+	s.Equal(&RolePage{Roles: roles, Page: 0, Size: DefaultPageSize, Total: 1}, page)
+}
+
+func (s *ListRolesSuite) TestListOffset() {
+	filter := repository.RoleFilter{Name: "adm", Sort: repository.RoleSortCreationDate, Desc: true}
+	expected := filter
+	expected.Offset = 20
+	expected.Limit = 10
+	s.roleRepository.EXPECT().FindAll(s.ctx, expected).Return([]*entity.Role{}, 25, nil).Times(1)
+
+	page, err := s.listRoles.Execute(s.ctx, filter, 2, 10)
+	s.NoError(err)
+	s.Equal(25, page.Total)
+	s.Equal(2, page.Page)
+}
+
+func (s *ListRolesSuite) TestListInvalidSort() {
+	_, err := s.listRoles.Execute(s.ctx, repository.RoleFilter{Sort: "description"}, 0, 0)
+	s.ErrorIs(err, ErrInvalidSort)
+}
+
+func (s *ListRolesSuite) TestListInvalidPage() {
+	_, err := s.listRoles.Execute(s.ctx, repository.RoleFilter{}, -1, 10)
+	s.ErrorIs(err, ErrInvalidPage)
+	_, err = s.listRoles.Execute(s.ctx, repository.RoleFilter{}, 0, MaxPageSize+1)
+	s.ErrorIs(err, ErrInvalidPage)
+}
+
+func (s *ListRolesSuite) TestListRepositoryError() {
+	s.roleRepository.EXPECT().FindAll(s.ctx, gomock.Any()).Return(nil, 0, errors.New("db down")).Times(1)
+
+	page, err := s.listRoles.Execute(s.ctx, repository.RoleFilter{}, 0, 0)
+	s.Nil(page)
+	s.EqualError(err, "db down")
+}
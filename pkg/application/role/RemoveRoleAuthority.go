@@ -0,0 +1,41 @@
+//go:generate mockgen -source RemoveRoleAuthority.go -destination mock/RemoveRoleAuthority_mock.go -package mock
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+type RemoveRoleAuthority interface {
+	Execute(ctx context.Context, roleID uuid.UUID, authorityID uuid.UUID) error
+}
+
+func NewRemoveRoleAuthority(repoFactory factory.RepositoryFactory) RemoveRoleAuthority {
+	return removeRoleAuthority{
+		roleRepository:          repoFactory.NewRoleRepository(),
+		authorityRepository:     repoFactory.NewAuthorityRepository(),
+		roleAuthorityRepository: repoFactory.NewRoleAuthorityRepository(),
+	}
+}
+
+type removeRoleAuthority struct {
+	roleRepository          repository.RoleRepository
+	authorityRepository     repository.AuthorityRepository
+	roleAuthorityRepository repository.RoleAuthorityRepository
+}
+
+func (uc removeRoleAuthority) Execute(ctx context.Context, roleID uuid.UUID, authorityID uuid.UUID) error {
+	err := requireRoleAndAuthority(ctx, uc.roleRepository, uc.authorityRepository, roleID, authorityID)
+	if err != nil {
+		return err
+	}
+	err = uc.roleAuthorityRepository.RemoveRoleAuthority(ctx, roleID, authorityID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrAuthorityNotAssigned, authorityID)
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package role
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/authority"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type RemoveRoleAuthoritySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory             *factoryMock.MockRepositoryFactory
+	roleRepository          *repoMock.MockRoleRepository
+	authorityRepository     *repoMock.MockAuthorityRepository
+	roleAuthorityRepository *repoMock.MockRoleAuthorityRepository
+
+	ctx                 context.Context
+	removeRoleAuthority RemoveRoleAuthority
+}
+
+func TestRemoveRoleAuthority(t *testing.T) {
+	suite.Run(t, new(RemoveRoleAuthoritySuite))
+}
+
+func (s *RemoveRoleAuthoritySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.roleRepository = repoMock.NewMockRoleRepository(s.mockCtrl)
+	s.authorityRepository = repoMock.NewMockAuthorityRepository(s.mockCtrl)
+	s.roleAuthorityRepository = repoMock.NewMockRoleAuthorityRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewRoleRepository().AnyTimes().Return(s.roleRepository)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepository)
+	s.repoFactory.EXPECT().NewRoleAuthorityRepository().AnyTimes().Return(s.roleAuthorityRepository)
+
+	s.ctx = context.Background()
+	s.removeRoleAuthority = NewRemoveRoleAuthority(s.repoFactory)
+}
+
+func (s *RemoveRoleAuthoritySuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *RemoveRoleAuthoritySuite) TestRemoveOk() {
+	roleId, authorityId := uuid.New(), uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.authorityRepository.EXPECT().ExistsById(s.ctx, authorityId).Return(true, nil).Times(1)
+	s.roleAuthorityRepository.EXPECT().RemoveRoleAuthority(s.ctx, roleId, authorityId).Return(nil).Times(1)
+
+	s.NoError(s.removeRoleAuthority.Execute(s.ctx, roleId, authorityId))
+}
+
+func (s *RemoveRoleAuthoritySuite) TestRemoveNotAssigned() {
+	roleId, authorityId := uuid.New(), uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.authorityRepository.EXPECT().ExistsById(s.ctx, authorityId).Return(true, nil).Times(1)
+	s.roleAuthorityRepository.EXPECT().RemoveRoleAuthority(s.ctx, roleId, authorityId).Return(fmt.Errorf("could not remove: %w", repository.ErrNotFound)).Times(1)
+
+	s.ErrorIs(s.removeRoleAuthority.Execute(s.ctx, roleId, authorityId), ErrAuthorityNotAssigned)
+}
+
+func (s *RemoveRoleAuthoritySuite) TestRemoveAuthorityNotFound() {
+	roleId, authorityId := uuid.New(), uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.authorityRepository.EXPECT().ExistsById(s.ctx, authorityId).Return(false, nil).Times(1)
+
+	s.ErrorIs(s.removeRoleAuthority.Execute(s.ctx, roleId, authorityId), authority.ErrAuthorityNotFound)
+}
+
+func (s *RemoveRoleAuthoritySuite) TestRemoveErr() {
+	roleId, authorityId := uuid.New(), uuid.New()
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.authorityRepository.EXPECT().ExistsById(s.ctx, authorityId).Return(true, nil).Times(1)
+	s.roleAuthorityRepository.EXPECT().RemoveRoleAuthority(s.ctx, roleId, authorityId).Return(errors.New("connection refused")).Times(1)
+
+	s.EqualError(s.removeRoleAuthority.Execute(s.ctx, roleId, authorityId), "connection refused")
+}
@@ -0,0 +1,144 @@
+//go:generate mockgen -source Denylist.go -destination mock/Denylist_mock.go -package mock
+package token
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"time"
+)
+
+// Denylist keeps the jti of revoked access tokens until they expire, and the subjects whose tokens
+// were all revoked at once, like a disabled user. Revocations are persisted in the repositories and
+// cached in memory, so checking a token never touches the database.
+type Denylist interface {
+	Load(ctx context.Context) error
+	Revoke(ctx context.Context, jti string, expiresAt time.Time) error
+	IsRevoked(jti string) bool
+	RevokeSubject(ctx context.Context, subject string, expiresAt time.Time) error
+	IsSubjectRevoked(subject string, issuedAt time.Time) bool
+}
+
+func NewDenylist(repo repository.RevokedTokenRepository, subjectRepo repository.RevokedSubjectRepository) Denylist {
+	return &denylist{
+		repo:        repo,
+		subjectRepo: subjectRepo,
+		revoked:     map[string]time.Time{},
+		subjects:    map[string]*entity.RevokedSubject{},
+		now:         time.Now,
+	}
+}
+
+type denylist struct {
+	mu          sync.RWMutex
+	repo        repository.RevokedTokenRepository
+	subjectRepo repository.RevokedSubjectRepository
+	revoked     map[string]time.Time
+	subjects    map[string]*entity.RevokedSubject
+	now         func() time.Time
+}
+
+func (d *denylist) Load(ctx context.Context) error {
+	now := d.now()
+	err := d.repo.DeleteExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+	tokens, err := d.repo.FindActive(ctx, now)
+	if err != nil {
+		return fmt.Errorf("could not load revoked tokens: %w", err)
+	}
+	err = d.subjectRepo.DeleteExpired(ctx, now)
+	if err != nil {
+		return err
+	}
+	revokedSubjects, err := d.subjectRepo.FindActive(ctx, now)
+	if err != nil {
+		return fmt.Errorf("could not load revoked subjects: %w", err)
+	}
+	revoked := make(map[string]time.Time, len(tokens))
+	for _, t := range tokens {
+		revoked[t.JTI] = t.ExpiresAt
+	}
+	subjects := make(map[string]*entity.RevokedSubject, len(revokedSubjects))
+	for _, rs := range revokedSubjects {
+		subjects[rs.Subject] = rs
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.revoked = revoked
+	d.subjects = subjects
+	return nil
+}
+
+func (d *denylist) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	_, err := d.repo.Create(ctx, &entity.RevokedToken{JTI: jti, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.revoked[jti] = expiresAt
+	return nil
+}
+
+func (d *denylist) IsRevoked(jti string) bool {
+	if jti == "" {
+		return false
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	expiresAt, ok := d.revoked[jti]
+	return ok && d.now().Before(expiresAt)
+}
+
+// RevokeSubject revokes every token of the subject issued until now. expiresAt must not be before the
+// expiration of the last of those tokens.
+func (d *denylist) RevokeSubject(ctx context.Context, subject string, expiresAt time.Time) error {
+	revoked := &entity.RevokedSubject{Subject: subject, RevokedAt: d.now(), ExpiresAt: expiresAt}
+	_, err := d.subjectRepo.Save(ctx, revoked)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subjects[subject] = revoked
+	return nil
+}
+
+// IsSubjectRevoked reports whether a token of the subject issued at issuedAt was revoked. Token issue
+// times have second precision, so tokens issued in the second of the revocation are revoked as well.
+func (d *denylist) IsSubjectRevoked(subject string, issuedAt time.Time) bool {
+	if subject == "" {
+		return false
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	revoked, ok := d.subjects[subject]
+	if !ok || !d.now().Before(revoked.ExpiresAt) {
+		return false
+	}
+	return !issuedAt.After(revoked.RevokedAt.Truncate(time.Second))
+}
+
+// ScheduleDenylistRefresh reloads the denylist every interval, picking up tokens revoked by other
+// replicas and dropping the ones that already expired.
+func ScheduleDenylistRefresh(ctx context.Context, denylist Denylist, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := denylist.Load(ctx); err != nil {
+					logrus.Error(err)
+				}
+			}
+		}
+	}()
+}
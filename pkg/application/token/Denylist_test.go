@@ -0,0 +1,121 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type DenylistSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	ctx      context.Context
+
+	revokedRepository *repoMock.MockRevokedTokenRepository
+	subjectRepository *repoMock.MockRevokedSubjectRepository
+	denylist          Denylist
+}
+
+func TestDenylist(t *testing.T) {
+	suite.Run(t, new(DenylistSuite))
+}
+
+func (s *DenylistSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.revokedRepository = repoMock.NewMockRevokedTokenRepository(s.mockCtrl)
+	s.subjectRepository = repoMock.NewMockRevokedSubjectRepository(s.mockCtrl)
+	s.denylist = NewDenylist(s.revokedRepository, s.subjectRepository)
+}
+
+func (s *DenylistSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *DenylistSuite) TestLoad() {
+	s.revokedRepository.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(nil).Times(1)
+	s.revokedRepository.EXPECT().FindActive(s.ctx, gomock.Any()).Return([]*entity.RevokedToken{
+		{JTI: "revoked", ExpiresAt: time.Now().Add(time.Hour)},
+	}, nil).Times(1)
+	revokedAt := time.Now()
+	s.subjectRepository.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(nil).Times(1)
+	s.subjectRepository.EXPECT().FindActive(s.ctx, gomock.Any()).Return([]*entity.RevokedSubject{
+		{Subject: "disabled-user", RevokedAt: revokedAt, ExpiresAt: revokedAt.Add(time.Hour)},
+	}, nil).Times(1)
+
+	s.NoError(s.denylist.Load(s.ctx))
+	s.True(s.denylist.IsRevoked("revoked"))
+	s.False(s.denylist.IsRevoked("other"))
+	s.False(s.denylist.IsRevoked(""))
+	s.True(s.denylist.IsSubjectRevoked("disabled-user", revokedAt.Add(-time.Minute)))
+	s.False(s.denylist.IsSubjectRevoked("disabled-user", revokedAt.Add(time.Minute)))
+	s.False(s.denylist.IsSubjectRevoked("other", revokedAt.Add(-time.Minute)))
+}
+
+func (s *DenylistSuite) TestLoadErr() {
+	s.revokedRepository.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(nil).Times(1)
+	s.revokedRepository.EXPECT().FindActive(s.ctx, gomock.Any()).Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	err := s.denylist.Load(s.ctx)
+	s.EqualError(err, "could not load revoked tokens: connection refused")
+}
+
+func (s *DenylistSuite) TestRevoke() {
+	expiresAt := time.Now().Add(time.Hour)
+	s.revokedRepository.EXPECT().Create(s.ctx, &entity.RevokedToken{JTI: "jti", ExpiresAt: expiresAt}).Return(&entity.RevokedToken{}, nil).Times(1)
+
+	s.NoError(s.denylist.Revoke(s.ctx, "jti", expiresAt))
+	s.True(s.denylist.IsRevoked("jti"))
+}
+
+func (s *DenylistSuite) TestRevokeErrIsNotCached() {
+	s.revokedRepository.EXPECT().Create(s.ctx, gomock.Any()).Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	s.Error(s.denylist.Revoke(s.ctx, "jti", time.Now().Add(time.Hour)))
+	s.False(s.denylist.IsRevoked("jti"))
+}
+
+func (s *DenylistSuite) TestExpiredEntryIsNotRevoked() {
+	s.revokedRepository.EXPECT().Create(s.ctx, gomock.Any()).Return(&entity.RevokedToken{}, nil).Times(1)
+
+	s.NoError(s.denylist.Revoke(s.ctx, "jti", time.Now().Add(-time.Second)))
+	s.False(s.denylist.IsRevoked("jti"))
+}
+
+func (s *DenylistSuite) TestLoadSubjectsErr() {
+	s.revokedRepository.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(nil).Times(1)
+	s.revokedRepository.EXPECT().FindActive(s.ctx, gomock.Any()).Return(nil, nil).Times(1)
+	s.subjectRepository.EXPECT().DeleteExpired(s.ctx, gomock.Any()).Return(nil).Times(1)
+	s.subjectRepository.EXPECT().FindActive(s.ctx, gomock.Any()).Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	err := s.denylist.Load(s.ctx)
+	s.EqualError(err, "could not load revoked subjects: connection refused")
+}
+
+func (s *DenylistSuite) TestRevokeSubject() {
+	issuedBefore := time.Now().Add(-time.Minute)
+	s.subjectRepository.EXPECT().Save(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rs *entity.RevokedSubject) (*entity.RevokedSubject, error) {
+		s.Equal("user", rs.Subject)
+		return rs, nil
+	}).Times(1)
+
+	s.NoError(s.denylist.RevokeSubject(s.ctx, "user", time.Now().Add(time.Hour)))
+	s.True(s.denylist.IsSubjectRevoked("user", issuedBefore))
+	s.False(s.denylist.IsSubjectRevoked("user", time.Now().Add(time.Minute)))
+	s.False(s.denylist.IsSubjectRevoked("", issuedBefore))
+}
+
+func (s *DenylistSuite) TestRevokeSubjectErrIsNotCached() {
+	s.subjectRepository.EXPECT().Save(s.ctx, gomock.Any()).Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	s.Error(s.denylist.RevokeSubject(s.ctx, "user", time.Now().Add(time.Hour)))
+	s.False(s.denylist.IsSubjectRevoked("user", time.Now().Add(-time.Minute)))
+}
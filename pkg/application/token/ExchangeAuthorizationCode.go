@@ -0,0 +1,96 @@
+//go:generate mockgen -source ExchangeAuthorizationCode.go -destination mock/ExchangeAuthorizationCode_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrInvalidAuthorizationCode = errors.New("invalid authorization code")
+
+type ExchangeAuthorizationCode interface {
+	Execute(ctx context.Context, client *entity.Client, code string, redirectURI string, codeVerifier string) (*entity.Token, error)
+}
+
+func NewExchangeAuthorizationCode(repoFactory factory.RepositoryFactory, jwtToken GenerateJwtToken) ExchangeAuthorizationCode {
+	return exchangeAuthorizationCode{
+		userRepository:              repoFactory.NewUserRepository(),
+		userAuthorityRepository:     repoFactory.NewUserAuthorityRepository(),
+		refreshTokenRepository:      repoFactory.NewRefreshTokenRepository(),
+		authorizationCodeRepository: repoFactory.NewAuthorizationCodeRepository(),
+		jwtToken:                    jwtToken,
+	}
+}
+
+type exchangeAuthorizationCode struct {
+	userRepository              repository.UserRepository
+	userAuthorityRepository     repository.UserAuthorityRepository
+	refreshTokenRepository      repository.RefreshTokenRepository
+	authorizationCodeRepository repository.AuthorizationCodeRepository
+	jwtToken                    GenerateJwtToken
+}
+
+func (uc exchangeAuthorizationCode) Execute(ctx context.Context, client *entity.Client, code string, redirectURI string, codeVerifier string) (*entity.Token, error) {
+	if !client.AllowsGrantType(GrantTypeAuthorizationCode) {
+		return nil, ErrUnauthorizedClient
+	}
+	stored, err := uc.authorizationCodeRepository.FindByHash(ctx, HashOpaqueToken(code))
+	if err != nil {
+		return nil, ErrInvalidAuthorizationCode
+	}
+	if stored.IsUsed() || stored.IsExpiredAt(time.Now()) {
+		return nil, ErrInvalidAuthorizationCode
+	}
+	marked, err := uc.authorizationCodeRepository.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not redeem authorization code: %w", err)
+	}
+	if !marked {
+		return nil, ErrInvalidAuthorizationCode
+	}
+	if stored.ClientID != client.ClientID || stored.RedirectURI != redirectURI {
+		return nil, ErrInvalidAuthorizationCode
+	}
+	if !VerifyCodeVerifier(codeVerifier, stored.CodeChallenge, stored.CodeChallengeMethod) {
+		return nil, ErrInvalidAuthorizationCode
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidAuthorizationCode
+	}
+	if !user.Enabled {
+		return nil, ErrAccountDisabled
+	}
+	authorities, err := uc.userAuthorityRepository.FindAuthoritiesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error when fetch authorities: %w", err)
+	}
+	accessToken, err := uc.jwtToken.ExecuteForScope(user, client, authorities, ParseScope(stored.Scope))
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	refreshToken, err := issueRefreshToken(ctx, uc.refreshTokenRepository, &entity.RefreshToken{
+		FamilyID: uuid.New(),
+		UserID:   user.ID,
+		ClientID: client.ClientID,
+		Scope:    &stored.Scope,
+	})
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	output := &entity.Token{AccessToken: accessToken, ExpiresIn: userTokenTTL(), RefreshToken: refreshToken}
+	if HasScope(ParseScope(stored.Scope), ScopeOpenID) {
+		output.IDToken, err = uc.jwtToken.ExecuteIDToken(user, client, stored.Nonce, stored.CreationDate, accessToken)
+		if err != nil {
+			return nil, ErrGeneratingToken
+		}
+	}
+	return output, nil
+}
@@ -0,0 +1,212 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type ExchangeAuthorizationCodeSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository              *repoMock.MockUserRepository
+	userAuthorityRepository     *repoMock.MockUserAuthorityRepository
+	refreshTokenRepository      *repoMock.MockRefreshTokenRepository
+	authorizationCodeRepository *repoMock.MockAuthorizationCodeRepository
+	jwtToken                    *tokenMock.MockGenerateJwtToken
+	repoFactory                 *factoryMock.MockRepositoryFactory
+
+	ctx          context.Context
+	exchangeCode ExchangeAuthorizationCode
+
+	client *entity.Client
+	user   *entity.User
+	code   string
+	stored *entity.AuthorizationCode
+}
+
+func TestExchangeAuthorizationCode(t *testing.T) {
+	suite.Run(t, new(ExchangeAuthorizationCodeSuite))
+}
+
+func (s *ExchangeAuthorizationCodeSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.userAuthorityRepository = repoMock.NewMockUserAuthorityRepository(s.mockCtrl)
+	s.refreshTokenRepository = repoMock.NewMockRefreshTokenRepository(s.mockCtrl)
+	s.authorizationCodeRepository = repoMock.NewMockAuthorizationCodeRepository(s.mockCtrl)
+	s.jwtToken = tokenMock.NewMockGenerateJwtToken(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewUserAuthorityRepository().AnyTimes().Return(s.userAuthorityRepository)
+	s.repoFactory.EXPECT().NewRefreshTokenRepository().AnyTimes().Return(s.refreshTokenRepository)
+	s.repoFactory.EXPECT().NewAuthorizationCodeRepository().AnyTimes().Return(s.authorizationCodeRepository)
+
+	s.ctx = context.Background()
+	s.exchangeCode = NewExchangeAuthorizationCode(s.repoFactory, s.jwtToken)
+
+	s.client = &entity.Client{ClientID: "spa", GrantTypes: []string{GrantTypeAuthorizationCode}, Enabled: true}
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", Enabled: true}
+	s.code = "opaque-code"
+	s.stored = &entity.AuthorizationCode{
+		ID:                  uuid.New(),
+		CodeHash:            HashOpaqueToken(s.code),
+		ClientID:            "spa",
+		UserID:              s.user.ID,
+		RedirectURI:         "https://app/callback",
+		CodeChallenge:       rfcCodeChallenge,
+		CodeChallengeMethod: CodeChallengeMethodS256,
+		ExpiresAt:           time.Now().Add(time.Minute),
+	}
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeOk() {
+	authorities := []string{"USER"}
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+	s.authorizationCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return(authorities, nil).Times(1)
+	s.jwtToken.EXPECT().ExecuteForScope(s.user, s.client, authorities, []string{}).Return("access", nil).Times(1)
+	s.refreshTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+		return rt, nil
+	}).Times(1)
+
+	tk, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.NoError(err)
+	s.Equal("access", tk.AccessToken)
+	s.NotEmpty(tk.RefreshToken)
+	s.Empty(tk.IDToken)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeLimitsTokensToAuthorizedScope() {
+	s.stored.Scope = "ORDERS_READ"
+	authorities := []string{"ADMIN", "ORDERS_READ"}
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+	s.authorizationCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return(authorities, nil).Times(1)
+	s.jwtToken.EXPECT().ExecuteForScope(s.user, s.client, authorities, []string{"ORDERS_READ"}).Return("access", nil).Times(1)
+	var refresh *entity.RefreshToken
+	s.refreshTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+		refresh = rt
+		return rt, nil
+	}).Times(1)
+
+	tk, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.NoError(err)
+	s.Equal("access", tk.AccessToken)
+	s.NotNil(refresh.Scope)
+	s.Equal("ORDERS_READ", *refresh.Scope)
+	s.Equal("spa", refresh.ClientID)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeOpenIDScopeIssuesIDToken() {
+	s.stored.Scope = "openid profile"
+	s.stored.Nonce = "n-0S6_WzA2Mj"
+	s.stored.CreationDate = time.Now().Add(-time.Minute)
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+	s.authorizationCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return(nil, nil).Times(1)
+	s.jwtToken.EXPECT().ExecuteForScope(s.user, s.client, nil, []string{"openid", "profile"}).Return("access", nil).Times(1)
+	s.jwtToken.EXPECT().ExecuteIDToken(s.user, s.client, "n-0S6_WzA2Mj", s.stored.CreationDate, "access").Return("id", nil).Times(1)
+	s.refreshTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+		return rt, nil
+	}).Times(1)
+
+	tk, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.NoError(err)
+	s.Equal("access", tk.AccessToken)
+	s.Equal("id", tk.IDToken)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeAlreadyUsed() {
+	usedAt := time.Now()
+	s.stored.UsedAt = &usedAt
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+
+	_, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.ErrorIs(err, ErrInvalidAuthorizationCode)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeConcurrentRedeem() {
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+	s.authorizationCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(false, nil).Times(1)
+
+	_, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.ErrorIs(err, ErrInvalidAuthorizationCode)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeExpired() {
+	s.stored.ExpiresAt = time.Now().Add(-time.Second)
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+
+	_, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.ErrorIs(err, ErrInvalidAuthorizationCode)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeOtherClient() {
+	other := &entity.Client{ClientID: "other", GrantTypes: []string{GrantTypeAuthorizationCode}, Enabled: true}
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+	s.authorizationCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+
+	_, err := s.exchangeCode.Execute(s.ctx, other, s.code, "https://app/callback", rfcCodeVerifier)
+	s.ErrorIs(err, ErrInvalidAuthorizationCode)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeRedirectURIMismatch() {
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+	s.authorizationCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+
+	_, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/other", rfcCodeVerifier)
+	s.ErrorIs(err, ErrInvalidAuthorizationCode)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeWrongVerifier() {
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+	s.authorizationCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+
+	_, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeChallenge)
+	s.ErrorIs(err, ErrInvalidAuthorizationCode)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeUnknownCode() {
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(nil, fmt.Errorf("not found")).Times(1)
+
+	_, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.ErrorIs(err, ErrInvalidAuthorizationCode)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeGrantTypeNotAllowed() {
+	s.client.GrantTypes = []string{GrantTypeClientCredentials}
+
+	_, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.ErrorIs(err, ErrUnauthorizedClient)
+}
+
+func (s *ExchangeAuthorizationCodeSuite) TestExchangeAccountDisabled() {
+	s.user.Enabled = false
+	s.authorizationCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.code)).Return(s.stored, nil).Times(1)
+	s.authorizationCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+
+	_, err := s.exchangeCode.Execute(s.ctx, s.client, s.code, "https://app/callback", rfcCodeVerifier)
+	s.ErrorIs(err, ErrAccountDisabled)
+}
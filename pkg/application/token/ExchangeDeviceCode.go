@@ -0,0 +1,119 @@
+//go:generate mockgen -source ExchangeDeviceCode.go -destination mock/ExchangeDeviceCode_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+// slowDownIncrement is the number of seconds added to the polling interval of a device polling too fast.
+const slowDownIncrement = 5
+
+// The messages of the polling errors are the RFC 8628 error codes, so clients can tell them apart.
+var (
+	ErrInvalidDeviceCode    = errors.New("invalid device code")
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	ErrSlowDown             = errors.New("slow_down")
+	ErrExpiredToken         = errors.New("expired_token")
+	ErrAccessDenied         = errors.New("access_denied")
+)
+
+// ExchangeDeviceCode answers the polling of a device: it issues the tokens once the user approved the
+// device code, and tells the device to keep waiting, slow down or give up otherwise.
+type ExchangeDeviceCode interface {
+	Execute(ctx context.Context, client *entity.Client, deviceCode string) (*entity.Token, error)
+}
+
+func NewExchangeDeviceCode(repoFactory factory.RepositoryFactory, jwtToken GenerateJwtToken) ExchangeDeviceCode {
+	return exchangeDeviceCode{
+		userRepository:          repoFactory.NewUserRepository(),
+		userAuthorityRepository: repoFactory.NewUserAuthorityRepository(),
+		refreshTokenRepository:  repoFactory.NewRefreshTokenRepository(),
+		deviceCodeRepository:    repoFactory.NewDeviceCodeRepository(),
+		jwtToken:                jwtToken,
+	}
+}
+
+type exchangeDeviceCode struct {
+	userRepository          repository.UserRepository
+	userAuthorityRepository repository.UserAuthorityRepository
+	refreshTokenRepository  repository.RefreshTokenRepository
+	deviceCodeRepository    repository.DeviceCodeRepository
+	jwtToken                GenerateJwtToken
+}
+
+func (uc exchangeDeviceCode) Execute(ctx context.Context, client *entity.Client, deviceCode string) (*entity.Token, error) {
+	if !client.AllowsGrantType(GrantTypeDeviceCode) {
+		return nil, ErrUnauthorizedClient
+	}
+	stored, err := uc.deviceCodeRepository.FindByHash(ctx, HashOpaqueToken(deviceCode))
+	if err != nil || stored.ClientID != client.ClientID || stored.IsUsed() {
+		return nil, ErrInvalidDeviceCode
+	}
+	if stored.IsExpiredAt(time.Now()) {
+		return nil, ErrExpiredToken
+	}
+	if stored.Denied {
+		return nil, ErrAccessDenied
+	}
+	polled, err := uc.deviceCodeRepository.Poll(ctx, stored.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not poll device code: %w", err)
+	}
+	if !polled {
+		err = uc.deviceCodeRepository.SlowDown(ctx, stored.ID, slowDownIncrement)
+		if err != nil {
+			return nil, fmt.Errorf("could not poll device code: %w", err)
+		}
+		return nil, ErrSlowDown
+	}
+	if !stored.IsApproved() {
+		return nil, ErrAuthorizationPending
+	}
+	marked, err := uc.deviceCodeRepository.MarkUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, fmt.Errorf("could not redeem device code: %w", err)
+	}
+	if !marked {
+		return nil, ErrInvalidDeviceCode
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, *stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidDeviceCode
+	}
+	if !user.Enabled {
+		return nil, ErrAccountDisabled
+	}
+	authorities, err := uc.userAuthorityRepository.FindAuthoritiesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error when fetch authorities: %w", err)
+	}
+	accessToken, err := uc.jwtToken.ExecuteForScope(user, client, authorities, ParseScope(stored.Scope))
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	refreshToken, err := issueRefreshToken(ctx, uc.refreshTokenRepository, &entity.RefreshToken{
+		FamilyID: uuid.New(),
+		UserID:   user.ID,
+		ClientID: client.ClientID,
+		Scope:    &stored.Scope,
+	})
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	output := &entity.Token{AccessToken: accessToken, ExpiresIn: userTokenTTL(), RefreshToken: refreshToken}
+	if HasScope(ParseScope(stored.Scope), ScopeOpenID) {
+		output.IDToken, err = uc.jwtToken.ExecuteIDToken(user, client, "", *stored.ApprovedAt, accessToken)
+		if err != nil {
+			return nil, ErrGeneratingToken
+		}
+	}
+	return output, nil
+}
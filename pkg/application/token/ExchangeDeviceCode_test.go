@@ -0,0 +1,214 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type ExchangeDeviceCodeSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository          *repoMock.MockUserRepository
+	userAuthorityRepository *repoMock.MockUserAuthorityRepository
+	refreshTokenRepository  *repoMock.MockRefreshTokenRepository
+	deviceCodeRepository    *repoMock.MockDeviceCodeRepository
+	jwtToken                *tokenMock.MockGenerateJwtToken
+	repoFactory             *factoryMock.MockRepositoryFactory
+
+	ctx                context.Context
+	exchangeDeviceCode ExchangeDeviceCode
+
+	client     *entity.Client
+	user       *entity.User
+	deviceCode string
+	stored     *entity.DeviceCode
+}
+
+func TestExchangeDeviceCode(t *testing.T) {
+	suite.Run(t, new(ExchangeDeviceCodeSuite))
+}
+
+func (s *ExchangeDeviceCodeSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.userAuthorityRepository = repoMock.NewMockUserAuthorityRepository(s.mockCtrl)
+	s.refreshTokenRepository = repoMock.NewMockRefreshTokenRepository(s.mockCtrl)
+	s.deviceCodeRepository = repoMock.NewMockDeviceCodeRepository(s.mockCtrl)
+	s.jwtToken = tokenMock.NewMockGenerateJwtToken(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewUserAuthorityRepository().AnyTimes().Return(s.userAuthorityRepository)
+	s.repoFactory.EXPECT().NewRefreshTokenRepository().AnyTimes().Return(s.refreshTokenRepository)
+	s.repoFactory.EXPECT().NewDeviceCodeRepository().AnyTimes().Return(s.deviceCodeRepository)
+
+	s.ctx = context.Background()
+	s.exchangeDeviceCode = NewExchangeDeviceCode(s.repoFactory, s.jwtToken)
+
+	s.client = &entity.Client{ClientID: "cli", GrantTypes: []string{GrantTypeDeviceCode}, Enabled: true}
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", Enabled: true}
+	s.deviceCode = "opaque-device-code"
+	s.stored = &entity.DeviceCode{
+		ID:             uuid.New(),
+		DeviceCodeHash: HashOpaqueToken(s.deviceCode),
+		UserCode:       "BCDFGHJK",
+		ClientID:       "cli",
+		Interval:       5,
+		ExpiresAt:      time.Now().Add(time.Minute),
+	}
+}
+
+func (s *ExchangeDeviceCodeSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ExchangeDeviceCodeSuite) approve() {
+	approvedAt := time.Now().Add(-time.Second)
+	s.stored.UserID = &s.user.ID
+	s.stored.ApprovedAt = &approvedAt
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeApproved() {
+	s.approve()
+	s.stored.Scope = "USER"
+	authorities := []string{"ADMIN", "USER"}
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Poll(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return(authorities, nil).Times(1)
+	s.jwtToken.EXPECT().ExecuteForScope(s.user, s.client, authorities, []string{"USER"}).Return("access", nil).Times(1)
+	var refresh *entity.RefreshToken
+	s.refreshTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+		refresh = rt
+		return rt, nil
+	}).Times(1)
+
+	tk, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.NoError(err)
+	s.Equal("access", tk.AccessToken)
+	s.NotEmpty(tk.RefreshToken)
+	s.Empty(tk.IDToken)
+	s.NotNil(refresh.Scope)
+	s.Equal("USER", *refresh.Scope)
+	s.Equal("cli", refresh.ClientID)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeOpenIDScopeIssuesIDToken() {
+	s.approve()
+	s.stored.Scope = "openid"
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Poll(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return(nil, nil).Times(1)
+	s.jwtToken.EXPECT().ExecuteForScope(s.user, s.client, nil, []string{"openid"}).Return("access", nil).Times(1)
+	s.jwtToken.EXPECT().ExecuteIDToken(s.user, s.client, "", *s.stored.ApprovedAt, "access").Return("id", nil).Times(1)
+	s.refreshTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+		return rt, nil
+	}).Times(1)
+
+	tk, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.NoError(err)
+	s.Equal("id", tk.IDToken)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangePending() {
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Poll(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrAuthorizationPending)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangePollingTooFast() {
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Poll(s.ctx, s.stored.ID).Return(false, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().SlowDown(s.ctx, s.stored.ID, slowDownIncrement).Return(nil).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrSlowDown)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeExpired() {
+	s.stored.ExpiresAt = time.Now().Add(-time.Second)
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrExpiredToken)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeDenied() {
+	s.stored.Denied = true
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrAccessDenied)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeAlreadyUsed() {
+	s.approve()
+	usedAt := time.Now()
+	s.stored.UsedAt = &usedAt
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrInvalidDeviceCode)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeConcurrentRedeem() {
+	s.approve()
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Poll(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(false, nil).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrInvalidDeviceCode)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeOtherClient() {
+	other := &entity.Client{ClientID: "other", GrantTypes: []string{GrantTypeDeviceCode}, Enabled: true}
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, other, s.deviceCode)
+	s.ErrorIs(err, ErrInvalidDeviceCode)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeUnknownCode() {
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(nil, fmt.Errorf("not found")).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrInvalidDeviceCode)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeGrantTypeNotAllowed() {
+	s.client.GrantTypes = []string{GrantTypeAuthorizationCode}
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrUnauthorizedClient)
+}
+
+func (s *ExchangeDeviceCodeSuite) TestExchangeAccountDisabled() {
+	s.approve()
+	s.user.Enabled = false
+	s.deviceCodeRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.deviceCode)).Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Poll(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+
+	_, err := s.exchangeDeviceCode.Execute(s.ctx, s.client, s.deviceCode)
+	s.ErrorIs(err, ErrAccountDisabled)
+}
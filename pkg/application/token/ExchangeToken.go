@@ -0,0 +1,108 @@
+//go:generate mockgen -source ExchangeToken.go -destination mock/ExchangeToken_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidSubjectToken  = errors.New("invalid subject token")
+	ErrUnsupportedTokenType = errors.New("unsupported token type")
+	ErrInvalidTarget        = errors.New("requested audience is not allowed")
+)
+
+// ExchangeToken implements RFC 8693 token exchange: a confidential client trades a user access token
+// for a token aimed at a downstream audience, carrying a subset of the authorities of both the user and
+// the subject token, and naming the client in the act claim.
+type ExchangeToken interface {
+	Execute(ctx context.Context, client *entity.Client, subjectToken string, subjectTokenType string, requestedTokenType string, audience string, scope string) (*entity.Token, error)
+}
+
+func NewExchangeToken(repoFactory factory.RepositoryFactory, introspectToken IntrospectToken, jwtToken GenerateJwtToken) ExchangeToken {
+	return exchangeToken{
+		userRepository:          repoFactory.NewUserRepository(),
+		userAuthorityRepository: repoFactory.NewUserAuthorityRepository(),
+		introspectToken:         introspectToken,
+		jwtToken:                jwtToken,
+	}
+}
+
+type exchangeToken struct {
+	userRepository          repository.UserRepository
+	userAuthorityRepository repository.UserAuthorityRepository
+	introspectToken         IntrospectToken
+	jwtToken                GenerateJwtToken
+}
+
+func (uc exchangeToken) Execute(ctx context.Context, client *entity.Client, subjectToken string, subjectTokenType string, requestedTokenType string, audience string, scope string) (*entity.Token, error) {
+	if client.IsPublic() || !client.AllowsGrantType(GrantTypeTokenExchange) {
+		return nil, ErrUnauthorizedClient
+	}
+	if !isAccessTokenType(subjectTokenType) || (requestedTokenType != "" && !isAccessTokenType(requestedTokenType)) {
+		return nil, ErrUnsupportedTokenType
+	}
+	audiences, err := resolveAudience(audience, client)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, err := uc.introspectToken.Execute(subjectToken)
+	if err != nil || claims.Username == "" || claims.ExpiresAt == nil {
+		return nil, ErrInvalidSubjectToken
+	}
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidSubjectToken
+	}
+	user, err := uc.userRepository.FindByID(ctx, userID)
+	if err != nil {
+		return nil, ErrInvalidSubjectToken
+	}
+	if !user.Enabled {
+		return nil, ErrAccountDisabled
+	}
+	granted, err := uc.userAuthorityRepository.FindAuthoritiesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error when fetch authorities: %w", err)
+	}
+	// the subject token may already be narrowed, and exchanging it must never widen it back
+	authorities, err := ResolveScopes(ParseScope(scope), IntersectScopes(granted, claims.Authorities))
+	if err != nil {
+		return nil, err
+	}
+
+	actor := &model.Actor{Subject: client.ClientID, Act: claims.Act}
+	accessToken, err := uc.jwtToken.ExecuteForActor(user, client, authorities, audiences, actor, claims.ExpiresAt.Time)
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	return &entity.Token{AccessToken: accessToken, ExpiresIn: actorTokenTTL(claims.ExpiresAt.Time), IssuedTokenType: TokenTypeAccessToken}, nil
+}
+
+func isAccessTokenType(tokenType string) bool {
+	return tokenType == TokenTypeAccessToken || tokenType == TokenTypeJWT
+}
+
+// resolveAudience returns the requested audience when the client may ask for it, or every audience
+// of the client when none is requested. A client without audiences must never get an unbound token.
+func resolveAudience(audience string, client *entity.Client) ([]string, error) {
+	if audience == "" {
+		if len(client.Audiences) == 0 {
+			return nil, fmt.Errorf("%w: no audience", ErrInvalidTarget)
+		}
+		return client.Audiences, nil
+	}
+	for _, a := range client.Audiences {
+		if a == audience {
+			return []string{audience}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrInvalidTarget, audience)
+}
@@ -0,0 +1,196 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/cristalhq/jwt/v3"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type ExchangeTokenSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	ctx      context.Context
+
+	userRepository          *repoMock.MockUserRepository
+	userAuthorityRepository *repoMock.MockUserAuthorityRepository
+	jwtToken                GenerateJwtToken
+	exchangeToken           ExchangeToken
+
+	client *entity.Client
+	user   *entity.User
+}
+
+func TestExchangeToken(t *testing.T) {
+	suite.Run(t, new(ExchangeTokenSuite))
+}
+
+func (s *ExchangeTokenSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+
+	key, err := GenerateSigningKey(DefaultAlgorithm)
+	s.NoError(err)
+	keyRepository := repoMock.NewMockSigningKeyRepository(s.mockCtrl)
+	keyRepository.EXPECT().FindAll(gomock.Any()).Return([]*entity.SigningKey{key}, nil).AnyTimes()
+	keyStore := NewKeyStore(keyRepository, KeyStoreConfig{})
+	s.NoError(keyStore.Load(s.ctx))
+	config := TokenConfig{Issuer: "https://golauth.test", Audience: "https://golauth.test"}
+	denylist := NewDenylist(repoMock.NewMockRevokedTokenRepository(s.mockCtrl), repoMock.NewMockRevokedSubjectRepository(s.mockCtrl))
+	s.jwtToken = NewGenerateJwtToken(keyStore, config)
+
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.userAuthorityRepository = repoMock.NewMockUserAuthorityRepository(s.mockCtrl)
+	repoFactory := factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	repoFactory.EXPECT().NewUserAuthorityRepository().AnyTimes().Return(s.userAuthorityRepository)
+	s.exchangeToken = NewExchangeToken(repoFactory, NewIntrospectToken(keyStore, denylist, config), s.jwtToken)
+
+	s.client = &entity.Client{
+		ClientID:   "gateway",
+		Secret:     "hash",
+		GrantTypes: []string{GrantTypeTokenExchange},
+		Audiences:  []string{"orders", "billing"},
+	}
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", Enabled: true}
+}
+
+func (s *ExchangeTokenSuite) TearDownTest() {
+	TokenExpirationTime = 60
+	s.mockCtrl.Finish()
+}
+
+func (s *ExchangeTokenSuite) claims(token string) *model.Claims {
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	claims := &model.Claims{}
+	s.NoError(json.Unmarshal(parsed.RawClaims(), claims))
+	return claims
+}
+
+func (s *ExchangeTokenSuite) TestExchangeOk() {
+	subjectToken, err := s.jwtToken.Execute(s.user, []string{"ORDERS_READ", "ORDERS_WRITE"})
+	s.NoError(err)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return([]string{"ORDERS_READ", "ORDERS_WRITE"}, nil).Times(1)
+
+	tk, err := s.exchangeToken.Execute(s.ctx, s.client, subjectToken, TokenTypeAccessToken, "", "orders", "ORDERS_READ")
+	s.NoError(err)
+	s.Equal(TokenTypeAccessToken, tk.IssuedTokenType)
+	s.Empty(tk.RefreshToken)
+
+	claims := s.claims(tk.AccessToken)
+	s.Equal(s.user.ID.String(), claims.Subject)
+	s.Equal(jwt.Audience{"orders"}, claims.Audience)
+	s.Equal([]string{"ORDERS_READ"}, claims.Authorities)
+	s.Equal(&model.Actor{Subject: "gateway"}, claims.Act)
+	s.False(claims.ExpiresAt.After(s.claims(subjectToken).ExpiresAt.Time))
+	s.InDelta(time.Until(s.claims(subjectToken).ExpiresAt.Time).Seconds(), tk.ExpiresIn.Seconds(), 1)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeNestsPreviousActor() {
+	subjectToken, err := s.jwtToken.ExecuteForActor(s.user, &entity.Client{ClientID: "web"}, []string{"ORDERS_READ"}, []string{"https://golauth.test"}, &model.Actor{Subject: "web"}, time.Now().Add(time.Hour))
+	s.NoError(err)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return([]string{"ORDERS_READ"}, nil).Times(1)
+
+	tk, err := s.exchangeToken.Execute(s.ctx, s.client, subjectToken, TokenTypeJWT, TokenTypeAccessToken, "", "")
+	s.NoError(err)
+	claims := s.claims(tk.AccessToken)
+	s.Equal(&model.Actor{Subject: "gateway", Act: &model.Actor{Subject: "web"}}, claims.Act)
+	s.Equal(jwt.Audience{"orders", "billing"}, claims.Audience)
+	s.Equal([]string{"ORDERS_READ"}, claims.Authorities)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeCannotWidenNarrowedToken() {
+	subjectToken, err := s.jwtToken.Execute(s.user, []string{"ORDERS_READ", "ORDERS_WRITE", "ADMIN"})
+	s.NoError(err)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(3)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return([]string{"ORDERS_READ", "ORDERS_WRITE", "ADMIN"}, nil).Times(3)
+
+	narrowed, err := s.exchangeToken.Execute(s.ctx, s.client, subjectToken, TokenTypeAccessToken, "", "", "ORDERS_READ")
+	s.NoError(err)
+	s.Equal([]string{"ORDERS_READ"}, s.claims(narrowed.AccessToken).Authorities)
+
+	// re-exchanging the narrowed token without a scope keeps it narrow
+	reexchanged, err := s.exchangeToken.Execute(s.ctx, s.client, narrowed.AccessToken, TokenTypeAccessToken, "", "", "")
+	s.NoError(err)
+	s.Equal([]string{"ORDERS_READ"}, s.claims(reexchanged.AccessToken).Authorities)
+
+	// and asking again for an authority it lost fails
+	_, err = s.exchangeToken.Execute(s.ctx, s.client, narrowed.AccessToken, TokenTypeAccessToken, "", "", "ADMIN")
+	s.ErrorIs(err, ErrInvalidScope)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeScopeNotGranted() {
+	subjectToken, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return([]string{"ORDERS_READ"}, nil).Times(1)
+
+	_, err = s.exchangeToken.Execute(s.ctx, s.client, subjectToken, TokenTypeAccessToken, "", "orders", "ADMIN")
+	s.ErrorIs(err, ErrInvalidScope)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeAudienceNotAllowed() {
+	_, err := s.exchangeToken.Execute(s.ctx, s.client, "subject", TokenTypeAccessToken, "", "payroll", "")
+	s.ErrorIs(err, ErrInvalidTarget)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeNoAudience() {
+	s.client.Audiences = nil
+
+	_, err := s.exchangeToken.Execute(s.ctx, s.client, "subject", TokenTypeAccessToken, "", "", "")
+	s.ErrorIs(err, ErrInvalidTarget)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeClientToken() {
+	subjectToken, err := s.jwtToken.ExecuteForClient(&entity.Client{ClientID: "service"}, nil)
+	s.NoError(err)
+
+	_, err = s.exchangeToken.Execute(s.ctx, s.client, subjectToken, TokenTypeAccessToken, "", "orders", "")
+	s.ErrorIs(err, ErrInvalidSubjectToken)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeInvalidSubjectToken() {
+	_, err := s.exchangeToken.Execute(s.ctx, s.client, "invalid", TokenTypeAccessToken, "", "orders", "")
+	s.ErrorIs(err, ErrInvalidSubjectToken)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeUnsupportedTokenType() {
+	_, err := s.exchangeToken.Execute(s.ctx, s.client, "subject", "urn:ietf:params:oauth:token-type:refresh_token", "", "orders", "")
+	s.ErrorIs(err, ErrUnsupportedTokenType)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeUnauthorizedClient() {
+	s.client.GrantTypes = []string{GrantTypeClientCredentials}
+	_, err := s.exchangeToken.Execute(s.ctx, s.client, "subject", TokenTypeAccessToken, "", "orders", "")
+	s.ErrorIs(err, ErrUnauthorizedClient)
+
+	s.client.GrantTypes = []string{GrantTypeTokenExchange}
+	s.client.Secret = ""
+	_, err = s.exchangeToken.Execute(s.ctx, s.client, "subject", TokenTypeAccessToken, "", "orders", "")
+	s.ErrorIs(err, ErrUnauthorizedClient)
+}
+
+func (s *ExchangeTokenSuite) TestExchangeAccountDisabled() {
+	subjectToken, err := s.jwtToken.Execute(s.user, []string{"ORDERS_READ"})
+	s.NoError(err)
+	disabled := *s.user
+	disabled.Enabled = false
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(&disabled, nil).Times(1)
+
+	_, err = s.exchangeToken.Execute(s.ctx, s.client, subjectToken, TokenTypeAccessToken, "", "orders", "ORDERS_READ")
+	s.ErrorIs(err, ErrAccountDisabled)
+}
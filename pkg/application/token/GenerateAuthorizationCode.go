@@ -0,0 +1,96 @@
+//go:generate mockgen -source GenerateAuthorizationCode.go -destination mock/GenerateAuthorizationCode_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"time"
+)
+
+var (
+	ErrInvalidRedirectURI           = errors.New("unknown client or unregistered redirect uri")
+	AuthorizationCodeExpirationTime = 5
+)
+
+// GenerateAuthorizationCode authenticates the resource owner and issues a single-use code bound to
+// the client, redirect uri, scope and PKCE challenge of the request. The client IP feeds the login throttle.
+// Validate checks the request alone, before the resource owner is asked to log in.
+type GenerateAuthorizationCode interface {
+	Validate(ctx context.Context, request *entity.AuthorizationCode) error
+	Execute(ctx context.Context, username string, password string, request *entity.AuthorizationCode, clientIP string) (string, error)
+}
+
+func NewGenerateAuthorizationCode(repoFactory factory.RepositoryFactory, loginThrottle LoginThrottle, config LoginConfig) GenerateAuthorizationCode {
+	return generateAuthorizationCode{
+		clientRepository:            repoFactory.NewClientRepository(),
+		userRepository:              repoFactory.NewUserRepository(),
+		authorizationCodeRepository: repoFactory.NewAuthorizationCodeRepository(),
+		loginThrottle:               loginThrottle,
+		config:                      config,
+	}
+}
+
+type generateAuthorizationCode struct {
+	clientRepository            repository.ClientRepository
+	userRepository              repository.UserRepository
+	authorizationCodeRepository repository.AuthorizationCodeRepository
+	loginThrottle               LoginThrottle
+	config                      LoginConfig
+}
+
+func (uc generateAuthorizationCode) Validate(ctx context.Context, request *entity.AuthorizationCode) error {
+	_, err := uc.validate(ctx, request)
+	return err
+}
+
+func (uc generateAuthorizationCode) Execute(ctx context.Context, username string, password string, request *entity.AuthorizationCode, clientIP string) (string, error) {
+	scopes, err := uc.validate(ctx, request)
+	if err != nil {
+		return "", err
+	}
+
+	user, err := authenticateUser(ctx, uc.userRepository, uc.loginThrottle, uc.config, username, password, clientIP)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	request.CodeHash = HashOpaqueToken(value)
+	request.UserID = user.ID
+	request.Scope = FormatScope(scopes)
+	request.ExpiresAt = time.Now().Add(time.Duration(AuthorizationCodeExpirationTime) * time.Minute)
+	_, err = uc.authorizationCodeRepository.Create(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// validate returns the scopes the code is bound to once the client, redirect uri, scope and PKCE challenge are valid.
+func (uc generateAuthorizationCode) validate(ctx context.Context, request *entity.AuthorizationCode) ([]string, error) {
+	client, err := uc.clientRepository.FindByClientID(ctx, request.ClientID)
+	if err != nil || !client.Enabled || !client.AllowsRedirectURI(request.RedirectURI) {
+		return nil, ErrInvalidRedirectURI
+	}
+	if !client.AllowsGrantType(GrantTypeAuthorizationCode) {
+		return nil, ErrUnauthorizedClient
+	}
+	scopes, err := resolveClientScopes(client, request.Scope)
+	if err != nil {
+		return nil, err
+	}
+	if request.CodeChallengeMethod == "" {
+		request.CodeChallengeMethod = CodeChallengeMethodPlain
+	}
+	err = ValidateCodeChallenge(request.CodeChallenge, request.CodeChallengeMethod)
+	if err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
@@ -0,0 +1,220 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+	"time"
+)
+
+type GenerateAuthorizationCodeSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	clientRepository            *repoMock.MockClientRepository
+	userRepository              *repoMock.MockUserRepository
+	authorizationCodeRepository *repoMock.MockAuthorizationCodeRepository
+	repoFactory                 *factoryMock.MockRepositoryFactory
+	loginThrottle               *tokenMock.MockLoginThrottle
+
+	ctx                       context.Context
+	generateAuthorizationCode GenerateAuthorizationCode
+
+	client *entity.Client
+	user   *entity.User
+}
+
+func TestGenerateAuthorizationCode(t *testing.T) {
+	suite.Run(t, new(GenerateAuthorizationCodeSuite))
+}
+
+func (s *GenerateAuthorizationCodeSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+
+	s.clientRepository = repoMock.NewMockClientRepository(s.mockCtrl)
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.authorizationCodeRepository = repoMock.NewMockAuthorizationCodeRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.loginThrottle = tokenMock.NewMockLoginThrottle(s.mockCtrl)
+	s.repoFactory.EXPECT().NewClientRepository().AnyTimes().Return(s.clientRepository)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewAuthorizationCodeRepository().AnyTimes().Return(s.authorizationCodeRepository)
+
+	s.ctx = context.Background()
+	s.generateAuthorizationCode = NewGenerateAuthorizationCode(s.repoFactory, s.loginThrottle, LoginConfig{})
+
+	s.client = &entity.Client{
+		ClientID:     "spa",
+		GrantTypes:   []string{GrantTypeAuthorizationCode},
+		Scopes:       []string{"openid", "profile"},
+		RedirectURIs: []string{"https://app/callback"},
+		Enabled:      true,
+	}
+	password, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", Password: string(password), Enabled: true}
+}
+
+func (s *GenerateAuthorizationCodeSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *GenerateAuthorizationCodeSuite) expectLogin(succeeded bool) {
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	if succeeded {
+		s.loginThrottle.EXPECT().Succeed(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	} else {
+		s.loginThrottle.EXPECT().Fail(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	}
+}
+
+func (s *GenerateAuthorizationCodeSuite) request() *entity.AuthorizationCode {
+	return &entity.AuthorizationCode{
+		ClientID:            "spa",
+		RedirectURI:         "https://app/callback",
+		Scope:               "openid",
+		CodeChallenge:       rfcCodeChallenge,
+		CodeChallengeMethod: CodeChallengeMethodS256,
+	}
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestGenerateOk() {
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+	s.userRepository.EXPECT().FindByUsername(s.ctx, "admin").Return(s.user, nil).Times(1)
+	s.expectLogin(true)
+	var stored *entity.AuthorizationCode
+	s.authorizationCodeRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *entity.AuthorizationCode) (*entity.AuthorizationCode, error) {
+		stored = c
+		return c, nil
+	}).Times(1)
+
+	code, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", s.request(), testClientIP)
+	s.NoError(err)
+	s.NotEmpty(code)
+	s.Equal(HashOpaqueToken(code), stored.CodeHash)
+	s.Equal(s.user.ID, stored.UserID)
+	s.Equal("openid", stored.Scope)
+	s.Equal(CodeChallengeMethodS256, stored.CodeChallengeMethod)
+	s.False(stored.IsUsed())
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestGenerateDefaultsToPlainAndAllowedScopes() {
+	request := s.request()
+	request.Scope = ""
+	request.CodeChallenge = rfcCodeVerifier
+	request.CodeChallengeMethod = ""
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+	s.userRepository.EXPECT().FindByUsername(s.ctx, "admin").Return(s.user, nil).Times(1)
+	s.expectLogin(true)
+	s.authorizationCodeRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *entity.AuthorizationCode) (*entity.AuthorizationCode, error) {
+		s.Equal(CodeChallengeMethodPlain, c.CodeChallengeMethod)
+		s.Equal("openid profile", c.Scope)
+		return c, nil
+	}).Times(1)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", request, testClientIP)
+	s.NoError(err)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestValidateDoesNotAuthenticate() {
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+
+	err := s.generateAuthorizationCode.Validate(s.ctx, s.request())
+	s.NoError(err)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestValidateUnregisteredRedirectURI() {
+	request := s.request()
+	request.RedirectURI = "https://evil/callback"
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+
+	err := s.generateAuthorizationCode.Validate(s.ctx, request)
+	s.ErrorIs(err, ErrInvalidRedirectURI)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestUnknownClient() {
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(nil, fmt.Errorf("not found")).Times(1)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", s.request(), testClientIP)
+	s.ErrorIs(err, ErrInvalidRedirectURI)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestUnregisteredRedirectURI() {
+	request := s.request()
+	request.RedirectURI = "https://evil/callback"
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", request, testClientIP)
+	s.ErrorIs(err, ErrInvalidRedirectURI)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestGrantTypeNotAllowed() {
+	s.client.GrantTypes = []string{GrantTypeClientCredentials}
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", s.request(), testClientIP)
+	s.ErrorIs(err, ErrUnauthorizedClient)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestInvalidScope() {
+	request := s.request()
+	request.Scope = "admin"
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", request, testClientIP)
+	s.ErrorIs(err, ErrInvalidScope)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestOpenIDWithSymmetricClientAlgorithm() {
+	s.client.SigningAlgorithm = "HS256"
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+
+	err := s.generateAuthorizationCode.Validate(s.ctx, s.request())
+	s.ErrorIs(err, ErrInvalidScope)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestMissingCodeChallenge() {
+	request := s.request()
+	request.CodeChallenge = ""
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", request, testClientIP)
+	s.ErrorIs(err, ErrInvalidCodeChallenge)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestInvalidPassword() {
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+	s.userRepository.EXPECT().FindByUsername(s.ctx, "admin").Return(s.user, nil).Times(1)
+	s.expectLogin(false)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "wrong", s.request(), testClientIP)
+	s.ErrorIs(err, ErrInvalidUsernameOrPassword)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestEmailNotVerified() {
+	s.generateAuthorizationCode = NewGenerateAuthorizationCode(s.repoFactory, s.loginThrottle, LoginConfig{RequireVerifiedEmail: true})
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+	s.userRepository.EXPECT().FindByUsername(s.ctx, "admin").Return(s.user, nil).Times(1)
+	s.expectLogin(true)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", s.request(), testClientIP)
+	s.ErrorIs(err, ErrEmailNotVerified)
+}
+
+func (s *GenerateAuthorizationCodeSuite) TestThrottled() {
+	s.clientRepository.EXPECT().FindByClientID(s.ctx, "spa").Return(s.client, nil).Times(1)
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(&LoginThrottledError{RetryAfter: time.Minute}).Times(1)
+
+	_, err := s.generateAuthorizationCode.Execute(s.ctx, "admin", "123456", s.request(), testClientIP)
+	s.ErrorIs(err, ErrTooManyLoginAttempts)
+}
@@ -0,0 +1,37 @@
+//go:generate mockgen -source GenerateClientToken.go -destination mock/GenerateClientToken_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+var ErrUnauthorizedClient = errors.New("client is not allowed to use this grant type")
+
+type GenerateClientToken interface {
+	Execute(ctx context.Context, client *entity.Client, scope string) (*entity.Token, error)
+}
+
+func NewGenerateClientToken(jwtToken GenerateJwtToken) GenerateClientToken {
+	return generateClientToken{jwtToken: jwtToken}
+}
+
+type generateClientToken struct {
+	jwtToken GenerateJwtToken
+}
+
+func (uc generateClientToken) Execute(_ context.Context, client *entity.Client, scope string) (*entity.Token, error) {
+	if client.IsPublic() || !client.AllowsGrantType(GrantTypeClientCredentials) {
+		return nil, ErrUnauthorizedClient
+	}
+	scopes, err := ResolveScopes(ParseScope(scope), client.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := uc.jwtToken.ExecuteForClient(client, scopes)
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	return &entity.Token{AccessToken: accessToken, ExpiresIn: clientTokenTTL(client)}, nil
+}
@@ -0,0 +1,99 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type GenerateClientTokenSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	ctx                 context.Context
+	jwtToken            *tokenMock.MockGenerateJwtToken
+	generateClientToken GenerateClientToken
+	client              *entity.Client
+}
+
+func TestGenerateClientToken(t *testing.T) {
+	suite.Run(t, new(GenerateClientTokenSuite))
+}
+
+func (s *GenerateClientTokenSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.jwtToken = tokenMock.NewMockGenerateJwtToken(s.mockCtrl)
+	s.generateClientToken = NewGenerateClientToken(s.jwtToken)
+	s.client = &entity.Client{
+		ClientID:   "service",
+		Secret:     "hash",
+		GrantTypes: []string{GrantTypeClientCredentials},
+		Scopes:     []string{"read", "write"},
+		Enabled:    true,
+	}
+}
+
+func (s *GenerateClientTokenSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *GenerateClientTokenSuite) TestRequestedScope() {
+	s.jwtToken.EXPECT().ExecuteForClient(s.client, []string{"read"}).Return("access", nil).Times(1)
+
+	tk, err := s.generateClientToken.Execute(s.ctx, s.client, "read")
+	s.NoError(err)
+	s.Equal("access", tk.AccessToken)
+	s.Equal(time.Hour, tk.ExpiresIn)
+	s.Empty(tk.RefreshToken)
+}
+
+func (s *GenerateClientTokenSuite) TestClientTokenTTL() {
+	s.client.TokenTTL = 300
+	s.jwtToken.EXPECT().ExecuteForClient(s.client, []string{"read"}).Return("access", nil).Times(1)
+
+	tk, err := s.generateClientToken.Execute(s.ctx, s.client, "read")
+	s.NoError(err)
+	s.Equal(5*time.Minute, tk.ExpiresIn)
+}
+
+func (s *GenerateClientTokenSuite) TestDefaultScope() {
+	s.jwtToken.EXPECT().ExecuteForClient(s.client, []string{"read", "write"}).Return("access", nil).Times(1)
+
+	_, err := s.generateClientToken.Execute(s.ctx, s.client, "")
+	s.NoError(err)
+}
+
+func (s *GenerateClientTokenSuite) TestScopeNotAllowed() {
+	_, err := s.generateClientToken.Execute(s.ctx, s.client, "read admin")
+	s.ErrorIs(err, ErrInvalidScope)
+}
+
+func (s *GenerateClientTokenSuite) TestGrantTypeNotAllowed() {
+	s.client.GrantTypes = []string{GrantTypePassword}
+
+	_, err := s.generateClientToken.Execute(s.ctx, s.client, "")
+	s.ErrorIs(err, ErrUnauthorizedClient)
+}
+
+func (s *GenerateClientTokenSuite) TestJwtErr() {
+	s.jwtToken.EXPECT().ExecuteForClient(s.client, []string{"read"}).Return("", fmt.Errorf("no signing key")).Times(1)
+
+	_, err := s.generateClientToken.Execute(s.ctx, s.client, "read")
+	s.ErrorIs(err, ErrGeneratingToken)
+}
+
+func (s *GenerateClientTokenSuite) TestPublicClientNotAllowed() {
+	s.client.Secret = ""
+
+	_, err := s.generateClientToken.Execute(s.ctx, s.client, "")
+	s.ErrorIs(err, ErrUnauthorizedClient)
+}
@@ -0,0 +1,113 @@
+//go:generate mockgen -source GenerateDeviceCode.go -destination mock/GenerateDeviceCode_mock.go -package mock
+package token
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// userCodeAlphabet has no vowels, so user codes never spell words, and no digits easily confused with letters.
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+	// userCodeAttempts bounds the retries when a new user code collides with one still in use.
+	userCodeAttempts = 5
+)
+
+var (
+	DeviceCodeExpirationTime = 10
+	DeviceCodeInterval       = 5
+)
+
+// GenerateDeviceCode starts a device authorization: it returns the device code the client polls with,
+// and the stored request carrying the user code the user types to approve it.
+type GenerateDeviceCode interface {
+	Execute(ctx context.Context, client *entity.Client, scope string) (string, *entity.DeviceCode, error)
+}
+
+func NewGenerateDeviceCode(repoFactory factory.RepositoryFactory) GenerateDeviceCode {
+	return generateDeviceCode{deviceCodeRepository: repoFactory.NewDeviceCodeRepository()}
+}
+
+type generateDeviceCode struct {
+	deviceCodeRepository repository.DeviceCodeRepository
+}
+
+func (uc generateDeviceCode) Execute(ctx context.Context, client *entity.Client, scope string) (string, *entity.DeviceCode, error) {
+	if !client.AllowsGrantType(GrantTypeDeviceCode) {
+		return "", nil, ErrUnauthorizedClient
+	}
+	scopes, err := resolveClientScopes(client, scope)
+	if err != nil {
+		return "", nil, err
+	}
+	for attempt := 1; ; attempt++ {
+		value, code, err := uc.create(ctx, client, scopes)
+		if errors.Is(err, repository.ErrDuplicate) && attempt < userCodeAttempts {
+			continue
+		}
+		return value, code, err
+	}
+}
+
+func (uc generateDeviceCode) create(ctx context.Context, client *entity.Client, scopes []string) (string, *entity.DeviceCode, error) {
+	value, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", nil, err
+	}
+	userCode, err := generateUserCode()
+	if err != nil {
+		return "", nil, err
+	}
+	code, err := uc.deviceCodeRepository.Create(ctx, &entity.DeviceCode{
+		DeviceCodeHash: HashOpaqueToken(value),
+		UserCode:       userCode,
+		ClientID:       client.ClientID,
+		Scope:          FormatScope(scopes),
+		Interval:       DeviceCodeInterval,
+		ExpiresAt:      time.Now().Add(time.Duration(DeviceCodeExpirationTime) * time.Minute),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return value, code, nil
+}
+
+func generateUserCode() (string, error) {
+	var sb strings.Builder
+	size := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := 0; i < userCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, size)
+		if err != nil {
+			return "", fmt.Errorf("could not generate user code: %w", err)
+		}
+		sb.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// NormalizeUserCode accepts the user code the way users type it, in any case and with or without separators.
+func NormalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(userCode))
+}
+
+// FormatUserCode splits the user code in two halves to make it easier to read and type.
+func FormatUserCode(userCode string) string {
+	if len(userCode) != userCodeLength {
+		return userCode
+	}
+	return userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+}
@@ -0,0 +1,137 @@
+package token
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"strings"
+	"testing"
+	"time"
+)
+
+type GenerateDeviceCodeSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	deviceCodeRepository *repoMock.MockDeviceCodeRepository
+	repoFactory          *factoryMock.MockRepositoryFactory
+
+	ctx                context.Context
+	generateDeviceCode GenerateDeviceCode
+
+	client *entity.Client
+}
+
+func TestGenerateDeviceCode(t *testing.T) {
+	suite.Run(t, new(GenerateDeviceCodeSuite))
+}
+
+func (s *GenerateDeviceCodeSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+
+	s.deviceCodeRepository = repoMock.NewMockDeviceCodeRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewDeviceCodeRepository().AnyTimes().Return(s.deviceCodeRepository)
+
+	s.ctx = context.Background()
+	s.generateDeviceCode = NewGenerateDeviceCode(s.repoFactory)
+
+	s.client = &entity.Client{
+		ClientID:   "cli",
+		GrantTypes: []string{GrantTypeDeviceCode},
+		Scopes:     []string{"openid", "profile"},
+		Enabled:    true,
+	}
+}
+
+func (s *GenerateDeviceCodeSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *GenerateDeviceCodeSuite) TestGenerateOk() {
+	var stored *entity.DeviceCode
+	s.deviceCodeRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *entity.DeviceCode) (*entity.DeviceCode, error) {
+		stored = c
+		return c, nil
+	}).Times(1)
+
+	deviceCode, code, err := s.generateDeviceCode.Execute(s.ctx, s.client, "openid")
+	s.NoError(err)
+	s.NotEmpty(deviceCode)
+	s.Equal(stored, code)
+	s.Equal(HashOpaqueToken(deviceCode), code.DeviceCodeHash)
+	s.Len(code.UserCode, userCodeLength)
+	for _, r := range code.UserCode {
+		s.True(strings.ContainsRune(userCodeAlphabet, r))
+	}
+	s.Equal("cli", code.ClientID)
+	s.Equal("openid", code.Scope)
+	s.Equal(DeviceCodeInterval, code.Interval)
+	s.True(code.ExpiresAt.After(time.Now()))
+	s.True(code.IsPending())
+}
+
+func (s *GenerateDeviceCodeSuite) TestGenerateWithoutScopeGrantsAllowedScopes() {
+	s.deviceCodeRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *entity.DeviceCode) (*entity.DeviceCode, error) {
+		return c, nil
+	}).Times(1)
+
+	_, code, err := s.generateDeviceCode.Execute(s.ctx, s.client, "")
+	s.NoError(err)
+	s.Equal("openid profile", code.Scope)
+}
+
+func (s *GenerateDeviceCodeSuite) TestGenerateRetriesUserCodeCollision() {
+	var userCodes []string
+	s.deviceCodeRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, c *entity.DeviceCode) (*entity.DeviceCode, error) {
+		userCodes = append(userCodes, c.UserCode)
+		if len(userCodes) == 1 {
+			return nil, repository.ErrDuplicate
+		}
+		return c, nil
+	}).Times(2)
+
+	_, code, err := s.generateDeviceCode.Execute(s.ctx, s.client, "openid")
+	s.NoError(err)
+	s.Equal(userCodes[1], code.UserCode)
+}
+
+func (s *GenerateDeviceCodeSuite) TestGenerateGivesUpAfterCollisions() {
+	s.deviceCodeRepository.EXPECT().Create(s.ctx, gomock.Any()).Return(nil, repository.ErrDuplicate).Times(userCodeAttempts)
+
+	_, _, err := s.generateDeviceCode.Execute(s.ctx, s.client, "openid")
+	s.ErrorIs(err, repository.ErrDuplicate)
+}
+
+func (s *GenerateDeviceCodeSuite) TestGenerateInvalidScope() {
+	_, _, err := s.generateDeviceCode.Execute(s.ctx, s.client, "admin")
+	s.ErrorIs(err, ErrInvalidScope)
+}
+
+func (s *GenerateDeviceCodeSuite) TestGenerateOpenIDWithSymmetricClientAlgorithm() {
+	s.client.SigningAlgorithm = "HS256"
+
+	_, _, err := s.generateDeviceCode.Execute(s.ctx, s.client, "openid")
+	s.ErrorIs(err, ErrInvalidScope)
+}
+
+func (s *GenerateDeviceCodeSuite) TestGenerateGrantTypeNotAllowed() {
+	s.client.GrantTypes = []string{GrantTypeAuthorizationCode}
+
+	_, _, err := s.generateDeviceCode.Execute(s.ctx, s.client, "openid")
+	s.ErrorIs(err, ErrUnauthorizedClient)
+}
+
+func (s *GenerateDeviceCodeSuite) TestNormalizeAndFormatUserCode() {
+	s.Equal("BCDFGHJK", NormalizeUserCode("bcdf-ghjk"))
+	s.Equal("BCDFGHJK", NormalizeUserCode(" BCDF GHJK "))
+	s.Equal("BCDF-GHJK", FormatUserCode("BCDFGHJK"))
+	s.Equal("BCD", FormatUserCode("BCD"))
+}
@@ -2,76 +2,179 @@
 package token
 
 import (
-	"crypto/rand"
-	"crypto/rsa"
 	"errors"
 	"fmt"
 	"github.com/cristalhq/jwt/v3"
 	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/google/uuid"
 	"time"
 )
 
 var (
 	ErrBearerTokenExtract = errors.New("bearer token extract error")
-	errSignerGenerate     = errors.New("could not generate signer from private key")
-	errVerifierGenerate   = errors.New("could not generate verifier from public key")
-	keyAlgorithm          = jwt.RS512
 	TokenExpirationTime   = 60
 )
 
 type GenerateJwtToken interface {
 	Execute(user *entity.User, authorities []string) (string, error)
+	ExecuteForScope(user *entity.User, client *entity.Client, authorities []string, scopes []string) (string, error)
+	ExecuteForClient(client *entity.Client, scopes []string) (string, error)
+	ExecuteIDToken(user *entity.User, client *entity.Client, nonce string, authTime time.Time, accessToken string) (string, error)
+	ExecuteForActor(user *entity.User, client *entity.Client, authorities []string, audience []string, actor *model.Actor, notAfter time.Time) (string, error)
 }
 
-func NewGenerateJwtToken(key *rsa.PrivateKey) GenerateJwtToken {
-	return generateJwtToken{signer: GenerateSigner(key)}
+func NewGenerateJwtToken(keyStore KeyStore, config TokenConfig) GenerateJwtToken {
+	return generateJwtToken{keyStore: keyStore, config: config}
 }
 
 type generateJwtToken struct {
-	signer jwt.Signer
+	keyStore KeyStore
+	config   TokenConfig
 }
 
 func (uc generateJwtToken) Execute(user *entity.User, authorities []string) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(TokenExpirationTime) * time.Minute)
+	return uc.executeForUser(user, "", authorities, "", "")
+}
+
+// ExecuteForScope issues a user token to the client limited to the scopes it was authorized for: only the
+// authorities that are also scopes are kept, and the scopes go to the scope claim. It is signed with the client
+// algorithm.
+func (uc generateJwtToken) ExecuteForScope(user *entity.User, client *entity.Client, authorities []string, scopes []string) (string, error) {
+	return uc.executeForUser(user, client.ClientID, IntersectScopes(authorities, scopes), FormatScope(scopes), client.SigningAlgorithm)
+}
+
+func (uc generateJwtToken) executeForUser(user *entity.User, clientID string, authorities []string, scope string, algorithm string) (string, error) {
+	expirationTime := time.Now().Add(userTokenTTL())
 	claims := &model.Claims{
-		Username:    user.Username,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		Authorities: authorities,
+		Username:      user.Username,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		EmailVerified: &user.EmailVerified,
+		ClientID:      clientID,
+		Scope:         scope,
+		Authorities:   authorities,
 		StandardClaims: jwt.StandardClaims{
+			Subject:   user.ID.String(),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
 	}
-	builder := jwt.NewBuilder(uc.signer)
-	tk, err := builder.Build(claims)
-	if err != nil {
-		return "", fmt.Errorf("could not build token with claims: %w", err)
+	return uc.build(claims, algorithm)
+}
+
+func (uc generateJwtToken) ExecuteForClient(client *entity.Client, scopes []string) (string, error) {
+	claims := &model.Claims{
+		ClientID:    client.ClientID,
+		Scope:       FormatScope(scopes),
+		Authorities: client.Authorities,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   client.ClientID,
+			Audience:  client.Audiences,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(clientTokenTTL(client))),
+		},
 	}
+	return uc.build(claims, client.SigningAlgorithm)
+}
 
-	return tk.String(), nil
+// ExecuteForActor issues a user token for the audience carrying the actor in the act claim, signed with the
+// algorithm of the client it is issued to. It never outlives notAfter, the expiration of the token it was
+// exchanged from.
+func (uc generateJwtToken) ExecuteForActor(user *entity.User, client *entity.Client, authorities []string, audience []string, actor *model.Actor, notAfter time.Time) (string, error) {
+	expirationTime := time.Now().Add(actorTokenTTL(notAfter))
+	claims := &model.Claims{
+		Username:      user.Username,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		EmailVerified: &user.EmailVerified,
+		Authorities:   authorities,
+		ClientID:      actor.Subject,
+		Act:           actor,
+		StandardClaims: jwt.StandardClaims{
+			Subject:   user.ID.String(),
+			Audience:  audience,
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+		},
+	}
+	return uc.build(claims, client.SigningAlgorithm)
 }
 
-func GenerateSigner(key *rsa.PrivateKey) jwt.Signer {
-	signer, err := jwt.NewSignerRS(keyAlgorithm, key)
+// ExecuteIDToken issues an OpenID Connect id token for the client, signed with the client algorithm
+// and bound to the access token returned with it by the at_hash claim.
+func (uc generateJwtToken) ExecuteIDToken(user *entity.User, client *entity.Client, nonce string, authTime time.Time, accessToken string) (string, error) {
+	if err := ValidateIDTokenAlgorithm(client.SigningAlgorithm); err != nil {
+		return "", err
+	}
+	key, err := uc.keyStore.SigningKey(client.SigningAlgorithm)
 	if err != nil {
-		panic(errSignerGenerate)
+		return "", fmt.Errorf("could not get signing key: %w", err)
 	}
-	return signer
+	atHash, err := accessTokenHash(key.Algorithm, accessToken)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := &model.IDTokenClaims{
+		AuthTime:      authTime.Unix(),
+		Nonce:         nonce,
+		AtHash:        atHash,
+		ProfileClaims: model.NewProfileClaimsFromEntity(user),
+		StandardClaims: jwt.StandardClaims{
+			ID:        uuid.NewString(),
+			Issuer:    uc.config.Issuer,
+			Subject:   user.ID.String(),
+			Audience:  jwt.Audience{client.ClientID},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(TokenExpirationTime) * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	return sign(key, claims)
+}
+
+// userTokenTTL is the lifetime of the access tokens issued on behalf of a user.
+func userTokenTTL() time.Duration {
+	return time.Duration(TokenExpirationTime) * time.Minute
+}
+
+// clientTokenTTL is the lifetime of the client_credentials tokens of the client.
+func clientTokenTTL(client *entity.Client) time.Duration {
+	if client.TokenTTL > 0 {
+		return time.Duration(client.TokenTTL) * time.Second
+	}
+	return userTokenTTL()
 }
 
-func GeneratePrivateKey() *rsa.PrivateKey {
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+// actorTokenTTL is the lifetime of an exchanged token, which never outlives notAfter.
+func actorTokenTTL(notAfter time.Time) time.Duration {
+	return min(userTokenTTL(), time.Until(notAfter))
+}
+
+// build signs the claims with the current key of the algorithm, or of the default algorithm when empty.
+func (uc generateJwtToken) build(claims *model.Claims, algorithm string) (string, error) {
+	now := jwt.NewNumericDate(time.Now())
+	claims.ID = uuid.NewString()
+	claims.Issuer = uc.config.Issuer
+	claims.IssuedAt = now
+	claims.NotBefore = now
+	if len(claims.Audience) == 0 && uc.config.Audience != "" {
+		claims.Audience = jwt.Audience{uc.config.Audience}
+	}
+	key, err := uc.keyStore.SigningKey(algorithm)
 	if err != nil {
-		panic(fmt.Errorf("could not generate private key: %w", err))
+		return "", fmt.Errorf("could not get signing key: %w", err)
 	}
-	return privateKey
+	return sign(key, claims)
 }
 
-func GenerateVerifier(key *rsa.PrivateKey) jwt.Verifier {
-	verifier, err := jwt.NewVerifierRS(keyAlgorithm, &key.PublicKey)
+func sign(key *entity.SigningKey, claims interface{}) (string, error) {
+	signer, err := GenerateSigner(key)
 	if err != nil {
-		panic(errVerifierGenerate)
+		return "", err
 	}
-	return verifier
+	builder := jwt.NewBuilder(signer, jwt.WithKeyID(key.ID))
+	tk, err := builder.Build(claims)
+	if err != nil {
+		return "", fmt.Errorf("could not build token with claims: %w", err)
+	}
+
+	return tk.String(), nil
 }
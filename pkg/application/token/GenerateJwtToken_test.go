@@ -0,0 +1,159 @@
+package token
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/cristalhq/jwt/v3"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type GenerateJwtTokenSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	keyStore KeyStore
+	jwtToken GenerateJwtToken
+	user     *entity.User
+}
+
+func TestGenerateJwtToken(t *testing.T) {
+	suite.Run(t, new(GenerateJwtTokenSuite))
+}
+
+func (s *GenerateJwtTokenSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	var keys []*entity.SigningKey
+	for _, algorithm := range []string{DefaultAlgorithm, "ES256"} {
+		key, err := GenerateSigningKey(algorithm)
+		s.NoError(err)
+		keys = append(keys, key)
+	}
+	keyRepository := repoMock.NewMockSigningKeyRepository(s.mockCtrl)
+	keyRepository.EXPECT().FindAll(gomock.Any()).Return(keys, nil).AnyTimes()
+	s.keyStore = NewKeyStore(keyRepository, KeyStoreConfig{Algorithms: []string{DefaultAlgorithm, "ES256"}})
+	s.NoError(s.keyStore.Load(context.Background()))
+	s.jwtToken = NewGenerateJwtToken(s.keyStore, TokenConfig{Issuer: "https://golauth.test"})
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", FirstName: "User", LastName: "Name", Email: "em@il.com"}
+}
+
+func (s *GenerateJwtTokenSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *GenerateJwtTokenSuite) verify(token string) *model.IDTokenClaims {
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	key, err := s.keyStore.VerificationKey(parsed.Header().KeyID)
+	s.NoError(err)
+	verifier, err := GenerateVerifier(key)
+	s.NoError(err)
+	parsed, err = jwt.ParseAndVerifyString(token, verifier)
+	s.NoError(err)
+	claims := &model.IDTokenClaims{}
+	s.NoError(json.Unmarshal(parsed.RawClaims(), claims))
+	return claims
+}
+
+func (s *GenerateJwtTokenSuite) TestExecuteIDToken() {
+	authTime := time.Now().Add(-time.Minute)
+	client := &entity.Client{ClientID: "spa", SigningAlgorithm: "ES256"}
+	token, err := s.jwtToken.ExecuteIDToken(s.user, client, "n-0S6_WzA2Mj", authTime, "access-token")
+	s.NoError(err)
+
+	claims := s.verify(token)
+	s.Equal(s.user.ID.String(), claims.Subject)
+	s.Equal("https://golauth.test", claims.Issuer)
+	s.Equal(jwt.Audience{"spa"}, claims.Audience)
+	s.Equal(authTime.Unix(), claims.AuthTime)
+	s.Equal("n-0S6_WzA2Mj", claims.Nonce)
+	s.Equal("User Name", claims.Name)
+	s.Equal("User", claims.GivenName)
+	s.Equal("Name", claims.FamilyName)
+	s.Equal("admin", claims.PreferredUsername)
+	s.Equal("em@il.com", claims.Email)
+
+	sum := sha256.Sum256([]byte("access-token"))
+	s.Equal(base64.RawURLEncoding.EncodeToString(sum[:16]), claims.AtHash)
+}
+
+func (s *GenerateJwtTokenSuite) TestExecuteIDTokenDefaultAlgorithm() {
+	token, err := s.jwtToken.ExecuteIDToken(s.user, &entity.Client{ClientID: "spa"}, "", time.Now(), "access-token")
+	s.NoError(err)
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	s.Equal(jwt.RS512, parsed.Header().Algorithm)
+	s.Len(s.verify(token).AtHash, 43)
+}
+
+func (s *GenerateJwtTokenSuite) TestExecuteIDTokenRejectsSymmetricAlgorithm() {
+	client := &entity.Client{ClientID: "spa", SigningAlgorithm: "HS256"}
+	_, err := s.jwtToken.ExecuteIDToken(s.user, client, "", time.Now(), "access-token")
+	s.ErrorIs(err, ErrUnsupportedAlgorithm)
+}
+
+func (s *GenerateJwtTokenSuite) TestExecuteEmailVerifiedClaim() {
+	s.user.EmailVerified = true
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	claims := &model.Claims{}
+	s.NoError(json.Unmarshal(parsed.RawClaims(), claims))
+	s.NotNil(claims.EmailVerified)
+	s.True(*claims.EmailVerified)
+}
+
+func (s *GenerateJwtTokenSuite) TestExecuteForScopeKeepsOnlyScopedAuthorities() {
+	token, err := s.jwtToken.ExecuteForScope(s.user, &entity.Client{ClientID: "spa"}, []string{"ADMIN", "ORDERS_READ"}, []string{"openid", "ORDERS_READ"})
+	s.NoError(err)
+
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	claims := &model.Claims{}
+	s.NoError(json.Unmarshal(parsed.RawClaims(), claims))
+	s.Equal([]string{"ORDERS_READ"}, claims.Authorities)
+	s.Equal("openid ORDERS_READ", claims.Scope)
+	s.Equal("spa", claims.ClientID)
+}
+
+func (s *GenerateJwtTokenSuite) TestExecuteForScopeClientAlgorithm() {
+	client := &entity.Client{ClientID: "spa", SigningAlgorithm: "ES256"}
+	token, err := s.jwtToken.ExecuteForScope(s.user, client, []string{"ADMIN"}, []string{"ADMIN"})
+	s.NoError(err)
+
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	s.Equal(jwt.ES256, parsed.Header().Algorithm)
+}
+
+func (s *GenerateJwtTokenSuite) TestExecuteForActorClientAlgorithm() {
+	client := &entity.Client{ClientID: "gateway", SigningAlgorithm: "ES256"}
+	token, err := s.jwtToken.ExecuteForActor(s.user, client, []string{"ADMIN"}, []string{"orders"}, &model.Actor{Subject: "gateway"}, time.Now().Add(time.Hour))
+	s.NoError(err)
+
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	s.Equal(jwt.ES256, parsed.Header().Algorithm)
+}
+
+func (s *GenerateJwtTokenSuite) TestExecuteForClientOmitsEmailVerifiedClaim() {
+	token, err := s.jwtToken.ExecuteForClient(&entity.Client{ClientID: "service"}, nil)
+	s.NoError(err)
+
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	s.NotContains(string(parsed.RawClaims()), "email_verified")
+}
@@ -8,25 +8,38 @@ import (
 	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/golauth/golauth/pkg/domain/factory"
 	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 var (
 	ErrInvalidUsernameOrPassword = errors.New("invalid username or password")
 	ErrGeneratingToken           = errors.New("error generating token")
+	ErrAccountDisabled           = errors.New("account_disabled")
+	ErrEmailNotVerified          = errors.New("email_not_verified")
 )
 
+// LoginConfig sets which users may authenticate with their password.
+type LoginConfig struct {
+	// RequireVerifiedEmail refuses users who have not verified their email yet.
+	RequireVerifiedEmail bool
+}
+
+// GenerateToken authenticates the resource owner with their password. The client IP feeds the login throttle.
 type GenerateToken interface {
-	Execute(ctx context.Context, username string, password string) (*entity.Token, error)
+	Execute(ctx context.Context, username string, password string, clientIP string) (*entity.Token, error)
 }
 
-func NewGenerateToken(repoFactory factory.RepositoryFactory, jwtToken GenerateJwtToken) GenerateToken {
+func NewGenerateToken(repoFactory factory.RepositoryFactory, jwtToken GenerateJwtToken, loginThrottle LoginThrottle, config LoginConfig) GenerateToken {
 	return generateToken{
 		userRepository:          repoFactory.NewUserRepository(),
 		roleRepository:          repoFactory.NewRoleRepository(),
 		userRoleRepository:      repoFactory.NewUserRoleRepository(),
 		userAuthorityRepository: repoFactory.NewUserAuthorityRepository(),
+		refreshTokenRepository:  repoFactory.NewRefreshTokenRepository(),
 		jwtToken:                jwtToken,
+		loginThrottle:           loginThrottle,
+		config:                  config,
 	}
 }
 
@@ -35,18 +48,16 @@ type generateToken struct {
 	roleRepository          repository.RoleRepository
 	userRoleRepository      repository.UserRoleRepository
 	userAuthorityRepository repository.UserAuthorityRepository
+	refreshTokenRepository  repository.RefreshTokenRepository
 	jwtToken                GenerateJwtToken
+	loginThrottle           LoginThrottle
+	config                  LoginConfig
 }
 
-func (uc generateToken) Execute(ctx context.Context, username string, password string) (*entity.Token, error) {
-	user, err := uc.userRepository.FindByUsername(ctx, username)
+func (uc generateToken) Execute(ctx context.Context, username string, password string, clientIP string) (*entity.Token, error) {
+	user, err := authenticateUser(ctx, uc.userRepository, uc.loginThrottle, uc.config, username, password, clientIP)
 	if err != nil {
-		return nil, ErrInvalidUsernameOrPassword
-	}
-
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
-	if err != nil {
-		return nil, ErrInvalidUsernameOrPassword
+		return nil, err
 	}
 
 	authorities, err := uc.userAuthorityRepository.FindAuthoritiesByUserID(ctx, user.ID)
@@ -58,5 +69,38 @@ func (uc generateToken) Execute(ctx context.Context, username string, password s
 	if err != nil {
 		return nil, ErrGeneratingToken
 	}
-	return &entity.Token{AccessToken: accessToken}, nil
+	refreshToken, err := issueRefreshToken(ctx, uc.refreshTokenRepository, &entity.RefreshToken{FamilyID: uuid.New(), UserID: user.ID})
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	return &entity.Token{AccessToken: accessToken, ExpiresIn: userTokenTTL(), RefreshToken: refreshToken}, nil
+}
+
+// authenticateUser checks the password of username. Throttled logins are refused before the password is checked.
+// Unknown usernames count as failures too, so guessing them is slowed down just the same.
+func authenticateUser(ctx context.Context, repo repository.UserRepository, throttle LoginThrottle, config LoginConfig, username string, password string, clientIP string) (*entity.User, error) {
+	err := throttle.Attempt(ctx, username, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	user, err := repo.FindByUsername(ctx, username)
+	if err == nil {
+		err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	}
+	if err != nil {
+		if err = throttle.Fail(ctx, username, clientIP); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidUsernameOrPassword
+	}
+	if err = throttle.Succeed(ctx, username, clientIP); err != nil {
+		return nil, err
+	}
+	if !user.Enabled {
+		return nil, ErrAccountDisabled
+	}
+	if config.RequireVerifiedEmail && !user.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+	return user, nil
 }
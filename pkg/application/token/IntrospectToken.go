@@ -0,0 +1,27 @@
+//go:generate mockgen -source IntrospectToken.go -destination mock/IntrospectToken_mock.go -package mock
+package token
+
+import (
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+)
+
+// IntrospectToken returns the claims of an active access token. Any error means the token is not active.
+type IntrospectToken interface {
+	Execute(token string) (*model.Claims, error)
+}
+
+func NewIntrospectToken(keyStore KeyStore, denylist Denylist, config TokenConfig) IntrospectToken {
+	return introspectToken{keyStore: keyStore, denylist: denylist, config: config}
+}
+
+type introspectToken struct {
+	keyStore KeyStore
+	denylist Denylist
+	config   TokenConfig
+}
+
+// Execute does not check the audience, as the resource server asking is the one to decide whether the
+// token was meant for it.
+func (uc introspectToken) Execute(token string) (*model.Claims, error) {
+	return activeClaims(uc.keyStore, uc.denylist, uc.config, token)
+}
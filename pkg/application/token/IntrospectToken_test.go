@@ -0,0 +1,97 @@
+package token
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type IntrospectTokenSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	revokedRepository *repoMock.MockRevokedTokenRepository
+	denylist          Denylist
+	jwtToken          GenerateJwtToken
+	introspectToken   IntrospectToken
+}
+
+func TestIntrospectToken(t *testing.T) {
+	suite.Run(t, new(IntrospectTokenSuite))
+}
+
+func (s *IntrospectTokenSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	keyRepository := repoMock.NewMockSigningKeyRepository(s.mockCtrl)
+	key, err := GenerateSigningKey(DefaultAlgorithm)
+	s.NoError(err)
+	keyRepository.EXPECT().FindAll(gomock.Any()).Return([]*entity.SigningKey{key}, nil).AnyTimes()
+	keyStore := NewKeyStore(keyRepository, KeyStoreConfig{})
+	s.NoError(keyStore.Load(context.Background()))
+
+	s.revokedRepository = repoMock.NewMockRevokedTokenRepository(s.mockCtrl)
+	s.denylist = NewDenylist(s.revokedRepository, repoMock.NewMockRevokedSubjectRepository(s.mockCtrl))
+	s.jwtToken = NewGenerateJwtToken(keyStore, TokenConfig{})
+	s.introspectToken = NewIntrospectToken(keyStore, s.denylist, TokenConfig{})
+}
+
+func (s *IntrospectTokenSuite) TearDownTest() {
+	TokenExpirationTime = 60
+	s.mockCtrl.Finish()
+}
+
+func (s *IntrospectTokenSuite) TestIntrospectUserToken() {
+	token, err := s.jwtToken.Execute(&entity.User{ID: uuid.New(), Username: "admin"}, []string{"ADMIN"})
+	s.NoError(err)
+
+	claims, err := s.introspectToken.Execute(token)
+	s.NoError(err)
+	s.Equal("admin", claims.Username)
+	s.Equal([]string{"ADMIN"}, claims.Authorities)
+	s.NotNil(claims.IssuedAt)
+	s.NotNil(claims.ExpiresAt)
+}
+
+func (s *IntrospectTokenSuite) TestIntrospectClientToken() {
+	token, err := s.jwtToken.ExecuteForClient(&entity.Client{ClientID: "service", Authorities: []string{"SYNC"}}, []string{"read"})
+	s.NoError(err)
+
+	claims, err := s.introspectToken.Execute(token)
+	s.NoError(err)
+	s.Equal("service", claims.Subject)
+	s.Equal("service", claims.ClientID)
+	s.Equal("read", claims.Scope)
+}
+
+func (s *IntrospectTokenSuite) TestIntrospectExpiredToken() {
+	TokenExpirationTime = -1
+	token, err := s.jwtToken.Execute(&entity.User{ID: uuid.New(), Username: "admin"}, nil)
+	s.NoError(err)
+
+	_, err = s.introspectToken.Execute(token)
+	s.ErrorIs(err, errExpiredToken)
+}
+
+func (s *IntrospectTokenSuite) TestIntrospectRevokedToken() {
+	token, err := s.jwtToken.Execute(&entity.User{ID: uuid.New(), Username: "admin"}, nil)
+	s.NoError(err)
+	claims, err := s.introspectToken.Execute(token)
+	s.NoError(err)
+	s.revokedRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.RevokedToken{}, nil).Times(1)
+	s.NoError(s.denylist.Revoke(context.Background(), claims.ID, claims.ExpiresAt.Time))
+
+	_, err = s.introspectToken.Execute(token)
+	s.ErrorIs(err, ErrRevokedToken)
+}
+
+func (s *IntrospectTokenSuite) TestIntrospectInvalidToken() {
+	_, err := s.introspectToken.Execute("invalid")
+	s.Error(err)
+}
@@ -0,0 +1,197 @@
+//go:generate mockgen -source KeyStore.go -destination mock/KeyStore_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoSigningKey  = errors.New("no active signing key")
+	ErrUnknownKeyID  = errors.New("unknown signing key id")
+	ErrKeyGraceEnded = errors.New("signing key retired and grace period ended")
+)
+
+// KeyStore keeps the signing keys loaded from a repository. The newest non retired key of each
+// configured algorithm signs new tokens, while every non retired key, and retired keys still
+// inside the grace period, are accepted to verify tokens.
+type KeyStore interface {
+	Load(ctx context.Context) error
+	Rotate(ctx context.Context) error
+	SigningKey(algorithm string) (*entity.SigningKey, error)
+	VerificationKey(kid string) (*entity.SigningKey, error)
+	VerificationKeys() []*entity.SigningKey
+}
+
+// KeyStoreConfig lists the algorithms a signing key is kept for. The first one is the default,
+// used when no algorithm is asked for, and DefaultAlgorithm is used when the list is empty.
+type KeyStoreConfig struct {
+	Algorithms       []string
+	RotationInterval time.Duration
+	GracePeriod      time.Duration
+}
+
+func NewKeyStore(repo repository.SigningKeyRepository, config KeyStoreConfig) KeyStore {
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = []string{DefaultAlgorithm}
+	}
+	return &keyStore{
+		repo:    repo,
+		config:  config,
+		keys:    map[string]*entity.SigningKey{},
+		current: map[string]*entity.SigningKey{},
+		now:     time.Now,
+	}
+}
+
+type keyStore struct {
+	mu      sync.RWMutex
+	repo    repository.SigningKeyRepository
+	config  KeyStoreConfig
+	keys    map[string]*entity.SigningKey
+	current map[string]*entity.SigningKey
+	now     func() time.Time
+}
+
+// Load reads the keys from the repository, rotating the ones that are due.
+func (ks *keyStore) Load(ctx context.Context) error {
+	return ks.Rotate(ctx)
+}
+
+// Rotate replaces the signing keys older than the rotation interval, or missing, with new ones. The keys are read
+// again from the repository first, and it only stores a key while the one it replaces is still due, so replicas
+// rotating at the same time create a single key per algorithm.
+func (ks *keyStore) Rotate(ctx context.Context) error {
+	err := ks.reload(ctx)
+	if err != nil {
+		return err
+	}
+	due := ks.dueAlgorithms()
+	if len(due) == 0 {
+		return nil
+	}
+	for _, algorithm := range due {
+		key, err := GenerateSigningKey(algorithm)
+		if err != nil {
+			return fmt.Errorf("could not create signing key: %w", err)
+		}
+		_, err = ks.repo.Rotate(ctx, key, ks.config.RotationInterval)
+		if err != nil {
+			return fmt.Errorf("could not rotate signing key: %w", err)
+		}
+	}
+	return ks.reload(ctx)
+}
+
+func (ks *keyStore) reload(ctx context.Context) error {
+	keys, err := ks.repo.FindAll(ctx)
+	if err != nil {
+		return fmt.Errorf("could not load signing keys: %w", err)
+	}
+	ks.replace(keys)
+	return nil
+}
+
+func (ks *keyStore) SigningKey(algorithm string) (*entity.SigningKey, error) {
+	if algorithm == "" {
+		algorithm = ks.config.Algorithms[0]
+	}
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok := ks.current[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoSigningKey, algorithm)
+	}
+	return key, nil
+}
+
+func (ks *keyStore) VerificationKey(kid string) (*entity.SigningKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKeyID, kid)
+	}
+	if !ks.acceptedForVerification(key) {
+		return nil, fmt.Errorf("%w: %s", ErrKeyGraceEnded, kid)
+	}
+	return key, nil
+}
+
+func (ks *keyStore) VerificationKeys() []*entity.SigningKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	var result []*entity.SigningKey
+	for _, k := range ks.keys {
+		if ks.acceptedForVerification(k) {
+			result = append(result, k)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreationDate.Before(result[j].CreationDate)
+	})
+	return result
+}
+
+func (ks *keyStore) acceptedForVerification(key *entity.SigningKey) bool {
+	if !key.IsRetired() {
+		return true
+	}
+	return ks.now().Before(key.RetiredAt.Add(ks.config.GracePeriod))
+}
+
+func (ks *keyStore) dueAlgorithms() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	var result []string
+	for _, algorithm := range ks.config.Algorithms {
+		current, ok := ks.current[algorithm]
+		if !ok || ks.config.RotationInterval > 0 && !ks.now().Before(current.CreationDate.Add(ks.config.RotationInterval)) {
+			result = append(result, algorithm)
+		}
+	}
+	return result
+}
+
+func (ks *keyStore) replace(keys []*entity.SigningKey) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.keys = make(map[string]*entity.SigningKey, len(keys))
+	ks.current = make(map[string]*entity.SigningKey, len(ks.config.Algorithms))
+	for _, k := range keys {
+		ks.keys[k.ID] = k
+		if k.IsRetired() {
+			continue
+		}
+		current, ok := ks.current[k.Algorithm]
+		if !ok || k.CreationDate.After(current.CreationDate) {
+			ks.current[k.Algorithm] = k
+		}
+	}
+}
+
+// ScheduleKeyRotation reloads the key store every interval, picking up keys rotated by other
+// replicas and rotating the signing key when it is older than the configured rotation interval.
+func ScheduleKeyRotation(ctx context.Context, keyStore KeyStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := keyStore.Load(ctx); err != nil {
+					logrus.Error(err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,234 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type KeyStoreSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	ctx      context.Context
+
+	keyRepository *repoMock.MockSigningKeyRepository
+}
+
+func TestKeyStore(t *testing.T) {
+	suite.Run(t, new(KeyStoreSuite))
+}
+
+func (s *KeyStoreSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.keyRepository = repoMock.NewMockSigningKeyRepository(s.mockCtrl)
+}
+
+func (s *KeyStoreSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *KeyStoreSuite) newKey(age time.Duration) *entity.SigningKey {
+	key, err := GenerateSigningKey(DefaultAlgorithm)
+	s.NoError(err)
+	key.CreationDate = time.Now().Add(-age)
+	return key
+}
+
+func (s *KeyStoreSuite) TestLoadNewestActiveKeySigns() {
+	older := s.newKey(2 * time.Hour)
+	newer := s.newKey(time.Hour)
+	s.keyRepository.EXPECT().FindAll(s.ctx).Return([]*entity.SigningKey{newer, older}, nil).Times(1)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{})
+	s.NoError(ks.Load(s.ctx))
+
+	key, err := ks.SigningKey("")
+	s.NoError(err)
+	s.Equal(newer.ID, key.ID)
+	s.Len(ks.VerificationKeys(), 2)
+}
+
+func (s *KeyStoreSuite) TestLoadWithoutKeysCreatesOne() {
+	var created *entity.SigningKey
+	gomock.InOrder(
+		s.keyRepository.EXPECT().FindAll(s.ctx).Return(nil, nil),
+		s.keyRepository.EXPECT().Rotate(s.ctx, gomock.Any(), time.Duration(0)).DoAndReturn(func(_ context.Context, key *entity.SigningKey, _ time.Duration) (bool, error) {
+			created = key
+			created.CreationDate = time.Now()
+			return true, nil
+		}),
+		s.keyRepository.EXPECT().FindAll(s.ctx).DoAndReturn(func(_ context.Context) ([]*entity.SigningKey, error) {
+			return []*entity.SigningKey{created}, nil
+		}),
+	)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{})
+	s.NoError(ks.Load(s.ctx))
+
+	key, err := ks.SigningKey("")
+	s.NoError(err)
+	s.Equal(created.ID, key.ID)
+	s.Equal(DefaultAlgorithm, key.Algorithm)
+}
+
+func (s *KeyStoreSuite) TestLoadRotatesExpiredKey() {
+	old := s.newKey(48 * time.Hour)
+	var created *entity.SigningKey
+	gomock.InOrder(
+		s.keyRepository.EXPECT().FindAll(s.ctx).Return([]*entity.SigningKey{old}, nil),
+		s.keyRepository.EXPECT().Rotate(s.ctx, gomock.Any(), 24*time.Hour).DoAndReturn(func(_ context.Context, key *entity.SigningKey, _ time.Duration) (bool, error) {
+			created = key
+			created.CreationDate = time.Now()
+			return true, nil
+		}),
+		s.keyRepository.EXPECT().FindAll(s.ctx).DoAndReturn(func(_ context.Context) ([]*entity.SigningKey, error) {
+			retiredAt := time.Now()
+			old.RetiredAt = &retiredAt
+			return []*entity.SigningKey{old, created}, nil
+		}),
+	)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{RotationInterval: 24 * time.Hour, GracePeriod: time.Hour})
+	s.NoError(ks.Load(s.ctx))
+
+	key, err := ks.SigningKey("")
+	s.NoError(err)
+	s.Equal(created.ID, key.ID)
+
+	retired, err := ks.VerificationKey(old.ID)
+	s.NoError(err)
+	s.Equal(old.ID, retired.ID)
+}
+
+func (s *KeyStoreSuite) TestLoadPicksUpKeyRotatedByAnotherReplica() {
+	old := s.newKey(48 * time.Hour)
+	rotated := s.newKey(time.Minute)
+	gomock.InOrder(
+		s.keyRepository.EXPECT().FindAll(s.ctx).Return([]*entity.SigningKey{old}, nil),
+		s.keyRepository.EXPECT().Rotate(s.ctx, gomock.Any(), 24*time.Hour).Return(false, nil),
+		s.keyRepository.EXPECT().FindAll(s.ctx).DoAndReturn(func(_ context.Context) ([]*entity.SigningKey, error) {
+			retiredAt := time.Now()
+			old.RetiredAt = &retiredAt
+			return []*entity.SigningKey{old, rotated}, nil
+		}),
+	)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{RotationInterval: 24 * time.Hour, GracePeriod: time.Hour})
+	s.NoError(ks.Load(s.ctx))
+
+	key, err := ks.SigningKey("")
+	s.NoError(err)
+	s.Equal(rotated.ID, key.ID)
+}
+
+func (s *KeyStoreSuite) TestRotateRereadsKeys() {
+	current := s.newKey(time.Hour)
+	s.keyRepository.EXPECT().FindAll(s.ctx).Return([]*entity.SigningKey{current}, nil).Times(1)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{RotationInterval: 24 * time.Hour})
+	s.NoError(ks.Rotate(s.ctx))
+
+	key, err := ks.SigningKey("")
+	s.NoError(err)
+	s.Equal(current.ID, key.ID)
+}
+
+func (s *KeyStoreSuite) TestRotateErr() {
+	gomock.InOrder(
+		s.keyRepository.EXPECT().FindAll(s.ctx).Return(nil, nil),
+		s.keyRepository.EXPECT().Rotate(s.ctx, gomock.Any(), time.Duration(0)).Return(false, fmt.Errorf("connection refused")),
+	)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{})
+	err := ks.Rotate(s.ctx)
+	s.EqualError(err, "could not rotate signing key: connection refused")
+}
+
+func (s *KeyStoreSuite) TestLoadKeyNotDueIsNotRotated() {
+	key := s.newKey(time.Hour)
+	s.keyRepository.EXPECT().FindAll(s.ctx).Return([]*entity.SigningKey{key}, nil).Times(1)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{RotationInterval: 24 * time.Hour})
+	s.NoError(ks.Load(s.ctx))
+
+	current, err := ks.SigningKey("")
+	s.NoError(err)
+	s.Equal(key.ID, current.ID)
+}
+
+func (s *KeyStoreSuite) TestVerificationKeyGraceEnded() {
+	active := s.newKey(time.Hour)
+	retired := s.newKey(72 * time.Hour)
+	retiredAt := time.Now().Add(-2 * time.Hour)
+	retired.RetiredAt = &retiredAt
+	s.keyRepository.EXPECT().FindAll(s.ctx).Return([]*entity.SigningKey{retired, active}, nil).Times(1)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{GracePeriod: time.Hour})
+	s.NoError(ks.Load(s.ctx))
+
+	_, err := ks.VerificationKey(retired.ID)
+	s.ErrorIs(err, ErrKeyGraceEnded)
+	s.Len(ks.VerificationKeys(), 1)
+}
+
+func (s *KeyStoreSuite) TestVerificationKeyUnknown() {
+	s.keyRepository.EXPECT().FindAll(s.ctx).Return([]*entity.SigningKey{s.newKey(time.Hour)}, nil).Times(1)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{})
+	s.NoError(ks.Load(s.ctx))
+
+	_, err := ks.VerificationKey("unknown")
+	s.ErrorIs(err, ErrUnknownKeyID)
+}
+
+func (s *KeyStoreSuite) TestSigningKeyNotLoaded() {
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{})
+	_, err := ks.SigningKey("")
+	s.ErrorIs(err, ErrNoSigningKey)
+}
+
+func (s *KeyStoreSuite) TestLoadErr() {
+	s.keyRepository.EXPECT().FindAll(s.ctx).Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{})
+	err := ks.Load(s.ctx)
+	s.EqualError(err, "could not load signing keys: connection refused")
+}
+
+func (s *KeyStoreSuite) TestLoadCreatesKeyForEachAlgorithm() {
+	rsaKey := s.newKey(time.Hour)
+	var created *entity.SigningKey
+	gomock.InOrder(
+		s.keyRepository.EXPECT().FindAll(s.ctx).Return([]*entity.SigningKey{rsaKey}, nil),
+		s.keyRepository.EXPECT().Rotate(s.ctx, gomock.Any(), time.Duration(0)).DoAndReturn(func(_ context.Context, key *entity.SigningKey, _ time.Duration) (bool, error) {
+			s.Equal("EdDSA", key.Algorithm)
+			key.CreationDate = time.Now()
+			created = key
+			return true, nil
+		}),
+		s.keyRepository.EXPECT().FindAll(s.ctx).DoAndReturn(func(_ context.Context) ([]*entity.SigningKey, error) {
+			return []*entity.SigningKey{rsaKey, created}, nil
+		}),
+	)
+
+	ks := NewKeyStore(s.keyRepository, KeyStoreConfig{Algorithms: []string{DefaultAlgorithm, "EdDSA"}})
+	s.NoError(ks.Load(s.ctx))
+
+	key, err := ks.SigningKey("")
+	s.NoError(err)
+	s.Equal(DefaultAlgorithm, key.Algorithm)
+	key, err = ks.SigningKey("EdDSA")
+	s.NoError(err)
+	s.Equal("EdDSA", key.Algorithm)
+	_, err = ks.SigningKey("ES256")
+	s.ErrorIs(err, ErrNoSigningKey)
+}
@@ -0,0 +1,28 @@
+//go:generate mockgen -source ListLockedLogins.go -destination mock/ListLockedLogins_mock.go -package mock
+package token
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"time"
+)
+
+// ListLockedLogins returns the usernames and client IPs the login throttle currently refuses, whether delayed
+// or locked out.
+type ListLockedLogins interface {
+	Execute(ctx context.Context) ([]*entity.LoginFailure, error)
+}
+
+func NewListLockedLogins(repoFactory factory.RepositoryFactory) ListLockedLogins {
+	return listLockedLogins{loginFailureRepository: repoFactory.NewLoginFailureRepository()}
+}
+
+type listLockedLogins struct {
+	loginFailureRepository repository.LoginFailureRepository
+}
+
+func (uc listLockedLogins) Execute(ctx context.Context) ([]*entity.LoginFailure, error) {
+	return uc.loginFailureRepository.FindLocked(ctx, time.Now())
+}
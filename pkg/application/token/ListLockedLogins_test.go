@@ -0,0 +1,56 @@
+package token
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type ListLockedLoginsSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	loginFailureRepository *repoMock.MockLoginFailureRepository
+	repoFactory            *factoryMock.MockRepositoryFactory
+
+	ctx              context.Context
+	listLockedLogins ListLockedLogins
+}
+
+func TestListLockedLogins(t *testing.T) {
+	suite.Run(t, new(ListLockedLoginsSuite))
+}
+
+func (s *ListLockedLoginsSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.loginFailureRepository = repoMock.NewMockLoginFailureRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewLoginFailureRepository().AnyTimes().Return(s.loginFailureRepository)
+
+	s.ctx = context.Background()
+	s.listLockedLogins = NewListLockedLogins(s.repoFactory)
+}
+
+func (s *ListLockedLoginsSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ListLockedLoginsSuite) TestListOk() {
+	locked := []*entity.LoginFailure{{Kind: entity.LoginFailureKindUsername, Subject: "jdoe", Failures: 10}}
+	s.loginFailureRepository.EXPECT().FindLocked(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, now time.Time) ([]*entity.LoginFailure, error) {
+		s.WithinDuration(time.Now(), now, time.Second)
+		return locked, nil
+	}).Times(1)
+
+	result, err := s.listLockedLogins.Execute(s.ctx)
+	s.NoError(err)
+	s.Equal(locked, result)
+}
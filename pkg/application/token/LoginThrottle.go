@@ -0,0 +1,229 @@
+//go:generate mockgen -source LoginThrottle.go -destination mock/LoginThrottle_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/audit"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/sirupsen/logrus"
+	"math"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultLoginDelayAfter      = 3
+	DefaultLoginLockoutAfter    = 10
+	DefaultLoginIPDelayAfter    = 20
+	DefaultLoginIPLockoutAfter  = 100
+	DefaultLoginDelay           = time.Second
+	DefaultLoginLockoutDuration = 15 * time.Minute
+	DefaultLoginFailureWindow   = 15 * time.Minute
+)
+
+var ErrTooManyLoginAttempts = errors.New("too_many_attempts")
+
+// LoginThrottledError refuses a login while its username or client IP is delayed or locked out. It matches
+// ErrTooManyLoginAttempts.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed logins, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// LoginThrottleRule sets after how many failures a subject is delayed and then locked out. Zero turns each
+// step off.
+type LoginThrottleRule struct {
+	DelayAfter   int
+	LockoutAfter int
+}
+
+func (r LoginThrottleRule) enabled() bool {
+	return r.DelayAfter > 0 || r.LockoutAfter > 0
+}
+
+// LoginThrottleConfig throttles usernames and client IPs with their own rules, IPs usually being shared by many
+// users. Delays start at Delay and double with every further failure, up to LockoutDuration. Failures older
+// than Window are forgotten.
+type LoginThrottleConfig struct {
+	Username        LoginThrottleRule
+	IP              LoginThrottleRule
+	Delay           time.Duration
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+// LoginThrottle slows down password guessing. Failed logins are counted per username and per client IP in the
+// database, so every replica sees them, and the subjects with too many failures are refused for a while.
+type LoginThrottle interface {
+	// Attempt counts a login attempt before its password is checked, failing with a LoginThrottledError when the
+	// username or the client IP may not log in yet. Counting first keeps concurrent attempts from all getting
+	// through under a threshold.
+	Attempt(ctx context.Context, username string, clientIP string) error
+	// Fail delays or locks out the subjects of a failed attempt that are over their threshold.
+	Fail(ctx context.Context, username string, clientIP string) error
+	// Succeed forgets the failures of the username and uncounts the attempt of the client IP. The other failures
+	// of the client IP are kept, or logging into an own account would hide the guesses made on others.
+	Succeed(ctx context.Context, username string, clientIP string) error
+}
+
+func NewLoginThrottle(repoFactory factory.RepositoryFactory, auditor audit.Auditor, config LoginThrottleConfig) LoginThrottle {
+	if config.Delay <= 0 {
+		config.Delay = DefaultLoginDelay
+	}
+	if config.LockoutDuration <= 0 {
+		config.LockoutDuration = DefaultLoginLockoutDuration
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultLoginFailureWindow
+	}
+	return loginThrottle{
+		loginFailureRepository: repoFactory.NewLoginFailureRepository(),
+		auditor:                auditor,
+		config:                 config,
+	}
+}
+
+type loginThrottle struct {
+	loginFailureRepository repository.LoginFailureRepository
+	auditor                audit.Auditor
+	config                 LoginThrottleConfig
+}
+
+type loginSubject struct {
+	kind    string
+	subject string
+	rule    LoginThrottleRule
+}
+
+func (t loginThrottle) subjects(username string, clientIP string) []loginSubject {
+	var subjects []loginSubject
+	if t.config.Username.enabled() {
+		subjects = append(subjects, loginSubject{kind: entity.LoginFailureKindUsername, subject: username, rule: t.config.Username})
+	}
+	if t.config.IP.enabled() && clientIP != "" {
+		subjects = append(subjects, loginSubject{kind: entity.LoginFailureKindIP, subject: clientIP, rule: t.config.IP})
+	}
+	return subjects
+}
+
+func (t loginThrottle) Attempt(ctx context.Context, username string, clientIP string) error {
+	now := time.Now()
+	var retryAfter time.Duration
+	var counted []loginSubject
+	for _, s := range t.subjects(username, clientIP) {
+		failure, err := t.loginFailureRepository.RecordAttempt(ctx, s.kind, s.subject, now, now.Add(-t.config.Window))
+		if err != nil {
+			return err
+		}
+		if failure.LockedUntil != nil && failure.LockedUntil.After(now) {
+			retryAfter = max(retryAfter, failure.LockedUntil.Sub(now))
+			continue
+		}
+		counted = append(counted, s)
+		// Attempts over a threshold get here only when made at the same time as the one reaching it, before its
+		// failure took effect.
+		if s.rule.LockoutAfter > 0 && failure.Failures > s.rule.LockoutAfter {
+			if err = t.lockout(ctx, s, failure.Failures, clientIP, now); err != nil {
+				return err
+			}
+			retryAfter = max(retryAfter, t.config.LockoutDuration)
+		} else if s.rule.DelayAfter > 0 && failure.Failures > s.rule.DelayAfter && failure.LockedUntil == nil {
+			retryAfter = max(retryAfter, t.config.Delay)
+		}
+	}
+	if retryAfter > 0 {
+		// A refused attempt is no guess, so it does not count towards the thresholds of the other subjects.
+		for _, s := range counted {
+			if err := t.loginFailureRepository.ForgetAttempt(ctx, s.kind, s.subject); err != nil {
+				return err
+			}
+		}
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+func (t loginThrottle) Fail(ctx context.Context, username string, clientIP string) error {
+	now := time.Now()
+	for _, s := range t.subjects(username, clientIP) {
+		failure, err := t.loginFailureRepository.Find(ctx, s.kind, s.subject)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if s.rule.LockoutAfter > 0 && failure.Failures >= s.rule.LockoutAfter {
+			if err = t.lockout(ctx, s, failure.Failures, clientIP, now); err != nil {
+				return err
+			}
+		} else if delay := t.delay(s.rule, failure.Failures); delay > 0 {
+			if err = t.loginFailureRepository.Lock(ctx, s.kind, s.subject, now.Add(delay)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t loginThrottle) lockout(ctx context.Context, s loginSubject, failures int, clientIP string, now time.Time) error {
+	until := now.Add(t.config.LockoutDuration)
+	if err := t.loginFailureRepository.Lock(ctx, s.kind, s.subject, until); err != nil {
+		return err
+	}
+	t.record(ctx, audit.Event{
+		Type:    audit.EventLoginLocked,
+		Time:    now,
+		Subject: s.subject,
+		Attributes: map[string]string{
+			"kind":         s.kind,
+			"failures":     strconv.Itoa(failures),
+			"locked_until": until.UTC().Format(time.RFC3339),
+			"client_ip":    clientIP,
+		},
+	})
+	return nil
+}
+
+// delay doubles from the first failure at DelayAfter on, never going over a lockout.
+func (t loginThrottle) delay(rule LoginThrottleRule, failures int) time.Duration {
+	if rule.DelayAfter <= 0 || failures < rule.DelayAfter {
+		return 0
+	}
+	factor := math.Exp2(float64(failures - rule.DelayAfter))
+	if float64(t.config.Delay)*factor >= float64(t.config.LockoutDuration) {
+		return t.config.LockoutDuration
+	}
+	return time.Duration(float64(t.config.Delay) * factor)
+}
+
+func (t loginThrottle) Succeed(ctx context.Context, username string, clientIP string) error {
+	if t.config.Username.enabled() {
+		err := t.loginFailureRepository.Delete(ctx, entity.LoginFailureKindUsername, username)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return err
+		}
+	}
+	if t.config.IP.enabled() && clientIP != "" {
+		return t.loginFailureRepository.ForgetAttempt(ctx, entity.LoginFailureKindIP, clientIP)
+	}
+	return nil
+}
+
+// record does not fail the login when the audit trail is unavailable, the lockout itself being in place.
+func (t loginThrottle) record(ctx context.Context, event audit.Event) {
+	if err := t.auditor.Record(ctx, event); err != nil {
+		logrus.Errorf("could not record audit event %s: %v", event.Type, err)
+	}
+}
@@ -0,0 +1,342 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/audit"
+	auditMock "github.com/golauth/golauth/pkg/domain/audit/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type LoginThrottleSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	loginFailureRepository *repoMock.MockLoginFailureRepository
+	repoFactory            *factoryMock.MockRepositoryFactory
+	auditor                *auditMock.MockAuditor
+
+	ctx      context.Context
+	throttle LoginThrottle
+}
+
+func TestLoginThrottle(t *testing.T) {
+	suite.Run(t, new(LoginThrottleSuite))
+}
+
+func (s *LoginThrottleSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.loginFailureRepository = repoMock.NewMockLoginFailureRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewLoginFailureRepository().AnyTimes().Return(s.loginFailureRepository)
+	s.auditor = auditMock.NewMockAuditor(s.mockCtrl)
+
+	s.ctx = context.Background()
+	s.throttle = NewLoginThrottle(s.repoFactory, s.auditor, LoginThrottleConfig{
+		Username:        LoginThrottleRule{DelayAfter: 3, LockoutAfter: 6},
+		IP:              LoginThrottleRule{DelayAfter: 20, LockoutAfter: 100},
+		Delay:           time.Second,
+		LockoutDuration: 15 * time.Minute,
+		Window:          time.Hour,
+	})
+}
+
+func (s *LoginThrottleSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *LoginThrottleSuite) expectFailures(username int, ip int) {
+	s.loginFailureRepository.EXPECT().Find(s.ctx, entity.LoginFailureKindUsername, "admin").
+		Return(&entity.LoginFailure{Failures: username}, nil).Times(1)
+	s.loginFailureRepository.EXPECT().Find(s.ctx, entity.LoginFailureKindIP, testClientIP).
+		Return(&entity.LoginFailure{Failures: ip}, nil).Times(1)
+}
+
+func (s *LoginThrottleSuite) expectAttempt(kind string, subject string, failure *entity.LoginFailure) {
+	s.loginFailureRepository.EXPECT().RecordAttempt(s.ctx, kind, subject, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, now time.Time, since time.Time) (*entity.LoginFailure, error) {
+			s.Equal(time.Hour, now.Sub(since))
+			return failure, nil
+		}).Times(1)
+}
+
+func (s *LoginThrottleSuite) TestAttemptOk() {
+	past := time.Now().Add(-time.Minute)
+	s.expectAttempt(entity.LoginFailureKindUsername, "admin", &entity.LoginFailure{Failures: 4, LockedUntil: &past})
+	s.expectAttempt(entity.LoginFailureKindIP, testClientIP, &entity.LoginFailure{Failures: 1})
+
+	s.NoError(s.throttle.Attempt(s.ctx, "admin", testClientIP))
+}
+
+func (s *LoginThrottleSuite) TestAttemptLockedTakesLongestWait() {
+	soon, later := time.Now().Add(time.Minute), time.Now().Add(time.Hour)
+	s.expectAttempt(entity.LoginFailureKindUsername, "admin", &entity.LoginFailure{Failures: 3, LockedUntil: &soon})
+	s.expectAttempt(entity.LoginFailureKindIP, testClientIP, &entity.LoginFailure{Failures: 20, LockedUntil: &later})
+
+	err := s.throttle.Attempt(s.ctx, "admin", testClientIP)
+	s.ErrorIs(err, ErrTooManyLoginAttempts)
+	var throttledErr *LoginThrottledError
+	s.ErrorAs(err, &throttledErr)
+	s.InDelta(time.Hour, throttledErr.RetryAfter, float64(time.Second))
+}
+
+func (s *LoginThrottleSuite) TestAttemptRefusedIsNotCounted() {
+	later := time.Now().Add(time.Hour)
+	s.expectAttempt(entity.LoginFailureKindUsername, "admin", &entity.LoginFailure{Failures: 1})
+	s.expectAttempt(entity.LoginFailureKindIP, testClientIP, &entity.LoginFailure{Failures: 100, LockedUntil: &later})
+	s.loginFailureRepository.EXPECT().ForgetAttempt(s.ctx, entity.LoginFailureKindUsername, "admin").Return(nil).Times(1)
+
+	s.ErrorIs(s.throttle.Attempt(s.ctx, "admin", testClientIP), ErrTooManyLoginAttempts)
+}
+
+func (s *LoginThrottleSuite) TestAttemptOverLockoutLocksOut() {
+	s.expectAttempt(entity.LoginFailureKindUsername, "admin", &entity.LoginFailure{Failures: 7})
+	s.expectAttempt(entity.LoginFailureKindIP, testClientIP, &entity.LoginFailure{Failures: 1})
+	s.loginFailureRepository.EXPECT().Lock(s.ctx, entity.LoginFailureKindUsername, "admin", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, until time.Time) error {
+			s.WithinDuration(time.Now().Add(15*time.Minute), until, time.Second)
+			return nil
+		}).Times(1)
+	s.auditor.EXPECT().Record(s.ctx, gomock.Any()).Return(nil).Times(1)
+	s.loginFailureRepository.EXPECT().ForgetAttempt(s.ctx, entity.LoginFailureKindUsername, "admin").Return(nil).Times(1)
+	s.loginFailureRepository.EXPECT().ForgetAttempt(s.ctx, entity.LoginFailureKindIP, testClientIP).Return(nil).Times(1)
+
+	err := s.throttle.Attempt(s.ctx, "admin", testClientIP)
+	var throttledErr *LoginThrottledError
+	s.ErrorAs(err, &throttledErr)
+	s.Equal(15*time.Minute, throttledErr.RetryAfter)
+}
+
+func (s *LoginThrottleSuite) TestAttemptOverDelayBeforeItsLock() {
+	s.expectAttempt(entity.LoginFailureKindUsername, "admin", &entity.LoginFailure{Failures: 4})
+	s.expectAttempt(entity.LoginFailureKindIP, testClientIP, &entity.LoginFailure{Failures: 1})
+	s.loginFailureRepository.EXPECT().ForgetAttempt(s.ctx, entity.LoginFailureKindUsername, "admin").Return(nil).Times(1)
+	s.loginFailureRepository.EXPECT().ForgetAttempt(s.ctx, entity.LoginFailureKindIP, testClientIP).Return(nil).Times(1)
+
+	err := s.throttle.Attempt(s.ctx, "admin", testClientIP)
+	var throttledErr *LoginThrottledError
+	s.ErrorAs(err, &throttledErr)
+	s.Equal(time.Second, throttledErr.RetryAfter)
+}
+
+func (s *LoginThrottleSuite) TestAttemptWithoutClientIP() {
+	s.expectAttempt(entity.LoginFailureKindUsername, "admin", &entity.LoginFailure{Failures: 1})
+
+	s.NoError(s.throttle.Attempt(s.ctx, "admin", ""))
+}
+
+func (s *LoginThrottleSuite) TestAttemptError() {
+	s.loginFailureRepository.EXPECT().RecordAttempt(s.ctx, entity.LoginFailureKindUsername, "admin", gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	s.EqualError(s.throttle.Attempt(s.ctx, "admin", testClientIP), "connection refused")
+}
+
+func (s *LoginThrottleSuite) TestAttemptConcurrent() {
+	repo := &memoryLoginFailureRepository{failures: map[string]*entity.LoginFailure{}}
+	repoFactory := factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	repoFactory.EXPECT().NewLoginFailureRepository().Return(repo).Times(1)
+	s.auditor.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	throttle := NewLoginThrottle(repoFactory, s.auditor, LoginThrottleConfig{
+		Username:        LoginThrottleRule{LockoutAfter: 5},
+		LockoutDuration: time.Minute,
+	})
+
+	var admitted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := throttle.Attempt(s.ctx, "admin", testClientIP)
+			if err == nil {
+				admitted.Add(1)
+				s.NoError(throttle.Fail(s.ctx, "admin", testClientIP))
+				return
+			}
+			s.ErrorIs(err, ErrTooManyLoginAttempts)
+		}()
+	}
+	wg.Wait()
+
+	s.Equal(int32(5), admitted.Load())
+}
+
+func (s *LoginThrottleSuite) TestFailUnderThreshold() {
+	s.expectFailures(2, 2)
+
+	s.NoError(s.throttle.Fail(s.ctx, "admin", testClientIP))
+}
+
+func (s *LoginThrottleSuite) TestFailDelaysProgressively() {
+	for failures, delay := range map[int]time.Duration{3: time.Second, 4: 2 * time.Second, 5: 4 * time.Second} {
+		s.expectFailures(failures, 1)
+		s.loginFailureRepository.EXPECT().Lock(s.ctx, entity.LoginFailureKindUsername, "admin", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, _ string, until time.Time) error {
+				s.WithinDuration(time.Now().Add(delay), until, 100*time.Millisecond)
+				return nil
+			}).Times(1)
+
+		s.NoError(s.throttle.Fail(s.ctx, "admin", testClientIP))
+	}
+}
+
+func (s *LoginThrottleSuite) TestFailLocksOutAndAudits() {
+	s.expectFailures(6, 1)
+	s.loginFailureRepository.EXPECT().Lock(s.ctx, entity.LoginFailureKindUsername, "admin", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, until time.Time) error {
+			s.WithinDuration(time.Now().Add(15*time.Minute), until, time.Second)
+			return nil
+		}).Times(1)
+	s.auditor.EXPECT().Record(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event audit.Event) error {
+		s.Equal(audit.EventLoginLocked, event.Type)
+		s.Equal("admin", event.Subject)
+		s.Equal(entity.LoginFailureKindUsername, event.Attributes["kind"])
+		s.Equal("6", event.Attributes["failures"])
+		s.Equal(testClientIP, event.Attributes["client_ip"])
+		return nil
+	}).Times(1)
+
+	s.NoError(s.throttle.Fail(s.ctx, "admin", testClientIP))
+}
+
+func (s *LoginThrottleSuite) TestFailLocksOutClientIP() {
+	s.expectFailures(1, 100)
+	s.loginFailureRepository.EXPECT().Lock(s.ctx, entity.LoginFailureKindIP, testClientIP, gomock.Any()).Return(nil).Times(1)
+	s.auditor.EXPECT().Record(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event audit.Event) error {
+		s.Equal(testClientIP, event.Subject)
+		s.Equal(entity.LoginFailureKindIP, event.Attributes["kind"])
+		return nil
+	}).Times(1)
+
+	s.NoError(s.throttle.Fail(s.ctx, "admin", testClientIP))
+}
+
+func (s *LoginThrottleSuite) TestFailAuditErrorKeepsLockout() {
+	s.expectFailures(6, 1)
+	s.loginFailureRepository.EXPECT().Lock(s.ctx, entity.LoginFailureKindUsername, "admin", gomock.Any()).Return(nil).Times(1)
+	s.auditor.EXPECT().Record(s.ctx, gomock.Any()).Return(fmt.Errorf("audit unavailable")).Times(1)
+
+	s.NoError(s.throttle.Fail(s.ctx, "admin", testClientIP))
+}
+
+func (s *LoginThrottleSuite) TestFailDelayNeverExceedsLockout() {
+	throttle := NewLoginThrottle(s.repoFactory, s.auditor, LoginThrottleConfig{
+		Username:        LoginThrottleRule{DelayAfter: 1},
+		LockoutDuration: time.Minute,
+	})
+	s.loginFailureRepository.EXPECT().Find(s.ctx, entity.LoginFailureKindUsername, "admin").
+		Return(&entity.LoginFailure{Failures: 500}, nil).Times(1)
+	s.loginFailureRepository.EXPECT().Lock(s.ctx, entity.LoginFailureKindUsername, "admin", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, until time.Time) error {
+			s.WithinDuration(time.Now().Add(time.Minute), until, time.Second)
+			return nil
+		}).Times(1)
+
+	s.NoError(throttle.Fail(s.ctx, "admin", testClientIP))
+}
+
+func (s *LoginThrottleSuite) TestFailWithoutAttempt() {
+	s.loginFailureRepository.EXPECT().Find(s.ctx, entity.LoginFailureKindUsername, "admin").Return(nil, fmt.Errorf("%w", repository.ErrNotFound)).Times(1)
+	s.loginFailureRepository.EXPECT().Find(s.ctx, entity.LoginFailureKindIP, testClientIP).Return(nil, fmt.Errorf("%w", repository.ErrNotFound)).Times(1)
+
+	s.NoError(s.throttle.Fail(s.ctx, "admin", testClientIP))
+}
+
+func (s *LoginThrottleSuite) TestFailError() {
+	s.loginFailureRepository.EXPECT().Find(s.ctx, entity.LoginFailureKindUsername, "admin").
+		Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	s.EqualError(s.throttle.Fail(s.ctx, "admin", testClientIP), "connection refused")
+}
+
+func (s *LoginThrottleSuite) TestSucceedForgetsUsername() {
+	s.loginFailureRepository.EXPECT().Delete(s.ctx, entity.LoginFailureKindUsername, "admin").Return(fmt.Errorf("%w", repository.ErrNotFound)).Times(1)
+	s.loginFailureRepository.EXPECT().ForgetAttempt(s.ctx, entity.LoginFailureKindIP, testClientIP).Return(nil).Times(1)
+
+	s.NoError(s.throttle.Succeed(s.ctx, "admin", testClientIP))
+}
+
+func (s *LoginThrottleSuite) TestDisabled() {
+	throttle := NewLoginThrottle(s.repoFactory, s.auditor, LoginThrottleConfig{})
+
+	s.NoError(throttle.Attempt(s.ctx, "admin", testClientIP))
+	s.NoError(throttle.Fail(s.ctx, "admin", testClientIP))
+	s.NoError(throttle.Succeed(s.ctx, "admin", testClientIP))
+}
+
+// memoryLoginFailureRepository counts attempts under a lock, as the database does within its upsert.
+type memoryLoginFailureRepository struct {
+	mu       sync.Mutex
+	failures map[string]*entity.LoginFailure
+}
+
+func (r *memoryLoginFailureRepository) Find(_ context.Context, kind string, subject string) (*entity.LoginFailure, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failure, ok := r.failures[kind+subject]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	found := *failure
+	return &found, nil
+}
+
+func (r *memoryLoginFailureRepository) RecordAttempt(_ context.Context, kind string, subject string, now time.Time, since time.Time) (*entity.LoginFailure, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	failure, ok := r.failures[kind+subject]
+	switch {
+	case !ok:
+		failure = &entity.LoginFailure{Kind: kind, Subject: subject, Failures: 1, LastFailure: now}
+		r.failures[kind+subject] = failure
+	case failure.LockedUntil != nil && failure.LockedUntil.After(now):
+	case failure.LastFailure.Before(since):
+		failure.Failures, failure.LastFailure = 1, now
+	default:
+		failure.Failures, failure.LastFailure = failure.Failures+1, now
+	}
+	recorded := *failure
+	return &recorded, nil
+}
+
+func (r *memoryLoginFailureRepository) ForgetAttempt(_ context.Context, kind string, subject string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if failure, ok := r.failures[kind+subject]; ok && failure.Failures > 0 {
+		failure.Failures--
+	}
+	return nil
+}
+
+func (r *memoryLoginFailureRepository) Lock(_ context.Context, kind string, subject string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if failure, ok := r.failures[kind+subject]; ok && (failure.LockedUntil == nil || failure.LockedUntil.Before(until)) {
+		failure.LockedUntil = &until
+	}
+	return nil
+}
+
+func (r *memoryLoginFailureRepository) Delete(_ context.Context, kind string, subject string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.failures, kind+subject)
+	return nil
+}
+
+func (r *memoryLoginFailureRepository) FindLocked(context.Context, time.Time) ([]*entity.LoginFailure, error) {
+	return nil, nil
+}
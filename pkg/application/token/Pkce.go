@@ -0,0 +1,54 @@
+package token
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+const (
+	CodeChallengeMethodPlain = "plain"
+	CodeChallengeMethodS256  = "S256"
+	pkceMinLength            = 43
+	pkceMaxLength            = 128
+)
+
+var ErrInvalidCodeChallenge = errors.New("invalid code challenge")
+
+// ValidateCodeChallenge checks the challenge sent to the authorize endpoint against RFC 7636.
+func ValidateCodeChallenge(challenge string, method string) error {
+	if method != CodeChallengeMethodPlain && method != CodeChallengeMethodS256 {
+		return fmt.Errorf("%w: unsupported method %s", ErrInvalidCodeChallenge, method)
+	}
+	if !isPkceValue(challenge) {
+		return ErrInvalidCodeChallenge
+	}
+	return nil
+}
+
+func VerifyCodeVerifier(verifier string, challenge string, method string) bool {
+	if !isPkceValue(verifier) {
+		return false
+	}
+	expected := verifier
+	if method == CodeChallengeMethodS256 {
+		sum := sha256.Sum256([]byte(verifier))
+		expected = base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+func isPkceValue(value string) bool {
+	if len(value) < pkceMinLength || len(value) > pkceMaxLength {
+		return false
+	}
+	for _, c := range value {
+		isAlphaNum := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !isAlphaNum && c != '-' && c != '.' && c != '_' && c != '~' {
+			return false
+		}
+	}
+	return true
+}
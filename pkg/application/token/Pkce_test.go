@@ -0,0 +1,30 @@
+package token
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+// verifier and challenge from RFC 7636, appendix B
+const (
+	rfcCodeVerifier  = "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	rfcCodeChallenge = "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+)
+
+func TestValidateCodeChallenge(t *testing.T) {
+	assert.NoError(t, ValidateCodeChallenge(rfcCodeChallenge, CodeChallengeMethodS256))
+	assert.NoError(t, ValidateCodeChallenge(rfcCodeVerifier, CodeChallengeMethodPlain))
+	assert.ErrorIs(t, ValidateCodeChallenge(rfcCodeChallenge, "S512"), ErrInvalidCodeChallenge)
+	assert.ErrorIs(t, ValidateCodeChallenge("short", CodeChallengeMethodS256), ErrInvalidCodeChallenge)
+	assert.ErrorIs(t, ValidateCodeChallenge(strings.Repeat("a", 129), CodeChallengeMethodPlain), ErrInvalidCodeChallenge)
+	assert.ErrorIs(t, ValidateCodeChallenge(strings.Repeat("a", 42)+"+", CodeChallengeMethodPlain), ErrInvalidCodeChallenge)
+}
+
+func TestVerifyCodeVerifier(t *testing.T) {
+	assert.True(t, VerifyCodeVerifier(rfcCodeVerifier, rfcCodeChallenge, CodeChallengeMethodS256))
+	assert.True(t, VerifyCodeVerifier(rfcCodeVerifier, rfcCodeVerifier, CodeChallengeMethodPlain))
+	assert.False(t, VerifyCodeVerifier(rfcCodeVerifier, rfcCodeVerifier, CodeChallengeMethodS256))
+	assert.False(t, VerifyCodeVerifier(strings.Repeat("a", 43), rfcCodeChallenge, CodeChallengeMethodS256))
+	assert.False(t, VerifyCodeVerifier("", "", CodeChallengeMethodPlain))
+}
@@ -0,0 +1,120 @@
+//go:generate mockgen -source RefreshToken.go -destination mock/RefreshToken_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken     = errors.New("invalid refresh token")
+	ErrRefreshTokenReused      = errors.New("refresh token already used, token family revoked")
+	RefreshTokenExpirationTime = 60 * 24 * 30
+)
+
+// RefreshToken redeems a refresh token for new tokens. A token issued to a client is only redeemed by that client,
+// already authenticated, and one issued by the password grant only without a client.
+type RefreshToken interface {
+	Execute(ctx context.Context, client *entity.Client, refreshToken string) (*entity.Token, error)
+}
+
+func NewRefreshToken(repoFactory factory.RepositoryFactory, jwtToken GenerateJwtToken) RefreshToken {
+	return refreshToken{
+		userRepository:          repoFactory.NewUserRepository(),
+		userAuthorityRepository: repoFactory.NewUserAuthorityRepository(),
+		refreshTokenRepository:  repoFactory.NewRefreshTokenRepository(),
+		jwtToken:                jwtToken,
+	}
+}
+
+type refreshToken struct {
+	userRepository          repository.UserRepository
+	userAuthorityRepository repository.UserAuthorityRepository
+	refreshTokenRepository  repository.RefreshTokenRepository
+	jwtToken                GenerateJwtToken
+}
+
+func (uc refreshToken) Execute(ctx context.Context, client *entity.Client, value string) (*entity.Token, error) {
+	stored, err := uc.refreshTokenRepository.FindByHash(ctx, HashOpaqueToken(value))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if stored.IsRevoked() || stored.IsExpiredAt(time.Now()) || !redeemableBy(stored, client) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	marked := false
+	if !stored.IsUsed() {
+		marked, err = uc.refreshTokenRepository.MarkUsed(ctx, stored.ID)
+		if err != nil {
+			return nil, fmt.Errorf("could not rotate refresh token: %w", err)
+		}
+	}
+	if !marked {
+		err = uc.refreshTokenRepository.RevokeFamily(ctx, stored.FamilyID)
+		if err != nil {
+			return nil, fmt.Errorf("could not revoke reused refresh token: %w", err)
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	user, err := uc.userRepository.FindByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if !user.Enabled {
+		return nil, ErrAccountDisabled
+	}
+	authorities, err := uc.userAuthorityRepository.FindAuthoritiesByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error when fetch authorities: %w", err)
+	}
+	var accessToken string
+	if client != nil && stored.Scope != nil {
+		accessToken, err = uc.jwtToken.ExecuteForScope(user, client, authorities, ParseScope(*stored.Scope))
+	} else {
+		accessToken, err = uc.jwtToken.Execute(user, authorities)
+	}
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	newRefreshToken, err := issueRefreshToken(ctx, uc.refreshTokenRepository, &entity.RefreshToken{
+		FamilyID: stored.FamilyID,
+		UserID:   user.ID,
+		ClientID: stored.ClientID,
+		Scope:    stored.Scope,
+	})
+	if err != nil {
+		return nil, ErrGeneratingToken
+	}
+	return &entity.Token{AccessToken: accessToken, ExpiresIn: userTokenTTL(), RefreshToken: newRefreshToken}, nil
+}
+
+// redeemableBy reports whether the refresh token was issued to the client, nil standing for no client.
+func redeemableBy(token *entity.RefreshToken, client *entity.Client) bool {
+	if client == nil {
+		return token.ClientID == ""
+	}
+	return token.ClientID == client.ClientID
+}
+
+// issueRefreshToken stores the refresh token with a new value and expiration, returning the value. Its scope, when
+// not nil, limits every access token it is refreshed into.
+func issueRefreshToken(ctx context.Context, repo repository.RefreshTokenRepository, token *entity.RefreshToken) (string, error) {
+	value, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	token.TokenHash = HashOpaqueToken(value)
+	token.ExpiresAt = time.Now().Add(time.Duration(RefreshTokenExpirationTime) * time.Minute)
+	_, err = repo.Create(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	return value, nil
+}
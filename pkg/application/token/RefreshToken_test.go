@@ -0,0 +1,207 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type RefreshTokenSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository          *repoMock.MockUserRepository
+	userAuthorityRepository *repoMock.MockUserAuthorityRepository
+	refreshTokenRepository  *repoMock.MockRefreshTokenRepository
+	jwtToken                *tokenMock.MockGenerateJwtToken
+	repoFactory             *factoryMock.MockRepositoryFactory
+
+	ctx          context.Context
+	refreshToken RefreshToken
+
+	user   *entity.User
+	value  string
+	stored *entity.RefreshToken
+}
+
+func TestRefreshToken(t *testing.T) {
+	suite.Run(t, new(RefreshTokenSuite))
+}
+
+func (s *RefreshTokenSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.userAuthorityRepository = repoMock.NewMockUserAuthorityRepository(s.mockCtrl)
+	s.refreshTokenRepository = repoMock.NewMockRefreshTokenRepository(s.mockCtrl)
+	s.jwtToken = tokenMock.NewMockGenerateJwtToken(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewUserAuthorityRepository().AnyTimes().Return(s.userAuthorityRepository)
+	s.repoFactory.EXPECT().NewRefreshTokenRepository().AnyTimes().Return(s.refreshTokenRepository)
+
+	s.ctx = context.Background()
+	s.refreshToken = NewRefreshToken(s.repoFactory, s.jwtToken)
+
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", Enabled: true}
+	s.value = "opaque-refresh-token"
+	s.stored = &entity.RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  uuid.New(),
+		UserID:    s.user.ID,
+		TokenHash: HashOpaqueToken(s.value),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func (s *RefreshTokenSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenOk() {
+	authorities := []string{"ADMIN"}
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return(authorities, nil).Times(1)
+	s.jwtToken.EXPECT().Execute(s.user, authorities).Return("access", nil).Times(1)
+	var rotated *entity.RefreshToken
+	s.refreshTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+		rotated = rt
+		return rt, nil
+	}).Times(1)
+
+	output, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.NoError(err)
+	s.Equal("access", output.AccessToken)
+	s.NotEmpty(output.RefreshToken)
+	s.NotEqual(s.value, output.RefreshToken)
+	s.Equal(s.stored.FamilyID, rotated.FamilyID)
+	s.Equal(HashOpaqueToken(output.RefreshToken), rotated.TokenHash)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenKeepsScope() {
+	scope := "openid ORDERS_READ"
+	s.stored.Scope = &scope
+	s.stored.ClientID = "spa"
+	authorities := []string{"ADMIN", "ORDERS_READ"}
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return(authorities, nil).Times(1)
+	client := &entity.Client{ClientID: "spa"}
+	s.jwtToken.EXPECT().ExecuteForScope(s.user, client, authorities, []string{"openid", "ORDERS_READ"}).Return("access", nil).Times(1)
+	var rotated *entity.RefreshToken
+	s.refreshTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, rt *entity.RefreshToken) (*entity.RefreshToken, error) {
+		rotated = rt
+		return rt, nil
+	}).Times(1)
+
+	output, err := s.refreshToken.Execute(s.ctx, client, s.value)
+	s.NoError(err)
+	s.Equal("access", output.AccessToken)
+	s.Equal(&scope, rotated.Scope)
+	s.Equal("spa", rotated.ClientID)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenOfAnotherClient() {
+	s.stored.ClientID = "spa"
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(2)
+
+	_, err := s.refreshToken.Execute(s.ctx, &entity.Client{ClientID: "other"}, s.value)
+	s.ErrorIs(err, ErrInvalidRefreshToken)
+	_, err = s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.ErrorIs(err, ErrInvalidRefreshToken)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenOfPasswordGrantWithClient() {
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+
+	_, err := s.refreshToken.Execute(s.ctx, &entity.Client{ClientID: "spa"}, s.value)
+	s.ErrorIs(err, ErrInvalidRefreshToken)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenNotFound() {
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(nil, fmt.Errorf("no rows")).Times(1)
+
+	output, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.ErrorIs(err, ErrInvalidRefreshToken)
+	s.Nil(output)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenExpired() {
+	s.stored.ExpiresAt = time.Now().Add(-time.Minute)
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+
+	_, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.ErrorIs(err, ErrInvalidRefreshToken)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenRevoked() {
+	revokedAt := time.Now()
+	s.stored.RevokedAt = &revokedAt
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+
+	_, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.ErrorIs(err, ErrInvalidRefreshToken)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenReuseRevokesFamily() {
+	usedAt := time.Now().Add(-time.Minute)
+	s.stored.UsedAt = &usedAt
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().RevokeFamily(s.ctx, s.stored.FamilyID).Return(nil).Times(1)
+
+	_, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.ErrorIs(err, ErrRefreshTokenReused)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenConcurrentReuseRevokesFamily() {
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(false, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().RevokeFamily(s.ctx, s.stored.FamilyID).Return(nil).Times(1)
+
+	_, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.ErrorIs(err, ErrRefreshTokenReused)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenErrMarkUsed() {
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(false, fmt.Errorf("connection refused")).Times(1)
+
+	_, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.EqualError(err, "could not rotate refresh token: connection refused")
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenErrGeneratingToken() {
+	authorities := []string{"ADMIN"}
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+	s.userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(s.ctx, s.user.ID).Return(authorities, nil).Times(1)
+	s.jwtToken.EXPECT().Execute(s.user, authorities).Return("", fmt.Errorf("no signing key")).Times(1)
+
+	_, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.ErrorIs(err, ErrGeneratingToken)
+}
+
+func (s *RefreshTokenSuite) TestRefreshTokenAccountDisabled() {
+	s.user.Enabled = false
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken(s.value)).Return(s.stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().MarkUsed(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.user.ID).Return(s.user, nil).Times(1)
+
+	_, err := s.refreshToken.Execute(s.ctx, nil, s.value)
+	s.ErrorIs(err, ErrAccountDisabled)
+}
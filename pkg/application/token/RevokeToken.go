@@ -0,0 +1,76 @@
+//go:generate mockgen -source RevokeToken.go -destination mock/RevokeToken_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"strings"
+	"time"
+)
+
+var ErrTokenNotIssuedToClient = errors.New("token was not issued to the client")
+
+// RevokeToken revokes an access token by denylisting its jti, or a refresh token by revoking its
+// whole family. Unknown, invalid or expired tokens are ignored, as required by RFC 7009. A token
+// issued to a client can only be revoked by that client, while tokens of the password grant are not
+// bound to any client.
+type RevokeToken interface {
+	Execute(ctx context.Context, client *entity.Client, token string) error
+}
+
+func NewRevokeToken(repoFactory factory.RepositoryFactory, keyStore KeyStore, denylist Denylist) RevokeToken {
+	return revokeToken{
+		refreshTokenRepository: repoFactory.NewRefreshTokenRepository(),
+		keyStore:               keyStore,
+		denylist:               denylist,
+	}
+}
+
+type revokeToken struct {
+	refreshTokenRepository repository.RefreshTokenRepository
+	keyStore               KeyStore
+	denylist               Denylist
+}
+
+func (uc revokeToken) Execute(ctx context.Context, client *entity.Client, token string) error {
+	if isJwt(token) {
+		return uc.revokeAccessToken(ctx, client, token)
+	}
+	return uc.revokeRefreshToken(ctx, client, token)
+}
+
+func (uc revokeToken) revokeAccessToken(ctx context.Context, client *entity.Client, token string) error {
+	claims, err := parseClaims(uc.keyStore, token)
+	if err != nil {
+		return nil
+	}
+	if claims.ID == "" || claims.ExpiresAt == nil || !claims.IsValidAt(time.Now()) {
+		return nil
+	}
+	if !issuedTo(claims.ClientID, client) {
+		return ErrTokenNotIssuedToClient
+	}
+	return uc.denylist.Revoke(ctx, claims.ID, claims.ExpiresAt.Time)
+}
+
+func (uc revokeToken) revokeRefreshToken(ctx context.Context, client *entity.Client, token string) error {
+	stored, err := uc.refreshTokenRepository.FindByHash(ctx, HashOpaqueToken(token))
+	if err != nil {
+		return nil
+	}
+	if !issuedTo(stored.ClientID, client) {
+		return ErrTokenNotIssuedToClient
+	}
+	return uc.refreshTokenRepository.RevokeFamily(ctx, stored.FamilyID)
+}
+
+func issuedTo(clientID string, client *entity.Client) bool {
+	return clientID == "" || clientID == client.ClientID
+}
+
+func isJwt(token string) bool {
+	return strings.Count(token, ".") == 2
+}
@@ -0,0 +1,141 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type RevokeTokenSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	ctx      context.Context
+
+	refreshTokenRepository *repoMock.MockRefreshTokenRepository
+	revokedRepository      *repoMock.MockRevokedTokenRepository
+	repoFactory            *factoryMock.MockRepositoryFactory
+
+	denylist      Denylist
+	jwtToken      GenerateJwtToken
+	validateToken ValidateToken
+	revokeToken   RevokeToken
+	user          *entity.User
+	client        *entity.Client
+}
+
+func TestRevokeToken(t *testing.T) {
+	suite.Run(t, new(RevokeTokenSuite))
+}
+
+func (s *RevokeTokenSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+
+	keyRepository := repoMock.NewMockSigningKeyRepository(s.mockCtrl)
+	key, err := GenerateSigningKey(DefaultAlgorithm)
+	s.NoError(err)
+	keyRepository.EXPECT().FindAll(gomock.Any()).Return([]*entity.SigningKey{key}, nil).AnyTimes()
+	keyStore := NewKeyStore(keyRepository, KeyStoreConfig{})
+	s.NoError(keyStore.Load(s.ctx))
+
+	s.refreshTokenRepository = repoMock.NewMockRefreshTokenRepository(s.mockCtrl)
+	s.revokedRepository = repoMock.NewMockRevokedTokenRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewRefreshTokenRepository().AnyTimes().Return(s.refreshTokenRepository)
+
+	s.denylist = NewDenylist(s.revokedRepository, repoMock.NewMockRevokedSubjectRepository(s.mockCtrl))
+	s.jwtToken = NewGenerateJwtToken(keyStore, TokenConfig{})
+	s.validateToken = NewValidateToken(keyStore, s.denylist, TokenConfig{})
+	s.revokeToken = NewRevokeToken(s.repoFactory, keyStore, s.denylist)
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", Enabled: true}
+	s.client = &entity.Client{ClientID: "spa", Enabled: true}
+}
+
+func (s *RevokeTokenSuite) TearDownTest() {
+	TokenExpirationTime = 60
+	s.mockCtrl.Finish()
+}
+
+func (s *RevokeTokenSuite) TestRevokeAccessToken() {
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	s.revokedRepository.EXPECT().Create(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, t *entity.RevokedToken) (*entity.RevokedToken, error) {
+		s.NotEmpty(t.JTI)
+		s.True(t.ExpiresAt.After(time.Now()))
+		return t, nil
+	}).Times(1)
+
+	s.NoError(s.revokeToken.Execute(s.ctx, s.client, token))
+	_, err = s.validateToken.Execute(token)
+	s.ErrorIs(err, ErrRevokedToken)
+}
+
+func (s *RevokeTokenSuite) TestRevokeAccessTokenOfClient() {
+	token, err := s.jwtToken.ExecuteForScope(s.user, s.client, []string{"ADMIN"}, []string{"ADMIN"})
+	s.NoError(err)
+	s.revokedRepository.EXPECT().Create(s.ctx, gomock.Any()).Return(&entity.RevokedToken{}, nil).Times(1)
+
+	s.NoError(s.revokeToken.Execute(s.ctx, s.client, token))
+}
+
+func (s *RevokeTokenSuite) TestRevokeAccessTokenOfAnotherClient() {
+	token, err := s.jwtToken.ExecuteForScope(s.user, &entity.Client{ClientID: "other"}, []string{"ADMIN"}, []string{"ADMIN"})
+	s.NoError(err)
+
+	err = s.revokeToken.Execute(s.ctx, s.client, token)
+	s.ErrorIs(err, ErrTokenNotIssuedToClient)
+	_, err = s.validateToken.Execute(token)
+	s.NoError(err)
+}
+
+func (s *RevokeTokenSuite) TestRevokeExpiredAccessTokenIsIgnored() {
+	TokenExpirationTime = -1
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+
+	s.NoError(s.revokeToken.Execute(s.ctx, s.client, token))
+}
+
+func (s *RevokeTokenSuite) TestRevokeInvalidAccessTokenIsIgnored() {
+	s.NoError(s.revokeToken.Execute(s.ctx, s.client, "not.a.jwt"))
+}
+
+func (s *RevokeTokenSuite) TestRevokeRefreshToken() {
+	stored := &entity.RefreshToken{ID: uuid.New(), FamilyID: uuid.New()}
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken("refresh")).Return(stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().RevokeFamily(s.ctx, stored.FamilyID).Return(nil).Times(1)
+
+	s.NoError(s.revokeToken.Execute(s.ctx, s.client, "refresh"))
+}
+
+func (s *RevokeTokenSuite) TestRevokeRefreshTokenOfAnotherClient() {
+	stored := &entity.RefreshToken{ID: uuid.New(), FamilyID: uuid.New(), ClientID: "other"}
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken("refresh")).Return(stored, nil).Times(1)
+
+	err := s.revokeToken.Execute(s.ctx, s.client, "refresh")
+	s.ErrorIs(err, ErrTokenNotIssuedToClient)
+}
+
+func (s *RevokeTokenSuite) TestRevokeUnknownRefreshTokenIsIgnored() {
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken("refresh")).Return(nil, fmt.Errorf("not found")).Times(1)
+
+	s.NoError(s.revokeToken.Execute(s.ctx, s.client, "refresh"))
+}
+
+func (s *RevokeTokenSuite) TestRevokeRefreshTokenErr() {
+	stored := &entity.RefreshToken{ID: uuid.New(), FamilyID: uuid.New()}
+	s.refreshTokenRepository.EXPECT().FindByHash(s.ctx, HashOpaqueToken("refresh")).Return(stored, nil).Times(1)
+	s.refreshTokenRepository.EXPECT().RevokeFamily(s.ctx, stored.FamilyID).Return(fmt.Errorf("connection refused")).Times(1)
+
+	s.Error(s.revokeToken.Execute(s.ctx, s.client, "refresh"))
+}
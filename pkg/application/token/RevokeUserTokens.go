@@ -0,0 +1,46 @@
+//go:generate mockgen -source RevokeUserTokens.go -destination mock/RevokeUserTokens_mock.go -package mock
+package token
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+// RevokeUserTokens revokes every outstanding token of the users: their refresh tokens, and the access
+// tokens already issued to them, which are denylisted by subject until the last of them expires.
+type RevokeUserTokens interface {
+	Execute(ctx context.Context, userIDs ...uuid.UUID) error
+}
+
+func NewRevokeUserTokens(repoFactory factory.RepositoryFactory, denylist Denylist, config TokenConfig) RevokeUserTokens {
+	return revokeUserTokens{
+		refreshTokenRepository: repoFactory.NewRefreshTokenRepository(),
+		denylist:               denylist,
+		config:                 config,
+	}
+}
+
+type revokeUserTokens struct {
+	refreshTokenRepository repository.RefreshTokenRepository
+	denylist               Denylist
+	config                 TokenConfig
+}
+
+func (uc revokeUserTokens) Execute(ctx context.Context, userIDs ...uuid.UUID) error {
+	expiresAt := time.Now().Add(time.Duration(TokenExpirationTime)*time.Minute + uc.config.Leeway)
+	for _, id := range userIDs {
+		err := uc.refreshTokenRepository.RevokeByUserID(ctx, id)
+		if err != nil {
+			return err
+		}
+		err = uc.denylist.RevokeSubject(ctx, id.String(), expiresAt)
+		if err != nil {
+			return fmt.Errorf("could not revoke tokens of user %s: %w", id, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type RevokeUserTokensSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	refreshTokenRepository *repoMock.MockRefreshTokenRepository
+	repoFactory            *factoryMock.MockRepositoryFactory
+	denylist               *tokenMock.MockDenylist
+
+	ctx              context.Context
+	revokeUserTokens RevokeUserTokens
+}
+
+func TestRevokeUserTokens(t *testing.T) {
+	suite.Run(t, new(RevokeUserTokensSuite))
+}
+
+func (s *RevokeUserTokensSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.refreshTokenRepository = repoMock.NewMockRefreshTokenRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewRefreshTokenRepository().AnyTimes().Return(s.refreshTokenRepository)
+	s.denylist = tokenMock.NewMockDenylist(s.mockCtrl)
+
+	s.ctx = context.Background()
+	s.revokeUserTokens = NewRevokeUserTokens(s.repoFactory, s.denylist, TokenConfig{Leeway: time.Minute})
+}
+
+func (s *RevokeUserTokensSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *RevokeUserTokensSuite) TestRevokeUsers() {
+	first, second := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{first, second} {
+		s.refreshTokenRepository.EXPECT().RevokeByUserID(s.ctx, id).Return(nil).Times(1)
+		s.denylist.EXPECT().RevokeSubject(s.ctx, id.String(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, expiresAt time.Time) error {
+			s.WithinDuration(time.Now().Add(time.Duration(TokenExpirationTime)*time.Minute+time.Minute), expiresAt, time.Second)
+			return nil
+		}).Times(1)
+	}
+
+	s.NoError(s.revokeUserTokens.Execute(s.ctx, first, second))
+}
+
+func (s *RevokeUserTokensSuite) TestRevokeRefreshTokensErr() {
+	id := uuid.New()
+	s.refreshTokenRepository.EXPECT().RevokeByUserID(s.ctx, id).Return(fmt.Errorf("connection refused")).Times(1)
+
+	s.Error(s.revokeUserTokens.Execute(s.ctx, id))
+}
+
+func (s *RevokeUserTokensSuite) TestRevokeSubjectErr() {
+	id := uuid.New()
+	s.refreshTokenRepository.EXPECT().RevokeByUserID(s.ctx, id).Return(nil).Times(1)
+	s.denylist.EXPECT().RevokeSubject(s.ctx, id.String(), gomock.Any()).Return(fmt.Errorf("connection refused")).Times(1)
+
+	err := s.revokeUserTokens.Execute(s.ctx, id)
+	s.ErrorContains(err, "could not revoke tokens of user")
+}
@@ -0,0 +1,75 @@
+package token
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"strings"
+)
+
+// ScopeOpenID asks for an OpenID Connect id token next to the access token.
+const ScopeOpenID = "openid"
+
+var (
+	ErrInvalidScope = errors.New("requested scope is not allowed")
+	// ErrOpenIDNotSupported is returned by the grants that never issue an id token.
+	ErrOpenIDNotSupported = fmt.Errorf("%w: %s requires the authorization_code or device_code grant", ErrInvalidScope, ScopeOpenID)
+)
+
+func ParseScope(scope string) []string {
+	return strings.Fields(scope)
+}
+
+func FormatScope(scopes []string) string {
+	return strings.Join(scopes, " ")
+}
+
+// ResolveScopes returns the requested scopes when all of them are allowed, or every allowed scope when none is requested.
+func ResolveScopes(requested []string, allowed []string) ([]string, error) {
+	if len(requested) == 0 {
+		return allowed, nil
+	}
+	allowedSet := make(map[string]bool, len(allowed))
+	for _, a := range allowed {
+		allowedSet[a] = true
+	}
+	for _, r := range requested {
+		if !allowedSet[r] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidScope, r)
+		}
+	}
+	return requested, nil
+}
+
+// resolveClientScopes resolves the requested scopes of the client, refusing openid when the client
+// algorithm cannot sign its id tokens.
+func resolveClientScopes(client *entity.Client, requested string) ([]string, error) {
+	scopes, err := ResolveScopes(ParseScope(requested), client.Scopes)
+	if err != nil {
+		return nil, err
+	}
+	if HasScope(scopes, ScopeOpenID) && ValidateIDTokenAlgorithm(client.SigningAlgorithm) != nil {
+		return nil, fmt.Errorf("%w: %s with the %s algorithm", ErrInvalidScope, ScopeOpenID, client.SigningAlgorithm)
+	}
+	return scopes, nil
+}
+
+// IntersectScopes returns the scopes of a that are also in b, in the order of a.
+func IntersectScopes(a []string, b []string) []string {
+	result := make([]string, 0, len(a))
+	for _, s := range a {
+		if HasScope(b, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
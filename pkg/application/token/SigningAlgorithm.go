@@ -0,0 +1,242 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/cristalhq/jwt/v3"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/google/uuid"
+	"hash"
+	"sort"
+)
+
+const (
+	DefaultAlgorithm = string(jwt.RS512)
+	rsaKeySize       = 2048
+	hmacSecretSize   = 32
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	errKeyTypeMismatch      = errors.New("private key type does not match the signing algorithm")
+)
+
+// signingAlgorithm knows how to generate a key for an algorithm and how to sign and verify with it,
+// so the rest of the package never looks at the concrete key type. hash is the function the
+// algorithm signs with, used for the OpenID Connect at_hash claim. symmetric algorithms share their
+// key with the verifier, so they cannot sign tokens read by clients.
+type signingAlgorithm struct {
+	symmetric   bool
+	hash        func() hash.Hash
+	generateKey func() (crypto.PrivateKey, error)
+	signer      func(key crypto.PrivateKey) (jwt.Signer, error)
+	verifier    func(key crypto.PrivateKey) (jwt.Verifier, error)
+}
+
+var signingAlgorithms = map[string]signingAlgorithm{
+	string(jwt.RS256): rsaAlgorithm(jwt.RS256, sha256.New, jwt.NewSignerRS, jwt.NewVerifierRS),
+	string(jwt.RS384): rsaAlgorithm(jwt.RS384, sha512.New384, jwt.NewSignerRS, jwt.NewVerifierRS),
+	string(jwt.RS512): rsaAlgorithm(jwt.RS512, sha512.New, jwt.NewSignerRS, jwt.NewVerifierRS),
+	string(jwt.PS256): rsaAlgorithm(jwt.PS256, sha256.New, jwt.NewSignerPS, jwt.NewVerifierPS),
+	string(jwt.ES256): ecdsaAlgorithm(jwt.ES256, sha256.New, elliptic.P256()),
+	string(jwt.ES384): ecdsaAlgorithm(jwt.ES384, sha512.New384, elliptic.P384()),
+	string(jwt.EdDSA): eddsaAlgorithm(),
+	string(jwt.HS256): hmacAlgorithm(jwt.HS256, sha256.New),
+}
+
+func rsaAlgorithm(
+	alg jwt.Algorithm,
+	newHash func() hash.Hash,
+	newSigner func(jwt.Algorithm, *rsa.PrivateKey) (jwt.Signer, error),
+	newVerifier func(jwt.Algorithm, *rsa.PublicKey) (jwt.Verifier, error),
+) signingAlgorithm {
+	return signingAlgorithm{
+		hash: newHash,
+		generateKey: func() (crypto.PrivateKey, error) {
+			return rsa.GenerateKey(rand.Reader, rsaKeySize)
+		},
+		signer: func(key crypto.PrivateKey) (jwt.Signer, error) {
+			k, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, errKeyTypeMismatch
+			}
+			return newSigner(alg, k)
+		},
+		verifier: func(key crypto.PrivateKey) (jwt.Verifier, error) {
+			k, ok := key.(*rsa.PrivateKey)
+			if !ok {
+				return nil, errKeyTypeMismatch
+			}
+			return newVerifier(alg, &k.PublicKey)
+		},
+	}
+}
+
+func ecdsaAlgorithm(alg jwt.Algorithm, newHash func() hash.Hash, curve elliptic.Curve) signingAlgorithm {
+	return signingAlgorithm{
+		hash: newHash,
+		generateKey: func() (crypto.PrivateKey, error) {
+			return ecdsa.GenerateKey(curve, rand.Reader)
+		},
+		signer: func(key crypto.PrivateKey) (jwt.Signer, error) {
+			k, ok := key.(*ecdsa.PrivateKey)
+			if !ok || k.Curve != curve {
+				return nil, errKeyTypeMismatch
+			}
+			return jwt.NewSignerES(alg, k)
+		},
+		verifier: func(key crypto.PrivateKey) (jwt.Verifier, error) {
+			k, ok := key.(*ecdsa.PrivateKey)
+			if !ok || k.Curve != curve {
+				return nil, errKeyTypeMismatch
+			}
+			return jwt.NewVerifierES(alg, &k.PublicKey)
+		},
+	}
+}
+
+func eddsaAlgorithm() signingAlgorithm {
+	return signingAlgorithm{
+		hash: sha512.New,
+		generateKey: func() (crypto.PrivateKey, error) {
+			_, key, err := ed25519.GenerateKey(rand.Reader)
+			return key, err
+		},
+		signer: func(key crypto.PrivateKey) (jwt.Signer, error) {
+			k, ok := key.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errKeyTypeMismatch
+			}
+			return jwt.NewSignerEdDSA(k)
+		},
+		verifier: func(key crypto.PrivateKey) (jwt.Verifier, error) {
+			k, ok := key.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errKeyTypeMismatch
+			}
+			return jwt.NewVerifierEdDSA(k.Public().(ed25519.PublicKey))
+		},
+	}
+}
+
+func hmacAlgorithm(alg jwt.Algorithm, newHash func() hash.Hash) signingAlgorithm {
+	return signingAlgorithm{
+		symmetric: true,
+		hash:      newHash,
+		generateKey: func() (crypto.PrivateKey, error) {
+			secret := make([]byte, hmacSecretSize)
+			_, err := rand.Read(secret)
+			return secret, err
+		},
+		signer: func(key crypto.PrivateKey) (jwt.Signer, error) {
+			k, ok := key.([]byte)
+			if !ok {
+				return nil, errKeyTypeMismatch
+			}
+			return jwt.NewSignerHS(alg, k)
+		},
+		verifier: func(key crypto.PrivateKey) (jwt.Verifier, error) {
+			k, ok := key.([]byte)
+			if !ok {
+				return nil, errKeyTypeMismatch
+			}
+			return jwt.NewVerifierHS(alg, k)
+		},
+	}
+}
+
+func findSigningAlgorithm(algorithm string) (signingAlgorithm, error) {
+	alg, ok := signingAlgorithms[algorithm]
+	if !ok {
+		return signingAlgorithm{}, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+	return alg, nil
+}
+
+func SupportedAlgorithms() []string {
+	result := make([]string, 0, len(signingAlgorithms))
+	for alg := range signingAlgorithms {
+		result = append(result, alg)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func ValidateAlgorithm(algorithm string) error {
+	_, err := findSigningAlgorithm(algorithm)
+	return err
+}
+
+// ValidateIDTokenAlgorithm checks that the algorithm can sign id tokens, which clients verify with the public keys
+// of the JWKS. An empty algorithm uses the default one.
+func ValidateIDTokenAlgorithm(algorithm string) error {
+	if algorithm == "" {
+		return nil
+	}
+	alg, err := findSigningAlgorithm(algorithm)
+	if err != nil {
+		return err
+	}
+	if alg.symmetric {
+		return fmt.Errorf("%w for id tokens: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+	return nil
+}
+
+func GenerateSigningKey(algorithm string) (*entity.SigningKey, error) {
+	alg, err := findSigningAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := alg.generateKey()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate %s private key: %w", algorithm, err)
+	}
+	return entity.NewSigningKey(uuid.NewString(), algorithm, privateKey), nil
+}
+
+func GenerateSigner(key *entity.SigningKey) (jwt.Signer, error) {
+	alg, err := findSigningAlgorithm(key.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := alg.signer(key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate signer for key %s: %w", key.ID, err)
+	}
+	return signer, nil
+}
+
+func GenerateVerifier(key *entity.SigningKey) (jwt.Verifier, error) {
+	alg, err := findSigningAlgorithm(key.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := alg.verifier(key.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate verifier for key %s: %w", key.ID, err)
+	}
+	return verifier, nil
+}
+
+// accessTokenHash returns the at_hash claim of an id token signed with the algorithm: the left half
+// of the access token hash, base64url encoded.
+func accessTokenHash(algorithm string, accessToken string) (string, error) {
+	alg, err := findSigningAlgorithm(algorithm)
+	if err != nil {
+		return "", err
+	}
+	h := alg.hash()
+	h.Write([]byte(accessToken))
+	sum := h.Sum(nil)
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2]), nil
+}
@@ -0,0 +1,114 @@
+package token
+
+import (
+	"context"
+	"github.com/cristalhq/jwt/v3"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type SigningAlgorithmSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	revokedRepo *repoMock.MockRevokedTokenRepository
+}
+
+func TestSigningAlgorithm(t *testing.T) {
+	suite.Run(t, new(SigningAlgorithmSuite))
+}
+
+func (s *SigningAlgorithmSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.revokedRepo = repoMock.NewMockRevokedTokenRepository(s.mockCtrl)
+}
+
+func (s *SigningAlgorithmSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *SigningAlgorithmSuite) newKeyStore(algorithms ...string) KeyStore {
+	var keys []*entity.SigningKey
+	for _, algorithm := range algorithms {
+		key, err := GenerateSigningKey(algorithm)
+		s.NoError(err)
+		keys = append(keys, key)
+	}
+	keyRepository := repoMock.NewMockSigningKeyRepository(s.mockCtrl)
+	keyRepository.EXPECT().FindAll(gomock.Any()).Return(keys, nil).AnyTimes()
+	keyStore := NewKeyStore(keyRepository, KeyStoreConfig{Algorithms: algorithms})
+	s.NoError(keyStore.Load(context.Background()))
+	return keyStore
+}
+
+func (s *SigningAlgorithmSuite) TestSignAndVerifyEachAlgorithm() {
+	for _, algorithm := range SupportedAlgorithms() {
+		s.Run(algorithm, func() {
+			keyStore := s.newKeyStore(algorithm)
+			token, err := NewGenerateJwtToken(keyStore, TokenConfig{}).Execute(&entity.User{ID: uuid.New(), Username: "admin"}, nil)
+			s.NoError(err)
+			parsed, err := jwt.ParseString(token)
+			s.NoError(err)
+			s.Equal(jwt.Algorithm(algorithm), parsed.Header().Algorithm)
+
+			p, err := NewValidateToken(keyStore, NewDenylist(s.revokedRepo, repoMock.NewMockRevokedSubjectRepository(s.mockCtrl)), TokenConfig{}).Execute(token)
+			s.NoError(err)
+			s.Equal("admin", p.Username)
+		})
+	}
+}
+
+func (s *SigningAlgorithmSuite) TestClientSigningAlgorithm() {
+	keyStore := s.newKeyStore(DefaultAlgorithm, "ES256")
+	jwtToken := NewGenerateJwtToken(keyStore, TokenConfig{})
+
+	token, err := jwtToken.ExecuteForClient(&entity.Client{ClientID: "device", SigningAlgorithm: "ES256"}, nil)
+	s.NoError(err)
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	s.Equal(jwt.ES256, parsed.Header().Algorithm)
+
+	token, err = jwtToken.ExecuteForClient(&entity.Client{ClientID: "service"}, nil)
+	s.NoError(err)
+	parsed, err = jwt.ParseString(token)
+	s.NoError(err)
+	s.Equal(jwt.RS512, parsed.Header().Algorithm)
+}
+
+func (s *SigningAlgorithmSuite) TestClientSigningAlgorithmWithoutKey() {
+	keyStore := s.newKeyStore(DefaultAlgorithm)
+	_, err := NewGenerateJwtToken(keyStore, TokenConfig{}).ExecuteForClient(&entity.Client{ClientID: "device", SigningAlgorithm: "EdDSA"}, nil)
+	s.ErrorIs(err, ErrNoSigningKey)
+}
+
+func (s *SigningAlgorithmSuite) TestGenerateSigningKeyUnsupported() {
+	_, err := GenerateSigningKey("none")
+	s.ErrorIs(err, ErrUnsupportedAlgorithm)
+	s.ErrorIs(ValidateAlgorithm("RS1"), ErrUnsupportedAlgorithm)
+	s.NoError(ValidateAlgorithm("EdDSA"))
+}
+
+func (s *SigningAlgorithmSuite) TestValidateIDTokenAlgorithm() {
+	s.NoError(ValidateIDTokenAlgorithm(""))
+	s.NoError(ValidateIDTokenAlgorithm("ES256"))
+	s.ErrorIs(ValidateIDTokenAlgorithm("HS256"), ErrUnsupportedAlgorithm)
+	s.ErrorIs(ValidateIDTokenAlgorithm("none"), ErrUnsupportedAlgorithm)
+}
+
+func (s *SigningAlgorithmSuite) TestKeyTypeMismatch() {
+	key, err := GenerateSigningKey("ES256")
+	s.NoError(err)
+	key.Algorithm = "ES384"
+	_, err = GenerateSigner(key)
+	s.ErrorIs(err, errKeyTypeMismatch)
+	key.Algorithm = "RS256"
+	_, err = GenerateVerifier(key)
+	s.ErrorIs(err, errKeyTypeMismatch)
+}
@@ -0,0 +1,12 @@
+package token
+
+import "time"
+
+// TokenConfig holds the registered claims golauth issues and expects back. Tokens are issued by Issuer
+// for Audience, unless the client names its own audiences, and Leeway absorbs clock skew between servers
+// when checking exp, nbf and iat.
+type TokenConfig struct {
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
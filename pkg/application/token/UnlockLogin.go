@@ -0,0 +1,55 @@
+//go:generate mockgen -source UnlockLogin.go -destination mock/UnlockLogin_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/audit"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"time"
+)
+
+var (
+	ErrInvalidLoginKind     = errors.New("kind must be username or ip")
+	ErrLoginFailureNotFound = errors.New("no failed logins recorded")
+)
+
+// UnlockLogin lifts the delay or lockout of a username or client IP and forgets its failures. The actor is the
+// administrator doing it, recorded in the audit trail.
+type UnlockLogin interface {
+	Execute(ctx context.Context, kind string, subject string, actor string) error
+}
+
+func NewUnlockLogin(repoFactory factory.RepositoryFactory, auditor audit.Auditor) UnlockLogin {
+	return unlockLogin{
+		loginFailureRepository: repoFactory.NewLoginFailureRepository(),
+		auditor:                auditor,
+	}
+}
+
+type unlockLogin struct {
+	loginFailureRepository repository.LoginFailureRepository
+	auditor                audit.Auditor
+}
+
+func (uc unlockLogin) Execute(ctx context.Context, kind string, subject string, actor string) error {
+	if kind != entity.LoginFailureKindUsername && kind != entity.LoginFailureKindIP {
+		return ErrInvalidLoginKind
+	}
+	err := uc.loginFailureRepository.Delete(ctx, kind, subject)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrLoginFailureNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return uc.auditor.Record(ctx, audit.Event{
+		Type:       audit.EventLoginUnlocked,
+		Time:       time.Now(),
+		Actor:      actor,
+		Subject:    subject,
+		Attributes: map[string]string{"kind": kind},
+	})
+}
@@ -0,0 +1,81 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/audit"
+	auditMock "github.com/golauth/golauth/pkg/domain/audit/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type UnlockLoginSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	loginFailureRepository *repoMock.MockLoginFailureRepository
+	repoFactory            *factoryMock.MockRepositoryFactory
+	auditor                *auditMock.MockAuditor
+
+	ctx         context.Context
+	unlockLogin UnlockLogin
+}
+
+func TestUnlockLogin(t *testing.T) {
+	suite.Run(t, new(UnlockLoginSuite))
+}
+
+func (s *UnlockLoginSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.loginFailureRepository = repoMock.NewMockLoginFailureRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewLoginFailureRepository().AnyTimes().Return(s.loginFailureRepository)
+	s.auditor = auditMock.NewMockAuditor(s.mockCtrl)
+
+	s.ctx = context.Background()
+	s.unlockLogin = NewUnlockLogin(s.repoFactory, s.auditor)
+}
+
+func (s *UnlockLoginSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *UnlockLoginSuite) TestUnlockOk() {
+	s.loginFailureRepository.EXPECT().Delete(s.ctx, entity.LoginFailureKindUsername, "jdoe").Return(nil).Times(1)
+	s.auditor.EXPECT().Record(s.ctx, gomock.Any()).DoAndReturn(func(_ context.Context, event audit.Event) error {
+		s.Equal(audit.EventLoginUnlocked, event.Type)
+		s.Equal("admin", event.Actor)
+		s.Equal("jdoe", event.Subject)
+		s.Equal(map[string]string{"kind": entity.LoginFailureKindUsername}, event.Attributes)
+		return nil
+	}).Times(1)
+
+	s.NoError(s.unlockLogin.Execute(s.ctx, entity.LoginFailureKindUsername, "jdoe", "admin"))
+}
+
+func (s *UnlockLoginSuite) TestUnlockInvalidKind() {
+	err := s.unlockLogin.Execute(s.ctx, "email", "jdoe", "admin")
+	s.ErrorIs(err, ErrInvalidLoginKind)
+}
+
+func (s *UnlockLoginSuite) TestUnlockNotFound() {
+	s.loginFailureRepository.EXPECT().Delete(s.ctx, entity.LoginFailureKindIP, testClientIP).Return(fmt.Errorf("%w", repository.ErrNotFound)).Times(1)
+
+	err := s.unlockLogin.Execute(s.ctx, entity.LoginFailureKindIP, testClientIP, "admin")
+	s.ErrorIs(err, ErrLoginFailureNotFound)
+}
+
+func (s *UnlockLoginSuite) TestUnlockError() {
+	s.loginFailureRepository.EXPECT().Delete(s.ctx, entity.LoginFailureKindIP, testClientIP).Return(fmt.Errorf("connection refused")).Times(1)
+
+	err := s.unlockLogin.Execute(s.ctx, entity.LoginFailureKindIP, testClientIP, "admin")
+	s.EqualError(err, "connection refused")
+}
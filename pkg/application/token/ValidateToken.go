@@ -2,43 +2,118 @@
 package token
 
 import (
-	"crypto/rsa"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/cristalhq/jwt/v3"
+	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/golauth/golauth/pkg/infra/api/controller/model"
 	"time"
 )
 
-var errExpiredToken = errors.New("expired token")
+var (
+	errExpiredToken    = errors.New("expired token")
+	ErrRevokedToken    = errors.New("revoked token")
+	ErrInvalidIssuer   = errors.New("invalid token issuer")
+	ErrInvalidAudience = errors.New("invalid token audience")
+)
 
 type ValidateToken interface {
-	Execute(token string) error
+	Execute(token string) (*entity.Principal, error)
 }
 
-func NewValidateToken(key *rsa.PrivateKey) ValidateToken {
-	return validateToken{verifier: GenerateVerifier(key)}
+func NewValidateToken(keyStore KeyStore, denylist Denylist, config TokenConfig) ValidateToken {
+	return validateToken{keyStore: keyStore, denylist: denylist, config: config}
 }
 
 type validateToken struct {
-	verifier jwt.Verifier
+	keyStore KeyStore
+	denylist Denylist
+	config   TokenConfig
 }
 
-func (uc validateToken) Execute(strToken string) error {
-	token, err := jwt.ParseAndVerifyString(strToken, uc.verifier)
+func (uc validateToken) Execute(strToken string) (*entity.Principal, error) {
+	claims, err := activeClaims(uc.keyStore, uc.denylist, uc.config, strToken)
 	if err != nil {
-		return fmt.Errorf("could not parse and verify strToken: %w", err)
+		return nil, err
+	}
+	if uc.config.Audience != "" && !claims.IsForAudience(uc.config.Audience) {
+		return nil, ErrInvalidAudience
 	}
+	return newPrincipal(claims), nil
+}
 
-	claims := &model.Claims{}
-	err = json.Unmarshal(token.RawClaims(), &claims)
+func newPrincipal(claims *model.Claims) *entity.Principal {
+	p := &entity.Principal{
+		Subject:     claims.Subject,
+		Username:    claims.Username,
+		Authorities: claims.Authorities,
+		Scopes:      ParseScope(claims.Scope),
+		ClientID:    claims.ClientID,
+	}
+	if claims.ExpiresAt != nil {
+		p.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return p
+}
+
+// activeClaims returns the claims of a token that is correctly signed, issued by the configured issuer,
+// inside its validity window and not revoked. The audience is left to the caller.
+func activeClaims(keyStore KeyStore, denylist Denylist, config TokenConfig, strToken string) (*model.Claims, error) {
+	claims, err := parseClaims(keyStore, strToken)
+	if err != nil {
+		return nil, err
+	}
+	if config.Issuer != "" && !claims.IsIssuer(config.Issuer) {
+		return nil, ErrInvalidIssuer
+	}
+	if !isValidAt(claims, time.Now(), config.Leeway) {
+		return nil, errExpiredToken
+	}
+	if denylist.IsRevoked(claims.ID) || denylist.IsSubjectRevoked(claims.Subject, issuedAt(claims)) {
+		return nil, ErrRevokedToken
+	}
+	return claims, nil
+}
+
+// issuedAt returns the iat claim, and the zero time for tokens without one so subject revocations still apply.
+func issuedAt(claims *model.Claims) time.Time {
+	if claims.IssuedAt == nil {
+		return time.Time{}
+	}
+	return claims.IssuedAt.Time
+}
+
+func isValidAt(claims *model.Claims, now time.Time, leeway time.Duration) bool {
+	return claims.IsValidExpiresAt(now.Add(-leeway)) &&
+		claims.IsValidNotBefore(now.Add(leeway)) &&
+		claims.IsValidIssuedAt(now.Add(leeway))
+}
+
+// parseClaims verifies the token signature with the key named by its kid header and returns its claims,
+// without checking expiration or revocation.
+func parseClaims(keyStore KeyStore, strToken string) (*model.Claims, error) {
+	token, err := jwt.ParseString(strToken)
 	if err != nil {
-		return fmt.Errorf("could not unmarshal claims: %w", err)
+		return nil, fmt.Errorf("could not parse and verify strToken: %w", err)
 	}
-	if !claims.IsValidAt(time.Now()) {
-		return errExpiredToken
+	key, err := keyStore.VerificationKey(token.Header().KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("could not find verification key: %w", err)
+	}
+	verifier, err := GenerateVerifier(key)
+	if err != nil {
+		return nil, err
+	}
+	token, err = jwt.ParseAndVerifyString(strToken, verifier)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse and verify strToken: %w", err)
 	}
 
-	return nil
+	claims := &model.Claims{}
+	err = json.Unmarshal(token.RawClaims(), &claims)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal claims: %w", err)
+	}
+	return claims, nil
 }
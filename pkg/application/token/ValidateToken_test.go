@@ -1,8 +1,13 @@
 package token
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/cristalhq/jwt/v3"
 	"github.com/golauth/golauth/pkg/domain/entity"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -16,6 +21,13 @@ type ValidateTokenSuite struct {
 	*require.Assertions
 	mockCtrl *gomock.Controller
 
+	keyRepository *repoMock.MockSigningKeyRepository
+	revokedRepo   *repoMock.MockRevokedTokenRepository
+	subjectRepo   *repoMock.MockRevokedSubjectRepository
+	denylist      Denylist
+	key           *entity.SigningKey
+	keyStore      KeyStore
+	config        TokenConfig
 	jwtToken      GenerateJwtToken
 	validateToken ValidateToken
 	user          *entity.User
@@ -28,9 +40,19 @@ func TestValidateToken(t *testing.T) {
 func (s *ValidateTokenSuite) SetupTest() {
 	s.Assertions = require.New(s.T())
 	s.mockCtrl = gomock.NewController(s.T())
-	key := GeneratePrivateKey()
-	s.jwtToken = NewGenerateJwtToken(key)
-	s.validateToken = NewValidateToken(key)
+	s.keyRepository = repoMock.NewMockSigningKeyRepository(s.mockCtrl)
+	key, err := GenerateSigningKey(DefaultAlgorithm)
+	s.NoError(err)
+	s.key = key
+	s.keyRepository.EXPECT().FindAll(gomock.Any()).Return([]*entity.SigningKey{s.key}, nil).AnyTimes()
+	s.keyStore = NewKeyStore(s.keyRepository, KeyStoreConfig{GracePeriod: time.Hour})
+	s.NoError(s.keyStore.Load(context.Background()))
+	s.config = TokenConfig{Issuer: "https://golauth.test", Audience: "https://golauth.test"}
+	s.jwtToken = NewGenerateJwtToken(s.keyStore, s.config)
+	s.revokedRepo = repoMock.NewMockRevokedTokenRepository(s.mockCtrl)
+	s.subjectRepo = repoMock.NewMockRevokedSubjectRepository(s.mockCtrl)
+	s.denylist = NewDenylist(s.revokedRepo, s.subjectRepo)
+	s.validateToken = NewValidateToken(s.keyStore, s.denylist, s.config)
 
 	s.user = &entity.User{
 		ID:           uuid.New(),
@@ -53,12 +75,15 @@ func (s *ValidateTokenSuite) TearDownTest() {
 func (s *ValidateTokenSuite) TestValidateTokenOk() {
 	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
 	s.NoError(err)
-	err = s.validateToken.Execute(fmt.Sprintf("%v", token))
+	p, err := s.validateToken.Execute(fmt.Sprintf("%v", token))
 	s.NoError(err)
+	s.Equal("user", p.Username)
+	s.Equal([]string{"ADMIN"}, p.Authorities)
+	s.True(p.ExpiresAt.After(time.Now()))
 }
 
 func (s *ValidateTokenSuite) TestValidateTokenInvalidFormat() {
-	err := s.validateToken.Execute("invalidTokenFormat")
+	_, err := s.validateToken.Execute("invalidTokenFormat")
 	s.Error(err)
 	s.EqualError(err, "could not parse and verify strToken: jwt: token format is not valid")
 }
@@ -67,7 +92,143 @@ func (s *ValidateTokenSuite) TestValidateTokenErrExpiredToken() {
 	TokenExpirationTime = -1
 	expiredToken, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
 	s.NoError(err)
-	err = s.validateToken.Execute(expiredToken)
+	_, err = s.validateToken.Execute(expiredToken)
 	s.Error(err)
 	s.ErrorIs(err, errExpiredToken)
 }
+
+func (s *ValidateTokenSuite) TestValidateTokenKeyIdHeader() {
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	s.Equal(s.key.ID, parsed.Header().KeyID)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenRetiredKeyInsideGracePeriod() {
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	retiredAt := time.Now().Add(-30 * time.Minute)
+	s.key.RetiredAt = &retiredAt
+	defer func() { s.key.RetiredAt = nil }()
+
+	_, err = s.validateToken.Execute(token)
+	s.NoError(err)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenRetiredKeyAfterGracePeriod() {
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	retiredAt := time.Now().Add(-2 * time.Hour)
+	s.key.RetiredAt = &retiredAt
+	defer func() { s.key.RetiredAt = nil }()
+
+	_, err = s.validateToken.Execute(token)
+	s.ErrorIs(err, ErrKeyGraceEnded)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenUnknownKeyId() {
+	otherKey, err := GenerateSigningKey(DefaultAlgorithm)
+	s.NoError(err)
+	otherRepository := repoMock.NewMockSigningKeyRepository(s.mockCtrl)
+	otherRepository.EXPECT().FindAll(gomock.Any()).Return([]*entity.SigningKey{otherKey}, nil).AnyTimes()
+	otherKeyStore := NewKeyStore(otherRepository, KeyStoreConfig{})
+	s.NoError(otherKeyStore.Load(context.Background()))
+
+	token, err := NewGenerateJwtToken(otherKeyStore, s.config).Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	_, err = s.validateToken.Execute(token)
+	s.ErrorIs(err, ErrUnknownKeyID)
+}
+
+func (s *ValidateTokenSuite) parseUnverified(token string) *model.Claims {
+	parsed, err := jwt.ParseString(token)
+	s.NoError(err)
+	claims := &model.Claims{}
+	s.NoError(json.Unmarshal(parsed.RawClaims(), claims))
+	return claims
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenRegisteredClaims() {
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	claims := s.parseUnverified(token)
+	s.Equal(s.user.ID.String(), claims.Subject)
+	s.Equal("https://golauth.test", claims.Issuer)
+	s.Equal(jwt.Audience{"https://golauth.test"}, claims.Audience)
+	s.NotEmpty(claims.ID)
+	s.NotNil(claims.IssuedAt)
+	s.NotNil(claims.NotBefore)
+	s.NotNil(claims.ExpiresAt)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenInvalidIssuer() {
+	other := NewGenerateJwtToken(s.keyStore, TokenConfig{Issuer: "https://other.test", Audience: s.config.Audience})
+	token, err := other.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	_, err = s.validateToken.Execute(token)
+	s.ErrorIs(err, ErrInvalidIssuer)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenInvalidAudience() {
+	c := &entity.Client{ClientID: "service", Audiences: []string{"https://orders.test"}}
+	token, err := s.jwtToken.ExecuteForClient(c, nil)
+	s.NoError(err)
+	s.Equal(jwt.Audience{"https://orders.test"}, s.parseUnverified(token).Audience)
+
+	_, err = s.validateToken.Execute(token)
+	s.ErrorIs(err, ErrInvalidAudience)
+
+	p, err := NewValidateToken(s.keyStore, s.denylist, TokenConfig{Issuer: s.config.Issuer, Audience: "https://orders.test"}).Execute(token)
+	s.NoError(err)
+	s.Equal("service", p.ClientID)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenExpiredInsideLeeway() {
+	TokenExpirationTime = -1
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+
+	config := s.config
+	config.Leeway = 2 * time.Minute
+	_, err = NewValidateToken(s.keyStore, s.denylist, config).Execute(token)
+	s.NoError(err)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenRevoked() {
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+	claims := s.parseUnverified(token)
+	s.NotEmpty(claims.ID)
+
+	s.revokedRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.RevokedToken{}, nil).Times(1)
+	s.NoError(s.denylist.Revoke(context.Background(), claims.ID, claims.ExpiresAt.Time))
+
+	_, err = s.validateToken.Execute(token)
+	s.ErrorIs(err, ErrRevokedToken)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenSubjectRevoked() {
+	token, err := s.jwtToken.Execute(s.user, []string{"ADMIN"})
+	s.NoError(err)
+
+	s.subjectRepo.EXPECT().Save(gomock.Any(), gomock.Any()).Return(&entity.RevokedSubject{}, nil).Times(1)
+	s.NoError(s.denylist.RevokeSubject(context.Background(), s.user.ID.String(), time.Now().Add(time.Hour)))
+
+	_, err = s.validateToken.Execute(token)
+	s.ErrorIs(err, ErrRevokedToken)
+}
+
+func (s *ValidateTokenSuite) TestValidateTokenClientPrincipal() {
+	c := &entity.Client{ClientID: "service", Authorities: []string{"SYNC"}}
+	token, err := s.jwtToken.ExecuteForClient(c, []string{"read", "write"})
+	s.NoError(err)
+
+	p, err := s.validateToken.Execute(token)
+	s.NoError(err)
+	s.Equal("service", p.Subject)
+	s.Equal("service", p.ClientID)
+	s.Empty(p.Username)
+	s.Equal([]string{"SYNC"}, p.Authorities)
+	s.Equal([]string{"read", "write"}, p.Scopes)
+}
@@ -0,0 +1,69 @@
+//go:generate mockgen -source VerifyDeviceCode.go -destination mock/VerifyDeviceCode_mock.go -package mock
+package token
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrInvalidUserCode = errors.New("invalid or expired user code")
+
+// VerifyDeviceCode lets a user approve or deny the pending device authorization of a user code. Execute is for a
+// user already logged in, and Login authenticates the user with their password first, for the verification page.
+type VerifyDeviceCode interface {
+	Execute(ctx context.Context, userID uuid.UUID, userCode string, approve bool) (*entity.DeviceCode, error)
+	Login(ctx context.Context, username string, password string, userCode string, approve bool, clientIP string) (*entity.DeviceCode, error)
+}
+
+func NewVerifyDeviceCode(repoFactory factory.RepositoryFactory, loginThrottle LoginThrottle, config LoginConfig) VerifyDeviceCode {
+	return verifyDeviceCode{
+		userRepository:       repoFactory.NewUserRepository(),
+		deviceCodeRepository: repoFactory.NewDeviceCodeRepository(),
+		loginThrottle:        loginThrottle,
+		config:               config,
+	}
+}
+
+type verifyDeviceCode struct {
+	userRepository       repository.UserRepository
+	deviceCodeRepository repository.DeviceCodeRepository
+	loginThrottle        LoginThrottle
+	config               LoginConfig
+}
+
+func (uc verifyDeviceCode) Login(ctx context.Context, username string, password string, userCode string, approve bool, clientIP string) (*entity.DeviceCode, error) {
+	user, err := authenticateUser(ctx, uc.userRepository, uc.loginThrottle, uc.config, username, password, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	return uc.Execute(ctx, user.ID, userCode, approve)
+}
+
+func (uc verifyDeviceCode) Execute(ctx context.Context, userID uuid.UUID, userCode string, approve bool) (*entity.DeviceCode, error) {
+	stored, err := uc.deviceCodeRepository.FindByUserCode(ctx, NormalizeUserCode(userCode))
+	if err != nil {
+		return nil, ErrInvalidUserCode
+	}
+	if !stored.IsPending() || stored.IsExpiredAt(time.Now()) {
+		return nil, ErrInvalidUserCode
+	}
+	var changed bool
+	if approve {
+		changed, err = uc.deviceCodeRepository.Approve(ctx, stored.ID, userID)
+	} else {
+		changed, err = uc.deviceCodeRepository.Deny(ctx, stored.ID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not verify device code: %w", err)
+	}
+	if !changed {
+		return nil, ErrInvalidUserCode
+	}
+	return stored, nil
+}
@@ -0,0 +1,139 @@
+package token
+
+import (
+	"context"
+	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+	"time"
+)
+
+type VerifyDeviceCodeSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository       *repoMock.MockUserRepository
+	deviceCodeRepository *repoMock.MockDeviceCodeRepository
+	repoFactory          *factoryMock.MockRepositoryFactory
+	loginThrottle        *tokenMock.MockLoginThrottle
+
+	ctx              context.Context
+	verifyDeviceCode VerifyDeviceCode
+
+	userID uuid.UUID
+	stored *entity.DeviceCode
+}
+
+func TestVerifyDeviceCode(t *testing.T) {
+	suite.Run(t, new(VerifyDeviceCodeSuite))
+}
+
+func (s *VerifyDeviceCodeSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.deviceCodeRepository = repoMock.NewMockDeviceCodeRepository(s.mockCtrl)
+	s.loginThrottle = tokenMock.NewMockLoginThrottle(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewDeviceCodeRepository().AnyTimes().Return(s.deviceCodeRepository)
+
+	s.ctx = context.Background()
+	s.verifyDeviceCode = NewVerifyDeviceCode(s.repoFactory, s.loginThrottle, LoginConfig{})
+
+	s.userID = uuid.New()
+	s.stored = &entity.DeviceCode{
+		ID:        uuid.New(),
+		UserCode:  "BCDFGHJK",
+		ClientID:  "cli",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+}
+
+func (s *VerifyDeviceCodeSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *VerifyDeviceCodeSuite) TestApprove() {
+	s.deviceCodeRepository.EXPECT().FindByUserCode(s.ctx, "BCDFGHJK").Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Approve(s.ctx, s.stored.ID, s.userID).Return(true, nil).Times(1)
+
+	code, err := s.verifyDeviceCode.Execute(s.ctx, s.userID, "bcdf-ghjk", true)
+	s.NoError(err)
+	s.Equal("cli", code.ClientID)
+}
+
+func (s *VerifyDeviceCodeSuite) TestDeny() {
+	s.deviceCodeRepository.EXPECT().FindByUserCode(s.ctx, "BCDFGHJK").Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Deny(s.ctx, s.stored.ID).Return(true, nil).Times(1)
+
+	_, err := s.verifyDeviceCode.Execute(s.ctx, s.userID, "BCDF-GHJK", false)
+	s.NoError(err)
+}
+
+func (s *VerifyDeviceCodeSuite) TestUnknownUserCode() {
+	s.deviceCodeRepository.EXPECT().FindByUserCode(s.ctx, "BCDFGHJK").Return(nil, fmt.Errorf("not found")).Times(1)
+
+	_, err := s.verifyDeviceCode.Execute(s.ctx, s.userID, "BCDF-GHJK", true)
+	s.ErrorIs(err, ErrInvalidUserCode)
+}
+
+func (s *VerifyDeviceCodeSuite) TestExpired() {
+	s.stored.ExpiresAt = time.Now().Add(-time.Second)
+	s.deviceCodeRepository.EXPECT().FindByUserCode(s.ctx, "BCDFGHJK").Return(s.stored, nil).Times(1)
+
+	_, err := s.verifyDeviceCode.Execute(s.ctx, s.userID, "BCDF-GHJK", true)
+	s.ErrorIs(err, ErrInvalidUserCode)
+}
+
+func (s *VerifyDeviceCodeSuite) TestAlreadyApproved() {
+	other := uuid.New()
+	s.stored.UserID = &other
+	s.deviceCodeRepository.EXPECT().FindByUserCode(s.ctx, "BCDFGHJK").Return(s.stored, nil).Times(1)
+
+	_, err := s.verifyDeviceCode.Execute(s.ctx, s.userID, "BCDF-GHJK", true)
+	s.ErrorIs(err, ErrInvalidUserCode)
+}
+
+func (s *VerifyDeviceCodeSuite) TestConcurrentApprove() {
+	s.deviceCodeRepository.EXPECT().FindByUserCode(s.ctx, "BCDFGHJK").Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Approve(s.ctx, s.stored.ID, s.userID).Return(false, nil).Times(1)
+
+	_, err := s.verifyDeviceCode.Execute(s.ctx, s.userID, "BCDF-GHJK", true)
+	s.ErrorIs(err, ErrInvalidUserCode)
+}
+
+func (s *VerifyDeviceCodeSuite) TestLoginApprove() {
+	password, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.MinCost)
+	user := &entity.User{ID: s.userID, Username: "admin", Password: string(password), Enabled: true}
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	s.userRepository.EXPECT().FindByUsername(s.ctx, "admin").Return(user, nil).Times(1)
+	s.loginThrottle.EXPECT().Succeed(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	s.deviceCodeRepository.EXPECT().FindByUserCode(s.ctx, "BCDFGHJK").Return(s.stored, nil).Times(1)
+	s.deviceCodeRepository.EXPECT().Approve(s.ctx, s.stored.ID, s.userID).Return(true, nil).Times(1)
+
+	code, err := s.verifyDeviceCode.Login(s.ctx, "admin", "admin123", "BCDF-GHJK", true, testClientIP)
+	s.NoError(err)
+	s.Equal("cli", code.ClientID)
+}
+
+func (s *VerifyDeviceCodeSuite) TestLoginWrongPassword() {
+	password, _ := bcrypt.GenerateFromPassword([]byte("admin123"), bcrypt.MinCost)
+	user := &entity.User{ID: s.userID, Username: "admin", Password: string(password), Enabled: true}
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	s.userRepository.EXPECT().FindByUsername(s.ctx, "admin").Return(user, nil).Times(1)
+	s.loginThrottle.EXPECT().Fail(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+
+	_, err := s.verifyDeviceCode.Login(s.ctx, "admin", "wrong", "BCDF-GHJK", true, testClientIP)
+	s.ErrorIs(err, ErrInvalidUsernameOrPassword)
+}
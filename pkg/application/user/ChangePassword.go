@@ -0,0 +1,106 @@
+//go:generate mockgen -source ChangePassword.go -destination mock/ChangePassword_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidPassword  = errors.New("current password is invalid")
+	ErrPasswordRequired = errors.New("new password is required")
+)
+
+// ChangePassword replaces the password of a user who proves to know the current one, and revokes the tokens the
+// account holds, so every session logs in again with the new password. The client IP feeds the login throttle.
+type ChangePassword interface {
+	Execute(ctx context.Context, id uuid.UUID, currentPassword string, newPassword string, clientIP string) error
+}
+
+func NewChangePassword(repo repository.UserRepository, loginThrottle token.LoginThrottle, revokeUserTokens token.RevokeUserTokens, passwordPolicy PasswordPolicy) ChangePassword {
+	return changePassword{repo: repo, loginThrottle: loginThrottle, revokeUserTokens: revokeUserTokens, passwordPolicy: passwordPolicy}
+}
+
+type changePassword struct {
+	repo             repository.UserRepository
+	loginThrottle    token.LoginThrottle
+	revokeUserTokens token.RevokeUserTokens
+	passwordPolicy   PasswordPolicy
+}
+
+func (uc changePassword) Execute(ctx context.Context, id uuid.UUID, currentPassword string, newPassword string, clientIP string) error {
+	if newPassword == "" {
+		return ErrPasswordRequired
+	}
+	user, err := checkCurrentPassword(ctx, uc.repo, uc.loginThrottle, id, currentPassword, clientIP)
+	if err != nil {
+		return err
+	}
+	err = uc.passwordPolicy.Validate(ctx, user, newPassword)
+	if err != nil {
+		return err
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	err = uc.repo.ChangePassword(ctx, id, hash)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	if err != nil {
+		return err
+	}
+	err = uc.revokeUserTokens.Execute(ctx, id)
+	if err != nil {
+		return err
+	}
+	return uc.passwordPolicy.Remember(ctx, id, hash)
+}
+
+// checkCurrentPassword returns the user once password is theirs. The check goes through the login throttle like
+// a login, so a stolen access token cannot be used to guess the password.
+func checkCurrentPassword(ctx context.Context, repo repository.UserRepository, throttle token.LoginThrottle, id uuid.UUID, password string, clientIP string) (*entity.User, error) {
+	user, err := repo.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = throttle.Attempt(ctx, user.Username, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := repo.FindPasswordByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) != nil {
+		if err = throttle.Fail(ctx, user.Username, clientIP); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidPassword
+	}
+	if err = throttle.Succeed(ctx, user.Username, clientIP); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func hashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcryptDefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("could not generate password: %w", err)
+	}
+	return string(hash), nil
+}
@@ -0,0 +1,166 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/application/token"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+const testClientIP = "10.0.0.1"
+
+type ChangePasswordSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository    *mock.MockUserRepository
+	historyRepository *mock.MockPasswordHistoryRepository
+	loginThrottle     *tokenMock.MockLoginThrottle
+	revokeUserTokens  *tokenMock.MockRevokeUserTokens
+
+	ctx            context.Context
+	changePassword ChangePassword
+	id             uuid.UUID
+	hash           string
+	user           *entity.User
+}
+
+func TestChangePassword(t *testing.T) {
+	suite.Run(t, new(ChangePasswordSuite))
+}
+
+func (s *ChangePasswordSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.userRepository = mock.NewMockUserRepository(s.mockCtrl)
+	s.historyRepository = mock.NewMockPasswordHistoryRepository(s.mockCtrl)
+	s.loginThrottle = tokenMock.NewMockLoginThrottle(s.mockCtrl)
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+	repoFactory := factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	repoFactory.EXPECT().NewPasswordHistoryRepository().AnyTimes().Return(s.historyRepository)
+
+	s.ctx = context.Background()
+	s.changePassword = NewChangePassword(s.userRepository, s.loginThrottle, s.revokeUserTokens,
+		NewPasswordPolicy(repoFactory, PasswordPolicyConfig{HistorySize: 3}))
+	s.id = uuid.New()
+	s.user = &entity.User{ID: s.id, Username: "admin", Email: "admin@example.com"}
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old-secret"), bcrypt.MinCost)
+	s.hash = string(hash)
+}
+
+func (s *ChangePasswordSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+// expectCurrentPassword expects the current password to be checked through the login throttle.
+func (s *ChangePasswordSuite) expectCurrentPassword(succeeded bool) {
+	s.userRepository.EXPECT().FindByID(s.ctx, s.id).Return(s.user, nil).Times(1)
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	s.userRepository.EXPECT().FindPasswordByID(s.ctx, s.id).Return(s.hash, nil).Times(1)
+	if succeeded {
+		s.loginThrottle.EXPECT().Succeed(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	} else {
+		s.loginThrottle.EXPECT().Fail(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	}
+}
+
+// expectHistory expects the password policy to compare the new password with the current one and the history.
+func (s *ChangePasswordSuite) expectHistory(hashes ...string) {
+	s.userRepository.EXPECT().FindPasswordByID(s.ctx, s.id).Return(s.hash, nil).Times(1)
+	s.historyRepository.EXPECT().FindLatest(s.ctx, s.id, 3).Return(hashes, nil).Times(1)
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordOk() {
+	var stored string
+	s.expectCurrentPassword(true)
+	s.expectHistory(s.hash)
+	s.userRepository.EXPECT().ChangePassword(s.ctx, s.id, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string) error {
+			s.NoError(bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-secret")))
+			stored = hash
+			return nil
+		}).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, s.id).Return(nil).Times(1)
+	s.historyRepository.EXPECT().Add(s.ctx, s.id, gomock.Any(), 3).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string, _ int) error {
+			s.Equal(stored, hash)
+			return nil
+		}).Times(1)
+
+	err := s.changePassword.Execute(s.ctx, s.id, "old-secret", "new-secret", testClientIP)
+	s.NoError(err)
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordWrongCurrent() {
+	s.expectCurrentPassword(false)
+
+	err := s.changePassword.Execute(s.ctx, s.id, "guess", "new-secret", testClientIP)
+	s.ErrorIs(err, ErrInvalidPassword)
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordThrottled() {
+	s.userRepository.EXPECT().FindByID(s.ctx, s.id).Return(s.user, nil).Times(1)
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(&token.LoginThrottledError{}).Times(1)
+
+	err := s.changePassword.Execute(s.ctx, s.id, "old-secret", "new-secret", testClientIP)
+	s.ErrorIs(err, token.ErrTooManyLoginAttempts)
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordReused() {
+	s.expectCurrentPassword(true)
+	s.expectHistory(s.hash)
+
+	err := s.changePassword.Execute(s.ctx, s.id, "old-secret", "old-secret", testClientIP)
+	s.ErrorIs(err, ErrPasswordPolicy)
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordReusedCurrentWithoutHistory() {
+	s.expectCurrentPassword(true)
+	s.expectHistory()
+
+	err := s.changePassword.Execute(s.ctx, s.id, "old-secret", "old-secret", testClientIP)
+	s.ErrorIs(err, ErrPasswordPolicy)
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordEmpty() {
+	err := s.changePassword.Execute(s.ctx, s.id, "old-secret", "", testClientIP)
+	s.ErrorIs(err, ErrPasswordRequired)
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordUserNotFound() {
+	s.userRepository.EXPECT().FindByID(s.ctx, s.id).Return(nil, repository.ErrNotFound).Times(1)
+
+	err := s.changePassword.Execute(s.ctx, s.id, "old-secret", "new-secret", testClientIP)
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordRepositoryError() {
+	s.expectCurrentPassword(true)
+	s.expectHistory()
+	s.userRepository.EXPECT().ChangePassword(s.ctx, s.id, gomock.Any()).Return(errors.New("db down")).Times(1)
+
+	err := s.changePassword.Execute(s.ctx, s.id, "old-secret", "new-secret", testClientIP)
+	s.EqualError(err, "db down")
+}
+
+func (s *ChangePasswordSuite) TestChangePasswordRevokeError() {
+	s.expectCurrentPassword(true)
+	s.expectHistory()
+	s.userRepository.EXPECT().ChangePassword(s.ctx, s.id, gomock.Any()).Return(nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, s.id).Return(errors.New("redis down")).Times(1)
+
+	err := s.changePassword.Execute(s.ctx, s.id, "old-secret", "new-secret", testClientIP)
+	s.EqualError(err, "redis down")
+}
@@ -0,0 +1,41 @@
+//go:generate mockgen -source ChangeUserStatus.go -destination mock/ChangeUserStatus_mock.go -package mock
+package user
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+type ChangeUserStatus interface {
+	Execute(ctx context.Context, id uuid.UUID, enabled bool, revokeTokens bool) error
+}
+
+func NewChangeUserStatus(repo repository.UserRepository, revokeUserTokens token.RevokeUserTokens) ChangeUserStatus {
+	return changeUserStatus{repo: repo, revokeUserTokens: revokeUserTokens}
+}
+
+type changeUserStatus struct {
+	repo             repository.UserRepository
+	revokeUserTokens token.RevokeUserTokens
+}
+
+func (uc changeUserStatus) Execute(ctx context.Context, id uuid.UUID, enabled bool, revokeTokens bool) error {
+	exists, err := uc.repo.ExistsById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	err = uc.repo.ChangeStatus(ctx, id, enabled)
+	if err != nil {
+		return err
+	}
+	if enabled || !revokeTokens {
+		return nil
+	}
+	return uc.revokeUserTokens.Execute(ctx, id)
+}
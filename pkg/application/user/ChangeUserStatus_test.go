@@ -0,0 +1,80 @@
+package user
+
+import (
+	"context"
+	"errors"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type ChangeUserStatusSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl         *gomock.Controller
+	ctx              context.Context
+	repo             *mock.MockUserRepository
+	revokeUserTokens *tokenMock.MockRevokeUserTokens
+	changeUserStatus ChangeUserStatus
+}
+
+func TestChangeUserStatus(t *testing.T) {
+	suite.Run(t, new(ChangeUserStatusSuite))
+}
+
+func (s *ChangeUserStatusSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.repo = mock.NewMockUserRepository(s.mockCtrl)
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+	s.changeUserStatus = NewChangeUserStatus(s.repo, s.revokeUserTokens)
+}
+
+func (s *ChangeUserStatusSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ChangeUserStatusSuite) TestChangeStatusOk() {
+	userId := uuid.New()
+	s.repo.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.repo.EXPECT().ChangeStatus(s.ctx, userId, false).Return(nil).Times(1)
+	err := s.changeUserStatus.Execute(s.ctx, userId, false, false)
+	s.NoError(err)
+}
+
+func (s *ChangeUserStatusSuite) TestChangeStatusRevokeTokens() {
+	userId := uuid.New()
+	s.repo.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.repo.EXPECT().ChangeStatus(s.ctx, userId, false).Return(nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, userId).Return(nil).Times(1)
+	err := s.changeUserStatus.Execute(s.ctx, userId, false, true)
+	s.NoError(err)
+}
+
+func (s *ChangeUserStatusSuite) TestEnableDoesNotRevokeTokens() {
+	userId := uuid.New()
+	s.repo.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.repo.EXPECT().ChangeStatus(s.ctx, userId, true).Return(nil).Times(1)
+	err := s.changeUserStatus.Execute(s.ctx, userId, true, true)
+	s.NoError(err)
+}
+
+func (s *ChangeUserStatusSuite) TestChangeStatusIdNotExists() {
+	userId := uuid.New()
+	s.repo.EXPECT().ExistsById(s.ctx, userId).Return(false, nil).Times(1)
+	err := s.changeUserStatus.Execute(s.ctx, userId, false, true)
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *ChangeUserStatusSuite) TestChangeStatusErr() {
+	userId := uuid.New()
+	s.repo.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.repo.EXPECT().ChangeStatus(s.ctx, userId, false).Return(errors.New("connection refused")).Times(1)
+	err := s.changeUserStatus.Execute(s.ctx, userId, false, true)
+	s.EqualError(err, "connection refused")
+}
@@ -0,0 +1,33 @@
+//go:generate mockgen -source CloseAccount.go -destination mock/CloseAccount_mock.go -package mock
+package user
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+// CloseAccount deletes the account of a user who proves to know its password, like DeleteUser does for an
+// administrator. The client IP feeds the login throttle.
+type CloseAccount interface {
+	Execute(ctx context.Context, id uuid.UUID, password string, clientIP string) error
+}
+
+func NewCloseAccount(repo repository.UserRepository, loginThrottle token.LoginThrottle, deleteUser DeleteUser) CloseAccount {
+	return closeAccount{repo: repo, loginThrottle: loginThrottle, deleteUser: deleteUser}
+}
+
+type closeAccount struct {
+	repo          repository.UserRepository
+	loginThrottle token.LoginThrottle
+	deleteUser    DeleteUser
+}
+
+func (uc closeAccount) Execute(ctx context.Context, id uuid.UUID, password string, clientIP string) error {
+	_, err := checkCurrentPassword(ctx, uc.repo, uc.loginThrottle, id, password, clientIP)
+	if err != nil {
+		return err
+	}
+	return uc.deleteUser.Execute(ctx, id)
+}
@@ -0,0 +1,80 @@
+package user
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/application/token"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+type CloseAccountSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository   *mock.MockUserRepository
+	loginThrottle    *tokenMock.MockLoginThrottle
+	revokeUserTokens *tokenMock.MockRevokeUserTokens
+
+	ctx          context.Context
+	closeAccount CloseAccount
+	id           uuid.UUID
+	hash         string
+}
+
+func TestCloseAccount(t *testing.T) {
+	suite.Run(t, new(CloseAccountSuite))
+}
+
+func (s *CloseAccountSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.userRepository = mock.NewMockUserRepository(s.mockCtrl)
+	s.loginThrottle = tokenMock.NewMockLoginThrottle(s.mockCtrl)
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+
+	s.ctx = context.Background()
+	s.closeAccount = NewCloseAccount(s.userRepository, s.loginThrottle, NewDeleteUser(s.userRepository, s.revokeUserTokens))
+	s.id = uuid.New()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	s.hash = string(hash)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.id).Return(&entity.User{ID: s.id, Username: "admin"}, nil).AnyTimes()
+}
+
+func (s *CloseAccountSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *CloseAccountSuite) TestCloseOk() {
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	s.userRepository.EXPECT().FindPasswordByID(s.ctx, s.id).Return(s.hash, nil).Times(1)
+	s.loginThrottle.EXPECT().Succeed(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	s.userRepository.EXPECT().Delete(s.ctx, s.id).Return(nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, s.id).Return(nil).Times(1)
+
+	err := s.closeAccount.Execute(s.ctx, s.id, "secret", testClientIP)
+	s.NoError(err)
+}
+
+func (s *CloseAccountSuite) TestCloseWrongPassword() {
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+	s.userRepository.EXPECT().FindPasswordByID(s.ctx, s.id).Return(s.hash, nil).Times(1)
+	s.loginThrottle.EXPECT().Fail(s.ctx, "admin", testClientIP).Return(nil).Times(1)
+
+	err := s.closeAccount.Execute(s.ctx, s.id, "guess", testClientIP)
+	s.ErrorIs(err, ErrInvalidPassword)
+}
+
+func (s *CloseAccountSuite) TestCloseThrottled() {
+	s.loginThrottle.EXPECT().Attempt(s.ctx, "admin", testClientIP).Return(&token.LoginThrottledError{}).Times(1)
+
+	err := s.closeAccount.Execute(s.ctx, s.id, "secret", testClientIP)
+	s.ErrorIs(err, token.ErrTooManyLoginAttempts)
+}
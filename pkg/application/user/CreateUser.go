@@ -3,6 +3,7 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/golauth/golauth/pkg/domain/factory"
@@ -14,15 +15,18 @@ const defaultRoleName = "USER"
 
 var bcryptDefaultCost = bcrypt.DefaultCost
 
+var ErrUserAlreadyExists = errors.New("username or email already in use")
+
 type CreateUser interface {
 	Execute(ctx context.Context, input *entity.User) (*entity.User, error)
 }
 
-func NewCreateUser(repoFactory factory.RepositoryFactory) CreateUser {
+func NewCreateUser(repoFactory factory.RepositoryFactory, passwordPolicy PasswordPolicy) CreateUser {
 	return createUser{
 		userRepository:     repoFactory.NewUserRepository(),
 		roleRepository:     repoFactory.NewRoleRepository(),
 		userRoleRepository: repoFactory.NewUserRoleRepository(),
+		passwordPolicy:     passwordPolicy,
 	}
 }
 
@@ -30,19 +34,31 @@ type createUser struct {
 	userRepository     repository.UserRepository
 	roleRepository     repository.RoleRepository
 	userRoleRepository repository.UserRoleRepository
+	passwordPolicy     PasswordPolicy
 }
 
 func (uc createUser) Execute(ctx context.Context, input *entity.User) (*entity.User, error) {
 	input.Enabled = true
-	hash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcryptDefaultCost)
+	err := uc.passwordPolicy.Validate(ctx, input, input.Password)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := hashPassword(input.Password)
 	if err != nil {
-		return nil, fmt.Errorf("could not generate password: %w", err)
+		return nil, err
 	}
-	input.Password = string(hash)
+	input.Password = hash
 	savedUser, err := uc.userRepository.Create(ctx, input)
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrUserAlreadyExists
+	}
 	if err != nil {
 		return nil, fmt.Errorf("could not save user: %w", err)
 	}
+	err = uc.passwordPolicy.Remember(ctx, savedUser.ID, hash)
+	if err != nil {
+		return nil, err
+	}
 	role, err := uc.roleRepository.FindByName(ctx, defaultRoleName)
 	if err != nil {
 		return nil, fmt.Errorf("could not fetch default role: %w", err)
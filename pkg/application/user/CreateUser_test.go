@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/golauth/golauth/pkg/domain/entity"
 	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
 	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -24,6 +25,7 @@ type CreateUserSuite struct {
 	userRepository     *repoMock.MockUserRepository
 	roleRepository     *repoMock.MockRoleRepository
 	userRoleRepository *repoMock.MockUserRoleRepository
+	historyRepository  *repoMock.MockPasswordHistoryRepository
 
 	ctx        context.Context
 	createUser CreateUser
@@ -44,13 +46,15 @@ func (s *CreateUserSuite) SetupTest() {
 	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
 	s.roleRepository = repoMock.NewMockRoleRepository(s.mockCtrl)
 	s.userRoleRepository = repoMock.NewMockUserRoleRepository(s.mockCtrl)
+	s.historyRepository = repoMock.NewMockPasswordHistoryRepository(s.mockCtrl)
 
 	s.repoFactory.EXPECT().NewRoleRepository().AnyTimes().Return(s.roleRepository)
 	s.repoFactory.EXPECT().NewUserRoleRepository().AnyTimes().Return(s.userRoleRepository)
 	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewPasswordHistoryRepository().AnyTimes().Return(s.historyRepository)
 
 	s.ctx = context.Background()
-	s.createUser = NewCreateUser(s.repoFactory)
+	s.createUser = NewCreateUser(s.repoFactory, NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{}))
 
 	s.input = &entity.User{
 		Username:  "admin",
@@ -58,7 +62,7 @@ func (s *CreateUserSuite) SetupTest() {
 		LastName:  "Name",
 		Email:     "em@il.com",
 		Document:  "1234",
-		Password:  "4567",
+		Password:  "correct-horse",
 		Enabled:   true,
 	}
 	s.mockSavedUser = &entity.User{
@@ -84,6 +88,11 @@ func (s *CreateUserSuite) TestCreateUserOk() {
 	userId := s.mockSavedUser.ID
 	role := entity.Role{ID: roleId, Name: "USER", Description: "User", Enabled: true, CreationDate: time.Now()}
 	s.userRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(s.mockSavedUser, nil).Times(1)
+	s.historyRepository.EXPECT().Add(s.ctx, userId, gomock.Any(), 1).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string, _ int) error {
+			s.NoError(bcrypt.CompareHashAndPassword([]byte(hash), []byte("correct-horse")))
+			return nil
+		}).Times(1)
 	s.roleRepository.EXPECT().FindByName(s.ctx, defaultRoleName).Return(&role, nil).Times(1)
 	s.userRoleRepository.EXPECT().AddUserRole(s.ctx, userId, roleId).Return(nil).Times(1)
 
@@ -93,15 +102,33 @@ func (s *CreateUserSuite) TestCreateUserOk() {
 	s.Equal(s.mockSavedUser.Username, createUser.Username)
 }
 
+func (s *CreateUserSuite) TestCreateUserPasswordPolicy() {
+	s.input.Password = "4567"
+
+	_, err := s.createUser.Execute(s.ctx, s.input)
+	s.ErrorIs(err, ErrPasswordPolicy)
+	var policyErr *PasswordPolicyError
+	s.ErrorAs(err, &policyErr)
+	s.Equal(PasswordRuleMinLength, policyErr.Violations[0].Rule)
+}
+
 func (s *CreateUserSuite) TestCreateUserErrWhenSave() {
 	s.userRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("could not create user admin")).Times(1)
 
-	_, err := s.createUser.Execute(s.ctx, &entity.User{})
+	_, err := s.createUser.Execute(s.ctx, s.input)
 	s.EqualError(err, "could not save user: could not create user admin")
 }
 
+func (s *CreateUserSuite) TestCreateUserAlreadyExists() {
+	s.userRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("could not create user admin: %w", repository.ErrDuplicate)).Times(1)
+
+	_, err := s.createUser.Execute(s.ctx, s.input)
+	s.ErrorIs(err, ErrUserAlreadyExists)
+}
+
 func (s *CreateUserSuite) TestCreateUserErrFindRole() {
 	s.userRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(s.mockSavedUser, nil).Times(1)
+	s.historyRepository.EXPECT().Add(s.ctx, s.mockSavedUser.ID, gomock.Any(), 1).Return(nil).Times(1)
 	s.roleRepository.EXPECT().FindByName(s.ctx, defaultRoleName).Return(nil, fmt.Errorf("could not find role USER")).Times(1)
 
 	_, err := s.createUser.Execute(s.ctx, s.input)
@@ -113,6 +140,7 @@ func (s *CreateUserSuite) TestCreateUserErrAddUserRole() {
 	userId := s.mockSavedUser.ID
 	role := entity.Role{ID: roleId, Name: "USER", Description: "User", Enabled: true, CreationDate: time.Now()}
 	s.userRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(s.mockSavedUser, nil).Times(1)
+	s.historyRepository.EXPECT().Add(s.ctx, userId, gomock.Any(), 1).Return(nil).Times(1)
 	s.roleRepository.EXPECT().FindByName(s.ctx, defaultRoleName).Return(&role, nil).Times(1)
 	s.userRoleRepository.
 		EXPECT().
@@ -125,6 +153,6 @@ func (s *CreateUserSuite) TestCreateUserErrAddUserRole() {
 
 func (s *CreateUserSuite) TestCreateUserErrGenerateHashPassword() {
 	bcryptDefaultCost = 50
-	_, err := s.createUser.Execute(s.ctx, &entity.User{Password: "1234"})
+	_, err := s.createUser.Execute(s.ctx, s.input)
 	s.EqualError(err, "could not generate password: crypto/bcrypt: cost 50 is outside allowed range (4,31)")
 }
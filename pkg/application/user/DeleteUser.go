@@ -0,0 +1,36 @@
+//go:generate mockgen -source DeleteUser.go -destination mock/DeleteUser_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+// DeleteUser removes a user with everything kept about it, and revokes the access tokens it still holds.
+type DeleteUser interface {
+	Execute(ctx context.Context, id uuid.UUID) error
+}
+
+func NewDeleteUser(repo repository.UserRepository, revokeUserTokens token.RevokeUserTokens) DeleteUser {
+	return deleteUser{repo: repo, revokeUserTokens: revokeUserTokens}
+}
+
+type deleteUser struct {
+	repo             repository.UserRepository
+	revokeUserTokens token.RevokeUserTokens
+}
+
+func (uc deleteUser) Execute(ctx context.Context, id uuid.UUID) error {
+	err := uc.repo.Delete(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	if err != nil {
+		return err
+	}
+	return uc.revokeUserTokens.Execute(ctx, id)
+}
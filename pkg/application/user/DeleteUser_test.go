@@ -0,0 +1,67 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type DeleteUserSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository   *mock.MockUserRepository
+	revokeUserTokens *tokenMock.MockRevokeUserTokens
+
+	ctx        context.Context
+	deleteUser DeleteUser
+}
+
+func TestDeleteUser(t *testing.T) {
+	suite.Run(t, new(DeleteUserSuite))
+}
+
+func (s *DeleteUserSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.userRepository = mock.NewMockUserRepository(s.mockCtrl)
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+
+	s.ctx = context.Background()
+	s.deleteUser = NewDeleteUser(s.userRepository, s.revokeUserTokens)
+}
+
+func (s *DeleteUserSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *DeleteUserSuite) TestDeleteOk() {
+	id := uuid.New()
+	s.userRepository.EXPECT().Delete(s.ctx, id).Return(nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, id).Return(nil).Times(1)
+
+	s.NoError(s.deleteUser.Execute(s.ctx, id))
+}
+
+func (s *DeleteUserSuite) TestDeleteNotFound() {
+	id := uuid.New()
+	s.userRepository.EXPECT().Delete(s.ctx, id).Return(fmt.Errorf("could not delete user: %w", repository.ErrNotFound)).Times(1)
+
+	s.ErrorIs(s.deleteUser.Execute(s.ctx, id), ErrUserNotFound)
+}
+
+func (s *DeleteUserSuite) TestDeleteErr() {
+	id := uuid.New()
+	s.userRepository.EXPECT().Delete(s.ctx, id).Return(errors.New("connection refused")).Times(1)
+
+	s.EqualError(s.deleteUser.Execute(s.ctx, id), "connection refused")
+}
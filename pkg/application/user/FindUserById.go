@@ -3,11 +3,15 @@ package user
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/google/uuid"
 )
 
+var ErrUserNotFound = errors.New("user not found")
+
 type FindUserById interface {
 	Execute(ctx context.Context, id uuid.UUID) (*entity.User, error)
 }
@@ -22,6 +26,9 @@ type findUserById struct {
 
 func (uc findUserById) Execute(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	user, err := uc.repo.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
 	if err != nil {
 		return nil, err
 	}
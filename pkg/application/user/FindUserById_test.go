@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/domain/repository/mock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -71,3 +72,12 @@ func (s *FindUserByIdSuite) TestFindByIDErr() {
 	s.Error(err)
 	s.ErrorAs(fmt.Errorf("could not find user"), &err)
 }
+
+func (s *FindUserByIdSuite) TestFindByIDNotFound() {
+	id := uuid.New()
+	s.userRepository.EXPECT().FindByID(s.ctx, id).Return(nil, fmt.Errorf("could not find user: %w", repository.ErrNotFound)).Times(1)
+
+	resp, err := s.finding.Execute(s.ctx, id)
+	s.Nil(resp)
+	s.ErrorIs(err, ErrUserNotFound)
+}
@@ -0,0 +1,61 @@
+//go:generate mockgen -source FindUserRoles.go -destination mock/FindUserRoles_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/role"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+var ErrRoleNotAssigned = errors.New("role not assigned to user")
+
+type FindUserRoles interface {
+	Execute(ctx context.Context, userID uuid.UUID) ([]*entity.Role, error)
+}
+
+func NewFindUserRoles(repoFactory factory.RepositoryFactory) FindUserRoles {
+	return findUserRoles{
+		userRepository:     repoFactory.NewUserRepository(),
+		userRoleRepository: repoFactory.NewUserRoleRepository(),
+	}
+}
+
+type findUserRoles struct {
+	userRepository     repository.UserRepository
+	userRoleRepository repository.UserRoleRepository
+}
+
+func (uc findUserRoles) Execute(ctx context.Context, userID uuid.UUID) ([]*entity.Role, error) {
+	err := requireUserAndRoles(ctx, uc.userRepository, nil, userID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.userRoleRepository.FindRolesByUserID(ctx, userID)
+}
+
+// requireUserAndRoles fails with ErrUserNotFound or role.ErrRoleNotFound unless the user and every role exist.
+func requireUserAndRoles(ctx context.Context, userRepository repository.UserRepository, roleRepository repository.RoleRepository,
+	userID uuid.UUID, roleIDs ...uuid.UUID) error {
+	exists, err := userRepository.ExistsById(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+	}
+	for _, roleID := range roleIDs {
+		exists, err = roleRepository.ExistsById(ctx, roleID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s", role.ErrRoleNotFound, roleID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type FindUserRolesSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory        *factoryMock.MockRepositoryFactory
+	userRepository     *repoMock.MockUserRepository
+	userRoleRepository *repoMock.MockUserRoleRepository
+
+	ctx           context.Context
+	findUserRoles FindUserRoles
+}
+
+func TestFindUserRoles(t *testing.T) {
+	suite.Run(t, new(FindUserRolesSuite))
+}
+
+func (s *FindUserRolesSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.userRoleRepository = repoMock.NewMockUserRoleRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewUserRoleRepository().AnyTimes().Return(s.userRoleRepository)
+
+	s.ctx = context.Background()
+	s.findUserRoles = NewFindUserRoles(s.repoFactory)
+}
+
+func (s *FindUserRolesSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *FindUserRolesSuite) TestFindRolesOk() {
+	userId := uuid.New()
+	roles := []*entity.Role{{ID: uuid.New(), Name: "ADMIN"}}
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().FindRolesByUserID(s.ctx, userId).Return(roles, nil).Times(1)
+
+	output, err := s.findUserRoles.Execute(s.ctx, userId)
+	s.NoError(err)
+	s.Equal(roles, output)
+}
+
+func (s *FindUserRolesSuite) TestFindRolesUserNotFound() {
+	userId := uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(false, nil).Times(1)
+
+	_, err := s.findUserRoles.Execute(s.ctx, userId)
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *FindUserRolesSuite) TestFindRolesExistsErr() {
+	userId := uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(false, errors.New("connection refused")).Times(1)
+
+	_, err := s.findUserRoles.Execute(s.ctx, userId)
+	s.EqualError(err, "connection refused")
+}
@@ -0,0 +1,70 @@
+//go:generate mockgen -source ListUsers.go -destination mock/ListUsers_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+var (
+	ErrInvalidSort = errors.New("invalid sort field")
+	ErrInvalidPage = errors.New("invalid page or size")
+)
+
+var sortFields = map[string]bool{
+	repository.UserSortUsername:     true,
+	repository.UserSortFirstName:    true,
+	repository.UserSortLastName:     true,
+	repository.UserSortEmail:        true,
+	repository.UserSortCreationDate: true,
+}
+
+type UserPage struct {
+	Users []*entity.User
+	Page  int
+	Size  int
+	Total int
+}
+
+// ListUsers returns the zero-based page of users matching filter. A zero size falls back to
+// DefaultPageSize and an empty sort orders by username.
+type ListUsers interface {
+	Execute(ctx context.Context, filter repository.UserFilter, page int, size int) (*UserPage, error)
+}
+
+func NewListUsers(repo repository.UserRepository) ListUsers {
+	return listUsers{repo: repo}
+}
+
+type listUsers struct {
+	repo repository.UserRepository
+}
+
+func (uc listUsers) Execute(ctx context.Context, filter repository.UserFilter, page int, size int) (*UserPage, error) {
+	if filter.Sort == "" {
+		filter.Sort = repository.UserSortUsername
+	}
+	if !sortFields[filter.Sort] {
+		return nil, ErrInvalidSort
+	}
+	if size == 0 {
+		size = DefaultPageSize
+	}
+	if page < 0 || size < 0 || size > MaxPageSize {
+		return nil, ErrInvalidPage
+	}
+	filter.Offset = page * size
+	filter.Limit = size
+	users, total, err := uc.repo.FindAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return &UserPage{Users: users, Page: page, Size: size, Total: total}, nil
+}
@@ -0,0 +1,85 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type ListUsersSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository *mock.MockUserRepository
+
+	ctx       context.Context
+	listUsers ListUsers
+}
+
+func TestListUsers(t *testing.T) {
+	suite.Run(t, new(ListUsersSuite))
+}
+
+func (s *ListUsersSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.userRepository = mock.NewMockUserRepository(s.mockCtrl)
+
+	s.ctx = context.Background()
+	s.listUsers = NewListUsers(s.userRepository)
+}
+
+func (s *ListUsersSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ListUsersSuite) TestListDefaults() {
+	users := []*entity.User{{Username: "admin"}}
+	expected := repository.UserFilter{Sort: repository.UserSortUsername, Limit: DefaultPageSize}
+	s.userRepository.EXPECT().FindAll(s.ctx, expected).Return(users, 1, nil).Times(1)
+
+	page, err := s.listUsers.Execute(s.ctx, repository.UserFilter{}, 0, 0)
+	s.NoError(err)
+	s.Equal(&UserPage{Users: users, Page: 0, Size: DefaultPageSize, Total: 1}, page)
+}
+
+func (s *ListUsersSuite) TestListPageAndFilters() {
+	enabled := false
+	filter := repository.UserFilter{Email: "goauth", Enabled: &enabled, Role: "ADMIN", Sort: repository.UserSortCreationDate, Desc: true}
+	expected := filter
+	expected.Offset = 20
+	expected.Limit = 10
+	s.userRepository.EXPECT().FindAll(s.ctx, expected).Return([]*entity.User{}, 21, nil).Times(1)
+
+	page, err := s.listUsers.Execute(s.ctx, filter, 2, 10)
+	s.NoError(err)
+	s.Equal(2, page.Page)
+	s.Equal(10, page.Size)
+	s.Equal(21, page.Total)
+}
+
+func (s *ListUsersSuite) TestListInvalidSort() {
+	_, err := s.listUsers.Execute(s.ctx, repository.UserFilter{Sort: "password"}, 0, 0)
+	s.ErrorIs(err, ErrInvalidSort)
+}
+
+func (s *ListUsersSuite) TestListInvalidPage() {
+	_, err := s.listUsers.Execute(s.ctx, repository.UserFilter{}, -1, 0)
+	s.ErrorIs(err, ErrInvalidPage)
+	_, err = s.listUsers.Execute(s.ctx, repository.UserFilter{}, 0, MaxPageSize+1)
+	s.ErrorIs(err, ErrInvalidPage)
+}
+
+func (s *ListUsersSuite) TestListErr() {
+	s.userRepository.EXPECT().FindAll(s.ctx, gomock.Any()).Return(nil, 0, errors.New("connection refused")).Times(1)
+
+	_, err := s.listUsers.Execute(s.ctx, repository.UserFilter{}, 0, 0)
+	s.EqualError(err, "connection refused")
+}
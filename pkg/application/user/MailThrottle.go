@@ -0,0 +1,94 @@
+//go:generate mockgen -source MailThrottle.go -destination mock/MailThrottle_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultMailRequestsPerEmail = 3
+	DefaultMailRequestsPerIP    = 20
+	DefaultMailRequestWindow    = time.Hour
+)
+
+var ErrTooManyMailRequests = errors.New("too_many_requests")
+
+// MailThrottledError refuses a password reset or email verification request while its email or client IP asked
+// too often. It matches ErrTooManyMailRequests.
+type MailThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *MailThrottledError) Error() string {
+	return fmt.Sprintf("too many email requests, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *MailThrottledError) Unwrap() error {
+	return ErrTooManyMailRequests
+}
+
+// MailThrottleConfig caps the requests made for an email and from a client IP. A cap of zero turns it off. The
+// requests are forgotten once none was made for Window.
+type MailThrottleConfig struct {
+	PerEmail int
+	PerIP    int
+	Window   time.Duration
+}
+
+// MailThrottle keeps password reset and email verification requests from flooding inboxes and the mailer. The
+// requests are counted in the login failure table under their own kinds, so every replica sees them.
+type MailThrottle interface {
+	// Attempt counts a request, failing with a MailThrottledError when the email or the client IP asked too
+	// often. Emails are counted whether they belong to an account or not.
+	Attempt(ctx context.Context, email string, clientIP string) error
+}
+
+func NewMailThrottle(repoFactory factory.RepositoryFactory, config MailThrottleConfig) MailThrottle {
+	if config.Window <= 0 {
+		config.Window = DefaultMailRequestWindow
+	}
+	return mailThrottle{
+		loginFailureRepository: repoFactory.NewLoginFailureRepository(),
+		config:                 config,
+	}
+}
+
+type mailThrottle struct {
+	loginFailureRepository repository.LoginFailureRepository
+	config                 MailThrottleConfig
+}
+
+func (t mailThrottle) Attempt(ctx context.Context, email string, clientIP string) error {
+	now := time.Now()
+	limits := []struct {
+		kind    string
+		subject string
+		limit   int
+	}{
+		{kind: entity.LoginFailureKindEmail, subject: strings.ToLower(email), limit: t.config.PerEmail},
+		{kind: entity.LoginFailureKindMailIP, subject: clientIP, limit: t.config.PerIP},
+	}
+	throttled := false
+	for _, l := range limits {
+		if l.limit <= 0 || l.subject == "" {
+			continue
+		}
+		// the count and the check are one statement, so concurrent requests cannot all slip under the cap
+		requests, err := t.loginFailureRepository.RecordAttempt(ctx, l.kind, l.subject, now, now.Add(-t.config.Window))
+		if err != nil {
+			return err
+		}
+		throttled = throttled || requests.Failures > l.limit
+	}
+	if throttled {
+		return &MailThrottledError{RetryAfter: t.config.Window}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type MailThrottleSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	loginFailureRepository *repoMock.MockLoginFailureRepository
+	repoFactory            *factoryMock.MockRepositoryFactory
+
+	ctx      context.Context
+	throttle MailThrottle
+}
+
+func TestMailThrottle(t *testing.T) {
+	suite.Run(t, new(MailThrottleSuite))
+}
+
+func (s *MailThrottleSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.loginFailureRepository = repoMock.NewMockLoginFailureRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewLoginFailureRepository().AnyTimes().Return(s.loginFailureRepository)
+
+	s.ctx = context.Background()
+	s.throttle = NewMailThrottle(s.repoFactory, MailThrottleConfig{PerEmail: 3, PerIP: 20})
+}
+
+func (s *MailThrottleSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *MailThrottleSuite) expectRequests(kind string, subject string, requests int) {
+	s.loginFailureRepository.EXPECT().RecordAttempt(s.ctx, kind, subject, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, _ string, now time.Time, since time.Time) (*entity.LoginFailure, error) {
+			s.Equal(DefaultMailRequestWindow, now.Sub(since))
+			return &entity.LoginFailure{Failures: requests}, nil
+		}).Times(1)
+}
+
+func (s *MailThrottleSuite) TestAttemptUnderCaps() {
+	s.expectRequests(entity.LoginFailureKindEmail, "admin@example.com", 3)
+	s.expectRequests(entity.LoginFailureKindMailIP, testClientIP, 20)
+
+	s.NoError(s.throttle.Attempt(s.ctx, "Admin@Example.com", testClientIP))
+}
+
+func (s *MailThrottleSuite) TestAttemptOverEmailCap() {
+	s.expectRequests(entity.LoginFailureKindEmail, "admin@example.com", 4)
+	s.expectRequests(entity.LoginFailureKindMailIP, testClientIP, 1)
+
+	err := s.throttle.Attempt(s.ctx, "admin@example.com", testClientIP)
+	s.ErrorIs(err, ErrTooManyMailRequests)
+	var throttledErr *MailThrottledError
+	s.ErrorAs(err, &throttledErr)
+	s.Equal(DefaultMailRequestWindow, throttledErr.RetryAfter)
+}
+
+func (s *MailThrottleSuite) TestAttemptOverIPCap() {
+	s.expectRequests(entity.LoginFailureKindEmail, "admin@example.com", 1)
+	s.expectRequests(entity.LoginFailureKindMailIP, testClientIP, 21)
+
+	s.ErrorIs(s.throttle.Attempt(s.ctx, "admin@example.com", testClientIP), ErrTooManyMailRequests)
+}
+
+func (s *MailThrottleSuite) TestAttemptError() {
+	s.loginFailureRepository.EXPECT().RecordAttempt(s.ctx, entity.LoginFailureKindEmail, "admin@example.com", gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	s.EqualError(s.throttle.Attempt(s.ctx, "admin@example.com", testClientIP), "connection refused")
+}
+
+func (s *MailThrottleSuite) TestDisabled() {
+	throttle := NewMailThrottle(s.repoFactory, MailThrottleConfig{})
+
+	s.NoError(throttle.Attempt(s.ctx, "admin@example.com", testClientIP))
+}
@@ -0,0 +1,221 @@
+//go:generate mockgen -source PasswordPolicy.go -destination mock/PasswordPolicy_mock.go -package mock
+package user
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	DefaultPasswordMinLength = 8
+	// MaxPasswordLength is the number of bytes bcrypt hashes. Anything after it would be silently ignored.
+	MaxPasswordLength = 72
+	// personalInfoMinLength keeps very short usernames from ruling out most passwords.
+	personalInfoMinLength = 3
+)
+
+const (
+	PasswordRuleMinLength    = "min_length"
+	PasswordRuleMaxLength    = "max_length"
+	PasswordRuleUppercase    = "uppercase"
+	PasswordRuleLowercase    = "lowercase"
+	PasswordRuleDigit        = "digit"
+	PasswordRuleSymbol       = "symbol"
+	PasswordRulePersonalInfo = "personal_info"
+	PasswordRuleBreached     = "breached"
+	PasswordRuleHistory      = "history"
+)
+
+var ErrPasswordPolicy = errors.New("password does not meet the password policy")
+
+// PasswordViolation is a password policy rule broken by a password.
+type PasswordViolation struct {
+	Rule    string
+	Message string
+}
+
+// PasswordPolicyError lists every rule broken by a password. It matches ErrPasswordPolicy.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return ErrPasswordPolicy.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *PasswordPolicyError) Unwrap() error {
+	return ErrPasswordPolicy
+}
+
+// PasswordPolicyConfig sets the rules new passwords must follow. MinLength counts characters and MaxLength bytes,
+// which never goes over MaxPasswordLength. Breached holds known leaked passwords, and HistorySize is how many
+// previous passwords of a user cannot be chosen again.
+type PasswordPolicyConfig struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	Breached      map[string]struct{}
+	HistorySize   int
+}
+
+// PasswordPolicy checks new passwords before they are hashed and remembers the hashes of the passwords set, so
+// that the last ones cannot be reused.
+type PasswordPolicy interface {
+	// Validate fails with a PasswordPolicyError when password breaks the policy as the new password of user.
+	// Users not created yet have no ID and no history.
+	Validate(ctx context.Context, user *entity.User, password string) error
+	// Remember adds hash to the password history of the user.
+	Remember(ctx context.Context, userID uuid.UUID, hash string) error
+}
+
+func NewPasswordPolicy(repoFactory factory.RepositoryFactory, config PasswordPolicyConfig) PasswordPolicy {
+	if config.MinLength <= 0 {
+		config.MinLength = DefaultPasswordMinLength
+	}
+	if config.MaxLength <= 0 || config.MaxLength > MaxPasswordLength {
+		config.MaxLength = MaxPasswordLength
+	}
+	return passwordPolicy{
+		userRepository:    repoFactory.NewUserRepository(),
+		historyRepository: repoFactory.NewPasswordHistoryRepository(),
+		config:            config,
+	}
+}
+
+type passwordPolicy struct {
+	userRepository    repository.UserRepository
+	historyRepository repository.PasswordHistoryRepository
+	config            PasswordPolicyConfig
+}
+
+func (p passwordPolicy) Validate(ctx context.Context, user *entity.User, password string) error {
+	var violations []PasswordViolation
+	add := func(rule string, message string) {
+		violations = append(violations, PasswordViolation{Rule: rule, Message: message})
+	}
+	if utf8.RuneCountInString(password) < p.config.MinLength {
+		add(PasswordRuleMinLength, fmt.Sprintf("must have at least %d characters", p.config.MinLength))
+	}
+	if len(password) > p.config.MaxLength {
+		add(PasswordRuleMaxLength, fmt.Sprintf("must have at most %d bytes", p.config.MaxLength))
+	}
+	if p.config.RequireUpper && !strings.ContainsFunc(password, unicode.IsUpper) {
+		add(PasswordRuleUppercase, "must contain an uppercase letter")
+	}
+	if p.config.RequireLower && !strings.ContainsFunc(password, unicode.IsLower) {
+		add(PasswordRuleLowercase, "must contain a lowercase letter")
+	}
+	if p.config.RequireDigit && !strings.ContainsFunc(password, unicode.IsDigit) {
+		add(PasswordRuleDigit, "must contain a digit")
+	}
+	if p.config.RequireSymbol && !strings.ContainsFunc(password, isSymbol) {
+		add(PasswordRuleSymbol, "must contain a symbol")
+	}
+	if containsPersonalInfo(user, password) {
+		add(PasswordRulePersonalInfo, "must not contain the username or email")
+	}
+	if _, ok := p.config.Breached[password]; ok {
+		add(PasswordRuleBreached, "appears in a list of breached passwords")
+	}
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	// the history is checked last because every hash costs a bcrypt comparison
+	reused, err := p.reused(ctx, user.ID, password)
+	if err != nil {
+		return err
+	}
+	if reused {
+		add(PasswordRuleHistory, fmt.Sprintf("must differ from the last %d passwords", p.config.HistorySize))
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// reused compares the password with the current one of the user as well as with the history, which may not hold
+// the current password of users who never changed it since the history was turned on.
+func (p passwordPolicy) reused(ctx context.Context, userID uuid.UUID, password string) (bool, error) {
+	if p.config.HistorySize <= 0 || userID == uuid.Nil {
+		return false, nil
+	}
+	current, err := p.userRepository.FindPasswordByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	hashes, err := p.historyRepository.FindLatest(ctx, userID, p.config.HistorySize)
+	if err != nil {
+		return false, err
+	}
+	if !slices.Contains(hashes, current) {
+		hashes = append(hashes, current)
+	}
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Remember keeps at least the current hash even when the history rule is off, so turning it on later already
+// covers the password in use.
+func (p passwordPolicy) Remember(ctx context.Context, userID uuid.UUID, hash string) error {
+	return p.historyRepository.Add(ctx, userID, hash, max(p.config.HistorySize, 1))
+}
+
+func isSymbol(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+}
+
+func containsPersonalInfo(user *entity.User, password string) bool {
+	password = strings.ToLower(password)
+	localPart, _, _ := strings.Cut(user.Email, "@")
+	for _, value := range []string{user.Username, localPart} {
+		if len(value) >= personalInfoMinLength && strings.Contains(password, strings.ToLower(value)) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadBreachedPasswords reads a breached password list with one password per line, as published by most leak
+// corpora. Blank lines are skipped.
+func LoadBreachedPasswords(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	breached := make(map[string]struct{})
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if password := strings.TrimRight(scanner.Text(), "\r"); password != "" {
+			breached[password] = struct{}{}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read breached password list: %w", err)
+	}
+	return breached, nil
+}
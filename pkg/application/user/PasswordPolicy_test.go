@@ -0,0 +1,165 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type PasswordPolicySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory       *factoryMock.MockRepositoryFactory
+	userRepository    *repoMock.MockUserRepository
+	historyRepository *repoMock.MockPasswordHistoryRepository
+
+	ctx  context.Context
+	user *entity.User
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	suite.Run(t, new(PasswordPolicySuite))
+}
+
+func (s *PasswordPolicySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.historyRepository = repoMock.NewMockPasswordHistoryRepository(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewPasswordHistoryRepository().AnyTimes().Return(s.historyRepository)
+
+	s.ctx = context.Background()
+	s.user = &entity.User{ID: uuid.New(), Username: "jdoe", Email: "john.doe@example.com"}
+}
+
+func (s *PasswordPolicySuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *PasswordPolicySuite) rules(err error) []string {
+	var policyErr *PasswordPolicyError
+	s.ErrorAs(err, &policyErr)
+	s.ErrorIs(err, ErrPasswordPolicy)
+	var rules []string
+	for _, v := range policyErr.Violations {
+		rules = append(rules, v.Rule)
+	}
+	return rules
+}
+
+func (s *PasswordPolicySuite) TestValidateOk() {
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true})
+	s.NoError(policy.Validate(s.ctx, s.user, "Tr0ub4dor&3"))
+}
+
+func (s *PasswordPolicySuite) TestValidateReportsEveryRule() {
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{RequireUpper: true, RequireDigit: true, RequireSymbol: true})
+	err := policy.Validate(s.ctx, s.user, "short")
+	s.Equal([]string{PasswordRuleMinLength, PasswordRuleUppercase, PasswordRuleDigit, PasswordRuleSymbol}, s.rules(err))
+	s.ErrorContains(err, "must have at least 8 characters")
+}
+
+func (s *PasswordPolicySuite) TestValidateLengthCountsCharacters() {
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{})
+	s.NoError(policy.Validate(s.ctx, s.user, "çãõéíóúâ"))
+}
+
+func (s *PasswordPolicySuite) TestValidateMaxLengthInBytes() {
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{MaxLength: 100})
+	s.NoError(policy.Validate(s.ctx, s.user, strings.Repeat("a", MaxPasswordLength)))
+
+	err := policy.Validate(s.ctx, s.user, strings.Repeat("ç", 40))
+	s.Equal([]string{PasswordRuleMaxLength}, s.rules(err))
+}
+
+func (s *PasswordPolicySuite) TestValidatePersonalInfo() {
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{})
+	for _, password := range []string{"my-JDOE-password", "John.Doe.1234"} {
+		err := policy.Validate(s.ctx, s.user, password)
+		s.Equal([]string{PasswordRulePersonalInfo}, s.rules(err), password)
+	}
+}
+
+func (s *PasswordPolicySuite) TestValidateIgnoresShortUsername() {
+	s.user.Username = "jd"
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{})
+	s.NoError(policy.Validate(s.ctx, s.user, "jd-is-a-fine-name"))
+}
+
+func (s *PasswordPolicySuite) TestValidateBreached() {
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{Breached: map[string]struct{}{"password1": {}}})
+	err := policy.Validate(s.ctx, s.user, "password1")
+	s.Equal([]string{PasswordRuleBreached}, s.rules(err))
+}
+
+func (s *PasswordPolicySuite) TestValidateHistory() {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("previous-secret"), bcrypt.MinCost)
+	current, _ := bcrypt.GenerateFromPassword([]byte("current-secret"), bcrypt.MinCost)
+	s.userRepository.EXPECT().FindPasswordByID(s.ctx, s.user.ID).Return(string(current), nil).Times(2)
+	s.historyRepository.EXPECT().FindLatest(s.ctx, s.user.ID, 5).Return([]string{string(current), string(hash)}, nil).Times(2)
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{HistorySize: 5})
+
+	err := policy.Validate(s.ctx, s.user, "previous-secret")
+	s.Equal([]string{PasswordRuleHistory}, s.rules(err))
+	s.NoError(policy.Validate(s.ctx, s.user, "brand-new-secret"))
+}
+
+func (s *PasswordPolicySuite) TestValidateHistoryCurrentPassword() {
+	current, _ := bcrypt.GenerateFromPassword([]byte("current-secret"), bcrypt.MinCost)
+	s.userRepository.EXPECT().FindPasswordByID(s.ctx, s.user.ID).Return(string(current), nil).Times(1)
+	s.historyRepository.EXPECT().FindLatest(s.ctx, s.user.ID, 5).Return(nil, nil).Times(1)
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{HistorySize: 5})
+
+	err := policy.Validate(s.ctx, s.user, "current-secret")
+	s.Equal([]string{PasswordRuleHistory}, s.rules(err))
+}
+
+func (s *PasswordPolicySuite) TestValidateHistorySkippedForNewUser() {
+	s.user.ID = uuid.Nil
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{HistorySize: 5})
+	s.NoError(policy.Validate(s.ctx, s.user, "brand-new-secret"))
+}
+
+func (s *PasswordPolicySuite) TestValidateHistoryError() {
+	s.userRepository.EXPECT().FindPasswordByID(s.ctx, s.user.ID).Return("hash", nil).Times(1)
+	s.historyRepository.EXPECT().FindLatest(s.ctx, s.user.ID, 5).Return(nil, errors.New("db down")).Times(1)
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{HistorySize: 5})
+
+	err := policy.Validate(s.ctx, s.user, "brand-new-secret")
+	s.EqualError(err, "db down")
+}
+
+func (s *PasswordPolicySuite) TestRememberKeepsCurrentHashWithoutHistory() {
+	s.historyRepository.EXPECT().Add(s.ctx, s.user.ID, "hash", 1).Return(nil).Times(1)
+	policy := NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{})
+	s.NoError(policy.Remember(s.ctx, s.user.ID, "hash"))
+}
+
+func (s *PasswordPolicySuite) TestLoadBreachedPasswords() {
+	path := filepath.Join(s.T().TempDir(), "breached.txt")
+	s.NoError(os.WriteFile(path, []byte("123456\r\npassword\n\nqwerty\n"), 0600))
+
+	breached, err := LoadBreachedPasswords(path)
+	s.NoError(err)
+	s.Equal(map[string]struct{}{"123456": {}, "password": {}, "qwerty": {}}, breached)
+}
+
+func (s *PasswordPolicySuite) TestLoadBreachedPasswordsMissingFile() {
+	_, err := LoadBreachedPasswords(filepath.Join(s.T().TempDir(), "missing.txt"))
+	s.ErrorContains(err, "could not open breached password list")
+}
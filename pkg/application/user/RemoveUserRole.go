@@ -0,0 +1,49 @@
+//go:generate mockgen -source RemoveUserRole.go -destination mock/RemoveUserRole_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+// RemoveUserRole takes a role from the user. With revokeTokens the tokens the user holds are revoked too, as they
+// still carry the authorities of the role until they expire.
+type RemoveUserRole interface {
+	Execute(ctx context.Context, userID uuid.UUID, roleID uuid.UUID, revokeTokens bool) error
+}
+
+func NewRemoveUserRole(repoFactory factory.RepositoryFactory, revokeUserTokens token.RevokeUserTokens) RemoveUserRole {
+	return removeUserRole{
+		userRepository:     repoFactory.NewUserRepository(),
+		roleRepository:     repoFactory.NewRoleRepository(),
+		userRoleRepository: repoFactory.NewUserRoleRepository(),
+		revokeUserTokens:   revokeUserTokens,
+	}
+}
+
+type removeUserRole struct {
+	userRepository     repository.UserRepository
+	roleRepository     repository.RoleRepository
+	userRoleRepository repository.UserRoleRepository
+	revokeUserTokens   token.RevokeUserTokens
+}
+
+func (uc removeUserRole) Execute(ctx context.Context, userID uuid.UUID, roleID uuid.UUID, revokeTokens bool) error {
+	err := requireUserAndRoles(ctx, uc.userRepository, uc.roleRepository, userID, roleID)
+	if err != nil {
+		return err
+	}
+	err = uc.userRoleRepository.RemoveUserRole(ctx, userID, roleID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrRoleNotAssigned, roleID)
+	}
+	if err != nil || !revokeTokens {
+		return err
+	}
+	return uc.revokeUserTokens.Execute(ctx, userID)
+}
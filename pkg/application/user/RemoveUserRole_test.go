@@ -0,0 +1,107 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/role"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type RemoveUserRoleSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory        *factoryMock.MockRepositoryFactory
+	userRepository     *repoMock.MockUserRepository
+	roleRepository     *repoMock.MockRoleRepository
+	userRoleRepository *repoMock.MockUserRoleRepository
+	revokeUserTokens   *tokenMock.MockRevokeUserTokens
+
+	ctx            context.Context
+	removeUserRole RemoveUserRole
+}
+
+func TestRemoveUserRole(t *testing.T) {
+	suite.Run(t, new(RemoveUserRoleSuite))
+}
+
+func (s *RemoveUserRoleSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.roleRepository = repoMock.NewMockRoleRepository(s.mockCtrl)
+	s.userRoleRepository = repoMock.NewMockUserRoleRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewRoleRepository().AnyTimes().Return(s.roleRepository)
+	s.repoFactory.EXPECT().NewUserRoleRepository().AnyTimes().Return(s.userRoleRepository)
+
+	s.ctx = context.Background()
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+	s.removeUserRole = NewRemoveUserRole(s.repoFactory, s.revokeUserTokens)
+}
+
+func (s *RemoveUserRoleSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *RemoveUserRoleSuite) TestRemoveOk() {
+	userId, roleId := uuid.New(), uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().RemoveUserRole(s.ctx, userId, roleId).Return(nil).Times(1)
+
+	s.NoError(s.removeUserRole.Execute(s.ctx, userId, roleId, false))
+}
+
+func (s *RemoveUserRoleSuite) TestRemoveRevokeTokens() {
+	userId, roleId := uuid.New(), uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().RemoveUserRole(s.ctx, userId, roleId).Return(nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, userId).Return(nil).Times(1)
+
+	s.NoError(s.removeUserRole.Execute(s.ctx, userId, roleId, true))
+}
+
+func (s *RemoveUserRoleSuite) TestRemoveUserNotFound() {
+	userId, roleId := uuid.New(), uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(false, nil).Times(1)
+
+	s.ErrorIs(s.removeUserRole.Execute(s.ctx, userId, roleId, false), ErrUserNotFound)
+}
+
+func (s *RemoveUserRoleSuite) TestRemoveRoleNotFound() {
+	userId, roleId := uuid.New(), uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(false, nil).Times(1)
+
+	s.ErrorIs(s.removeUserRole.Execute(s.ctx, userId, roleId, false), role.ErrRoleNotFound)
+}
+
+func (s *RemoveUserRoleSuite) TestRemoveRoleNotAssigned() {
+	userId, roleId := uuid.New(), uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().RemoveUserRole(s.ctx, userId, roleId).Return(fmt.Errorf("could not remove: %w", repository.ErrNotFound)).Times(1)
+
+	s.ErrorIs(s.removeUserRole.Execute(s.ctx, userId, roleId, false), ErrRoleNotAssigned)
+}
+
+func (s *RemoveUserRoleSuite) TestRemoveRoleNotAssignedKeepsTokens() {
+	userId, roleId := uuid.New(), uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().RemoveUserRole(s.ctx, userId, roleId).Return(repository.ErrNotFound).Times(1)
+
+	s.ErrorIs(s.removeUserRole.Execute(s.ctx, userId, roleId, true), ErrRoleNotAssigned)
+}
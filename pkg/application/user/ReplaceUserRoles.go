@@ -0,0 +1,52 @@
+//go:generate mockgen -source ReplaceUserRoles.go -destination mock/ReplaceUserRoles_mock.go -package mock
+package user
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+// ReplaceUserRoles leaves the user with exactly the given roles. An empty set removes every role. With
+// revokeTokens the tokens the user holds are revoked too, so the authorities of removed roles stop working at once.
+type ReplaceUserRoles interface {
+	Execute(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, revokeTokens bool) error
+}
+
+func NewReplaceUserRoles(repoFactory factory.RepositoryFactory, revokeUserTokens token.RevokeUserTokens) ReplaceUserRoles {
+	return replaceUserRoles{
+		userRepository:     repoFactory.NewUserRepository(),
+		roleRepository:     repoFactory.NewRoleRepository(),
+		userRoleRepository: repoFactory.NewUserRoleRepository(),
+		revokeUserTokens:   revokeUserTokens,
+	}
+}
+
+type replaceUserRoles struct {
+	userRepository     repository.UserRepository
+	roleRepository     repository.RoleRepository
+	userRoleRepository repository.UserRoleRepository
+	revokeUserTokens   token.RevokeUserTokens
+}
+
+func (uc replaceUserRoles) Execute(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, revokeTokens bool) error {
+	unique := make([]uuid.UUID, 0, len(roleIDs))
+	seen := make(map[uuid.UUID]bool, len(roleIDs))
+	for _, id := range roleIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	err := requireUserAndRoles(ctx, uc.userRepository, uc.roleRepository, userID, unique...)
+	if err != nil {
+		return err
+	}
+	err = uc.userRoleRepository.ReplaceUserRoles(ctx, userID, unique)
+	if err != nil || !revokeTokens {
+		return err
+	}
+	return uc.revokeUserTokens.Execute(ctx, userID)
+}
@@ -0,0 +1,97 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/application/role"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type ReplaceUserRolesSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory        *factoryMock.MockRepositoryFactory
+	userRepository     *repoMock.MockUserRepository
+	roleRepository     *repoMock.MockRoleRepository
+	userRoleRepository *repoMock.MockUserRoleRepository
+	revokeUserTokens   *tokenMock.MockRevokeUserTokens
+
+	ctx              context.Context
+	replaceUserRoles ReplaceUserRoles
+}
+
+func TestReplaceUserRoles(t *testing.T) {
+	suite.Run(t, new(ReplaceUserRolesSuite))
+}
+
+func (s *ReplaceUserRolesSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.roleRepository = repoMock.NewMockRoleRepository(s.mockCtrl)
+	s.userRoleRepository = repoMock.NewMockUserRoleRepository(s.mockCtrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewRoleRepository().AnyTimes().Return(s.roleRepository)
+	s.repoFactory.EXPECT().NewUserRoleRepository().AnyTimes().Return(s.userRoleRepository)
+
+	s.ctx = context.Background()
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+	s.replaceUserRoles = NewReplaceUserRoles(s.repoFactory, s.revokeUserTokens)
+}
+
+func (s *ReplaceUserRolesSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ReplaceUserRolesSuite) TestReplaceOk() {
+	userId, first, second := uuid.New(), uuid.New(), uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.roleRepository.EXPECT().ExistsById(s.ctx, first).Return(true, nil).Times(1)
+	s.roleRepository.EXPECT().ExistsById(s.ctx, second).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().ReplaceUserRoles(s.ctx, userId, []uuid.UUID{first, second}).Return(nil).Times(1)
+
+	s.NoError(s.replaceUserRoles.Execute(s.ctx, userId, []uuid.UUID{first, second, first}, false))
+}
+
+func (s *ReplaceUserRolesSuite) TestReplaceRevokeTokens() {
+	userId := uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().ReplaceUserRoles(s.ctx, userId, []uuid.UUID{}).Return(nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, userId).Return(nil).Times(1)
+
+	s.NoError(s.replaceUserRoles.Execute(s.ctx, userId, nil, true))
+}
+
+func (s *ReplaceUserRolesSuite) TestReplaceWithNoRoles() {
+	userId := uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().ReplaceUserRoles(s.ctx, userId, []uuid.UUID{}).Return(nil).Times(1)
+
+	s.NoError(s.replaceUserRoles.Execute(s.ctx, userId, nil, false))
+}
+
+func (s *ReplaceUserRolesSuite) TestReplaceRoleNotFound() {
+	userId, roleId := uuid.New(), uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.roleRepository.EXPECT().ExistsById(s.ctx, roleId).Return(false, nil).Times(1)
+
+	s.ErrorIs(s.replaceUserRoles.Execute(s.ctx, userId, []uuid.UUID{roleId}, false), role.ErrRoleNotFound)
+}
+
+func (s *ReplaceUserRolesSuite) TestReplaceErr() {
+	userId := uuid.New()
+	s.userRepository.EXPECT().ExistsById(s.ctx, userId).Return(true, nil).Times(1)
+	s.userRoleRepository.EXPECT().ReplaceUserRoles(s.ctx, userId, gomock.Any()).Return(errors.New("connection refused")).Times(1)
+
+	s.EqualError(s.replaceUserRoles.Execute(s.ctx, userId, nil, false), "connection refused")
+}
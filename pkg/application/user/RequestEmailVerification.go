@@ -0,0 +1,100 @@
+//go:generate mockgen -source RequestEmailVerification.go -destination mock/RequestEmailVerification_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"net/url"
+	"time"
+)
+
+const DefaultEmailVerificationTTL = 24 * time.Hour
+
+// EmailVerificationConfig sets how long verification tokens last and the page the verification link points to.
+// The token is added to URL as the token query parameter. When URL is empty the email carries the bare token.
+type EmailVerificationConfig struct {
+	TTL time.Duration
+	URL string
+}
+
+// RequestEmailVerification mails a single-use verification token to the user owning email. Unknown emails,
+// disabled users and addresses already verified get no email and no error, so callers cannot tell which emails
+// are registered. Neither do addresses with a valid token, so asking again cannot flood their inbox.
+type RequestEmailVerification interface {
+	Execute(ctx context.Context, email string) error
+}
+
+func NewRequestEmailVerification(repoFactory factory.RepositoryFactory, mailer mail.Mailer, config EmailVerificationConfig) RequestEmailVerification {
+	if config.TTL == 0 {
+		config.TTL = DefaultEmailVerificationTTL
+	}
+	return requestEmailVerification{
+		userRepository:              repoFactory.NewUserRepository(),
+		verificationTokenRepository: repoFactory.NewEmailVerificationTokenRepository(),
+		mailer:                      mailer,
+		config:                      config,
+	}
+}
+
+type requestEmailVerification struct {
+	userRepository              repository.UserRepository
+	verificationTokenRepository repository.EmailVerificationTokenRepository
+	mailer                      mail.Mailer
+	config                      EmailVerificationConfig
+}
+
+func (uc requestEmailVerification) Execute(ctx context.Context, email string) error {
+	user, err := uc.userRepository.FindByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Enabled || user.EmailVerified {
+		return nil
+	}
+	pending, err := uc.verificationTokenRepository.HasPending(ctx, user.ID, user.Email, time.Now())
+	if err != nil || pending {
+		return err
+	}
+	value, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	_, err = uc.verificationTokenRepository.Create(ctx, &entity.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: token.HashOpaqueToken(value),
+		ExpiresAt: time.Now().Add(uc.config.TTL),
+	})
+	if err != nil {
+		return err
+	}
+	err = uc.mailer.Send(ctx, uc.message(user, value))
+	if err != nil {
+		return fmt.Errorf("could not send email verification: %w", err)
+	}
+	return nil
+}
+
+func (uc requestEmailVerification) message(user *entity.User, value string) mail.Message {
+	instructions := "Use this token to confirm your email address: " + value
+	if uc.config.URL != "" {
+		link := uc.config.URL + "?" + url.Values{"token": {value}}.Encode()
+		instructions = "Follow this link to confirm your email address: " + link
+	}
+	return mail.Message{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm that %s is the email address of your account %s.\n%s\n\n"+
+			"It expires in %s and works once. If you did not create this account, ignore this email.\n",
+			user.FirstName, user.Email, user.Username, instructions, uc.config.TTL),
+	}
+}
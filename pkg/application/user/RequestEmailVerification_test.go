@@ -0,0 +1,135 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	mailMock "github.com/golauth/golauth/pkg/domain/mail/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type RequestEmailVerificationSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory                 *factoryMock.MockRepositoryFactory
+	userRepository              *repoMock.MockUserRepository
+	verificationTokenRepository *repoMock.MockEmailVerificationTokenRepository
+	mailer                      *mailMock.MockMailer
+
+	ctx                      context.Context
+	requestEmailVerification RequestEmailVerification
+	user                     *entity.User
+}
+
+func TestRequestEmailVerification(t *testing.T) {
+	suite.Run(t, new(RequestEmailVerificationSuite))
+}
+
+func (s *RequestEmailVerificationSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.verificationTokenRepository = repoMock.NewMockEmailVerificationTokenRepository(s.mockCtrl)
+	s.mailer = mailMock.NewMockMailer(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewEmailVerificationTokenRepository().AnyTimes().Return(s.verificationTokenRepository)
+
+	s.ctx = context.Background()
+	s.requestEmailVerification = NewRequestEmailVerification(s.repoFactory, s.mailer, EmailVerificationConfig{URL: "https://app.example.com/verify"})
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", FirstName: "Admin", Email: "admin@example.com", Enabled: true}
+}
+
+func (s *RequestEmailVerificationSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *RequestEmailVerificationSuite) TestRequestMailsLinkWithToken() {
+	var stored *entity.EmailVerificationToken
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+	s.verificationTokenRepository.EXPECT().HasPending(s.ctx, s.user.ID, s.user.Email, gomock.Any()).Return(false, nil).Times(1)
+	s.verificationTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, t *entity.EmailVerificationToken) (*entity.EmailVerificationToken, error) {
+			stored = t
+			return t, nil
+		}).Times(1)
+	s.mailer.EXPECT().Send(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, message mail.Message) error {
+			s.Equal(s.user.Email, message.To)
+			start := strings.Index(message.Body, "https://app.example.com/verify?")
+			s.GreaterOrEqual(start, 0)
+			link, err := url.Parse(strings.Fields(message.Body[start:])[0])
+			s.NoError(err)
+			s.Equal(stored.TokenHash, token.HashOpaqueToken(link.Query().Get("token")))
+			return nil
+		}).Times(1)
+
+	err := s.requestEmailVerification.Execute(s.ctx, s.user.Email)
+	s.NoError(err)
+	s.Equal(s.user.ID, stored.UserID)
+	s.Equal(s.user.Email, stored.Email)
+	s.WithinDuration(time.Now().Add(DefaultEmailVerificationTTL), stored.ExpiresAt, time.Minute)
+}
+
+func (s *RequestEmailVerificationSuite) TestRequestUnknownEmail() {
+	s.userRepository.EXPECT().FindByEmail(s.ctx, "nobody@example.com").Return(nil, repository.ErrNotFound).Times(1)
+
+	err := s.requestEmailVerification.Execute(s.ctx, "nobody@example.com")
+	s.NoError(err)
+}
+
+func (s *RequestEmailVerificationSuite) TestRequestAlreadyVerified() {
+	s.user.EmailVerified = true
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+
+	err := s.requestEmailVerification.Execute(s.ctx, s.user.Email)
+	s.NoError(err)
+}
+
+func (s *RequestEmailVerificationSuite) TestRequestDisabledUser() {
+	s.user.Enabled = false
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+
+	err := s.requestEmailVerification.Execute(s.ctx, s.user.Email)
+	s.NoError(err)
+}
+
+func (s *RequestEmailVerificationSuite) TestRequestMailerError() {
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+	s.verificationTokenRepository.EXPECT().HasPending(s.ctx, s.user.ID, s.user.Email, gomock.Any()).Return(false, nil).Times(1)
+	s.verificationTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).Return(&entity.EmailVerificationToken{}, nil).Times(1)
+	s.mailer.EXPECT().Send(s.ctx, gomock.Any()).Return(errors.New("smtp down")).Times(1)
+
+	err := s.requestEmailVerification.Execute(s.ctx, s.user.Email)
+	s.ErrorContains(err, "smtp down")
+}
+
+func (s *RequestEmailVerificationSuite) TestRequestWhileTokenPending() {
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+	s.verificationTokenRepository.EXPECT().HasPending(s.ctx, s.user.ID, s.user.Email, gomock.Any()).Return(true, nil).Times(1)
+
+	err := s.requestEmailVerification.Execute(s.ctx, s.user.Email)
+	s.NoError(err)
+}
+
+func (s *RequestEmailVerificationSuite) TestRequestPendingError() {
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+	s.verificationTokenRepository.EXPECT().HasPending(s.ctx, s.user.ID, s.user.Email, gomock.Any()).Return(false, errors.New("connection refused")).Times(1)
+
+	err := s.requestEmailVerification.Execute(s.ctx, s.user.Email)
+	s.EqualError(err, "connection refused")
+}
@@ -0,0 +1,99 @@
+//go:generate mockgen -source RequestPasswordReset.go -destination mock/RequestPasswordReset_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"net/url"
+	"time"
+)
+
+const DefaultPasswordResetTTL = time.Hour
+
+// PasswordResetConfig sets how long reset tokens last and the page the reset link points to. The token is
+// added to URL as the token query parameter. When URL is empty the email carries the bare token.
+type PasswordResetConfig struct {
+	TTL time.Duration
+	URL string
+}
+
+// RequestPasswordReset mails a single-use reset token to the user owning email. Unknown emails and disabled
+// users get no email and no error, so callers cannot tell which emails are registered. Neither do users still
+// holding a valid token, so asking again cannot flood their inbox.
+type RequestPasswordReset interface {
+	Execute(ctx context.Context, email string) error
+}
+
+func NewRequestPasswordReset(repoFactory factory.RepositoryFactory, mailer mail.Mailer, config PasswordResetConfig) RequestPasswordReset {
+	if config.TTL == 0 {
+		config.TTL = DefaultPasswordResetTTL
+	}
+	return requestPasswordReset{
+		userRepository:       repoFactory.NewUserRepository(),
+		resetTokenRepository: repoFactory.NewPasswordResetTokenRepository(),
+		mailer:               mailer,
+		config:               config,
+	}
+}
+
+type requestPasswordReset struct {
+	userRepository       repository.UserRepository
+	resetTokenRepository repository.PasswordResetTokenRepository
+	mailer               mail.Mailer
+	config               PasswordResetConfig
+}
+
+func (uc requestPasswordReset) Execute(ctx context.Context, email string) error {
+	user, err := uc.userRepository.FindByEmail(ctx, email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Enabled {
+		return nil
+	}
+	pending, err := uc.resetTokenRepository.HasPending(ctx, user.ID, time.Now())
+	if err != nil || pending {
+		return err
+	}
+	value, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+	_, err = uc.resetTokenRepository.Create(ctx, &entity.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: token.HashOpaqueToken(value),
+		ExpiresAt: time.Now().Add(uc.config.TTL),
+	})
+	if err != nil {
+		return err
+	}
+	err = uc.mailer.Send(ctx, uc.message(user, value))
+	if err != nil {
+		return fmt.Errorf("could not send password reset email: %w", err)
+	}
+	return nil
+}
+
+func (uc requestPasswordReset) message(user *entity.User, value string) mail.Message {
+	instructions := "Use this token to choose a new password: " + value
+	if uc.config.URL != "" {
+		link := uc.config.URL + "?" + url.Values{"token": {value}}.Encode()
+		instructions = "Follow this link to choose a new password: " + link
+	}
+	return mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nSomeone asked to reset the password of your account %s.\n%s\n\n"+
+			"It expires in %s and works once. If you did not ask for it, ignore this email.\n",
+			user.FirstName, user.Username, instructions, uc.config.TTL),
+	}
+}
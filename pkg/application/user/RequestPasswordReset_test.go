@@ -0,0 +1,126 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	mailMock "github.com/golauth/golauth/pkg/domain/mail/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type RequestPasswordResetSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory          *factoryMock.MockRepositoryFactory
+	userRepository       *repoMock.MockUserRepository
+	resetTokenRepository *repoMock.MockPasswordResetTokenRepository
+	mailer               *mailMock.MockMailer
+
+	ctx                  context.Context
+	requestPasswordReset RequestPasswordReset
+	user                 *entity.User
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	suite.Run(t, new(RequestPasswordResetSuite))
+}
+
+func (s *RequestPasswordResetSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.resetTokenRepository = repoMock.NewMockPasswordResetTokenRepository(s.mockCtrl)
+	s.mailer = mailMock.NewMockMailer(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewPasswordResetTokenRepository().AnyTimes().Return(s.resetTokenRepository)
+
+	s.ctx = context.Background()
+	s.requestPasswordReset = NewRequestPasswordReset(s.repoFactory, s.mailer, PasswordResetConfig{URL: "https://app.example.com/reset"})
+	s.user = &entity.User{ID: uuid.New(), Username: "admin", FirstName: "Admin", Email: "admin@example.com", Enabled: true}
+}
+
+func (s *RequestPasswordResetSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *RequestPasswordResetSuite) TestRequestMailsLinkWithToken() {
+	var stored *entity.PasswordResetToken
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+	s.resetTokenRepository.EXPECT().HasPending(s.ctx, s.user.ID, gomock.Any()).Return(false, nil).Times(1)
+	s.resetTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, t *entity.PasswordResetToken) (*entity.PasswordResetToken, error) {
+			stored = t
+			return t, nil
+		}).Times(1)
+	s.mailer.EXPECT().Send(s.ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, message mail.Message) error {
+			s.Equal(s.user.Email, message.To)
+			start := strings.Index(message.Body, "https://app.example.com/reset?")
+			s.GreaterOrEqual(start, 0)
+			link, err := url.Parse(strings.Fields(message.Body[start:])[0])
+			s.NoError(err)
+			s.Equal(stored.TokenHash, token.HashOpaqueToken(link.Query().Get("token")))
+			return nil
+		}).Times(1)
+
+	err := s.requestPasswordReset.Execute(s.ctx, s.user.Email)
+	s.NoError(err)
+	s.Equal(s.user.ID, stored.UserID)
+	s.WithinDuration(time.Now().Add(DefaultPasswordResetTTL), stored.ExpiresAt, time.Minute)
+}
+
+func (s *RequestPasswordResetSuite) TestRequestUnknownEmail() {
+	s.userRepository.EXPECT().FindByEmail(s.ctx, "nobody@example.com").Return(nil, repository.ErrNotFound).Times(1)
+
+	err := s.requestPasswordReset.Execute(s.ctx, "nobody@example.com")
+	s.NoError(err)
+}
+
+func (s *RequestPasswordResetSuite) TestRequestDisabledUser() {
+	s.user.Enabled = false
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+
+	err := s.requestPasswordReset.Execute(s.ctx, s.user.Email)
+	s.NoError(err)
+}
+
+func (s *RequestPasswordResetSuite) TestRequestMailerError() {
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+	s.resetTokenRepository.EXPECT().HasPending(s.ctx, s.user.ID, gomock.Any()).Return(false, nil).Times(1)
+	s.resetTokenRepository.EXPECT().Create(s.ctx, gomock.Any()).Return(&entity.PasswordResetToken{}, nil).Times(1)
+	s.mailer.EXPECT().Send(s.ctx, gomock.Any()).Return(errors.New("smtp down")).Times(1)
+
+	err := s.requestPasswordReset.Execute(s.ctx, s.user.Email)
+	s.ErrorContains(err, "smtp down")
+}
+
+func (s *RequestPasswordResetSuite) TestRequestWhileTokenPending() {
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+	s.resetTokenRepository.EXPECT().HasPending(s.ctx, s.user.ID, gomock.Any()).Return(true, nil).Times(1)
+
+	err := s.requestPasswordReset.Execute(s.ctx, s.user.Email)
+	s.NoError(err)
+}
+
+func (s *RequestPasswordResetSuite) TestRequestPendingError() {
+	s.userRepository.EXPECT().FindByEmail(s.ctx, s.user.Email).Return(s.user, nil).Times(1)
+	s.resetTokenRepository.EXPECT().HasPending(s.ctx, s.user.ID, gomock.Any()).Return(false, errors.New("connection refused")).Times(1)
+
+	err := s.requestPasswordReset.Execute(s.ctx, s.user.Email)
+	s.EqualError(err, "connection refused")
+}
@@ -0,0 +1,89 @@
+//go:generate mockgen -source ResetPassword.go -destination mock/ResetPassword_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"time"
+)
+
+var ErrInvalidResetToken = errors.New("invalid or expired password reset token")
+
+// ResetPassword sets a new password for the owner of a reset token and revokes the tokens the account still
+// holds. A password the policy rejects leaves the reset token usable, but once the password is accepted the
+// token is consumed even if a later step fails.
+type ResetPassword interface {
+	Execute(ctx context.Context, resetToken string, newPassword string) error
+}
+
+func NewResetPassword(repoFactory factory.RepositoryFactory, revokeUserTokens token.RevokeUserTokens, passwordPolicy PasswordPolicy) ResetPassword {
+	return resetPassword{
+		userRepository:       repoFactory.NewUserRepository(),
+		resetTokenRepository: repoFactory.NewPasswordResetTokenRepository(),
+		revokeUserTokens:     revokeUserTokens,
+		passwordPolicy:       passwordPolicy,
+	}
+}
+
+type resetPassword struct {
+	userRepository       repository.UserRepository
+	resetTokenRepository repository.PasswordResetTokenRepository
+	revokeUserTokens     token.RevokeUserTokens
+	passwordPolicy       PasswordPolicy
+}
+
+func (uc resetPassword) Execute(ctx context.Context, resetToken string, newPassword string) error {
+	if newPassword == "" {
+		return ErrPasswordRequired
+	}
+	if resetToken == "" {
+		return ErrInvalidResetToken
+	}
+	tokenHash := token.HashOpaqueToken(resetToken)
+	userID, err := uc.resetTokenRepository.FindUserID(ctx, tokenHash, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	user, err := uc.userRepository.FindByID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+	}
+	if err != nil {
+		return err
+	}
+	err = uc.passwordPolicy.Validate(ctx, user, newPassword)
+	if err != nil {
+		return err
+	}
+	// consuming checks the token again, so two concurrent resets cannot both use it
+	userID, err = uc.resetTokenRepository.Consume(ctx, tokenHash, time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	hash, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	err = uc.userRepository.ChangePassword(ctx, userID, hash)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrUserNotFound, userID)
+	}
+	if err != nil {
+		return err
+	}
+	err = uc.revokeUserTokens.Execute(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return uc.passwordPolicy.Remember(ctx, userID, hash)
+}
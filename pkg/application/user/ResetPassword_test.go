@@ -0,0 +1,118 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/application/token"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+type ResetPasswordSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory          *factoryMock.MockRepositoryFactory
+	userRepository       *repoMock.MockUserRepository
+	resetTokenRepository *repoMock.MockPasswordResetTokenRepository
+	historyRepository    *repoMock.MockPasswordHistoryRepository
+	revokeUserTokens     *tokenMock.MockRevokeUserTokens
+
+	ctx           context.Context
+	resetPassword ResetPassword
+	userID        uuid.UUID
+	user          *entity.User
+}
+
+func TestResetPassword(t *testing.T) {
+	suite.Run(t, new(ResetPasswordSuite))
+}
+
+func (s *ResetPasswordSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.resetTokenRepository = repoMock.NewMockPasswordResetTokenRepository(s.mockCtrl)
+	s.historyRepository = repoMock.NewMockPasswordHistoryRepository(s.mockCtrl)
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewPasswordResetTokenRepository().AnyTimes().Return(s.resetTokenRepository)
+	s.repoFactory.EXPECT().NewPasswordHistoryRepository().AnyTimes().Return(s.historyRepository)
+
+	s.ctx = context.Background()
+	s.resetPassword = NewResetPassword(s.repoFactory, s.revokeUserTokens, NewPasswordPolicy(s.repoFactory, PasswordPolicyConfig{}))
+	s.userID = uuid.New()
+	s.user = &entity.User{ID: s.userID, Username: "admin", Email: "admin@example.com"}
+}
+
+func (s *ResetPasswordSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *ResetPasswordSuite) TestResetOk() {
+	s.resetTokenRepository.EXPECT().FindUserID(s.ctx, token.HashOpaqueToken("reset-token"), gomock.Any()).Return(s.userID, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.userID).Return(s.user, nil).Times(1)
+	s.resetTokenRepository.EXPECT().Consume(s.ctx, token.HashOpaqueToken("reset-token"), gomock.Any()).Return(s.userID, nil).Times(1)
+	s.userRepository.EXPECT().ChangePassword(s.ctx, s.userID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string) error {
+			s.NoError(bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-secret")))
+			return nil
+		}).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(s.ctx, s.userID).Return(nil).Times(1)
+	s.historyRepository.EXPECT().Add(s.ctx, s.userID, gomock.Any(), 1).Return(nil).Times(1)
+
+	err := s.resetPassword.Execute(s.ctx, "reset-token", "new-secret")
+	s.NoError(err)
+}
+
+func (s *ResetPasswordSuite) TestResetPolicyKeepsToken() {
+	s.resetTokenRepository.EXPECT().FindUserID(s.ctx, token.HashOpaqueToken("reset-token"), gomock.Any()).Return(s.userID, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.userID).Return(s.user, nil).Times(1)
+
+	err := s.resetPassword.Execute(s.ctx, "reset-token", "short")
+	s.ErrorIs(err, ErrPasswordPolicy)
+}
+
+func (s *ResetPasswordSuite) TestResetTokenUsedConcurrently() {
+	s.resetTokenRepository.EXPECT().FindUserID(s.ctx, gomock.Any(), gomock.Any()).Return(s.userID, nil).Times(1)
+	s.userRepository.EXPECT().FindByID(s.ctx, s.userID).Return(s.user, nil).Times(1)
+	s.resetTokenRepository.EXPECT().Consume(s.ctx, gomock.Any(), gomock.Any()).Return(uuid.Nil, repository.ErrNotFound).Times(1)
+
+	err := s.resetPassword.Execute(s.ctx, "reset-token", "new-secret")
+	s.ErrorIs(err, ErrInvalidResetToken)
+}
+
+func (s *ResetPasswordSuite) TestResetInvalidToken() {
+	s.resetTokenRepository.EXPECT().FindUserID(s.ctx, token.HashOpaqueToken("used-token"), gomock.Any()).Return(uuid.Nil, repository.ErrNotFound).Times(1)
+
+	err := s.resetPassword.Execute(s.ctx, "used-token", "new-secret")
+	s.ErrorIs(err, ErrInvalidResetToken)
+}
+
+func (s *ResetPasswordSuite) TestResetEmptyToken() {
+	err := s.resetPassword.Execute(s.ctx, "", "new-secret")
+	s.ErrorIs(err, ErrInvalidResetToken)
+}
+
+func (s *ResetPasswordSuite) TestResetEmptyPasswordKeepsToken() {
+	err := s.resetPassword.Execute(s.ctx, "reset-token", "")
+	s.ErrorIs(err, ErrPasswordRequired)
+}
+
+func (s *ResetPasswordSuite) TestResetRepositoryError() {
+	s.resetTokenRepository.EXPECT().FindUserID(s.ctx, gomock.Any(), gomock.Any()).Return(uuid.Nil, errors.New("db down")).Times(1)
+
+	err := s.resetPassword.Execute(s.ctx, "reset-token", "new-secret")
+	s.EqualError(err, "db down")
+}
@@ -0,0 +1,39 @@
+//go:generate mockgen -source UpdateUser.go -destination mock/UpdateUser_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/google/uuid"
+)
+
+// UpdateUser changes the profile fields of a user. Username, password and status are left untouched.
+type UpdateUser interface {
+	Execute(ctx context.Context, id uuid.UUID, input *entity.User) (*entity.User, error)
+}
+
+func NewUpdateUser(repo repository.UserRepository) UpdateUser {
+	return updateUser{repo: repo}
+}
+
+type updateUser struct {
+	repo repository.UserRepository
+}
+
+func (uc updateUser) Execute(ctx context.Context, id uuid.UUID, input *entity.User) (*entity.User, error) {
+	input.ID = id
+	user, err := uc.repo.Update(ctx, input)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	if errors.Is(err, repository.ErrDuplicate) {
+		return nil, ErrUserAlreadyExists
+	}
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
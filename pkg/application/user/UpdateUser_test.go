@@ -0,0 +1,80 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type UpdateUserSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	userRepository *mock.MockUserRepository
+
+	ctx        context.Context
+	updateUser UpdateUser
+}
+
+func TestUpdateUser(t *testing.T) {
+	suite.Run(t, new(UpdateUserSuite))
+}
+
+func (s *UpdateUserSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.userRepository = mock.NewMockUserRepository(s.mockCtrl)
+
+	s.ctx = context.Background()
+	s.updateUser = NewUpdateUser(s.userRepository)
+}
+
+func (s *UpdateUserSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *UpdateUserSuite) TestUpdateOk() {
+	id := uuid.New()
+	input := &entity.User{FirstName: "New", LastName: "Name", Email: "new@goauth.org", Document: "999"}
+	s.userRepository.EXPECT().Update(s.ctx, &entity.User{ID: id, FirstName: "New", LastName: "Name", Email: "new@goauth.org", Document: "999"}).
+		DoAndReturn(func(_ context.Context, u *entity.User) (*entity.User, error) {
+			u.Username = "admin"
+			return u, nil
+		}).Times(1)
+
+	output, err := s.updateUser.Execute(s.ctx, id, input)
+	s.NoError(err)
+	s.Equal(id, output.ID)
+	s.Equal("admin", output.Username)
+}
+
+func (s *UpdateUserSuite) TestUpdateNotFound() {
+	id := uuid.New()
+	s.userRepository.EXPECT().Update(s.ctx, gomock.Any()).Return(nil, fmt.Errorf("could not update user: %w", repository.ErrNotFound)).Times(1)
+
+	_, err := s.updateUser.Execute(s.ctx, id, &entity.User{})
+	s.ErrorIs(err, ErrUserNotFound)
+}
+
+func (s *UpdateUserSuite) TestUpdateDuplicateEmail() {
+	s.userRepository.EXPECT().Update(s.ctx, gomock.Any()).Return(nil, fmt.Errorf("could not update user: %w", repository.ErrDuplicate)).Times(1)
+
+	_, err := s.updateUser.Execute(s.ctx, uuid.New(), &entity.User{})
+	s.ErrorIs(err, ErrUserAlreadyExists)
+}
+
+func (s *UpdateUserSuite) TestUpdateErr() {
+	s.userRepository.EXPECT().Update(s.ctx, gomock.Any()).Return(nil, errors.New("connection refused")).Times(1)
+
+	_, err := s.updateUser.Execute(s.ctx, uuid.New(), &entity.User{})
+	s.EqualError(err, "connection refused")
+}
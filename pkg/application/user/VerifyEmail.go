@@ -0,0 +1,50 @@
+//go:generate mockgen -source VerifyEmail.go -destination mock/VerifyEmail_mock.go -package mock
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"time"
+)
+
+var ErrInvalidVerificationToken = errors.New("invalid or expired email verification token")
+
+// VerifyEmail marks the email address of the owner of a verification token as verified. The token only verifies
+// the address it was mailed to, so it is refused once the user has changed their email.
+type VerifyEmail interface {
+	Execute(ctx context.Context, verificationToken string) error
+}
+
+func NewVerifyEmail(repoFactory factory.RepositoryFactory) VerifyEmail {
+	return verifyEmail{
+		userRepository:              repoFactory.NewUserRepository(),
+		verificationTokenRepository: repoFactory.NewEmailVerificationTokenRepository(),
+	}
+}
+
+type verifyEmail struct {
+	userRepository              repository.UserRepository
+	verificationTokenRepository repository.EmailVerificationTokenRepository
+}
+
+func (uc verifyEmail) Execute(ctx context.Context, verificationToken string) error {
+	if verificationToken == "" {
+		return ErrInvalidVerificationToken
+	}
+	stored, err := uc.verificationTokenRepository.Consume(ctx, token.HashOpaqueToken(verificationToken), time.Now())
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrInvalidVerificationToken
+	}
+	if err != nil {
+		return err
+	}
+	err = uc.userRepository.MarkEmailVerified(ctx, stored.UserID, stored.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: email of user %s changed or user removed", ErrInvalidVerificationToken, stored.UserID)
+	}
+	return err
+}
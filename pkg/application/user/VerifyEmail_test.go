@@ -0,0 +1,93 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type VerifyEmailSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	repoFactory                 *factoryMock.MockRepositoryFactory
+	userRepository              *repoMock.MockUserRepository
+	verificationTokenRepository *repoMock.MockEmailVerificationTokenRepository
+
+	ctx         context.Context
+	verifyEmail VerifyEmail
+	userID      uuid.UUID
+}
+
+func TestVerifyEmail(t *testing.T) {
+	suite.Run(t, new(VerifyEmailSuite))
+}
+
+func (s *VerifyEmailSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.mockCtrl)
+	s.userRepository = repoMock.NewMockUserRepository(s.mockCtrl)
+	s.verificationTokenRepository = repoMock.NewMockEmailVerificationTokenRepository(s.mockCtrl)
+	s.repoFactory.EXPECT().NewUserRepository().AnyTimes().Return(s.userRepository)
+	s.repoFactory.EXPECT().NewEmailVerificationTokenRepository().AnyTimes().Return(s.verificationTokenRepository)
+
+	s.ctx = context.Background()
+	s.verifyEmail = NewVerifyEmail(s.repoFactory)
+	s.userID = uuid.New()
+}
+
+func (s *VerifyEmailSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *VerifyEmailSuite) stored() *entity.EmailVerificationToken {
+	return &entity.EmailVerificationToken{UserID: s.userID, Email: "john.doe@example.com"}
+}
+
+func (s *VerifyEmailSuite) TestVerifyOk() {
+	s.verificationTokenRepository.EXPECT().Consume(s.ctx, token.HashOpaqueToken("verification-token"), gomock.Any()).Return(s.stored(), nil).Times(1)
+	s.userRepository.EXPECT().MarkEmailVerified(s.ctx, s.userID, "john.doe@example.com").Return(nil).Times(1)
+
+	err := s.verifyEmail.Execute(s.ctx, "verification-token")
+	s.NoError(err)
+}
+
+func (s *VerifyEmailSuite) TestVerifyEmptyToken() {
+	err := s.verifyEmail.Execute(s.ctx, "")
+	s.ErrorIs(err, ErrInvalidVerificationToken)
+}
+
+func (s *VerifyEmailSuite) TestVerifyInvalidToken() {
+	s.verificationTokenRepository.EXPECT().Consume(s.ctx, gomock.Any(), gomock.Any()).Return(nil, repository.ErrNotFound).Times(1)
+
+	err := s.verifyEmail.Execute(s.ctx, "verification-token")
+	s.ErrorIs(err, ErrInvalidVerificationToken)
+}
+
+// TestVerifyAfterEmailChanged covers a user asking for a token, changing their email to an address they do not
+// own and then confirming: the new address must stay unverified.
+func (s *VerifyEmailSuite) TestVerifyAfterEmailChanged() {
+	s.verificationTokenRepository.EXPECT().Consume(s.ctx, gomock.Any(), gomock.Any()).Return(s.stored(), nil).Times(1)
+	s.userRepository.EXPECT().MarkEmailVerified(s.ctx, s.userID, "john.doe@example.com").Return(repository.ErrNotFound).Times(1)
+
+	err := s.verifyEmail.Execute(s.ctx, "verification-token")
+	s.ErrorIs(err, ErrInvalidVerificationToken)
+}
+
+func (s *VerifyEmailSuite) TestVerifyConsumeError() {
+	s.verificationTokenRepository.EXPECT().Consume(s.ctx, gomock.Any(), gomock.Any()).Return(nil, errors.New("db down")).Times(1)
+
+	err := s.verifyEmail.Execute(s.ctx, "verification-token")
+	s.ErrorContains(err, "db down")
+}
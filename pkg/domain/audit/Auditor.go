@@ -0,0 +1,27 @@
+//go:generate mockgen -source Auditor.go -destination mock/Auditor_mock.go -package mock
+package audit
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	EventLoginLocked   = "login_locked"
+	EventLoginUnlocked = "login_unlocked"
+)
+
+// Event is a security relevant action, such as an account being locked after too many failed logins. Actor is
+// who caused it, when it was someone else than the subject.
+type Event struct {
+	Type       string
+	Time       time.Time
+	Actor      string
+	Subject    string
+	Attributes map[string]string
+}
+
+// Auditor keeps the audit trail of golauth.
+type Auditor interface {
+	Record(ctx context.Context, event Event) error
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type AuthorizationCode struct {
+	ID                  uuid.UUID
+	CodeHash            string
+	ClientID            string
+	UserID              uuid.UUID
+	RedirectURI         string
+	Scope               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string
+	ExpiresAt           time.Time
+	UsedAt              *time.Time
+	CreationDate        time.Time
+}
+
+func (c AuthorizationCode) IsUsed() bool {
+	return c.UsedAt != nil
+}
+
+func (c AuthorizationCode) IsExpiredAt(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
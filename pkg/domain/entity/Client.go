@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type Client struct {
+	ID               uuid.UUID
+	ClientID         string
+	Secret           string
+	Name             string
+	GrantTypes       []string
+	Scopes           []string
+	Authorities      []string
+	RedirectURIs     []string
+	Audiences        []string
+	SigningAlgorithm string
+	TokenTTL         int
+	Enabled          bool
+	CreationDate     time.Time
+}
+
+func (c Client) AllowsGrantType(grantType string) bool {
+	for _, g := range c.GrantTypes {
+		if g == grantType {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Client) AllowsRedirectURI(redirectURI string) bool {
+	for _, u := range c.RedirectURIs {
+		if u == redirectURI {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublic reports whether the client has no secret, as browser and mobile apps cannot keep one.
+func (c Client) IsPublic() bool {
+	return c.Secret == ""
+}
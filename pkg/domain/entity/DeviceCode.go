@@ -0,0 +1,40 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// DeviceCode is a pending device authorization. The device polls with the device code while the user
+// approves or denies the request by typing the user code on another device.
+type DeviceCode struct {
+	ID             uuid.UUID
+	DeviceCodeHash string
+	UserCode       string
+	ClientID       string
+	Scope          string
+	UserID         *uuid.UUID
+	Denied         bool
+	Interval       int
+	ExpiresAt      time.Time
+	LastPolledAt   *time.Time
+	ApprovedAt     *time.Time
+	UsedAt         *time.Time
+	CreationDate   time.Time
+}
+
+func (c DeviceCode) IsApproved() bool {
+	return c.UserID != nil && !c.Denied
+}
+
+func (c DeviceCode) IsPending() bool {
+	return c.UserID == nil && !c.Denied
+}
+
+func (c DeviceCode) IsUsed() bool {
+	return c.UsedAt != nil
+}
+
+func (c DeviceCode) IsExpiredAt(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
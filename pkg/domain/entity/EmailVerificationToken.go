@@ -0,0 +1,19 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// EmailVerificationToken proves that a user can read mail sent to Email, the address on their account when the
+// token was issued. Only the hash of the token mailed to the user is stored, and it can be used once before it
+// expires.
+type EmailVerificationToken struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	Email        string
+	TokenHash    string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreationDate time.Time
+}
@@ -0,0 +1,22 @@
+package entity
+
+import (
+	"time"
+)
+
+const (
+	LoginFailureKindUsername = "username"
+	LoginFailureKindIP       = "ip"
+	LoginFailureKindEmail    = "email"
+	LoginFailureKindMailIP   = "mail_ip"
+)
+
+// LoginFailure counts the failed password logins of a username or client IP. Logins of the subject are refused
+// until LockedUntil, when set. The email kinds count the reset and verification emails asked for instead.
+type LoginFailure struct {
+	Kind        string
+	Subject     string
+	Failures    int
+	LastFailure time.Time
+	LockedUntil *time.Time
+}
@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// PasswordResetToken lets the owner of a forgotten password choose a new one. Only the hash of the token
+// mailed to the user is stored, and it can be used once before it expires.
+type PasswordResetToken struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	TokenHash    string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreationDate time.Time
+}
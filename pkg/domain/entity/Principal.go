@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"time"
+)
+
+// Principal is the caller authenticated by an access token: a user, or a client acting on its own behalf.
+type Principal struct {
+	Subject     string
+	Username    string
+	Authorities []string
+	Scopes      []string
+	ClientID    string
+	ExpiresAt   time.Time
+}
+
+// HasAnyAuthority reports whether the principal holds at least one of the given authorities.
+func (p Principal) HasAnyAuthority(authorities ...string) bool {
+	for _, required := range authorities {
+		for _, a := range p.Authorities {
+			if a == required {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type RefreshToken struct {
+	ID           uuid.UUID
+	FamilyID     uuid.UUID
+	UserID       uuid.UUID
+	TokenHash    string
+	ClientID     string  // client the tokens were issued to, empty for the password grant
+	Scope        *string // scope the tokens are limited to, nil when issued with every authority of the user
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	RevokedAt    *time.Time
+	CreationDate time.Time
+}
+
+func (t RefreshToken) IsUsed() bool {
+	return t.UsedAt != nil
+}
+
+func (t RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+func (t RefreshToken) IsExpiredAt(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
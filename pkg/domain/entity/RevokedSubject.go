@@ -0,0 +1,13 @@
+package entity
+
+import (
+	"time"
+)
+
+// RevokedSubject revokes every token of a subject issued up to RevokedAt. It is kept until ExpiresAt,
+// when the last of those tokens has expired.
+type RevokedSubject struct {
+	Subject   string
+	RevokedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,11 @@
+package entity
+
+import (
+	"time"
+)
+
+type RevokedToken struct {
+	JTI          string
+	ExpiresAt    time.Time
+	CreationDate time.Time
+}
@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"crypto"
+	"time"
+)
+
+// SigningKey holds the private key of a signing algorithm: *rsa.PrivateKey for RS and PS, *ecdsa.PrivateKey
+// for ES, ed25519.PrivateKey for EdDSA and the raw secret ([]byte) for HS.
+type SigningKey struct {
+	ID           string
+	Algorithm    string
+	PrivateKey   crypto.PrivateKey
+	CreationDate time.Time
+	RetiredAt    *time.Time
+}
+
+func NewSigningKey(id string, algorithm string, privateKey crypto.PrivateKey) *SigningKey {
+	return &SigningKey{
+		ID:         id,
+		Algorithm:  algorithm,
+		PrivateKey: privateKey,
+	}
+}
+
+func (k SigningKey) IsRetired() bool {
+	return k.RetiredAt != nil
+}
+
+// IsSymmetric reports whether the key is a shared secret, which must never be published.
+func (k SigningKey) IsSymmetric() bool {
+	_, ok := k.PrivateKey.([]byte)
+	return ok
+}
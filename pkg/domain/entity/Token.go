@@ -1,5 +1,11 @@
 package entity
 
+import "time"
+
 type Token struct {
-	AccessToken string
+	AccessToken     string
+	ExpiresIn       time.Duration
+	RefreshToken    string
+	IDToken         string
+	IssuedTokenType string
 }
@@ -7,6 +7,6 @@ import (
 
 type RoleAuthority struct {
 	RoleID       uuid.UUID
-	AuthorityID  string
+	AuthorityID  uuid.UUID
 	CreationDate time.Time
 }
@@ -0,0 +1,8 @@
+package entity
+
+// RoleUsage tells how many users hold a role and which authorities it grants.
+type RoleUsage struct {
+	Role        Role
+	Users       int
+	Authorities []string
+}
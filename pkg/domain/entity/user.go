@@ -6,13 +6,14 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID
-	Username     string
-	FirstName    string
-	LastName     string
-	Email        string
-	Document     string
-	Password     string
-	Enabled      bool
-	CreationDate time.Time
+	ID            uuid.UUID
+	Username      string
+	FirstName     string
+	LastName      string
+	Email         string
+	Document      string
+	Password      string
+	Enabled       bool
+	EmailVerified bool
+	CreationDate  time.Time
 }
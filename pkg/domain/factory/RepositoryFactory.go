@@ -6,7 +6,20 @@ import (
 )
 
 type RepositoryFactory interface {
+	NewAuthorityRepository() repository.AuthorityRepository
+	NewAuthorizationCodeRepository() repository.AuthorizationCodeRepository
+	NewClientRepository() repository.ClientRepository
+	NewDeviceCodeRepository() repository.DeviceCodeRepository
+	NewEmailVerificationTokenRepository() repository.EmailVerificationTokenRepository
+	NewLoginFailureRepository() repository.LoginFailureRepository
+	NewPasswordHistoryRepository() repository.PasswordHistoryRepository
+	NewPasswordResetTokenRepository() repository.PasswordResetTokenRepository
+	NewRefreshTokenRepository() repository.RefreshTokenRepository
+	NewRevokedSubjectRepository() repository.RevokedSubjectRepository
+	NewRevokedTokenRepository() repository.RevokedTokenRepository
+	NewRoleAuthorityRepository() repository.RoleAuthorityRepository
 	NewRoleRepository() repository.RoleRepository
+	NewSigningKeyRepository() repository.SigningKeyRepository
 	NewUserAuthorityRepository() repository.UserAuthorityRepository
 	NewUserRepository() repository.UserRepository
 	NewUserRoleRepository() repository.UserRoleRepository
@@ -0,0 +1,18 @@
+//go:generate mockgen -source Mailer.go -destination mock/Mailer_mock.go -package mock
+package mail
+
+import (
+	"context"
+)
+
+// Message is a plain text email to a single recipient.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers the emails golauth sends to users, such as password reset links.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
@@ -0,0 +1,16 @@
+//go:generate mockgen -source AuthorityRepository.go -destination mock/AuthorityRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/google/uuid"
+)
+
+type AuthorityRepository interface {
+	FindAll(ctx context.Context) ([]*entity.Authority, error)
+	Create(ctx context.Context, authority *entity.Authority) (*entity.Authority, error)
+	Edit(ctx context.Context, authority *entity.Authority) error
+	ChangeStatus(ctx context.Context, id uuid.UUID, enabled bool) error
+	ExistsById(ctx context.Context, id uuid.UUID) (bool, error)
+}
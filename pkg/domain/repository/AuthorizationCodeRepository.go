@@ -0,0 +1,14 @@
+//go:generate mockgen -source AuthorizationCodeRepository.go -destination mock/AuthorizationCodeRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/google/uuid"
+)
+
+type AuthorizationCodeRepository interface {
+	Create(ctx context.Context, code *entity.AuthorizationCode) (*entity.AuthorizationCode, error)
+	FindByHash(ctx context.Context, hash string) (*entity.AuthorizationCode, error)
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,12 @@
+//go:generate mockgen -source ClientRepository.go -destination mock/ClientRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+type ClientRepository interface {
+	FindByClientID(ctx context.Context, clientID string) (*entity.Client, error)
+	Create(ctx context.Context, client *entity.Client) (*entity.Client, error)
+}
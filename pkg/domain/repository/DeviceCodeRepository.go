@@ -0,0 +1,19 @@
+//go:generate mockgen -source DeviceCodeRepository.go -destination mock/DeviceCodeRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/google/uuid"
+)
+
+type DeviceCodeRepository interface {
+	Create(ctx context.Context, code *entity.DeviceCode) (*entity.DeviceCode, error)
+	FindByHash(ctx context.Context, hash string) (*entity.DeviceCode, error)
+	FindByUserCode(ctx context.Context, userCode string) (*entity.DeviceCode, error)
+	Approve(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error)
+	Deny(ctx context.Context, id uuid.UUID) (bool, error)
+	Poll(ctx context.Context, id uuid.UUID) (bool, error)
+	SlowDown(ctx context.Context, id uuid.UUID, increment int) error
+	MarkUsed(ctx context.Context, id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,19 @@
+//go:generate mockgen -source EmailVerificationTokenRepository.go -destination mock/EmailVerificationTokenRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/google/uuid"
+	"time"
+)
+
+type EmailVerificationTokenRepository interface {
+	// Create stores a verification token and invalidates the tokens issued to the same user before it.
+	Create(ctx context.Context, token *entity.EmailVerificationToken) (*entity.EmailVerificationToken, error)
+	// HasPending tells whether the user holds a token for email neither used nor expired at now.
+	HasPending(ctx context.Context, userID uuid.UUID, email string, now time.Time) (bool, error)
+	// Consume marks the token with the given hash as used and returns it. It fails with ErrNotFound when the
+	// token is unknown, already used or expired at now.
+	Consume(ctx context.Context, hash string, now time.Time) (*entity.EmailVerificationToken, error)
+}
@@ -0,0 +1,24 @@
+//go:generate mockgen -source LoginFailureRepository.go -destination mock/LoginFailureRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"time"
+)
+
+type LoginFailureRepository interface {
+	// Find returns the failures of the subject, or ErrNotFound when it has none.
+	Find(ctx context.Context, kind string, subject string) (*entity.LoginFailure, error)
+	// RecordAttempt counts a login attempt of the subject at now, unless it is locked at now, and returns the
+	// failures with the attempt. Failures older than since are forgotten and the count starts again from one.
+	RecordAttempt(ctx context.Context, kind string, subject string, now time.Time, since time.Time) (*entity.LoginFailure, error)
+	// ForgetAttempt uncounts an attempt of the subject that turned out to be a successful login.
+	ForgetAttempt(ctx context.Context, kind string, subject string) error
+	// Lock refuses the logins of the subject until the given time, never shortening a longer lock.
+	Lock(ctx context.Context, kind string, subject string, until time.Time) error
+	// Delete forgets the failures and lock of the subject, failing with ErrNotFound when it has none.
+	Delete(ctx context.Context, kind string, subject string) error
+	// FindLocked returns the subjects still locked at now, the ones unlocked first coming first.
+	FindLocked(ctx context.Context, now time.Time) ([]*entity.LoginFailure, error)
+}
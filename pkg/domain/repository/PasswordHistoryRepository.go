@@ -0,0 +1,14 @@
+//go:generate mockgen -source PasswordHistoryRepository.go -destination mock/PasswordHistoryRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/google/uuid"
+)
+
+type PasswordHistoryRepository interface {
+	// Add stores hash as the newest password of the user and drops all but the newest keep hashes.
+	Add(ctx context.Context, userID uuid.UUID, hash string, keep int) error
+	// FindLatest returns the newest limit password hashes of the user, newest first.
+	FindLatest(ctx context.Context, userID uuid.UUID, limit int) ([]string, error)
+}
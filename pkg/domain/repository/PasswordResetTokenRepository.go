@@ -0,0 +1,22 @@
+//go:generate mockgen -source PasswordResetTokenRepository.go -destination mock/PasswordResetTokenRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PasswordResetTokenRepository interface {
+	// Create stores a reset token and invalidates the tokens issued to the same user before it.
+	Create(ctx context.Context, token *entity.PasswordResetToken) (*entity.PasswordResetToken, error)
+	// HasPending tells whether the user holds a token neither used nor expired at now.
+	HasPending(ctx context.Context, userID uuid.UUID, now time.Time) (bool, error)
+	// FindUserID returns the user of the token with the given hash without using it. It fails with ErrNotFound
+	// when the token is unknown, already used or expired at now.
+	FindUserID(ctx context.Context, hash string, now time.Time) (uuid.UUID, error)
+	// Consume marks the token with the given hash as used and returns its user. It fails with ErrNotFound
+	// when the token is unknown, already used or expired at now.
+	Consume(ctx context.Context, hash string, now time.Time) (uuid.UUID, error)
+}
@@ -0,0 +1,14 @@
+//go:generate mockgen -source RevokedSubjectRepository.go -destination mock/RevokedSubjectRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"time"
+)
+
+type RevokedSubjectRepository interface {
+	Save(ctx context.Context, subject *entity.RevokedSubject) (*entity.RevokedSubject, error)
+	FindActive(ctx context.Context, now time.Time) ([]*entity.RevokedSubject, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
@@ -0,0 +1,14 @@
+//go:generate mockgen -source RevokedTokenRepository.go -destination mock/RevokedTokenRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"time"
+)
+
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, token *entity.RevokedToken) (*entity.RevokedToken, error)
+	FindActive(ctx context.Context, now time.Time) ([]*entity.RevokedToken, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
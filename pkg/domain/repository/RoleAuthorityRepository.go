@@ -0,0 +1,14 @@
+//go:generate mockgen -source RoleAuthorityRepository.go -destination mock/RoleAuthorityRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/google/uuid"
+)
+
+type RoleAuthorityRepository interface {
+	AddRoleAuthority(ctx context.Context, roleId uuid.UUID, authorityId uuid.UUID) error
+	RemoveRoleAuthority(ctx context.Context, roleId uuid.UUID, authorityId uuid.UUID) error
+	FindAuthoritiesByRoleID(ctx context.Context, roleId uuid.UUID) ([]*entity.Authority, error)
+}
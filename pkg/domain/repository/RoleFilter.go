@@ -0,0 +1,17 @@
+package repository
+
+const (
+	RoleSortName         = "name"
+	RoleSortCreationDate = "creationDate"
+)
+
+// RoleFilter narrows and orders a role listing. Name matches partially and ignoring case,
+// and zero values are not applied.
+type RoleFilter struct {
+	Name    string
+	Enabled *bool
+	Sort    string
+	Desc    bool
+	Offset  int
+	Limit   int
+}
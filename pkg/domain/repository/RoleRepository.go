@@ -13,4 +13,7 @@ type RoleRepository interface {
 	Edit(ctx context.Context, role *entity.Role) error
 	ChangeStatus(ctx context.Context, id uuid.UUID, enabled bool) error
 	ExistsById(ctx context.Context, id uuid.UUID) (bool, error)
+	FindAll(ctx context.Context, filter RoleFilter) ([]*entity.Role, int, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindUsage(ctx context.Context) ([]*entity.RoleUsage, error)
 }
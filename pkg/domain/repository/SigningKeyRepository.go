@@ -0,0 +1,18 @@
+//go:generate mockgen -source SigningKeyRepository.go -destination mock/SigningKeyRepository_mock.go -package mock
+package repository
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"time"
+)
+
+type SigningKeyRepository interface {
+	FindAll(ctx context.Context) ([]*entity.SigningKey, error)
+	Create(ctx context.Context, key *entity.SigningKey) (*entity.SigningKey, error)
+	Retire(ctx context.Context, id string) error
+	// Rotate stores the key as the active key of its algorithm and retires the previous one, unless that one is
+	// younger than the interval, or the interval is zero. It reports whether the key was stored, and stores a
+	// single key when several replicas rotate at the same time.
+	Rotate(ctx context.Context, key *entity.SigningKey, interval time.Duration) (bool, error)
+}
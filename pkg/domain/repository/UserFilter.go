@@ -0,0 +1,22 @@
+package repository
+
+const (
+	UserSortUsername     = "username"
+	UserSortFirstName    = "firstName"
+	UserSortLastName     = "lastName"
+	UserSortEmail        = "email"
+	UserSortCreationDate = "creationDate"
+)
+
+// UserFilter narrows and orders a user listing. Username and Email match partially and ignoring case,
+// Role matches a role name, and zero values are not applied.
+type UserFilter struct {
+	Username string
+	Email    string
+	Enabled  *bool
+	Role     string
+	Sort     string
+	Desc     bool
+	Offset   int
+	Limit    int
+}
@@ -10,5 +10,16 @@ import (
 type UserRepository interface {
 	FindByUsername(ctx context.Context, username string) (*entity.User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*entity.User, error)
+	FindByEmail(ctx context.Context, email string) (*entity.User, error)
 	Create(ctx context.Context, user *entity.User) (*entity.User, error)
+	ChangeStatus(ctx context.Context, id uuid.UUID, enabled bool) error
+	ExistsById(ctx context.Context, id uuid.UUID) (bool, error)
+	FindAll(ctx context.Context, filter UserFilter) ([]*entity.User, int, error)
+	Update(ctx context.Context, user *entity.User) (*entity.User, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	FindPasswordByID(ctx context.Context, id uuid.UUID) (string, error)
+	ChangePassword(ctx context.Context, id uuid.UUID, password string) error
+	// MarkEmailVerified verifies the email of the user only while it is still email, failing with ErrNotFound
+	// otherwise, so a token mailed to an address the user has changed since cannot verify the new one.
+	MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error
 }
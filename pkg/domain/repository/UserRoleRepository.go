@@ -3,9 +3,14 @@ package repository
 
 import (
 	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/google/uuid"
 )
 
 type UserRoleRepository interface {
 	AddUserRole(ctx context.Context, userId uuid.UUID, roleId uuid.UUID) error
+	FindUserIDsByRoleID(ctx context.Context, roleId uuid.UUID) ([]uuid.UUID, error)
+	FindRolesByUserID(ctx context.Context, userId uuid.UUID) ([]*entity.Role, error)
+	RemoveUserRole(ctx context.Context, userId uuid.UUID, roleId uuid.UUID) error
+	ReplaceUserRoles(ctx context.Context, userId uuid.UUID, roleIds []uuid.UUID) error
 }
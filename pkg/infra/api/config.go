@@ -0,0 +1,58 @@
+package api
+
+import (
+	"github.com/sirupsen/logrus"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func getEnvDuration(name string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		logrus.Fatalf("invalid duration on env %s: %v", name, err)
+	}
+	return d
+}
+
+func getEnvInt(name string, defaultValue int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		logrus.Fatalf("invalid integer on env %s: %v", name, err)
+	}
+	return i
+}
+
+func getEnvBool(name string, defaultValue bool) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		logrus.Fatalf("invalid boolean on env %s: %v", name, err)
+	}
+	return b
+}
+
+func getEnvList(name string, defaultValue []string) []string {
+	var result []string
+	for _, v := range strings.Split(os.Getenv(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	if len(result) == 0 {
+		return defaultValue
+	}
+	return result
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+// AccountController serves the /me endpoints, where users manage their own account. The account is the
+// subject of the validated access token.
+type AccountController struct {
+	findById       user.FindUserById
+	updateUser     user.UpdateUser
+	changePassword user.ChangePassword
+	closeAccount   user.CloseAccount
+}
+
+func NewAccountController(findById user.FindUserById, updateUser user.UpdateUser, changePassword user.ChangePassword, closeAccount user.CloseAccount) AccountController {
+	return AccountController{
+		findById:       findById,
+		updateUser:     updateUser,
+		changePassword: changePassword,
+		closeAccount:   closeAccount,
+	}
+}
+
+func (c AccountController) Profile(ctx *fiber.Ctx) error {
+	id, err := accountID(ctx)
+	if err != nil {
+		return err
+	}
+	output, err := c.findById.Execute(ctx.UserContext(), id)
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewUserResponseFromEntity(output))
+}
+
+func (c AccountController) UpdateProfile(ctx *fiber.Ctx) error {
+	id, err := accountID(ctx)
+	if err != nil {
+		return err
+	}
+	var data model.UpdateProfileRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	current, err := c.findById.Execute(ctx.UserContext(), id)
+	if err != nil {
+		return userError(err)
+	}
+	output, err := c.updateUser.Execute(ctx.UserContext(), id, data.Apply(current))
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewUserResponseFromEntity(output))
+}
+
+func (c AccountController) ChangePassword(ctx *fiber.Ctx) error {
+	id, err := accountID(ctx)
+	if err != nil {
+		return err
+	}
+	var data model.ChangePasswordRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err = c.changePassword.Execute(ctx.UserContext(), id, data.CurrentPassword, data.NewPassword, ctx.IP())
+	if errors.Is(err, token.ErrTooManyLoginAttempts) {
+		return loginThrottled(ctx, err)
+	}
+	if err != nil {
+		return passwordError(ctx, err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (c AccountController) Close(ctx *fiber.Ctx) error {
+	id, err := accountID(ctx)
+	if err != nil {
+		return err
+	}
+	var data model.CloseAccountRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err = c.closeAccount.Execute(ctx.UserContext(), id, data.Password, ctx.IP())
+	if errors.Is(err, token.ErrTooManyLoginAttempts) {
+		return loginThrottled(ctx, err)
+	}
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+// accountID returns the user owning the bearer access token. Client tokens have no user and are rejected.
+func accountID(ctx *fiber.Ctx) (uuid.UUID, error) {
+	p, ok := principal.FromContext(ctx)
+	if !ok || p.Username == "" {
+		ctx.Set(fiber.HeaderWWWAuthenticate, bearerInvalidToken)
+		return uuid.Nil, fiber.NewError(http.StatusUnauthorized)
+	}
+	id, err := uuid.Parse(p.Subject)
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, bearerInvalidToken)
+		return uuid.Nil, fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+	return id, nil
+}
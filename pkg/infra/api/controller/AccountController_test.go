@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/application/user/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type AccountControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	ctrl           *gomock.Controller
+	findUserById   *mock.MockFindUserById
+	updateUser     *mock.MockUpdateUser
+	changePassword *mock.MockChangePassword
+	closeAccount   *mock.MockCloseAccount
+	principal      *entity.Principal
+	app            *fiber.App
+	user           *entity.User
+}
+
+func TestAccountControllerSuite(t *testing.T) {
+	suite.Run(t, new(AccountControllerSuite))
+}
+
+func (s *AccountControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctrl = gomock.NewController(s.T())
+	s.findUserById = mock.NewMockFindUserById(s.ctrl)
+	s.updateUser = mock.NewMockUpdateUser(s.ctrl)
+	s.changePassword = mock.NewMockChangePassword(s.ctrl)
+	s.closeAccount = mock.NewMockCloseAccount(s.ctrl)
+	s.user = &entity.User{
+		ID:        uuid.New(),
+		Username:  "admin",
+		FirstName: "User",
+		LastName:  "Name",
+		Email:     "em@il.com",
+		Document:  "000",
+		Enabled:   true,
+	}
+	s.principal = &entity.Principal{Subject: s.user.ID.String(), Username: s.user.Username}
+
+	ac := NewAccountController(s.findUserById, s.updateUser, s.changePassword, s.closeAccount)
+	s.app = fiber.New()
+	s.app.Use(func(ctx *fiber.Ctx) error {
+		if s.principal != nil {
+			principal.Store(ctx, s.principal)
+		}
+		return ctx.Next()
+	})
+	s.app.Get("/me", ac.Profile)
+	s.app.Patch("/me", ac.UpdateProfile)
+	s.app.Post("/me/password", ac.ChangePassword)
+	s.app.Delete("/me", ac.Close)
+}
+
+func (s *AccountControllerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *AccountControllerSuite) TestProfileOk() {
+	r, _ := http.NewRequest("GET", "/me", nil)
+	s.findUserById.EXPECT().Execute(gomock.Any(), s.user.ID).Return(s.user, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result model.UserResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Equal(s.user.ID, result.ID)
+	s.Equal("admin", result.Username)
+}
+
+func (s *AccountControllerSuite) TestProfileWithoutPrincipal() {
+	s.principal = nil
+	r, _ := http.NewRequest("GET", "/me", nil)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(bearerInvalidToken, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
+
+func (s *AccountControllerSuite) TestProfileClientToken() {
+	s.principal = &entity.Principal{Subject: "client-id"}
+	r, _ := http.NewRequest("GET", "/me", nil)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *AccountControllerSuite) TestUpdateProfileKeepsAbsentFields() {
+	r, _ := http.NewRequest("PATCH", "/me", strings.NewReader(`{"firstName": "New"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.findUserById.EXPECT().Execute(gomock.Any(), s.user.ID).Return(s.user, nil).Times(1)
+	s.updateUser.EXPECT().Execute(gomock.Any(), s.user.ID, gomock.Any()).
+		DoAndReturn(func(_ any, _ uuid.UUID, input *entity.User) (*entity.User, error) {
+			s.Equal("New", input.FirstName)
+			s.Equal("Name", input.LastName)
+			s.Equal("em@il.com", input.Email)
+			return input, nil
+		}).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *AccountControllerSuite) TestUpdateProfileEmailInUse() {
+	r, _ := http.NewRequest("PATCH", "/me", strings.NewReader(`{"email": "taken@il.com"}`))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.findUserById.EXPECT().Execute(gomock.Any(), s.user.ID).Return(s.user, nil).Times(1)
+	s.updateUser.EXPECT().Execute(gomock.Any(), s.user.ID, gomock.Any()).Return(nil, user.ErrUserAlreadyExists).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *AccountControllerSuite) TestChangePasswordOk() {
+	body, _ := json.Marshal(model.ChangePasswordRequest{CurrentPassword: "old", NewPassword: "new"})
+	r, _ := http.NewRequest("POST", "/me/password", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.changePassword.EXPECT().Execute(gomock.Any(), s.user.ID, "old", "new", "0.0.0.0").Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *AccountControllerSuite) TestChangePasswordWrongCurrent() {
+	body, _ := json.Marshal(model.ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "new"})
+	r, _ := http.NewRequest("POST", "/me/password", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.changePassword.EXPECT().Execute(gomock.Any(), s.user.ID, "guess", "new", "0.0.0.0").Return(user.ErrInvalidPassword).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (s *AccountControllerSuite) TestChangePasswordPolicy() {
+	body, _ := json.Marshal(model.ChangePasswordRequest{CurrentPassword: "old", NewPassword: "new"})
+	r, _ := http.NewRequest("POST", "/me/password", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	violation := user.PasswordViolation{Rule: user.PasswordRuleMinLength, Message: "must have at least 8 characters"}
+	s.changePassword.EXPECT().Execute(gomock.Any(), s.user.ID, "old", "new", "0.0.0.0").
+		Return(&user.PasswordPolicyError{Violations: []user.PasswordViolation{violation}}).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	var output model.PasswordPolicyErrorResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&output))
+	s.Equal(user.ErrPasswordPolicy.Error(), output.Error)
+	s.Equal([]model.PasswordViolationResponse{{Rule: "min_length", Message: "must have at least 8 characters"}}, output.Violations)
+}
+
+func (s *AccountControllerSuite) TestChangePasswordThrottled() {
+	body, _ := json.Marshal(model.ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "new"})
+	r, _ := http.NewRequest("POST", "/me/password", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.changePassword.EXPECT().Execute(gomock.Any(), s.user.ID, "guess", "new", "0.0.0.0").
+		Return(&token.LoginThrottledError{RetryAfter: time.Minute}).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("60", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func (s *AccountControllerSuite) TestCloseOk() {
+	r, _ := http.NewRequest("DELETE", "/me", strings.NewReader(`{"password": "secret"}`))
+	r.Header.Set("Content-Type", "application/json")
+	s.closeAccount.EXPECT().Execute(gomock.Any(), s.user.ID, "secret", "0.0.0.0").Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *AccountControllerSuite) TestCloseWrongPassword() {
+	r, _ := http.NewRequest("DELETE", "/me", strings.NewReader(`{"password": "guess"}`))
+	r.Header.Set("Content-Type", "application/json")
+	s.closeAccount.EXPECT().Execute(gomock.Any(), s.user.ID, "guess", "0.0.0.0").Return(user.ErrInvalidPassword).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (s *AccountControllerSuite) TestCloseThrottled() {
+	r, _ := http.NewRequest("DELETE", "/me", strings.NewReader(`{"password": "guess"}`))
+	r.Header.Set("Content-Type", "application/json")
+	s.closeAccount.EXPECT().Execute(gomock.Any(), s.user.ID, "guess", "0.0.0.0").
+		Return(&token.LoginThrottledError{RetryAfter: time.Minute}).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+}
@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/authority"
+	"github.com/golauth/golauth/pkg/application/role"
+	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+type AuthorityController struct {
+	listAuthorities       authority.ListAuthorities
+	addAuthority          authority.AddAuthority
+	editAuthority         authority.EditAuthority
+	changeAuthorityStatus authority.ChangeAuthorityStatus
+}
+
+func NewAuthorityController(repoFactory factory.RepositoryFactory) AuthorityController {
+	return AuthorityController{
+		listAuthorities:       authority.NewListAuthorities(repoFactory),
+		addAuthority:          authority.NewAddAuthority(repoFactory),
+		editAuthority:         authority.NewEditAuthority(repoFactory),
+		changeAuthorityStatus: authority.NewChangeAuthorityStatus(repoFactory),
+	}
+}
+
+func (c AuthorityController) List(ctx *fiber.Ctx) error {
+	authorities, err := c.listAuthorities.Execute(ctx.UserContext())
+	if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewAuthorityResponses(authorities))
+}
+
+func (c AuthorityController) Create(ctx *fiber.Ctx) error {
+	var data model.AuthorityRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	output, err := c.addAuthority.Execute(ctx.UserContext(), data.ToEntity())
+	if err != nil {
+		return authorityError(err)
+	}
+
+	return ctx.Status(http.StatusCreated).JSON(model.NewAuthorityResponseFromEntity(output))
+}
+
+func (c AuthorityController) Edit(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	var data model.AuthorityRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err = c.editAuthority.Execute(ctx.UserContext(), id, data.ToEntity())
+	if err != nil {
+		return authorityError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (c AuthorityController) ChangeStatus(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	var data model.AuthorityChangeStatus
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err = c.changeAuthorityStatus.Execute(ctx.UserContext(), id, data.Enabled)
+	if err != nil {
+		return authorityError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func authorityError(err error) error {
+	switch {
+	case errors.Is(err, authority.ErrAuthorityNotFound), errors.Is(err, role.ErrRoleNotFound), errors.Is(err, role.ErrAuthorityNotAssigned):
+		return fiber.NewError(http.StatusNotFound, err.Error())
+	case errors.Is(err, authority.ErrAuthorityAlreadyExists), errors.Is(err, role.ErrAuthorityAlreadyAssigned):
+		return fiber.NewError(http.StatusConflict, err.Error())
+	}
+	return fiber.NewError(http.StatusInternalServerError, err.Error())
+}
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type AuthorityControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	ctrl          *gomock.Controller
+	repoFactory   *factoryMock.MockRepositoryFactory
+	authorityRepo *repoMock.MockAuthorityRepository
+	app           *fiber.App
+	ac            AuthorityController
+}
+
+func TestAuthorityControllerSuite(t *testing.T) {
+	suite.Run(t, new(AuthorityControllerSuite))
+}
+
+func (s *AuthorityControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctrl = gomock.NewController(s.T())
+	s.authorityRepo = repoMock.NewMockAuthorityRepository(s.ctrl)
+	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.ctrl)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepo)
+
+	s.ac = NewAuthorityController(s.repoFactory)
+	s.app = fiber.New()
+	s.app.Get("/authorities", s.ac.List)
+	s.app.Post("/authorities", s.ac.Create)
+	s.app.Put("/authorities/:id", s.ac.Edit)
+	s.app.Patch("/authorities/:id/change-status", s.ac.ChangeStatus)
+}
+
+func (s *AuthorityControllerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *AuthorityControllerSuite) TestListOk() {
+	authorities := []*entity.Authority{
+		{ID: uuid.New(), Name: "ADMIN", Enabled: true},
+		{ID: uuid.New(), Name: "USER", Enabled: false},
+	}
+	r, _ := http.NewRequest("GET", "/authorities", nil)
+
+	s.authorityRepo.EXPECT().FindAll(r.Context()).Return(authorities, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result []model.AuthorityResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Len(result, 2)
+	s.Equal("USER", result[1].Name)
+	s.False(result[1].Enabled)
+}
+
+func (s *AuthorityControllerSuite) TestListErrSvc() {
+	errMessage := "could not list authorities"
+	r, _ := http.NewRequest("GET", "/authorities", nil)
+
+	s.authorityRepo.EXPECT().FindAll(r.Context()).Return(nil, errors.New(errMessage)).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusInternalServerError, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Contains(string(b), errMessage)
+}
+
+func (s *AuthorityControllerSuite) TestCreateOk() {
+	input := model.AuthorityRequest{Name: "REPORTS_READ", Description: "Read reports"}
+	body, _ := json.Marshal(input)
+	r, _ := http.NewRequest("POST", "/authorities", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.authorityRepo.EXPECT().Create(r.Context(), gomock.Any()).
+		DoAndReturn(func(_ any, a *entity.Authority) (*entity.Authority, error) {
+			a.ID = uuid.New()
+			return a, nil
+		}).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusCreated, resp.StatusCode)
+	var result model.AuthorityResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.NotZero(result.ID)
+	s.Equal(input.Name, result.Name)
+	s.True(result.Enabled)
+}
+
+func (s *AuthorityControllerSuite) TestCreateDuplicate() {
+	input := model.AuthorityRequest{Name: "ADMIN"}
+	body, _ := json.Marshal(input)
+	r, _ := http.NewRequest("POST", "/authorities", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.authorityRepo.EXPECT().Create(r.Context(), gomock.Any()).Return(nil, repository.ErrDuplicate).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *AuthorityControllerSuite) TestEditOk() {
+	id := uuid.New()
+	input := model.AuthorityRequest{Name: "ADMIN", Description: "Edited"}
+	body, _ := json.Marshal(input)
+	r, _ := http.NewRequest("PUT", fmt.Sprintf("/authorities/%s", id), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.authorityRepo.EXPECT().Edit(r.Context(), &entity.Authority{ID: id, Name: "ADMIN", Description: "Edited"}).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *AuthorityControllerSuite) TestEditNotFound() {
+	id := uuid.New()
+	body, _ := json.Marshal(model.AuthorityRequest{Name: "ADMIN"})
+	r, _ := http.NewRequest("PUT", fmt.Sprintf("/authorities/%s", id), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.authorityRepo.EXPECT().Edit(r.Context(), gomock.Any()).Return(repository.ErrNotFound).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *AuthorityControllerSuite) TestEditErrParseUUID() {
+	body, _ := json.Marshal(model.AuthorityRequest{Name: "ADMIN"})
+	r, _ := http.NewRequest("PUT", "/authorities/abc", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *AuthorityControllerSuite) TestChangeStatusOk() {
+	id := uuid.New()
+	body, _ := json.Marshal(model.AuthorityChangeStatus{Enabled: false})
+	r, _ := http.NewRequest("PATCH", fmt.Sprintf("/authorities/%s/change-status", id), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.authorityRepo.EXPECT().ChangeStatus(r.Context(), id, false).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *AuthorityControllerSuite) TestChangeStatusNotFound() {
+	id := uuid.New()
+	body, _ := json.Marshal(model.AuthorityChangeStatus{Enabled: true})
+	r, _ := http.NewRequest("PATCH", fmt.Sprintf("/authorities/%s/change-status", id), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.authorityRepo.EXPECT().ChangeStatus(r.Context(), id, true).Return(repository.ErrNotFound).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"net/http"
+	"net/url"
+)
+
+const responseTypeCode = "code"
+
+var ErrUnsupportedResponseType = errors.New("unsupported response type")
+
+// AuthorizeController serves the authorization endpoint. Authorize answers the browser redirect of the
+// client with the golauth login page, and Login authenticates the user posting that page and redirects
+// back to the client with the code. Credentials never go through the client.
+type AuthorizeController interface {
+	Authorize(ctx *fiber.Ctx) error
+	Login(ctx *fiber.Ctx) error
+}
+
+type authorizeController struct {
+	generateAuthorizationCode token.GenerateAuthorizationCode
+}
+
+func NewAuthorizeController(generateAuthorizationCode token.GenerateAuthorizationCode) AuthorizeController {
+	return authorizeController{generateAuthorizationCode: generateAuthorizationCode}
+}
+
+func (c authorizeController) Authorize(ctx *fiber.Ctx) error {
+	var request model.AuthorizeRequest
+	if err := ctx.QueryParser(&request); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if request.ResponseType != responseTypeCode {
+		return fiber.NewError(http.StatusBadRequest, ErrUnsupportedResponseType.Error())
+	}
+
+	err := c.generateAuthorizationCode.Validate(ctx.UserContext(), request.ToEntity())
+	if err != nil {
+		return c.fail(ctx, request, err)
+	}
+	return c.loginPage(ctx, http.StatusOK, request, "")
+}
+
+func (c authorizeController) Login(ctx *fiber.Ctx) error {
+	var request model.AuthorizeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if request.ResponseType != responseTypeCode {
+		return fiber.NewError(http.StatusBadRequest, ErrUnsupportedResponseType.Error())
+	}
+	// only the login page holds the token of the cookie, so a client cannot post credentials it collected itself
+	if !validCSRFToken(ctx, request.CSRFToken) {
+		return fiber.NewError(http.StatusForbidden, ErrInvalidCSRFToken.Error())
+	}
+
+	code, err := c.generateAuthorizationCode.Execute(ctx.UserContext(), request.Username, request.Password, request.ToEntity(), ctx.IP())
+	switch {
+	case err == nil:
+		return c.redirect(ctx, request, url.Values{"code": {code}})
+	case errors.Is(err, token.ErrInvalidUsernameOrPassword):
+		return c.loginPage(ctx, http.StatusUnauthorized, request, err.Error())
+	case errors.Is(err, token.ErrTooManyLoginAttempts):
+		setRetryAfter(ctx, err)
+		return c.loginPage(ctx, http.StatusTooManyRequests, request, token.ErrTooManyLoginAttempts.Error())
+	case errors.Is(err, token.ErrAccountDisabled), errors.Is(err, token.ErrEmailNotVerified):
+		return c.loginPage(ctx, http.StatusForbidden, request, err.Error())
+	default:
+		return c.fail(ctx, request, err)
+	}
+}
+
+// fail answers an invalid authorization request. Errors are only sent back to the client once its redirect uri is known
+// to be registered.
+func (c authorizeController) fail(ctx *fiber.Ctx, request model.AuthorizeRequest, err error) error {
+	switch {
+	case errors.Is(err, token.ErrInvalidRedirectURI):
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, token.ErrUnauthorizedClient):
+		return c.redirect(ctx, request, url.Values{"error": {"unauthorized_client"}})
+	case errors.Is(err, token.ErrInvalidScope):
+		return c.redirect(ctx, request, url.Values{"error": {"invalid_scope"}})
+	case errors.Is(err, token.ErrInvalidCodeChallenge):
+		return c.redirect(ctx, request, url.Values{"error": {"invalid_request"}, "error_description": {err.Error()}})
+	default:
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+}
+
+// loginPage renders the login form for the request with a fresh CSRF token.
+func (c authorizeController) loginPage(ctx *fiber.Ctx, status int, request model.AuthorizeRequest, message string) error {
+	csrfToken, err := startLoginForm(ctx, status)
+	if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	request.Password = ""
+	request.CSRFToken = csrfToken
+	return loginTemplate.Execute(ctx, loginPageData{Request: request, Error: message})
+}
+
+// redirect sends the user agent back to the already validated redirect uri, keeping its own query parameters.
+func (c authorizeController) redirect(ctx *fiber.Ctx, request model.AuthorizeRequest, params url.Values) error {
+	location, err := url.Parse(request.RedirectURI)
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, token.ErrInvalidRedirectURI.Error())
+	}
+	query := location.Query()
+	for k, v := range params {
+		query[k] = v
+	}
+	if request.State != "" {
+		query.Set("state", request.State)
+	}
+	location.RawQuery = query.Encode()
+	return ctx.Redirect(location.String(), http.StatusFound)
+}
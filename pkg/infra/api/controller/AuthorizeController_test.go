@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type AuthorizeControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	ctx                       context.Context
+	generateAuthorizationCode *mock.MockGenerateAuthorizationCode
+
+	ctrl AuthorizeController
+	app  *fiber.App
+	form url.Values
+}
+
+func TestAuthorizeController(t *testing.T) {
+	suite.Run(t, new(AuthorizeControllerSuite))
+}
+
+func (s *AuthorizeControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.ctx = context.Background()
+	s.generateAuthorizationCode = mock.NewMockGenerateAuthorizationCode(s.mockCtrl)
+
+	s.ctrl = NewAuthorizeController(s.generateAuthorizationCode)
+	s.app = fiber.New()
+	s.app.Get("/authorize", s.ctrl.Authorize)
+	s.app.Post("/authorize", s.ctrl.Login)
+	s.form = url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"spa"},
+		"redirect_uri":          {"https://app/callback?tenant=1"},
+		"scope":                 {"openid"},
+		"state":                 {"xyz"},
+		"code_challenge":        {"challenge"},
+		"code_challenge_method": {"S256"},
+		"username":              {"admin"},
+		"password":              {"123456"},
+		"csrf_token":            {"csrf"},
+	}
+}
+
+func (s *AuthorizeControllerSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *AuthorizeControllerSuite) request() *http.Request {
+	r, _ := http.NewRequest("POST", "/authorize", strings.NewReader(s.form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.AddCookie(&http.Cookie{Name: csrfCookieName, Value: "csrf"})
+	return r
+}
+
+func (s *AuthorizeControllerSuite) pageRequest() *http.Request {
+	query := url.Values{}
+	for _, k := range []string{"response_type", "client_id", "redirect_uri", "scope", "state", "code_challenge", "code_challenge_method"} {
+		query.Set(k, s.form.Get(k))
+	}
+	r, _ := http.NewRequest("GET", "/authorize?"+query.Encode(), nil)
+	return r
+}
+
+func (s *AuthorizeControllerSuite) body(resp *http.Response) string {
+	b, err := io.ReadAll(resp.Body)
+	s.NoError(err)
+	return string(b)
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeRendersLoginPage() {
+	expected := &entity.AuthorizationCode{
+		ClientID:            "spa",
+		RedirectURI:         "https://app/callback?tenant=1",
+		Scope:               "openid",
+		CodeChallenge:       "challenge",
+		CodeChallengeMethod: "S256",
+	}
+	s.generateAuthorizationCode.EXPECT().Validate(gomock.Any(), expected).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(s.pageRequest(), -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Contains(resp.Header.Get(fiber.HeaderContentType), "text/html")
+	s.Equal("DENY", resp.Header.Get(fiber.HeaderXFrameOptions))
+	cookies := resp.Cookies()
+	s.Len(cookies, 1)
+	s.Equal(csrfCookieName, cookies[0].Name)
+	s.True(cookies[0].HttpOnly)
+	page := s.body(resp)
+	s.Contains(page, `name="csrf_token" value="`+cookies[0].Value+`"`)
+	s.Contains(page, `name="redirect_uri" value="https://app/callback?tenant=1"`)
+	s.Contains(page, `name="code_challenge" value="challenge"`)
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeUnsupportedResponseTypeOnPage() {
+	s.form.Set("response_type", "token")
+
+	resp, _ := s.app.Test(s.pageRequest(), -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeInvalidRedirectURIOnPage() {
+	s.generateAuthorizationCode.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(token.ErrInvalidRedirectURI).Times(1)
+
+	resp, _ := s.app.Test(s.pageRequest(), -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Empty(resp.Header.Get(fiber.HeaderLocation))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeInvalidScopeOnPageRedirects() {
+	s.generateAuthorizationCode.EXPECT().Validate(gomock.Any(), gomock.Any()).Return(token.ErrInvalidScope).Times(1)
+
+	resp, _ := s.app.Test(s.pageRequest(), -1)
+	s.Equal(http.StatusFound, resp.StatusCode)
+	query := s.location(resp)
+	s.Equal("invalid_scope", query.Get("error"))
+	s.Equal("xyz", query.Get("state"))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeWithoutCSRFCookie() {
+	r, _ := http.NewRequest("POST", "/authorize", strings.NewReader(s.form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeCSRFTokenMismatch() {
+	s.form.Set("csrf_token", "forged")
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+}
+
+func (s *AuthorizeControllerSuite) location(resp *http.Response) url.Values {
+	location, err := url.Parse(resp.Header.Get(fiber.HeaderLocation))
+	s.NoError(err)
+	s.Equal("app", location.Host)
+	s.Equal("/callback", location.Path)
+	return location.Query()
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeOk() {
+	expected := &entity.AuthorizationCode{
+		ClientID:            "spa",
+		RedirectURI:         "https://app/callback?tenant=1",
+		Scope:               "openid",
+		CodeChallenge:       "challenge",
+		CodeChallengeMethod: "S256",
+	}
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", expected, gomock.Any()).Return("the-code", nil).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusFound, resp.StatusCode)
+	query := s.location(resp)
+	s.Equal("the-code", query.Get("code"))
+	s.Equal("xyz", query.Get("state"))
+	s.Equal("1", query.Get("tenant"))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeUnsupportedResponseType() {
+	s.form.Set("response_type", "token")
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeInvalidRedirectURI() {
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any(), gomock.Any()).Return("", token.ErrInvalidRedirectURI).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	s.Empty(resp.Header.Get(fiber.HeaderLocation))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeInvalidCredentials() {
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any(), gomock.Any()).Return("", token.ErrInvalidUsernameOrPassword).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	page := s.body(resp)
+	s.Contains(page, token.ErrInvalidUsernameOrPassword.Error())
+	s.Contains(page, `name="username" value="admin"`)
+	s.NotContains(page, "123456")
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeLoginThrottled() {
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any(), "0.0.0.0").Return("", &token.LoginThrottledError{RetryAfter: time.Minute}).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("60", resp.Header.Get(fiber.HeaderRetryAfter))
+	s.Empty(resp.Header.Get(fiber.HeaderLocation))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeAccountDisabled() {
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any(), gomock.Any()).Return("", token.ErrAccountDisabled).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	s.Empty(resp.Header.Get(fiber.HeaderLocation))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeEmailNotVerified() {
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any(), gomock.Any()).Return("", token.ErrEmailNotVerified).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	s.Empty(resp.Header.Get(fiber.HeaderLocation))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeInvalidScopeRedirects() {
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any(), gomock.Any()).Return("", token.ErrInvalidScope).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusFound, resp.StatusCode)
+	query := s.location(resp)
+	s.Equal("invalid_scope", query.Get("error"))
+	s.Equal("xyz", query.Get("state"))
+	s.Empty(query.Get("code"))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeInvalidCodeChallengeRedirects() {
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any(), gomock.Any()).Return("", token.ErrInvalidCodeChallenge).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusFound, resp.StatusCode)
+	s.Equal("invalid_request", s.location(resp).Get("error"))
+}
+
+func (s *AuthorizeControllerSuite) TestAuthorizeErr() {
+	s.generateAuthorizationCode.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any(), gomock.Any()).Return("", fmt.Errorf("connection refused")).Times(1)
+
+	resp, _ := s.app.Test(s.request(), -1)
+	s.Equal(http.StatusInternalServerError, resp.StatusCode)
+}
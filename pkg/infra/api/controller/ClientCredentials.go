@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"encoding/base64"
+	"github.com/gofiber/fiber/v2"
+	"net/url"
+	"strings"
+)
+
+const basicAuthPrefix = "Basic "
+
+// clientCredentials reads the client credentials from the Basic Authorization header, falling back
+// to the client_id and client_secret sent in the request body.
+func clientCredentials(ctx *fiber.Ctx, bodyClientID string, bodyClientSecret string) (string, string) {
+	if clientID, secret, ok := basicCredentials(ctx.Get(fiber.HeaderAuthorization)); ok {
+		return clientID, secret
+	}
+	return bodyClientID, bodyClientSecret
+}
+
+func basicCredentials(header string) (string, string, bool) {
+	if len(header) <= len(basicAuthPrefix) || !strings.EqualFold(header[:len(basicAuthPrefix)], basicAuthPrefix) {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(header[len(basicAuthPrefix):])
+	if err != nil {
+		return "", "", false
+	}
+	clientID, secret, ok := strings.Cut(string(decoded), ":")
+	if !ok {
+		return "", "", false
+	}
+	clientID, err = url.QueryUnescape(clientID)
+	if err != nil {
+		return "", "", false
+	}
+	secret, err = url.QueryUnescape(secret)
+	if err != nil {
+		return "", "", false
+	}
+	return clientID, secret, true
+}
@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/client"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/google/uuid"
+	"net/http"
+	"time"
+)
+
+const (
+	deviceActionApprove = "approve"
+	deviceActionDeny    = "deny"
+)
+
+var ErrUnsupportedDeviceAction = errors.New("action must be approve or deny")
+
+// DeviceAuthorizationController serves the device flow. Verify takes the user code from an already logged-in user,
+// while VerificationPage serves the browser page of the verification uri and VerificationLogin authenticates the
+// user posting it.
+type DeviceAuthorizationController interface {
+	DeviceAuthorization(ctx *fiber.Ctx) error
+	Verify(ctx *fiber.Ctx) error
+	VerificationPage(ctx *fiber.Ctx) error
+	VerificationLogin(ctx *fiber.Ctx) error
+}
+
+type deviceAuthorizationController struct {
+	authenticateClient client.AuthenticateClient
+	generateDeviceCode token.GenerateDeviceCode
+	verifyDeviceCode   token.VerifyDeviceCode
+	verificationURI    string
+}
+
+func NewDeviceAuthorizationController(
+	authenticateClient client.AuthenticateClient,
+	generateDeviceCode token.GenerateDeviceCode,
+	verifyDeviceCode token.VerifyDeviceCode,
+	verificationURI string) DeviceAuthorizationController {
+	return deviceAuthorizationController{
+		authenticateClient: authenticateClient,
+		generateDeviceCode: generateDeviceCode,
+		verifyDeviceCode:   verifyDeviceCode,
+		verificationURI:    verificationURI,
+	}
+}
+
+// DeviceAuthorization starts the device flow for a client that cannot receive redirects, like a CLI or a TV app.
+func (c deviceAuthorizationController) DeviceAuthorization(ctx *fiber.Ctx) error {
+	var request model.DeviceAuthorizationRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	clientID, secret := clientCredentials(ctx, request.ClientID, request.ClientSecret)
+	cl, err := c.authenticateClient.Execute(ctx.UserContext(), clientID, secret)
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, `Basic realm="golauth"`)
+		return fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+
+	deviceCode, code, err := c.generateDeviceCode.Execute(ctx.UserContext(), cl, request.Scope)
+	if errors.Is(err, token.ErrUnauthorizedClient) || errors.Is(err, token.ErrInvalidScope) {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	output := model.NewDeviceAuthorizationResponse(deviceCode, token.FormatUserCode(code.UserCode), code, c.verificationURI, time.Now())
+	return ctx.Status(http.StatusOK).JSON(output)
+}
+
+// Verify lets the logged-in user approve or deny the device showing the user code.
+func (c deviceAuthorizationController) Verify(ctx *fiber.Ctx) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok || p.Username == "" {
+		ctx.Set(fiber.HeaderWWWAuthenticate, bearerInvalidToken)
+		return fiber.NewError(http.StatusUnauthorized)
+	}
+	userID, err := uuid.Parse(p.Subject)
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, bearerInvalidToken)
+		return fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+	var request model.DeviceVerificationRequest
+	if err = ctx.BodyParser(&request); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if request.Action != deviceActionApprove && request.Action != deviceActionDeny {
+		return fiber.NewError(http.StatusBadRequest, ErrUnsupportedDeviceAction.Error())
+	}
+
+	_, err = c.verifyDeviceCode.Execute(ctx.UserContext(), userID, request.UserCode, request.Action == deviceActionApprove)
+	if errors.Is(err, token.ErrInvalidUserCode) {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (c deviceAuthorizationController) VerificationPage(ctx *fiber.Ctx) error {
+	var form model.DeviceVerificationForm
+	if err := ctx.QueryParser(&form); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	return c.verificationPage(ctx, http.StatusOK, form, "")
+}
+
+func (c deviceAuthorizationController) VerificationLogin(ctx *fiber.Ctx) error {
+	var form model.DeviceVerificationForm
+	if err := ctx.BodyParser(&form); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if !validCSRFToken(ctx, form.CSRFToken) {
+		return fiber.NewError(http.StatusForbidden, ErrInvalidCSRFToken.Error())
+	}
+	if form.Action != deviceActionApprove && form.Action != deviceActionDeny {
+		return c.verificationPage(ctx, http.StatusBadRequest, form, ErrUnsupportedDeviceAction.Error())
+	}
+
+	approve := form.Action == deviceActionApprove
+	_, err := c.verifyDeviceCode.Login(ctx.UserContext(), form.Username, form.Password, form.UserCode, approve, ctx.IP())
+	switch {
+	case err == nil:
+		return c.verificationDone(ctx, approve)
+	case errors.Is(err, token.ErrInvalidUsernameOrPassword):
+		return c.verificationPage(ctx, http.StatusUnauthorized, form, err.Error())
+	case errors.Is(err, token.ErrTooManyLoginAttempts):
+		setRetryAfter(ctx, err)
+		return c.verificationPage(ctx, http.StatusTooManyRequests, form, token.ErrTooManyLoginAttempts.Error())
+	case errors.Is(err, token.ErrAccountDisabled), errors.Is(err, token.ErrEmailNotVerified):
+		return c.verificationPage(ctx, http.StatusForbidden, form, err.Error())
+	case errors.Is(err, token.ErrInvalidUserCode):
+		return c.verificationPage(ctx, http.StatusBadRequest, form, err.Error())
+	default:
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+}
+
+// verificationPage renders the verification form with a fresh CSRF token.
+func (c deviceAuthorizationController) verificationPage(ctx *fiber.Ctx, status int, form model.DeviceVerificationForm, message string) error {
+	csrfToken, err := startLoginForm(ctx, status)
+	if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	form.Password = ""
+	form.CSRFToken = csrfToken
+	return deviceVerificationTemplate.Execute(ctx, deviceVerificationPageData{Form: form, Error: message})
+}
+
+// verificationDone tells the user to go back to the device, which gets its token on its next poll.
+func (c deviceAuthorizationController) verificationDone(ctx *fiber.Ctx, approved bool) error {
+	message := "The device was denied access."
+	if approved {
+		message = "The device is connected. You can go back to it."
+	}
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	ctx.Type("html", "utf-8")
+	return deviceVerificationTemplate.Execute(ctx, deviceVerificationPageData{Message: message})
+}
@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/client"
+	clientMock "github.com/golauth/golauth/pkg/application/client/mock"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type DeviceAuthorizationControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	authClient         *clientMock.MockAuthenticateClient
+	generateDeviceCode *mock.MockGenerateDeviceCode
+	verifyDeviceCode   *mock.MockVerifyDeviceCode
+	principal          *entity.Principal
+	userID             uuid.UUID
+
+	app *fiber.App
+}
+
+func TestDeviceAuthorizationController(t *testing.T) {
+	suite.Run(t, new(DeviceAuthorizationControllerSuite))
+}
+
+func (s *DeviceAuthorizationControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.authClient = clientMock.NewMockAuthenticateClient(s.mockCtrl)
+	s.generateDeviceCode = mock.NewMockGenerateDeviceCode(s.mockCtrl)
+	s.verifyDeviceCode = mock.NewMockVerifyDeviceCode(s.mockCtrl)
+	s.userID = uuid.New()
+	s.principal = &entity.Principal{Subject: s.userID.String(), Username: "admin"}
+
+	ctrl := NewDeviceAuthorizationController(s.authClient, s.generateDeviceCode, s.verifyDeviceCode, "https://auth.golauth.io/device")
+	s.app = fiber.New()
+	s.app.Use(func(ctx *fiber.Ctx) error {
+		if s.principal != nil {
+			principal.Store(ctx, s.principal)
+		}
+		return ctx.Next()
+	})
+	s.app.Post("/auth/device_authorization", ctrl.DeviceAuthorization)
+	s.app.Post("/auth/device", ctrl.Verify)
+	s.app.Get("/auth/device/verify", ctrl.VerificationPage)
+	s.app.Post("/auth/device/verify", ctrl.VerificationLogin)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *DeviceAuthorizationControllerSuite) post(path string, body string) *http.Response {
+	r, _ := http.NewRequest("POST", path, strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	return resp
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestDeviceAuthorizationOk() {
+	c := &entity.Client{ClientID: "cli"}
+	code := &entity.DeviceCode{UserCode: "BCDFGHJK", Interval: 5, ExpiresAt: time.Now().Add(10 * time.Minute)}
+	s.authClient.EXPECT().Execute(gomock.Any(), "cli", "").Return(c, nil).Times(1)
+	s.generateDeviceCode.EXPECT().Execute(gomock.Any(), c, "openid").Return("device-code", code, nil).Times(1)
+
+	resp := s.post("/auth/device_authorization", "client_id=cli&scope=openid")
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result model.DeviceAuthorizationResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Equal("device-code", result.DeviceCode)
+	s.Equal("BCDF-GHJK", result.UserCode)
+	s.Equal("https://auth.golauth.io/device", result.VerificationURI)
+	s.Equal("https://auth.golauth.io/device?user_code=BCDF-GHJK", result.VerificationURIComplete)
+	s.InDelta(600, result.ExpiresIn, 2)
+	s.Equal(5, result.Interval)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestDeviceAuthorizationInvalidClient() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "cli", "wrong").Return(nil, client.ErrInvalidClient).Times(1)
+
+	resp := s.post("/auth/device_authorization", "client_id=cli&client_secret=wrong")
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestDeviceAuthorizationUnauthorizedClient() {
+	c := &entity.Client{ClientID: "cli"}
+	s.authClient.EXPECT().Execute(gomock.Any(), "cli", "").Return(c, nil).Times(1)
+	s.generateDeviceCode.EXPECT().Execute(gomock.Any(), c, "").Return("", nil, token.ErrUnauthorizedClient).Times(1)
+
+	resp := s.post("/auth/device_authorization", "client_id=cli")
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerifyApprove() {
+	s.verifyDeviceCode.EXPECT().Execute(gomock.Any(), s.userID, "BCDF-GHJK", true).Return(&entity.DeviceCode{}, nil).Times(1)
+
+	resp := s.post("/auth/device", "user_code=BCDF-GHJK&action=approve")
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerifyDeny() {
+	s.verifyDeviceCode.EXPECT().Execute(gomock.Any(), s.userID, "BCDF-GHJK", false).Return(&entity.DeviceCode{}, nil).Times(1)
+
+	resp := s.post("/auth/device", "user_code=BCDF-GHJK&action=deny")
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerifyUnsupportedAction() {
+	resp := s.post("/auth/device", "user_code=BCDF-GHJK")
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(ErrUnsupportedDeviceAction.Error(), string(b))
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerifyInvalidUserCode() {
+	s.verifyDeviceCode.EXPECT().Execute(gomock.Any(), s.userID, "XXXX", true).Return(nil, token.ErrInvalidUserCode).Times(1)
+
+	resp := s.post("/auth/device", "user_code=XXXX&action=approve")
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerifyClientToken() {
+	s.principal = &entity.Principal{Subject: "cli", ClientID: "cli"}
+
+	resp := s.post("/auth/device", "user_code=BCDF-GHJK&action=approve")
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(bearerInvalidToken, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
+
+func (s *DeviceAuthorizationControllerSuite) postPage(form url.Values) *http.Response {
+	form.Set("csrf_token", "csrf")
+	r, _ := http.NewRequest("POST", "/auth/device/verify", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.AddCookie(&http.Cookie{Name: csrfCookieName, Value: "csrf"})
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	return resp
+}
+
+func (s *DeviceAuthorizationControllerSuite) pageForm(action string) url.Values {
+	return url.Values{"user_code": {"BCDF-GHJK"}, "username": {"admin"}, "password": {"admin123"}, "action": {action}}
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerificationPage() {
+	s.principal = nil
+	r, _ := http.NewRequest("GET", "/auth/device/verify?user_code=BCDF-GHJK", nil)
+
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Contains(resp.Header.Get(fiber.HeaderContentType), "text/html")
+	cookies := resp.Cookies()
+	s.Len(cookies, 1)
+	s.Equal(csrfCookieName, cookies[0].Name)
+	b, _ := io.ReadAll(resp.Body)
+	page := string(b)
+	s.Contains(page, `name="user_code" value="BCDF-GHJK"`)
+	s.Contains(page, `name="csrf_token" value="`+cookies[0].Value+`"`)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerificationLoginApprove() {
+	s.principal = nil
+	s.verifyDeviceCode.EXPECT().Login(gomock.Any(), "admin", "admin123", "BCDF-GHJK", true, "0.0.0.0").Return(&entity.DeviceCode{}, nil).Times(1)
+
+	resp := s.postPage(s.pageForm(deviceActionApprove))
+	s.Equal(http.StatusOK, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Contains(string(b), "The device is connected")
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerificationLoginDeny() {
+	s.principal = nil
+	s.verifyDeviceCode.EXPECT().Login(gomock.Any(), "admin", "admin123", "BCDF-GHJK", false, "0.0.0.0").Return(&entity.DeviceCode{}, nil).Times(1)
+
+	resp := s.postPage(s.pageForm(deviceActionDeny))
+	s.Equal(http.StatusOK, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Contains(string(b), "The device was denied access")
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerificationLoginInvalidCredentials() {
+	s.principal = nil
+	s.verifyDeviceCode.EXPECT().Login(gomock.Any(), "admin", "admin123", "BCDF-GHJK", true, "0.0.0.0").Return(nil, token.ErrInvalidUsernameOrPassword).Times(1)
+
+	resp := s.postPage(s.pageForm(deviceActionApprove))
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	page := string(b)
+	s.Contains(page, token.ErrInvalidUsernameOrPassword.Error())
+	s.Contains(page, `name="user_code" value="BCDF-GHJK"`)
+	s.NotContains(page, "admin123")
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerificationLoginInvalidUserCode() {
+	s.principal = nil
+	s.verifyDeviceCode.EXPECT().Login(gomock.Any(), "admin", "admin123", "BCDF-GHJK", true, "0.0.0.0").Return(nil, token.ErrInvalidUserCode).Times(1)
+
+	resp := s.postPage(s.pageForm(deviceActionApprove))
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerificationLoginThrottled() {
+	s.principal = nil
+	s.verifyDeviceCode.EXPECT().Login(gomock.Any(), "admin", "admin123", "BCDF-GHJK", true, "0.0.0.0").Return(nil, &token.LoginThrottledError{RetryAfter: time.Minute}).Times(1)
+
+	resp := s.postPage(s.pageForm(deviceActionApprove))
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("60", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func (s *DeviceAuthorizationControllerSuite) TestVerificationLoginWithoutCSRFCookie() {
+	s.principal = nil
+	r, _ := http.NewRequest("POST", "/auth/device/verify", strings.NewReader(s.pageForm(deviceActionApprove).Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+}
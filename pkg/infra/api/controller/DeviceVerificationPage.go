@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"html/template"
+)
+
+type deviceVerificationPageData struct {
+	Form    model.DeviceVerificationForm
+	Error   string
+	Message string
+}
+
+// deviceVerificationTemplate asks for the user code shown by the device, filled in when the user came through the
+// complete verification uri, and for the credentials of the user approving or denying it.
+var deviceVerificationTemplate = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>Connect a device</title>
+    <style>
+        body { font-family: sans-serif; background: #f4f5f7; display: flex; justify-content: center; }
+        form, section { background: #fff; margin-top: 10vh; padding: 2em; width: 20em; border-radius: 4px; }
+        label, input, button { display: block; width: 100%; box-sizing: border-box; }
+        input { margin: .25em 0 1em; padding: .5em; }
+        button { padding: .6em; margin-bottom: .5em; }
+        .error { color: #b00020; }
+    </style>
+</head>
+<body>
+{{if .Message}}
+<section>
+    <h1>Connect a device</h1>
+    <p>{{.Message}}</p>
+</section>
+{{else}}
+<form method="post">
+    <h1>Connect a device</h1>
+    <p>Enter the code shown on your device and sign in.</p>
+    {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+    <input type="hidden" name="csrf_token" value="{{.Form.CSRFToken}}">
+    <label for="user_code">Code</label>
+    <input id="user_code" name="user_code" value="{{.Form.UserCode}}" autocomplete="off" required>
+    <label for="username">Username</label>
+    <input id="username" name="username" value="{{.Form.Username}}" autocomplete="username" required autofocus>
+    <label for="password">Password</label>
+    <input id="password" name="password" type="password" autocomplete="current-password" required>
+    <button type="submit" name="action" value="approve">Allow</button>
+    <button type="submit" name="action" value="deny">Deny</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
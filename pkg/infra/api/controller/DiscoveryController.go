@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"net/http"
+	"strings"
+)
+
+const (
+	authorizeRouteName           = "authorize"
+	tokenRouteName               = "token"
+	jwksRouteName                = "jwks"
+	revokeRouteName              = "revoke"
+	introspectRouteName          = "introspect"
+	userInfoRouteName            = "userinfo"
+	deviceAuthorizationRouteName = "deviceAuthorization"
+)
+
+type DiscoveryConfig struct {
+	Issuer     string
+	GrantTypes []string
+	Scopes     []string
+}
+
+type DiscoveryController interface {
+	OpenIDConfiguration(ctx *fiber.Ctx) error
+}
+
+type discoveryController struct {
+	keyStore token.KeyStore
+	config   DiscoveryConfig
+}
+
+func NewDiscoveryController(keyStore token.KeyStore, config DiscoveryConfig) DiscoveryController {
+	return discoveryController{keyStore: keyStore, config: config}
+}
+
+func (c discoveryController) OpenIDConfiguration(ctx *fiber.Ctx) error {
+	issuer := c.issuer(ctx)
+	output := &model.OpenIDConfigurationResponse{
+		Issuer:                           issuer,
+		AuthorizationEndpoint:            c.authorizationEndpoint(ctx, issuer),
+		TokenEndpoint:                    c.endpoint(ctx, issuer, tokenRouteName),
+		JwksURI:                          c.endpoint(ctx, issuer, jwksRouteName),
+		RevocationEndpoint:               c.endpoint(ctx, issuer, revokeRouteName),
+		IntrospectionEndpoint:            c.endpoint(ctx, issuer, introspectRouteName),
+		UserInfoEndpoint:                 c.endpoint(ctx, issuer, userInfoRouteName),
+		DeviceAuthorizationEndpoint:      c.endpoint(ctx, issuer, deviceAuthorizationRouteName),
+		ScopesSupported:                  c.config.Scopes,
+		ResponseTypesSupported:           []string{},
+		GrantTypesSupported:              c.config.GrantTypes,
+		SubjectTypesSupported:            []string{"public"},
+		IDTokenSigningAlgValuesSupported: c.signingAlgorithms(),
+		ClaimsSupported:                  model.ClaimNames(),
+	}
+	if output.AuthorizationEndpoint != "" {
+		output.ResponseTypesSupported = append(output.ResponseTypesSupported, "code")
+		output.CodeChallengeMethodsSupported = []string{token.CodeChallengeMethodS256, token.CodeChallengeMethodPlain}
+	}
+	return ctx.Status(http.StatusOK).JSON(output)
+}
+
+func (c discoveryController) issuer(ctx *fiber.Ctx) string {
+	if c.config.Issuer != "" {
+		return strings.TrimSuffix(c.config.Issuer, "/")
+	}
+	return ctx.BaseURL()
+}
+
+func (c discoveryController) endpoint(ctx *fiber.Ctx, issuer string, routeName string) string {
+	route := ctx.App().GetRoute(routeName)
+	if route.Path == "" {
+		return ""
+	}
+	return issuer + route.Path
+}
+
+// authorizationEndpoint is only advertised when it accepts the GET redirect of the browser, as required by RFC 6749.
+func (c discoveryController) authorizationEndpoint(ctx *fiber.Ctx, issuer string) string {
+	if ctx.App().GetRoute(authorizeRouteName).Method != fiber.MethodGet {
+		return ""
+	}
+	return c.endpoint(ctx, issuer, authorizeRouteName)
+}
+
+func (c discoveryController) signingAlgorithms() []string {
+	algorithms := make([]string, 0)
+	seen := map[string]bool{}
+	for _, k := range c.keyStore.VerificationKeys() {
+		if k.IsSymmetric() {
+			continue
+		}
+		if !seen[k.Algorithm] {
+			seen[k.Algorithm] = true
+			algorithms = append(algorithms, k.Algorithm)
+		}
+	}
+	return algorithms
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"testing"
+)
+
+type DiscoveryControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	ctrl     *gomock.Controller
+	keyStore *mock.MockKeyStore
+}
+
+func TestDiscoveryControllerSuite(t *testing.T) {
+	suite.Run(t, new(DiscoveryControllerSuite))
+}
+
+func (s *DiscoveryControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctrl = gomock.NewController(s.T())
+	s.keyStore = mock.NewMockKeyStore(s.ctrl)
+	var keys []*entity.SigningKey
+	for _, algorithm := range []string{"RS512", "RS512", "ES256", "HS256"} {
+		key, err := token.GenerateSigningKey(algorithm)
+		s.NoError(err)
+		keys = append(keys, key)
+	}
+	s.keyStore.EXPECT().VerificationKeys().Return(keys).AnyTimes()
+}
+
+func (s *DiscoveryControllerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *DiscoveryControllerSuite) newApp(config DiscoveryConfig) *fiber.App {
+	app := fiber.New()
+	noop := func(ctx *fiber.Ctx) error { return nil }
+	app.Get("/.well-known/openid-configuration", NewDiscoveryController(s.keyStore, config).OpenIDConfiguration)
+	app.Get("/.well-known/jwks.json", noop).Name("jwks")
+	auth := app.Group("/auth")
+	auth.Post("/token", noop).Name("token")
+	auth.Post("/revoke", noop).Name("revoke")
+	auth.Post("/introspect", noop).Name("introspect")
+	auth.Get("/userinfo", noop).Name("userinfo")
+	auth.Post("/device_authorization", noop).Name("deviceAuthorization")
+	return app
+}
+
+func (s *DiscoveryControllerSuite) fetch(app *fiber.App) model.OpenIDConfigurationResponse {
+	r, _ := http.NewRequest("GET", "http://localhost:8080/.well-known/openid-configuration", nil)
+	resp, err := app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result model.OpenIDConfigurationResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+func (s *DiscoveryControllerSuite) TestOpenIDConfigurationWithIssuer() {
+	app := s.newApp(DiscoveryConfig{
+		Issuer:     "https://auth.golauth.io/",
+		GrantTypes: []string{"password"},
+		Scopes:     []string{"openid"},
+	})
+
+	result := s.fetch(app)
+	s.Equal("https://auth.golauth.io", result.Issuer)
+	s.Equal("https://auth.golauth.io/auth/token", result.TokenEndpoint)
+	s.Equal("https://auth.golauth.io/.well-known/jwks.json", result.JwksURI)
+	s.Equal("https://auth.golauth.io/auth/revoke", result.RevocationEndpoint)
+	s.Equal("https://auth.golauth.io/auth/introspect", result.IntrospectionEndpoint)
+	s.Equal("https://auth.golauth.io/auth/userinfo", result.UserInfoEndpoint)
+	s.Equal("https://auth.golauth.io/auth/device_authorization", result.DeviceAuthorizationEndpoint)
+	s.Empty(result.AuthorizationEndpoint)
+	s.Empty(result.ResponseTypesSupported)
+	s.Empty(result.CodeChallengeMethodsSupported)
+	s.Equal([]string{"password"}, result.GrantTypesSupported)
+	s.Equal([]string{"openid"}, result.ScopesSupported)
+	s.Equal([]string{"RS512", "ES256"}, result.IDTokenSigningAlgValuesSupported)
+	s.Contains(result.ClaimsSupported, "username")
+	s.Contains(result.ClaimsSupported, "exp")
+	s.Contains(result.ClaimsSupported, "nonce")
+	s.Contains(result.ClaimsSupported, "email")
+}
+
+func (s *DiscoveryControllerSuite) TestOpenIDConfigurationIssuerFromRequest() {
+	result := s.fetch(s.newApp(DiscoveryConfig{}))
+	s.Equal("http://localhost:8080", result.Issuer)
+	s.Equal("http://localhost:8080/auth/token", result.TokenEndpoint)
+}
+
+func (s *DiscoveryControllerSuite) TestOpenIDConfigurationAuthorizationEndpoint() {
+	app := s.newApp(DiscoveryConfig{Issuer: "https://auth.golauth.io"})
+	app.Get("/auth/authorize", func(ctx *fiber.Ctx) error { return nil }).Name("authorize")
+
+	result := s.fetch(app)
+	s.Equal("https://auth.golauth.io/auth/authorize", result.AuthorizationEndpoint)
+	s.Equal([]string{"code"}, result.ResponseTypesSupported)
+	s.Equal([]string{"S256", "plain"}, result.CodeChallengeMethodsSupported)
+}
+
+func (s *DiscoveryControllerSuite) TestOpenIDConfigurationPostOnlyAuthorizeNotAdvertised() {
+	app := s.newApp(DiscoveryConfig{Issuer: "https://auth.golauth.io"})
+	app.Post("/auth/authorize", func(ctx *fiber.Ctx) error { return nil }).Name("authorize")
+
+	result := s.fetch(app)
+	s.Empty(result.AuthorizationEndpoint)
+	s.Empty(result.ResponseTypesSupported)
+}
@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+type EmailVerificationController struct {
+	requestVerification user.RequestEmailVerification
+	verifyEmail         user.VerifyEmail
+	mailThrottle        user.MailThrottle
+}
+
+func NewEmailVerificationController(requestVerification user.RequestEmailVerification, verifyEmail user.VerifyEmail, mailThrottle user.MailThrottle) EmailVerificationController {
+	return EmailVerificationController{requestVerification: requestVerification, verifyEmail: verifyEmail, mailThrottle: mailThrottle}
+}
+
+// Request sends a new verification token. Like the password reset request it answers 202 Accepted at once and
+// mails in the background, so the response does not tell whether the email belongs to an account, and it is
+// throttled the same way.
+func (c EmailVerificationController) Request(ctx *fiber.Ctx) error {
+	var data model.EmailVerificationRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if data.Email == "" {
+		return fiber.NewError(http.StatusBadRequest, "email is required")
+	}
+	if err := c.mailThrottle.Attempt(ctx.UserContext(), data.Email, ctx.IP()); err != nil {
+		return mailThrottled(ctx, err)
+	}
+	email := strings.Clone(data.Email)
+	background := context.WithoutCancel(ctx.UserContext())
+	go func() {
+		if err := c.requestVerification.Execute(background, email); err != nil {
+			logrus.Errorf("could not request email verification: %v", err)
+		}
+	}()
+
+	return ctx.SendStatus(http.StatusAccepted)
+}
+
+func (c EmailVerificationController) Confirm(ctx *fiber.Ctx) error {
+	var data model.EmailVerificationConfirmRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err := c.verifyEmail.Execute(ctx.UserContext(), data.Token)
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
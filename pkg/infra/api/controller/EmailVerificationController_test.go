@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/application/user/mock"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type EmailVerificationControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	ctrl                *gomock.Controller
+	requestVerification *mock.MockRequestEmailVerification
+	verifyEmail         *mock.MockVerifyEmail
+	mailThrottle        *mock.MockMailThrottle
+	app                 *fiber.App
+}
+
+func TestEmailVerificationControllerSuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationControllerSuite))
+}
+
+func (s *EmailVerificationControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctrl = gomock.NewController(s.T())
+	s.requestVerification = mock.NewMockRequestEmailVerification(s.ctrl)
+	s.verifyEmail = mock.NewMockVerifyEmail(s.ctrl)
+	s.mailThrottle = mock.NewMockMailThrottle(s.ctrl)
+
+	vc := NewEmailVerificationController(s.requestVerification, s.verifyEmail, s.mailThrottle)
+	s.app = fiber.New()
+	s.app.Post("/verify-email", vc.Request)
+	s.app.Post("/verify-email/confirm", vc.Confirm)
+}
+
+func (s *EmailVerificationControllerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *EmailVerificationControllerSuite) request(path string, body interface{}) *http.Response {
+	b, _ := json.Marshal(body)
+	r, _ := http.NewRequest("POST", path, strings.NewReader(string(b)))
+	r.Header.Set("Content-Type", "application/json")
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	return resp
+}
+
+func (s *EmailVerificationControllerSuite) TestRequestAccepted() {
+	sent := make(chan string, 1)
+	s.mailThrottle.EXPECT().Attempt(gomock.Any(), "admin@example.com", "0.0.0.0").Return(nil).Times(1)
+	s.requestVerification.EXPECT().Execute(gomock.Any(), "admin@example.com").
+		DoAndReturn(func(_ any, email string) error {
+			sent <- email
+			return nil
+		}).Times(1)
+
+	resp := s.request("/verify-email", model.EmailVerificationRequest{Email: "admin@example.com"})
+	s.Equal(http.StatusAccepted, resp.StatusCode)
+	select {
+	case email := <-sent:
+		s.Equal("admin@example.com", email)
+	case <-time.After(time.Second):
+		s.Fail("email verification was not requested")
+	}
+}
+
+func (s *EmailVerificationControllerSuite) TestRequestThrottled() {
+	s.mailThrottle.EXPECT().Attempt(gomock.Any(), "admin@example.com", "0.0.0.0").
+		Return(&user.MailThrottledError{RetryAfter: time.Hour}).Times(1)
+
+	resp := s.request("/verify-email", model.EmailVerificationRequest{Email: "admin@example.com"})
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("3600", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func (s *EmailVerificationControllerSuite) TestRequestWithoutEmail() {
+	resp := s.request("/verify-email", model.EmailVerificationRequest{})
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *EmailVerificationControllerSuite) TestConfirmOk() {
+	s.verifyEmail.EXPECT().Execute(gomock.Any(), "verification-token").Return(nil).Times(1)
+
+	resp := s.request("/verify-email/confirm", model.EmailVerificationConfirmRequest{Token: "verification-token"})
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *EmailVerificationControllerSuite) TestConfirmInvalidToken() {
+	s.verifyEmail.EXPECT().Execute(gomock.Any(), "used-token").Return(user.ErrInvalidVerificationToken).Times(1)
+
+	resp := s.request("/verify-email/confirm", model.EmailVerificationConfirmRequest{Token: "used-token"})
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
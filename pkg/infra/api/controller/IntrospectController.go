@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/client"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"net/http"
+)
+
+type IntrospectController interface {
+	Introspect(ctx *fiber.Ctx) error
+}
+
+type introspectController struct {
+	authenticateClient client.AuthenticateClient
+	introspectToken    token.IntrospectToken
+}
+
+func NewIntrospectController(authenticateClient client.AuthenticateClient, introspectToken token.IntrospectToken) IntrospectController {
+	return introspectController{authenticateClient: authenticateClient, introspectToken: introspectToken}
+}
+
+func (c introspectController) Introspect(ctx *fiber.Ctx) error {
+	var request model.IntrospectionRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	clientID, secret := clientCredentials(ctx, request.ClientID, request.ClientSecret)
+	authenticated, err := c.authenticateClient.Execute(ctx.UserContext(), clientID, secret)
+	if err == nil && authenticated.IsPublic() {
+		err = client.ErrInvalidClient
+	}
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, `Basic realm="golauth"`)
+		return fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+
+	if request.Token == "" {
+		return fiber.NewError(http.StatusBadRequest, ErrMissingToken.Error())
+	}
+	claims, err := c.introspectToken.Execute(request.Token)
+	if err != nil {
+		return ctx.Status(http.StatusOK).JSON(model.IntrospectionResponse{Active: false})
+	}
+	return ctx.Status(http.StatusOK).JSON(model.NewIntrospectionResponseFromClaims(claims))
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/cristalhq/jwt/v3"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/client"
+	clientMock "github.com/golauth/golauth/pkg/application/client/mock"
+	"github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type IntrospectControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	authClient      *clientMock.MockAuthenticateClient
+	introspectToken *mock.MockIntrospectToken
+
+	ctrl IntrospectController
+	app  *fiber.App
+}
+
+func TestIntrospectController(t *testing.T) {
+	suite.Run(t, new(IntrospectControllerSuite))
+}
+
+func (s *IntrospectControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.authClient = clientMock.NewMockAuthenticateClient(s.mockCtrl)
+	s.introspectToken = mock.NewMockIntrospectToken(s.mockCtrl)
+
+	s.ctrl = NewIntrospectController(s.authClient, s.introspectToken)
+	s.app = fiber.New()
+	s.app.Post("/introspect", s.ctrl.Introspect)
+}
+
+func (s *IntrospectControllerSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *IntrospectControllerSuite) request(body string) *http.Request {
+	r, _ := http.NewRequest("POST", "/introspect", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("resource", "secret")
+	return r
+}
+
+func (s *IntrospectControllerSuite) decode(resp *http.Response) map[string]interface{} {
+	var result map[string]interface{}
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+func (s *IntrospectControllerSuite) TestIntrospectActive() {
+	now := time.Now().Truncate(time.Second)
+	claims := &model.Claims{
+		Username:    "admin",
+		Authorities: []string{"ADMIN"},
+		ClientID:    "spa",
+		Scope:       "openid",
+		StandardClaims: jwt.StandardClaims{
+			Subject:   "user-id",
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+	s.authClient.EXPECT().Execute(gomock.Any(), "resource", "secret").Return(&entity.Client{ClientID: "resource", Secret: "hash"}, nil).Times(1)
+	s.introspectToken.EXPECT().Execute("abc").Return(claims, nil).Times(1)
+
+	resp, _ := s.app.Test(s.request("token=abc"), -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	result := s.decode(resp)
+	s.Equal(true, result["active"])
+	s.Equal("user-id", result["sub"])
+	s.Equal("admin", result["username"])
+	s.Equal("openid", result["scope"])
+	s.Equal([]interface{}{"ADMIN"}, result["authorities"])
+	s.Equal(float64(now.Add(time.Hour).Unix()), result["exp"])
+	s.Equal(float64(now.Unix()), result["iat"])
+	s.Equal("spa", result["client_id"])
+	s.Equal("Bearer", result["token_type"])
+}
+
+func (s *IntrospectControllerSuite) TestIntrospectInactive() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "resource", "secret").Return(&entity.Client{ClientID: "resource", Secret: "hash"}, nil).Times(1)
+	s.introspectToken.EXPECT().Execute("abc").Return(nil, fmt.Errorf("expired token")).Times(1)
+
+	resp, _ := s.app.Test(s.request("token=abc"), -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.Equal(map[string]interface{}{"active": false}, s.decode(resp))
+}
+
+func (s *IntrospectControllerSuite) TestIntrospectInvalidClient() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "resource", "secret").Return(nil, client.ErrInvalidClient).Times(1)
+
+	resp, _ := s.app.Test(s.request("token=abc"), -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.NotEmpty(resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
+
+func (s *IntrospectControllerSuite) TestIntrospectPublicClient() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "resource", "secret").Return(&entity.Client{ClientID: "spa"}, nil).Times(1)
+
+	resp, _ := s.app.Test(s.request("token=abc"), -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *IntrospectControllerSuite) TestIntrospectMissingToken() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "resource", "secret").Return(&entity.Client{ClientID: "resource", Secret: "hash"}, nil).Times(1)
+
+	resp, _ := s.app.Test(s.request(""), -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"net/http"
+)
+
+const jwksCacheControl = "public, max-age=300"
+
+type JwksController interface {
+	Jwks(ctx *fiber.Ctx) error
+}
+
+type jwksController struct {
+	keyStore token.KeyStore
+}
+
+func NewJwksController(keyStore token.KeyStore) JwksController {
+	return jwksController{keyStore: keyStore}
+}
+
+func (c jwksController) Jwks(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderCacheControl, jwksCacheControl)
+	return ctx.Status(http.StatusOK).JSON(model.NewJwksResponseFromEntities(c.keyStore.VerificationKeys()))
+}
@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"math/big"
+	"net/http"
+	"testing"
+)
+
+type JwksControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	ctrl     *gomock.Controller
+	keyStore *mock.MockKeyStore
+	app      *fiber.App
+}
+
+func TestJwksControllerSuite(t *testing.T) {
+	suite.Run(t, new(JwksControllerSuite))
+}
+
+func (s *JwksControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctrl = gomock.NewController(s.T())
+	s.keyStore = mock.NewMockKeyStore(s.ctrl)
+	s.app = fiber.New()
+	s.app.Get("/.well-known/jwks.json", NewJwksController(s.keyStore).Jwks)
+}
+
+func (s *JwksControllerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *JwksControllerSuite) TestJwksOk() {
+	key, err := token.GenerateSigningKey(token.DefaultAlgorithm)
+	s.NoError(err)
+	s.keyStore.EXPECT().VerificationKeys().Return([]*entity.SigningKey{key}).Times(1)
+
+	r, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	s.NotEmpty(resp.Header.Get(fiber.HeaderCacheControl))
+
+	var result model.JwksResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Len(result.Keys, 1)
+	jwk := result.Keys[0]
+	s.Equal(key.ID, jwk.KeyID)
+	s.Equal("RS512", jwk.Algorithm)
+	s.Equal("RSA", jwk.KeyType)
+	s.Equal("sig", jwk.Use)
+
+	n, err := base64.RawURLEncoding.DecodeString(jwk.Modulus)
+	s.NoError(err)
+	e, err := base64.RawURLEncoding.DecodeString(jwk.Exponent)
+	s.NoError(err)
+	publicKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	s.True(key.PrivateKey.(*rsa.PrivateKey).PublicKey.Equal(publicKey))
+}
+
+func (s *JwksControllerSuite) TestJwksEllipticCurve() {
+	key, err := token.GenerateSigningKey("ES256")
+	s.NoError(err)
+	s.keyStore.EXPECT().VerificationKeys().Return([]*entity.SigningKey{key}).Times(1)
+
+	r, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+
+	var result model.JwksResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Len(result.Keys, 1)
+	jwk := result.Keys[0]
+	s.Equal("EC", jwk.KeyType)
+	s.Equal("ES256", jwk.Algorithm)
+	s.Equal("P-256", jwk.Curve)
+	s.Empty(jwk.Modulus)
+
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	s.NoError(err)
+	y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+	s.NoError(err)
+	s.Len(x, 32)
+	publicKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	s.True(key.PrivateKey.(*ecdsa.PrivateKey).PublicKey.Equal(publicKey))
+}
+
+func (s *JwksControllerSuite) TestJwksEdDSA() {
+	key, err := token.GenerateSigningKey("EdDSA")
+	s.NoError(err)
+	s.keyStore.EXPECT().VerificationKeys().Return([]*entity.SigningKey{key}).Times(1)
+
+	r, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+
+	var result model.JwksResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Len(result.Keys, 1)
+	s.Equal("OKP", result.Keys[0].KeyType)
+	s.Equal("Ed25519", result.Keys[0].Curve)
+	x, err := base64.RawURLEncoding.DecodeString(result.Keys[0].X)
+	s.NoError(err)
+	s.Equal([]byte(key.PrivateKey.(ed25519.PrivateKey).Public().(ed25519.PublicKey)), x)
+}
+
+func (s *JwksControllerSuite) TestJwksHidesHmacSecrets() {
+	key, err := token.GenerateSigningKey("HS256")
+	s.NoError(err)
+	s.keyStore.EXPECT().VerificationKeys().Return([]*entity.SigningKey{key}).Times(1)
+
+	r, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+
+	var result model.JwksResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Empty(result.Keys)
+}
+
+func (s *JwksControllerSuite) TestJwksEmpty() {
+	s.keyStore.EXPECT().VerificationKeys().Return(nil).Times(1)
+
+	r, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result map[string]interface{}
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Equal([]interface{}{}, result["keys"])
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"net/http"
+	"net/url"
+)
+
+// LoginLockController lets administrators see and lift the delays and lockouts of the login throttle.
+type LoginLockController struct {
+	listLockedLogins token.ListLockedLogins
+	unlockLogin      token.UnlockLogin
+}
+
+func NewLoginLockController(listLockedLogins token.ListLockedLogins, unlockLogin token.UnlockLogin) LoginLockController {
+	return LoginLockController{listLockedLogins: listLockedLogins, unlockLogin: unlockLogin}
+}
+
+func (c LoginLockController) List(ctx *fiber.Ctx) error {
+	locked, err := c.listLockedLogins.Execute(ctx.UserContext())
+	if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.Status(http.StatusOK).JSON(model.NewLockedLoginResponses(locked))
+}
+
+// Unlock takes the subject escaped in the path, as client IPs and usernames may hold characters routes split on.
+func (c LoginLockController) Unlock(ctx *fiber.Ctx) error {
+	subject, err := url.PathUnescape(ctx.Params("subject"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	var actor string
+	if p, ok := principal.FromContext(ctx); ok {
+		actor = p.Username
+	}
+	err = c.unlockLogin.Execute(ctx.UserContext(), ctx.Params("kind"), subject, actor)
+	switch {
+	case err == nil:
+		return ctx.SendStatus(http.StatusNoContent)
+	case errors.Is(err, token.ErrInvalidLoginKind):
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, token.ErrLoginFailureNotFound):
+		return fiber.NewError(http.StatusNotFound, err.Error())
+	default:
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+}
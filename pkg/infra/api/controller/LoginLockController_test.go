@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type LoginLockControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	ctrl             *gomock.Controller
+	listLockedLogins *mock.MockListLockedLogins
+	unlockLogin      *mock.MockUnlockLogin
+	app              *fiber.App
+}
+
+func TestLoginLockControllerSuite(t *testing.T) {
+	suite.Run(t, new(LoginLockControllerSuite))
+}
+
+func (s *LoginLockControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctrl = gomock.NewController(s.T())
+	s.listLockedLogins = mock.NewMockListLockedLogins(s.ctrl)
+	s.unlockLogin = mock.NewMockUnlockLogin(s.ctrl)
+
+	lc := NewLoginLockController(s.listLockedLogins, s.unlockLogin)
+	s.app = fiber.New()
+	s.app.Use(func(ctx *fiber.Ctx) error {
+		principal.Store(ctx, &entity.Principal{Username: "admin"})
+		return ctx.Next()
+	})
+	s.app.Get("/lockouts", lc.List)
+	s.app.Delete("/lockouts/:kind/:subject", lc.Unlock)
+}
+
+func (s *LoginLockControllerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *LoginLockControllerSuite) TestListOk() {
+	lockedUntil := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	s.listLockedLogins.EXPECT().Execute(gomock.Any()).Return([]*entity.LoginFailure{
+		{Kind: entity.LoginFailureKindUsername, Subject: "jdoe", Failures: 10, LockedUntil: &lockedUntil},
+	}, nil).Times(1)
+
+	r, _ := http.NewRequest("GET", "/lockouts", nil)
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var body []model.LockedLoginResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.Len(body, 1)
+	s.Equal("jdoe", body[0].Subject)
+	s.Equal(10, body[0].Failures)
+	s.True(lockedUntil.Equal(body[0].LockedUntil))
+}
+
+func (s *LoginLockControllerSuite) TestListEmpty() {
+	s.listLockedLogins.EXPECT().Execute(gomock.Any()).Return(nil, nil).Times(1)
+
+	r, _ := http.NewRequest("GET", "/lockouts", nil)
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var body []model.LockedLoginResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&body))
+	s.NotNil(body)
+	s.Empty(body)
+}
+
+func (s *LoginLockControllerSuite) TestUnlockOk() {
+	s.unlockLogin.EXPECT().Execute(gomock.Any(), entity.LoginFailureKindIP, "2001:db8::1", "admin").Return(nil).Times(1)
+
+	r, _ := http.NewRequest("DELETE", "/lockouts/ip/2001:db8::1", nil)
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *LoginLockControllerSuite) TestUnlockEscapedSubject() {
+	s.unlockLogin.EXPECT().Execute(gomock.Any(), entity.LoginFailureKindUsername, "john/doe", "admin").Return(nil).Times(1)
+
+	r, _ := http.NewRequest("DELETE", "/lockouts/username/john%2Fdoe", nil)
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *LoginLockControllerSuite) TestUnlockErrors() {
+	for err, status := range map[error]int{
+		token.ErrInvalidLoginKind:        http.StatusBadRequest,
+		token.ErrLoginFailureNotFound:    http.StatusNotFound,
+		fmt.Errorf("connection refused"): http.StatusInternalServerError,
+	} {
+		s.unlockLogin.EXPECT().Execute(gomock.Any(), "username", "jdoe", "admin").Return(err).Times(1)
+
+		r, _ := http.NewRequest("DELETE", "/lockouts/username/jdoe", nil)
+		resp, _ := s.app.Test(r, -1)
+		s.Equal(status, resp.StatusCode, err.Error())
+	}
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"html/template"
+)
+
+const csrfCookieName = "golauth_csrf"
+
+var ErrInvalidCSRFToken = errors.New("login form expired, please log in again")
+
+// startLoginForm prepares the response of a page asking for the user password. It returns a fresh CSRF token for
+// the form, also set in a cookie only sent back by the form itself.
+func startLoginForm(ctx *fiber.Ctx, status int) (string, error) {
+	csrfToken, err := token.GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	ctx.Cookie(&fiber.Cookie{
+		Name:     csrfCookieName,
+		Value:    csrfToken,
+		Path:     ctx.Path(),
+		Secure:   ctx.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteStrictMode,
+	})
+	ctx.Set(fiber.HeaderCacheControl, "no-store")
+	ctx.Set(fiber.HeaderXFrameOptions, "DENY")
+	ctx.Set(fiber.HeaderContentSecurityPolicy, "default-src 'none'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	ctx.Type("html", "utf-8")
+	ctx.Status(status)
+	return csrfToken, nil
+}
+
+// validCSRFToken reports whether the posted form carries the token of the cookie set with it.
+func validCSRFToken(ctx *fiber.Ctx, formToken string) bool {
+	cookie := ctx.Cookies(csrfCookieName)
+	return cookie != "" && subtle.ConstantTimeCompare([]byte(cookie), []byte(formToken)) == 1
+}
+
+type loginPageData struct {
+	Request model.AuthorizeRequest
+	Error   string
+}
+
+// loginTemplate posts the credentials back to the authorize endpoint together with the authorization request.
+var loginTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="utf-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1">
+    <title>Sign in</title>
+    <style>
+        body { font-family: sans-serif; background: #f4f5f7; display: flex; justify-content: center; }
+        form { background: #fff; margin-top: 10vh; padding: 2em; width: 20em; border-radius: 4px; }
+        label, input, button { display: block; width: 100%; box-sizing: border-box; }
+        input { margin: .25em 0 1em; padding: .5em; }
+        button { padding: .6em; }
+        .error { color: #b00020; }
+    </style>
+</head>
+<body>
+<form method="post">
+    <h1>Sign in</h1>
+    <p>to continue to <strong>{{.Request.ClientID}}</strong></p>
+    {{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+    <input type="hidden" name="response_type" value="{{.Request.ResponseType}}">
+    <input type="hidden" name="client_id" value="{{.Request.ClientID}}">
+    <input type="hidden" name="redirect_uri" value="{{.Request.RedirectURI}}">
+    <input type="hidden" name="scope" value="{{.Request.Scope}}">
+    <input type="hidden" name="state" value="{{.Request.State}}">
+    <input type="hidden" name="nonce" value="{{.Request.Nonce}}">
+    <input type="hidden" name="code_challenge" value="{{.Request.CodeChallenge}}">
+    <input type="hidden" name="code_challenge_method" value="{{.Request.CodeChallengeMethod}}">
+    <input type="hidden" name="csrf_token" value="{{.Request.CSRFToken}}">
+    <label for="username">Username</label>
+    <input id="username" name="username" value="{{.Request.Username}}" autocomplete="username" required autofocus>
+    <label for="password">Password</label>
+    <input id="password" name="password" type="password" autocomplete="current-password" required>
+    <button type="submit">Sign in</button>
+</form>
+</body>
+</html>
+`))
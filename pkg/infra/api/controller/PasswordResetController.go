@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+)
+
+type PasswordResetController struct {
+	requestReset  user.RequestPasswordReset
+	resetPassword user.ResetPassword
+	mailThrottle  user.MailThrottle
+}
+
+func NewPasswordResetController(requestReset user.RequestPasswordReset, resetPassword user.ResetPassword, mailThrottle user.MailThrottle) PasswordResetController {
+	return PasswordResetController{requestReset: requestReset, resetPassword: resetPassword, mailThrottle: mailThrottle}
+}
+
+// Request answers 202 Accepted at once and mails the reset token in the background, so neither the response
+// nor its timing tells whether the email belongs to an account. Emails and client IPs asking too often get 429
+// Too Many Requests, known emails or not.
+func (c PasswordResetController) Request(ctx *fiber.Ctx) error {
+	var data model.PasswordResetRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if data.Email == "" {
+		return fiber.NewError(http.StatusBadRequest, "email is required")
+	}
+	if err := c.mailThrottle.Attempt(ctx.UserContext(), data.Email, ctx.IP()); err != nil {
+		return mailThrottled(ctx, err)
+	}
+	// fiber may reuse the request buffer backing parsed values once the handler returns
+	email := strings.Clone(data.Email)
+	background := context.WithoutCancel(ctx.UserContext())
+	go func() {
+		if err := c.requestReset.Execute(background, email); err != nil {
+			logrus.Errorf("could not request password reset: %v", err)
+		}
+	}()
+
+	return ctx.SendStatus(http.StatusAccepted)
+}
+
+// mailThrottled answers 429 with a Retry-After header when the mail throttle refused the request.
+func mailThrottled(ctx *fiber.Ctx, err error) error {
+	if !errors.Is(err, user.ErrTooManyMailRequests) {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	setRetryAfter(ctx, err)
+	return fiber.NewError(http.StatusTooManyRequests, user.ErrTooManyMailRequests.Error())
+}
+
+func (c PasswordResetController) Confirm(ctx *fiber.Ctx) error {
+	var data model.PasswordResetConfirmRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err := c.resetPassword.Execute(ctx.UserContext(), data.Token, data.NewPassword)
+	if err != nil {
+		return passwordError(ctx, err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
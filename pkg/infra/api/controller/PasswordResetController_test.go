@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/application/user/mock"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type PasswordResetControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	ctrl          *gomock.Controller
+	requestReset  *mock.MockRequestPasswordReset
+	resetPassword *mock.MockResetPassword
+	mailThrottle  *mock.MockMailThrottle
+	app           *fiber.App
+}
+
+func TestPasswordResetControllerSuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetControllerSuite))
+}
+
+func (s *PasswordResetControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctrl = gomock.NewController(s.T())
+	s.requestReset = mock.NewMockRequestPasswordReset(s.ctrl)
+	s.resetPassword = mock.NewMockResetPassword(s.ctrl)
+	s.mailThrottle = mock.NewMockMailThrottle(s.ctrl)
+
+	pc := NewPasswordResetController(s.requestReset, s.resetPassword, s.mailThrottle)
+	s.app = fiber.New()
+	s.app.Post("/password-reset", pc.Request)
+	s.app.Post("/password-reset/confirm", pc.Confirm)
+}
+
+func (s *PasswordResetControllerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *PasswordResetControllerSuite) request(path string, body interface{}) *http.Response {
+	b, _ := json.Marshal(body)
+	r, _ := http.NewRequest("POST", path, strings.NewReader(string(b)))
+	r.Header.Set("Content-Type", "application/json")
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	return resp
+}
+
+func (s *PasswordResetControllerSuite) TestRequestAccepted() {
+	sent := make(chan string, 1)
+	s.mailThrottle.EXPECT().Attempt(gomock.Any(), "admin@example.com", "0.0.0.0").Return(nil).Times(1)
+	s.requestReset.EXPECT().Execute(gomock.Any(), "admin@example.com").
+		DoAndReturn(func(_ any, email string) error {
+			sent <- email
+			return nil
+		}).Times(1)
+
+	resp := s.request("/password-reset", model.PasswordResetRequest{Email: "admin@example.com"})
+	s.Equal(http.StatusAccepted, resp.StatusCode)
+	select {
+	case email := <-sent:
+		s.Equal("admin@example.com", email)
+	case <-time.After(time.Second):
+		s.Fail("password reset was not requested")
+	}
+}
+
+func (s *PasswordResetControllerSuite) TestRequestThrottled() {
+	s.mailThrottle.EXPECT().Attempt(gomock.Any(), "admin@example.com", "0.0.0.0").
+		Return(&user.MailThrottledError{RetryAfter: time.Hour}).Times(1)
+
+	resp := s.request("/password-reset", model.PasswordResetRequest{Email: "admin@example.com"})
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("3600", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func (s *PasswordResetControllerSuite) TestRequestWithoutEmail() {
+	resp := s.request("/password-reset", model.PasswordResetRequest{})
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *PasswordResetControllerSuite) TestConfirmOk() {
+	s.resetPassword.EXPECT().Execute(gomock.Any(), "reset-token", "new-secret").Return(nil).Times(1)
+
+	resp := s.request("/password-reset/confirm", model.PasswordResetConfirmRequest{Token: "reset-token", NewPassword: "new-secret"})
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *PasswordResetControllerSuite) TestConfirmInvalidToken() {
+	s.resetPassword.EXPECT().Execute(gomock.Any(), "used-token", "new-secret").Return(user.ErrInvalidResetToken).Times(1)
+
+	resp := s.request("/password-reset/confirm", model.PasswordResetConfirmRequest{Token: "used-token", NewPassword: "new-secret"})
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *PasswordResetControllerSuite) TestConfirmPasswordPolicy() {
+	policyErr := &user.PasswordPolicyError{Violations: []user.PasswordViolation{
+		{Rule: user.PasswordRuleBreached, Message: "appears in a list of breached passwords"},
+		{Rule: user.PasswordRuleDigit, Message: "must contain a digit"},
+	}}
+	s.resetPassword.EXPECT().Execute(gomock.Any(), "reset-token", "password").Return(policyErr).Times(1)
+
+	resp := s.request("/password-reset/confirm", model.PasswordResetConfirmRequest{Token: "reset-token", NewPassword: "password"})
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	var output model.PasswordPolicyErrorResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&output))
+	s.Len(output.Violations, 2)
+	s.Equal(user.PasswordRuleBreached, output.Violations[0].Rule)
+	s.Equal(user.PasswordRuleDigit, output.Violations[1].Rule)
+}
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/client"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"net/http"
+)
+
+var ErrMissingToken = errors.New("missing token")
+
+type RevokeController interface {
+	Revoke(ctx *fiber.Ctx) error
+}
+
+type revokeController struct {
+	authenticateClient client.AuthenticateClient
+	revokeToken        token.RevokeToken
+}
+
+func NewRevokeController(authenticateClient client.AuthenticateClient, revokeToken token.RevokeToken) RevokeController {
+	return revokeController{authenticateClient: authenticateClient, revokeToken: revokeToken}
+}
+
+// Revoke authenticates the client as required by RFC 7009. Public clients identify themselves with their client_id
+// alone, so they can still revoke the tokens issued to them.
+func (c revokeController) Revoke(ctx *fiber.Ctx) error {
+	var request model.RevokeRequest
+	if err := ctx.BodyParser(&request); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+
+	clientID, secret := clientCredentials(ctx, request.ClientID, request.ClientSecret)
+	authenticated, err := c.authenticateClient.Execute(ctx.UserContext(), clientID, secret)
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, `Basic realm="golauth"`)
+		return fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+
+	if request.Token == "" {
+		return fiber.NewError(http.StatusBadRequest, ErrMissingToken.Error())
+	}
+	err = c.revokeToken.Execute(ctx.UserContext(), authenticated, request.Token)
+	if errors.Is(err, token.ErrTokenNotIssuedToClient) {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return ctx.SendStatus(http.StatusOK)
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/client"
+	clientMock "github.com/golauth/golauth/pkg/application/client/mock"
+	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/token/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type RevokeControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+
+	authClient  *clientMock.MockAuthenticateClient
+	revokeToken *mock.MockRevokeToken
+	client      *entity.Client
+
+	ctrl RevokeController
+	app  *fiber.App
+}
+
+func TestRevokeController(t *testing.T) {
+	suite.Run(t, new(RevokeControllerSuite))
+}
+
+func (s *RevokeControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.authClient = clientMock.NewMockAuthenticateClient(s.mockCtrl)
+	s.revokeToken = mock.NewMockRevokeToken(s.mockCtrl)
+	s.client = &entity.Client{ClientID: "spa", Enabled: true}
+
+	s.ctrl = NewRevokeController(s.authClient, s.revokeToken)
+	s.app = fiber.New()
+	s.app.Post("/revoke", s.ctrl.Revoke)
+}
+
+func (s *RevokeControllerSuite) TearDownTest() {
+	s.mockCtrl.Finish()
+}
+
+func (s *RevokeControllerSuite) request(body string) *http.Request {
+	r, _ := http.NewRequest("POST", "/revoke", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func (s *RevokeControllerSuite) TestRevokeOk() {
+	r := s.request("token=abc&token_type_hint=refresh_token")
+	r.SetBasicAuth("spa", "secret")
+	s.authClient.EXPECT().Execute(gomock.Any(), "spa", "secret").Return(s.client, nil).Times(1)
+	s.revokeToken.EXPECT().Execute(gomock.Any(), s.client, "abc").Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *RevokeControllerSuite) TestRevokePublicClient() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "spa", "").Return(s.client, nil).Times(1)
+	s.revokeToken.EXPECT().Execute(gomock.Any(), s.client, "abc").Return(nil).Times(1)
+
+	resp, _ := s.app.Test(s.request("token=abc&client_id=spa"), -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *RevokeControllerSuite) TestRevokeUnauthenticatedClient() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "", "").Return(nil, client.ErrInvalidClient).Times(1)
+
+	resp, _ := s.app.Test(s.request("token=abc"), -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(`Basic realm="golauth"`, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
+
+func (s *RevokeControllerSuite) TestRevokeTokenOfAnotherClient() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "spa", "").Return(s.client, nil).Times(1)
+	s.revokeToken.EXPECT().Execute(gomock.Any(), s.client, "abc").Return(token.ErrTokenNotIssuedToClient).Times(1)
+
+	resp, _ := s.app.Test(s.request("token=abc&client_id=spa"), -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *RevokeControllerSuite) TestRevokeMissingToken() {
+	s.authClient.EXPECT().Execute(gomock.Any(), "spa", "").Return(s.client, nil).Times(1)
+
+	resp, _ := s.app.Test(s.request("token_type_hint=refresh_token&client_id=spa"), -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *RevokeControllerSuite) TestRevokeErr() {
+	r := s.request("token=abc&client_id=spa")
+	s.authClient.EXPECT().Execute(gomock.Any(), "spa", "").Return(s.client, nil).Times(1)
+	s.revokeToken.EXPECT().Execute(gomock.Any(), s.client, "abc").Return(fmt.Errorf("connection refused")).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusInternalServerError, resp.StatusCode)
+}
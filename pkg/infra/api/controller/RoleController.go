@@ -1,14 +1,19 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golauth/golauth/pkg/application/role"
+	"github.com/golauth/golauth/pkg/application/token"
 	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/infra/api/controller/model"
 	"github.com/google/uuid"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type RoleController struct {
@@ -16,14 +21,26 @@ type RoleController struct {
 	editRole         role.EditRole
 	changeRoleStatus role.ChangeRoleStatus
 	findByName       role.FindRoleByName
+	findAuthorities  role.FindRoleAuthorities
+	addAuthority     role.AddRoleAuthority
+	removeAuthority  role.RemoveRoleAuthority
+	listRoles        role.ListRoles
+	deleteRole       role.DeleteRole
+	findUsage        role.FindRoleUsage
 }
 
-func NewRoleController(repoFactory factory.RepositoryFactory) RoleController {
+func NewRoleController(repoFactory factory.RepositoryFactory, revokeUserTokens token.RevokeUserTokens) RoleController {
 	return RoleController{
 		addRole:          role.NewAddRole(repoFactory),
 		editRole:         role.NewEditRole(repoFactory.NewRoleRepository()),
-		changeRoleStatus: role.NewChangeRoleStatus(repoFactory.NewRoleRepository()),
+		changeRoleStatus: role.NewChangeRoleStatus(repoFactory.NewRoleRepository(), repoFactory.NewUserRoleRepository(), revokeUserTokens),
 		findByName:       role.NewFindRoleByName(repoFactory.NewRoleRepository()),
+		findAuthorities:  role.NewFindRoleAuthorities(repoFactory),
+		addAuthority:     role.NewAddRoleAuthority(repoFactory),
+		removeAuthority:  role.NewRemoveRoleAuthority(repoFactory),
+		listRoles:        role.NewListRoles(repoFactory.NewRoleRepository()),
+		deleteRole:       role.NewDeleteRole(repoFactory.NewRoleRepository(), repoFactory.NewUserRoleRepository(), revokeUserTokens),
+		findUsage:        role.NewFindRoleUsage(repoFactory.NewRoleRepository()),
 	}
 }
 
@@ -69,7 +86,7 @@ func (c RoleController) ChangeStatus(ctx *fiber.Ctx) error {
 	if err := ctx.BodyParser(&data); err != nil {
 		return fiber.NewError(http.StatusInternalServerError, err.Error())
 	}
-	err = c.changeRoleStatus.Execute(ctx.UserContext(), id, data.Enabled)
+	err = c.changeRoleStatus.Execute(ctx.UserContext(), id, data.Enabled, data.RevokeTokens)
 	if err != nil {
 		return fiber.NewError(http.StatusInternalServerError, err.Error())
 	}
@@ -86,3 +103,124 @@ func (c RoleController) FindByName(ctx *fiber.Ctx) error {
 
 	return ctx.Status(http.StatusOK).JSON(data)
 }
+
+func (c RoleController) Authorities(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	authorities, err := c.findAuthorities.Execute(ctx.UserContext(), id)
+	if err != nil {
+		return authorityError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewAuthorityResponses(authorities))
+}
+
+func (c RoleController) AddAuthority(ctx *fiber.Ctx) error {
+	id, authorityId, err := roleAuthorityParams(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.addAuthority.Execute(ctx.UserContext(), id, authorityId)
+	if err != nil {
+		return authorityError(err)
+	}
+
+	return ctx.SendStatus(http.StatusCreated)
+}
+
+func (c RoleController) RemoveAuthority(ctx *fiber.Ctx) error {
+	id, authorityId, err := roleAuthorityParams(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.removeAuthority.Execute(ctx.UserContext(), id, authorityId)
+	if err != nil {
+		return authorityError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func roleAuthorityParams(ctx *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	authorityId, err := uuid.Parse(ctx.Params("authorityId"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	return id, authorityId, nil
+}
+
+// List pages through roles. sort names a field, prefixed with "-" for descending order.
+func (c RoleController) List(ctx *fiber.Ctx) error {
+	filter := repository.RoleFilter{
+		Name: ctx.Query("name"),
+		Sort: strings.TrimPrefix(ctx.Query("sort"), "-"),
+		Desc: strings.HasPrefix(ctx.Query("sort"), "-"),
+	}
+	if value := ctx.Query("enabled"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, fmt.Sprintf("invalid enabled %s: %v", value, err))
+		}
+		filter.Enabled = &enabled
+	}
+	page, err := strconv.Atoi(ctx.Query("page", "0"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, role.ErrInvalidPage.Error())
+	}
+	size, err := strconv.Atoi(ctx.Query("size", "0"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, role.ErrInvalidPage.Error())
+	}
+
+	output, err := c.listRoles.Execute(ctx.UserContext(), filter, page, size)
+	if err != nil {
+		return roleError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewRolePageResponse(output.Roles, output.Page, output.Size, output.Total))
+}
+
+func (c RoleController) Delete(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	cascade := false
+	if value := ctx.Query("cascade"); value != "" {
+		cascade, err = strconv.ParseBool(value)
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, fmt.Sprintf("invalid cascade %s: %v", value, err))
+		}
+	}
+	err = c.deleteRole.Execute(ctx.UserContext(), id, cascade)
+	if err != nil {
+		return roleError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (c RoleController) Usage(ctx *fiber.Ctx) error {
+	usage, err := c.findUsage.Execute(ctx.UserContext())
+	if err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewRoleUsageResponses(usage))
+}
+
+func roleError(err error) error {
+	switch {
+	case errors.Is(err, role.ErrRoleInUse):
+		return fiber.NewError(http.StatusConflict, err.Error())
+	case errors.Is(err, role.ErrInvalidSort), errors.Is(err, role.ErrInvalidPage):
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	return authorityError(err)
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
 	"github.com/golauth/golauth/pkg/domain/entity"
 	factoryMock "github.com/golauth/golauth/pkg/domain/factory/mock"
+	"github.com/golauth/golauth/pkg/domain/repository"
 	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
 	"github.com/golauth/golauth/pkg/infra/api/controller/model"
 	"github.com/google/uuid"
@@ -23,11 +25,15 @@ import (
 type RoleControllerSuite struct {
 	suite.Suite
 	*require.Assertions
-	ctrl        *gomock.Controller
-	repoFactory *factoryMock.MockRepositoryFactory
-	roleRepo    *repoMock.MockRoleRepository
-	app         *fiber.App
-	rc          RoleController
+	ctrl             *gomock.Controller
+	repoFactory      *factoryMock.MockRepositoryFactory
+	roleRepo         *repoMock.MockRoleRepository
+	userRoleRepo     *repoMock.MockUserRoleRepository
+	authorityRepo    *repoMock.MockAuthorityRepository
+	roleAuthRepo     *repoMock.MockRoleAuthorityRepository
+	revokeUserTokens *tokenMock.MockRevokeUserTokens
+	app              *fiber.App
+	rc               RoleController
 }
 
 func TestRoleControllerSuite(t *testing.T) {
@@ -38,15 +44,28 @@ func (s *RoleControllerSuite) SetupTest() {
 	s.Assertions = require.New(s.T())
 	s.ctrl = gomock.NewController(s.T())
 	s.roleRepo = repoMock.NewMockRoleRepository(s.ctrl)
+	s.userRoleRepo = repoMock.NewMockUserRoleRepository(s.ctrl)
+	s.authorityRepo = repoMock.NewMockAuthorityRepository(s.ctrl)
+	s.roleAuthRepo = repoMock.NewMockRoleAuthorityRepository(s.ctrl)
+	s.revokeUserTokens = tokenMock.NewMockRevokeUserTokens(s.ctrl)
 	s.repoFactory = factoryMock.NewMockRepositoryFactory(s.ctrl)
 	s.repoFactory.EXPECT().NewRoleRepository().AnyTimes().Return(s.roleRepo)
+	s.repoFactory.EXPECT().NewUserRoleRepository().AnyTimes().Return(s.userRoleRepo)
+	s.repoFactory.EXPECT().NewAuthorityRepository().AnyTimes().Return(s.authorityRepo)
+	s.repoFactory.EXPECT().NewRoleAuthorityRepository().AnyTimes().Return(s.roleAuthRepo)
 
-	s.rc = NewRoleController(s.repoFactory)
+	s.rc = NewRoleController(s.repoFactory, s.revokeUserTokens)
 	s.app = fiber.New()
+	s.app.Get("/roles", s.rc.List)
+	s.app.Get("/reports/role-usage", s.rc.Usage)
 	s.app.Post("/roles", s.rc.Create)
+	s.app.Delete("/roles/:id", s.rc.Delete)
 	s.app.Put("/roles/:id", s.rc.Edit)
 	s.app.Patch("/roles/:id/change-status", s.rc.ChangeStatus)
 	s.app.Get("/roles/:name", s.rc.FindByName)
+	s.app.Get("/roles/:id/authorities", s.rc.Authorities)
+	s.app.Post("/roles/:id/authorities/:authorityId", s.rc.AddAuthority)
+	s.app.Delete("/roles/:id/authorities/:authorityId", s.rc.RemoveAuthority)
 }
 
 func (s *RoleControllerSuite) TearDownTest() {
@@ -148,6 +167,24 @@ func (s *RoleControllerSuite) TestChangeStatusOk() {
 	s.Equal(http.StatusNoContent, resp.StatusCode)
 }
 
+func (s *RoleControllerSuite) TestChangeStatusRevokeTokens() {
+	roleId := uuid.New()
+	userId := uuid.New()
+	changeStatus := model.RoleChangeStatus{Enabled: false, RevokeTokens: true}
+
+	body, _ := json.Marshal(changeStatus)
+	r, _ := http.NewRequest("PATCH", fmt.Sprintf("/roles/%s/change-status", roleId), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.roleRepo.EXPECT().ExistsById(r.Context(), roleId).Return(true, nil).Times(1)
+	s.roleRepo.EXPECT().ChangeStatus(r.Context(), roleId, false).Return(nil).Times(1)
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(r.Context(), roleId).Return([]uuid.UUID{userId}, nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(r.Context(), userId).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
 func (s *RoleControllerSuite) TestChangeStatusErrParseUUID() {
 	changeStatus := model.RoleChangeStatus{Enabled: false}
 	body, _ := json.Marshal(changeStatus)
@@ -217,3 +254,191 @@ func (s *RoleControllerSuite) TestFindByNameErrSvc() {
 	b, _ := io.ReadAll(resp.Body)
 	s.Contains(string(b), errMessage)
 }
+
+func (s *RoleControllerSuite) TestAuthoritiesOk() {
+	roleId := uuid.New()
+	authorities := []*entity.Authority{{ID: uuid.New(), Name: "ADMIN", Enabled: true}}
+
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/roles/%s/authorities", roleId), nil)
+
+	s.roleRepo.EXPECT().ExistsById(r.Context(), roleId).Return(true, nil).Times(1)
+	s.roleAuthRepo.EXPECT().FindAuthoritiesByRoleID(r.Context(), roleId).Return(authorities, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result []model.AuthorityResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Len(result, 1)
+	s.Equal("ADMIN", result[0].Name)
+}
+
+func (s *RoleControllerSuite) TestAuthoritiesRoleNotFound() {
+	roleId := uuid.New()
+
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/roles/%s/authorities", roleId), nil)
+
+	s.roleRepo.EXPECT().ExistsById(r.Context(), roleId).Return(false, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestAddAuthorityOk() {
+	roleId := uuid.New()
+	authorityId := uuid.New()
+
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/roles/%s/authorities/%s", roleId, authorityId), nil)
+
+	s.roleRepo.EXPECT().ExistsById(r.Context(), roleId).Return(true, nil).Times(1)
+	s.authorityRepo.EXPECT().ExistsById(r.Context(), authorityId).Return(true, nil).Times(1)
+	s.roleAuthRepo.EXPECT().AddRoleAuthority(r.Context(), roleId, authorityId).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusCreated, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestAddAuthorityAlreadyAssigned() {
+	roleId := uuid.New()
+	authorityId := uuid.New()
+
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/roles/%s/authorities/%s", roleId, authorityId), nil)
+
+	s.roleRepo.EXPECT().ExistsById(r.Context(), roleId).Return(true, nil).Times(1)
+	s.authorityRepo.EXPECT().ExistsById(r.Context(), authorityId).Return(true, nil).Times(1)
+	s.roleAuthRepo.EXPECT().AddRoleAuthority(r.Context(), roleId, authorityId).Return(repository.ErrDuplicate).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestAddAuthorityErrParseUUID() {
+	r, _ := http.NewRequest("POST", fmt.Sprintf("/roles/%s/authorities/abc", uuid.New()), nil)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestRemoveAuthorityOk() {
+	roleId := uuid.New()
+	authorityId := uuid.New()
+
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/roles/%s/authorities/%s", roleId, authorityId), nil)
+
+	s.roleRepo.EXPECT().ExistsById(r.Context(), roleId).Return(true, nil).Times(1)
+	s.authorityRepo.EXPECT().ExistsById(r.Context(), authorityId).Return(true, nil).Times(1)
+	s.roleAuthRepo.EXPECT().RemoveRoleAuthority(r.Context(), roleId, authorityId).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestRemoveAuthorityNotAssigned() {
+	roleId := uuid.New()
+	authorityId := uuid.New()
+
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/roles/%s/authorities/%s", roleId, authorityId), nil)
+
+	s.roleRepo.EXPECT().ExistsById(r.Context(), roleId).Return(true, nil).Times(1)
+	s.authorityRepo.EXPECT().ExistsById(r.Context(), authorityId).Return(true, nil).Times(1)
+	s.roleAuthRepo.EXPECT().RemoveRoleAuthority(r.Context(), roleId, authorityId).Return(repository.ErrNotFound).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestListOk() {
+	roles := []*entity.Role{{ID: uuid.New(), Name: "ADMIN", Enabled: true}}
+	enabled := true
+	expected := repository.RoleFilter{Name: "adm", Enabled: &enabled, Sort: repository.RoleSortCreationDate, Desc: true, Offset: 10, Limit: 10}
+
+	r, _ := http.NewRequest("GET", "/roles?name=adm&enabled=true&sort=-creationDate&page=1&size=10", nil)
+
+	s.roleRepo.EXPECT().FindAll(r.Context(), expected).Return(roles, 11, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result model.RolePageResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Len(result.Content, 1)
+	s.Equal(11, result.Total)
+	s.Equal(1, result.Page)
+}
+
+func (s *RoleControllerSuite) TestListInvalidSort() {
+	r, _ := http.NewRequest("GET", "/roles?sort=description", nil)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestDeleteOk() {
+	roleId := uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/roles/%s", roleId), nil)
+
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(r.Context(), roleId).Return(nil, nil).Times(1)
+	s.roleRepo.EXPECT().Delete(r.Context(), roleId).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestDeleteInUse() {
+	roleId := uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/roles/%s", roleId), nil)
+
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(r.Context(), roleId).Return([]uuid.UUID{uuid.New()}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestDeleteCascade() {
+	roleId := uuid.New()
+	userId := uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/roles/%s?cascade=true", roleId), nil)
+
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(r.Context(), roleId).Return([]uuid.UUID{userId}, nil).Times(1)
+	s.roleRepo.EXPECT().Delete(r.Context(), roleId).Return(nil).Times(1)
+	s.revokeUserTokens.EXPECT().Execute(r.Context(), userId).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestDeleteNotFound() {
+	roleId := uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/roles/%s", roleId), nil)
+
+	s.userRoleRepo.EXPECT().FindUserIDsByRoleID(r.Context(), roleId).Return(nil, nil).Times(1)
+	s.roleRepo.EXPECT().Delete(r.Context(), roleId).Return(repository.ErrNotFound).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestDeleteInvalidCascade() {
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/roles/%s?cascade=maybe", uuid.New()), nil)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *RoleControllerSuite) TestUsageOk() {
+	usage := []*entity.RoleUsage{
+		{Role: entity.Role{ID: uuid.New(), Name: "ADMIN", Enabled: true}, Users: 2, Authorities: []string{"ADMIN"}},
+		{Role: entity.Role{ID: uuid.New(), Name: "UNUSED"}},
+	}
+	r, _ := http.NewRequest("GET", "/reports/role-usage", nil)
+
+	s.roleRepo.EXPECT().FindUsage(r.Context()).Return(usage, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	var result []model.RoleUsageResponse
+	s.NoError(json.Unmarshal(b, &result))
+	s.Len(result, 2)
+	s.Equal(2, result[0].Users)
+	s.Equal([]string{"ADMIN"}, result[0].Authorities)
+	s.Contains(string(b), `"authorities":[]`)
+}
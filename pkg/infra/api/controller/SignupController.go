@@ -1,10 +1,13 @@
 package controller
 
 import (
+	"context"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golauth/golauth/pkg/application/user"
 	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/sirupsen/logrus"
 	"net/http"
+	"strings"
 )
 
 type SignupController interface {
@@ -12,13 +15,16 @@ type SignupController interface {
 }
 
 type signupController struct {
-	createUser user.CreateUser
+	createUser          user.CreateUser
+	requestVerification user.RequestEmailVerification
 }
 
-func NewSignupController(createUser user.CreateUser) SignupController {
-	return &signupController{createUser: createUser}
+func NewSignupController(createUser user.CreateUser, requestVerification user.RequestEmailVerification) SignupController {
+	return &signupController{createUser: createUser, requestVerification: requestVerification}
 }
 
+// CreateUser signs the user up and mails the email verification token in the background, so a slow or failing
+// mail server does not fail the signup. The user can ask for a new token later.
 func (s *signupController) CreateUser(ctx *fiber.Ctx) error {
 	var decodedUser model.CreateUserRequest
 	if err := ctx.BodyParser(&decodedUser); err != nil {
@@ -26,8 +32,16 @@ func (s *signupController) CreateUser(ctx *fiber.Ctx) error {
 	}
 	output, err := s.createUser.Execute(ctx.UserContext(), decodedUser.ToEntity())
 	if err != nil {
-		return fiber.NewError(http.StatusInternalServerError, err.Error())
+		return passwordError(ctx, err)
 	}
+	// the email may still point into the request body, which fiber recycles once the handler returns
+	email := strings.Clone(output.Email)
+	background := context.WithoutCancel(ctx.UserContext())
+	go func() {
+		if err := s.requestVerification.Execute(background, email); err != nil {
+			logrus.Errorf("could not request email verification: %v", err)
+		}
+	}()
 
 	return ctx.Status(http.StatusCreated).JSON(output)
 }
@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/gofiber/fiber/v2"
+	userApp "github.com/golauth/golauth/pkg/application/user"
 	userMock "github.com/golauth/golauth/pkg/application/user/mock"
 	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -21,11 +23,12 @@ import (
 type SignupControllerSuite struct {
 	suite.Suite
 	*require.Assertions
-	mockCtrl   *gomock.Controller
-	ctx        context.Context
-	createUser *userMock.MockCreateUser
-	app        *fiber.App
-	ctrl       SignupController
+	mockCtrl            *gomock.Controller
+	ctx                 context.Context
+	createUser          *userMock.MockCreateUser
+	requestVerification *userMock.MockRequestEmailVerification
+	app                 *fiber.App
+	ctrl                SignupController
 }
 
 func TestSignupController(t *testing.T) {
@@ -37,8 +40,9 @@ func (s *SignupControllerSuite) SetupTest() {
 	s.mockCtrl = gomock.NewController(s.T())
 	s.ctx = context.Background()
 	s.createUser = userMock.NewMockCreateUser(s.mockCtrl)
+	s.requestVerification = userMock.NewMockRequestEmailVerification(s.mockCtrl)
 
-	s.ctrl = NewSignupController(s.createUser)
+	s.ctrl = NewSignupController(s.createUser, s.requestVerification)
 	s.app = fiber.New()
 	s.app.Post("/users", s.ctrl.CreateUser)
 }
@@ -68,6 +72,12 @@ func (s *SignupControllerSuite) TestCreateUserOK() {
 		CreationDate: time.Now().Add(-5 * time.Second),
 	}
 	s.createUser.EXPECT().Execute(s.ctx, input).Return(savedUser, nil).Times(1)
+	sent := make(chan string, 1)
+	s.requestVerification.EXPECT().Execute(gomock.Any(), "em@il.com").
+		DoAndReturn(func(_ any, email string) error {
+			sent <- email
+			return nil
+		}).Times(1)
 
 	body, _ := json.Marshal(input)
 	r, _ := http.NewRequest("POST", "/users", strings.NewReader(string(body)))
@@ -79,6 +89,12 @@ func (s *SignupControllerSuite) TestCreateUserOK() {
 	var output entity.User
 	_ = json.NewDecoder(resp.Body).Decode(&output)
 	s.Equal(savedUser.ID, output.ID)
+	select {
+	case email := <-sent:
+		s.Equal("em@il.com", email)
+	case <-time.After(time.Second):
+		s.Fail("email verification was not requested")
+	}
 }
 
 func (s *SignupControllerSuite) TestCreateUserErrBadRequest() {
@@ -112,3 +128,31 @@ func (s *SignupControllerSuite) TestCreateUserErrSvc() {
 	b, _ := io.ReadAll(resp.Body)
 	s.Equal(errMessage, string(b))
 }
+
+func (s *SignupControllerSuite) TestCreateUserConflict() {
+	input := &entity.User{Username: "admin", Email: "em@il.com", Enabled: true}
+	s.createUser.EXPECT().Execute(s.ctx, input).Return(nil, userApp.ErrUserAlreadyExists).Times(1)
+
+	body, _ := json.Marshal(input)
+	r, _ := http.NewRequest("POST", "/users", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusConflict, resp.StatusCode)
+}
+
+func (s *SignupControllerSuite) TestCreateUserPasswordPolicy() {
+	input := &entity.User{Username: "admin", Email: "em@il.com", Password: "admin", Enabled: true}
+	policyErr := &userApp.PasswordPolicyError{Violations: []userApp.PasswordViolation{{Rule: userApp.PasswordRulePersonalInfo, Message: "must not contain the username or email"}}}
+	s.createUser.EXPECT().Execute(s.ctx, input).Return(nil, policyErr).Times(1)
+
+	body, _ := json.Marshal(input)
+	r, _ := http.NewRequest("POST", "/users", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	var output model.PasswordPolicyErrorResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&output))
+	s.Equal(userApp.PasswordRulePersonalInfo, output.Violations[0].Rule)
+}
@@ -4,15 +4,22 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/client"
 	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 var (
 	ErrContentTypeNotSupported = errors.New("content-type not supported")
 	ErrMissingBodyData         = errors.New("missing body data")
+	ErrUnsupportedGrantType    = errors.New("unsupported grant type")
 )
 
 type TokenController interface {
@@ -23,16 +30,34 @@ type tokenController struct {
 	userRepository          repository.UserRepository
 	userAuthorityRepository repository.UserAuthorityRepository
 	generateToken           token.GenerateToken
+	refreshToken            token.RefreshToken
+	authenticateClient      client.AuthenticateClient
+	generateClientToken     token.GenerateClientToken
+	exchangeCode            token.ExchangeAuthorizationCode
+	exchangeToken           token.ExchangeToken
+	exchangeDeviceCode      token.ExchangeDeviceCode
 }
 
 func NewTokenController(
 	userRepository repository.UserRepository,
 	userAuthorityRepository repository.UserAuthorityRepository,
-	generateToken token.GenerateToken) TokenController {
+	generateToken token.GenerateToken,
+	refreshToken token.RefreshToken,
+	authenticateClient client.AuthenticateClient,
+	generateClientToken token.GenerateClientToken,
+	exchangeCode token.ExchangeAuthorizationCode,
+	exchangeToken token.ExchangeToken,
+	exchangeDeviceCode token.ExchangeDeviceCode) TokenController {
 	return tokenController{
 		userRepository:          userRepository,
 		userAuthorityRepository: userAuthorityRepository,
 		generateToken:           generateToken,
+		refreshToken:            refreshToken,
+		authenticateClient:      authenticateClient,
+		generateClientToken:     generateClientToken,
+		exchangeCode:            exchangeCode,
+		exchangeToken:           exchangeToken,
+		exchangeDeviceCode:      exchangeDeviceCode,
 	}
 }
 
@@ -52,10 +77,187 @@ func (s tokenController) Token(ctx *fiber.Ctx) error {
 		return fiber.NewError(http.StatusBadRequest, ErrMissingBodyData.Error())
 	}
 
-	output, err := s.generateToken.Execute(ctx.UserContext(), userLogin.Username, userLogin.Password)
+	var output *entity.Token
+	var err error
+	switch userLogin.GrantType {
+	case "", token.GrantTypePassword:
+		output, err = s.passwordGrant(ctx, userLogin)
+	case token.GrantTypeRefreshToken:
+		output, err = s.refreshTokenGrant(ctx, userLogin)
+	case token.GrantTypeClientCredentials:
+		output, err = s.clientCredentialsGrant(ctx, userLogin)
+	case token.GrantTypeAuthorizationCode:
+		output, err = s.authorizationCodeGrant(ctx, userLogin)
+	case token.GrantTypeTokenExchange:
+		output, err = s.tokenExchangeGrant(ctx, userLogin)
+	case token.GrantTypeDeviceCode:
+		output, err = s.deviceCodeGrant(ctx, userLogin)
+	default:
+		return fiber.NewError(http.StatusBadRequest, ErrUnsupportedGrantType.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewTokenResponseFromEntity(output))
+}
+
+func (s tokenController) passwordGrant(ctx *fiber.Ctx, userLogin model.UserLoginRequest) (*entity.Token, error) {
+	if err := rejectOpenID(userLogin.Scope); err != nil {
+		return nil, err
+	}
+	output, err := s.generateToken.Execute(ctx.UserContext(), userLogin.Username, userLogin.Password, ctx.IP())
+	if errors.Is(err, token.ErrTooManyLoginAttempts) {
+		return nil, loginThrottled(ctx, err)
+	}
+	if errors.Is(err, token.ErrAccountDisabled) || errors.Is(err, token.ErrEmailNotVerified) {
+		return nil, fiber.NewError(http.StatusForbidden, err.Error())
+	}
+	if err != nil {
+		return nil, fiber.NewError(http.StatusUnauthorized)
+	}
+	return output, nil
+}
+
+// rejectOpenID answers 400 when a grant that never issues an id token is asked for the openid scope, instead of
+// silently returning only the access token.
+func rejectOpenID(scope string) error {
+	if token.HasScope(token.ParseScope(scope), token.ScopeOpenID) {
+		return fiber.NewError(http.StatusBadRequest, token.ErrOpenIDNotSupported.Error())
+	}
+	return nil
+}
+
+// loginThrottled answers 429 with a Retry-After header telling when the throttled login may be tried again.
+func loginThrottled(ctx *fiber.Ctx, err error) error {
+	setRetryAfter(ctx, err)
+	return fiber.NewError(http.StatusTooManyRequests, token.ErrTooManyLoginAttempts.Error())
+}
+
+func setRetryAfter(ctx *fiber.Ctx, err error) {
+	var retryAfter time.Duration
+	var throttledErr *token.LoginThrottledError
+	var mailThrottledErr *user.MailThrottledError
+	switch {
+	case errors.As(err, &throttledErr):
+		retryAfter = throttledErr.RetryAfter
+	case errors.As(err, &mailThrottledErr):
+		retryAfter = mailThrottledErr.RetryAfter
+	default:
+		return
+	}
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+}
+
+// refreshTokenGrant authenticates the client whenever it sends credentials. Refresh tokens issued to a client need
+// them, while those of the password grant are refreshed without a client.
+func (s tokenController) refreshTokenGrant(ctx *fiber.Ctx, userLogin model.UserLoginRequest) (*entity.Token, error) {
+	if err := rejectOpenID(userLogin.Scope); err != nil {
+		return nil, err
+	}
+	var c *entity.Client
+	if clientID, _ := clientCredentials(ctx, userLogin.ClientID, userLogin.ClientSecret); clientID != "" {
+		var err error
+		c, err = s.authenticate(ctx, userLogin)
+		if err != nil {
+			return nil, err
+		}
+	}
+	output, err := s.refreshToken.Execute(ctx.UserContext(), c, userLogin.RefreshToken)
+	if errors.Is(err, token.ErrAccountDisabled) {
+		return nil, fiber.NewError(http.StatusForbidden, err.Error())
+	}
+	if errors.Is(err, token.ErrInvalidRefreshToken) || errors.Is(err, token.ErrRefreshTokenReused) {
+		return nil, fiber.NewError(http.StatusBadRequest, err.Error())
+	}
 	if err != nil {
-		return fiber.NewError(http.StatusUnauthorized)
+		return nil, fiber.NewError(http.StatusInternalServerError, err.Error())
 	}
+	return output, nil
+}
 
-	return ctx.Status(http.StatusOK).JSON(&model.TokenResponse{AccessToken: output.AccessToken})
+func (s tokenController) clientCredentialsGrant(ctx *fiber.Ctx, userLogin model.UserLoginRequest) (*entity.Token, error) {
+	c, err := s.authenticate(ctx, userLogin)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.generateClientToken.Execute(ctx.UserContext(), c, userLogin.Scope)
+	if errors.Is(err, token.ErrUnauthorizedClient) || errors.Is(err, token.ErrInvalidScope) {
+		return nil, fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return nil, fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return output, nil
+}
+
+func (s tokenController) authorizationCodeGrant(ctx *fiber.Ctx, userLogin model.UserLoginRequest) (*entity.Token, error) {
+	c, err := s.authenticate(ctx, userLogin)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.exchangeCode.Execute(ctx.UserContext(), c, userLogin.Code, userLogin.RedirectURI, userLogin.CodeVerifier)
+	if errors.Is(err, token.ErrAccountDisabled) {
+		return nil, fiber.NewError(http.StatusForbidden, err.Error())
+	}
+	if errors.Is(err, token.ErrUnauthorizedClient) || errors.Is(err, token.ErrInvalidAuthorizationCode) {
+		return nil, fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return nil, fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return output, nil
+}
+
+func (s tokenController) tokenExchangeGrant(ctx *fiber.Ctx, userLogin model.UserLoginRequest) (*entity.Token, error) {
+	c, err := s.authenticate(ctx, userLogin)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.exchangeToken.Execute(ctx.UserContext(), c, userLogin.SubjectToken, userLogin.SubjectTokenType,
+		userLogin.RequestedTokenType, userLogin.Audience, userLogin.Scope)
+	if errors.Is(err, token.ErrAccountDisabled) {
+		return nil, fiber.NewError(http.StatusForbidden, err.Error())
+	}
+	if errors.Is(err, token.ErrUnauthorizedClient) || errors.Is(err, token.ErrInvalidSubjectToken) ||
+		errors.Is(err, token.ErrUnsupportedTokenType) || errors.Is(err, token.ErrInvalidTarget) || errors.Is(err, token.ErrInvalidScope) {
+		return nil, fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return nil, fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return output, nil
+}
+
+// deviceCodeGrant answers a polling device. The error messages are the RFC 8628 error codes, which tell
+// the device to keep polling, poll slower or stop.
+func (s tokenController) deviceCodeGrant(ctx *fiber.Ctx, userLogin model.UserLoginRequest) (*entity.Token, error) {
+	c, err := s.authenticate(ctx, userLogin)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.exchangeDeviceCode.Execute(ctx.UserContext(), c, userLogin.DeviceCode)
+	if errors.Is(err, token.ErrAccountDisabled) {
+		return nil, fiber.NewError(http.StatusForbidden, err.Error())
+	}
+	if errors.Is(err, token.ErrUnauthorizedClient) || errors.Is(err, token.ErrInvalidDeviceCode) ||
+		errors.Is(err, token.ErrAuthorizationPending) || errors.Is(err, token.ErrSlowDown) ||
+		errors.Is(err, token.ErrExpiredToken) || errors.Is(err, token.ErrAccessDenied) {
+		return nil, fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return nil, fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	return output, nil
+}
+
+func (s tokenController) authenticate(ctx *fiber.Ctx, userLogin model.UserLoginRequest) (*entity.Client, error) {
+	clientID, secret := clientCredentials(ctx, userLogin.ClientID, userLogin.ClientSecret)
+	c, err := s.authenticateClient.Execute(ctx.UserContext(), clientID, secret)
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, `Basic realm="golauth"`)
+		return nil, fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+	return c, nil
 }
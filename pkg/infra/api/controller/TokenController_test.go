@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/client"
+	clientMock "github.com/golauth/golauth/pkg/application/client/mock"
+	"github.com/golauth/golauth/pkg/application/token"
 	"github.com/golauth/golauth/pkg/application/token/mock"
 	"github.com/golauth/golauth/pkg/domain/entity"
 	repoMock "github.com/golauth/golauth/pkg/domain/repository/mock"
@@ -16,6 +19,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 type TokenControllerSuite struct {
@@ -23,10 +27,16 @@ type TokenControllerSuite struct {
 	*require.Assertions
 	mockCtrl *gomock.Controller
 
-	ctx           context.Context
-	uRepo         *repoMock.MockUserRepository
-	uaRepo        *repoMock.MockUserAuthorityRepository
-	generateToken *mock.MockGenerateToken
+	ctx            context.Context
+	uRepo          *repoMock.MockUserRepository
+	uaRepo         *repoMock.MockUserAuthorityRepository
+	generateToken  *mock.MockGenerateToken
+	refreshToken   *mock.MockRefreshToken
+	authClient     *clientMock.MockAuthenticateClient
+	clientToken    *mock.MockGenerateClientToken
+	exchangeCode   *mock.MockExchangeAuthorizationCode
+	exchangeToken  *mock.MockExchangeToken
+	exchangeDevice *mock.MockExchangeDeviceCode
 
 	ctrl TokenController
 	app  *fiber.App
@@ -43,8 +53,14 @@ func (s *TokenControllerSuite) SetupTest() {
 	s.uRepo = repoMock.NewMockUserRepository(s.mockCtrl)
 	s.uaRepo = repoMock.NewMockUserAuthorityRepository(s.mockCtrl)
 	s.generateToken = mock.NewMockGenerateToken(s.mockCtrl)
+	s.refreshToken = mock.NewMockRefreshToken(s.mockCtrl)
+	s.authClient = clientMock.NewMockAuthenticateClient(s.mockCtrl)
+	s.clientToken = mock.NewMockGenerateClientToken(s.mockCtrl)
+	s.exchangeCode = mock.NewMockExchangeAuthorizationCode(s.mockCtrl)
+	s.exchangeToken = mock.NewMockExchangeToken(s.mockCtrl)
+	s.exchangeDevice = mock.NewMockExchangeDeviceCode(s.mockCtrl)
 
-	s.ctrl = NewTokenController(s.uRepo, s.uaRepo, s.generateToken)
+	s.ctrl = NewTokenController(s.uRepo, s.uaRepo, s.generateToken, s.refreshToken, s.authClient, s.clientToken, s.exchangeCode, s.exchangeToken, s.exchangeDevice)
 	s.app = fiber.New()
 	s.app.Post("/token", s.ctrl.Token)
 }
@@ -61,7 +77,7 @@ func (s *TokenControllerSuite) TestTokenFormOk() {
 	r, _ := http.NewRequest("POST", "/token", strings.NewReader(fmt.Sprintf("username=%s&password=%s", username, password)))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	s.generateToken.EXPECT().Execute(r.Context(), username, password).Return(&entity.Token{AccessToken: token}, nil).Times(1)
+	s.generateToken.EXPECT().Execute(r.Context(), username, password, gomock.Any()).Return(&entity.Token{AccessToken: token}, nil).Times(1)
 
 	resp, _ := s.app.Test(r, -1)
 	s.Equal(http.StatusOK, resp.StatusCode)
@@ -84,7 +100,7 @@ func (s *TokenControllerSuite) TestTokenJsonOk() {
 	r, _ := http.NewRequest("POST", "/token", strings.NewReader(string(body)))
 	r.Header.Set("Content-Type", "application/json")
 
-	s.generateToken.EXPECT().Execute(r.Context(), username, password).Return(&entity.Token{AccessToken: token}, nil).Times(1)
+	s.generateToken.EXPECT().Execute(r.Context(), username, password, gomock.Any()).Return(&entity.Token{AccessToken: token}, nil).Times(1)
 
 	resp, _ := s.app.Test(r, -1)
 	s.Equal(http.StatusOK, resp.StatusCode)
@@ -134,8 +150,364 @@ func (s *TokenControllerSuite) TestTokenErrGenerateToken() {
 	r, _ := http.NewRequest("POST", "/token", strings.NewReader(fmt.Sprintf("username=%s&password=%s", username, password)))
 	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	s.generateToken.EXPECT().Execute(s.ctx, username, password).Return(nil, fmt.Errorf("could not find user by username admin")).Times(1)
+	s.generateToken.EXPECT().Execute(s.ctx, username, password, gomock.Any()).Return(nil, fmt.Errorf("could not find user by username admin")).Times(1)
 
 	resp, _ := s.app.Test(r, -1)
 	s.Equal(http.StatusUnauthorized, resp.StatusCode)
 }
+
+func (s *TokenControllerSuite) TestTokenAccountDisabled() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("username=admin&password=123456"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.generateToken.EXPECT().Execute(s.ctx, "admin", "123456", gomock.Any()).Return(nil, token.ErrAccountDisabled).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrAccountDisabled.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenEmailNotVerified() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("username=admin&password=123456"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.generateToken.EXPECT().Execute(s.ctx, "admin", "123456", gomock.Any()).Return(nil, token.ErrEmailNotVerified).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrEmailNotVerified.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenLoginThrottled() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("username=admin&password=123456"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.generateToken.EXPECT().Execute(s.ctx, "admin", "123456", "0.0.0.0").Return(nil, &token.LoginThrottledError{RetryAfter: 1500 * time.Millisecond}).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusTooManyRequests, resp.StatusCode)
+	s.Equal("2", resp.Header.Get(fiber.HeaderRetryAfter))
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrTooManyLoginAttempts.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenPasswordGrantReturnsRefreshToken() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=password&username=admin&password=123456"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.generateToken.EXPECT().Execute(gomock.Any(), "admin", "123456", gomock.Any()).Return(&entity.Token{AccessToken: "access", RefreshToken: "refresh"}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result model.TokenResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	s.Equal("access", result.AccessToken)
+	s.Equal("refresh", result.RefreshToken)
+}
+
+func (s *TokenControllerSuite) TestTokenPasswordGrantRejectsOpenID() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=password&username=admin&password=123456&scope=openid"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrOpenIDNotSupported.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenRefreshTokenGrantOk() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=refresh_token&refresh_token=old"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.refreshToken.EXPECT().Execute(gomock.Any(), nil, "old").Return(&entity.Token{AccessToken: "access", RefreshToken: "new"}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result model.TokenResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	s.Equal("access", result.AccessToken)
+	s.Equal("new", result.RefreshToken)
+}
+
+func (s *TokenControllerSuite) TestTokenRefreshTokenGrantClientOk() {
+	c := &entity.Client{ClientID: "service"}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=refresh_token&refresh_token=old"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("service", "secret")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "service", "secret").Return(c, nil).Times(1)
+	s.refreshToken.EXPECT().Execute(gomock.Any(), c, "old").Return(&entity.Token{AccessToken: "access", RefreshToken: "new"}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *TokenControllerSuite) TestTokenRefreshTokenGrantClientMissingSecret() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=refresh_token&refresh_token=old&client_id=service"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "service", "").Return(nil, client.ErrInvalidClient).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.NotEmpty(resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
+
+func (s *TokenControllerSuite) TestTokenRefreshTokenGrantWrongClient() {
+	c := &entity.Client{ClientID: "other"}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=refresh_token&refresh_token=old"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("other", "secret")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "other", "secret").Return(c, nil).Times(1)
+	s.refreshToken.EXPECT().Execute(gomock.Any(), c, "old").Return(nil, token.ErrInvalidRefreshToken).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrInvalidRefreshToken.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenRefreshTokenGrantRejectsOpenID() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=refresh_token&refresh_token=old&scope=openid"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrOpenIDNotSupported.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenRefreshTokenGrantReused() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=refresh_token&refresh_token=old"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.refreshToken.EXPECT().Execute(gomock.Any(), nil, "old").Return(nil, token.ErrRefreshTokenReused).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrRefreshTokenReused.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenRefreshTokenGrantAccountDisabled() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=refresh_token&refresh_token=old"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.refreshToken.EXPECT().Execute(gomock.Any(), nil, "old").Return(nil, token.ErrAccountDisabled).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusForbidden, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrAccountDisabled.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenRefreshTokenGrantErr() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=refresh_token&refresh_token=old"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.refreshToken.EXPECT().Execute(gomock.Any(), nil, "old").Return(nil, fmt.Errorf("connection refused")).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusInternalServerError, resp.StatusCode)
+}
+
+func (s *TokenControllerSuite) TestTokenUnsupportedGrantType() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=implicit"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(ErrUnsupportedGrantType.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenClientCredentialsBasicAuthOk() {
+	c := &entity.Client{ClientID: "service", Scopes: []string{"read"}}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=client_credentials&scope=read"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("service", "secret")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "service", "secret").Return(c, nil).Times(1)
+	s.clientToken.EXPECT().Execute(gomock.Any(), c, "read").Return(&entity.Token{AccessToken: "access", ExpiresIn: 5 * time.Minute}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result model.TokenResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	s.Equal("access", result.AccessToken)
+	s.Equal(model.TokenTypeBearer, result.TokenType)
+	s.Equal(300, result.ExpiresIn)
+	s.Empty(result.RefreshToken)
+}
+
+func (s *TokenControllerSuite) TestTokenClientCredentialsFormOk() {
+	c := &entity.Client{ClientID: "service"}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=client_credentials&client_id=service&client_secret=secret"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "service", "secret").Return(c, nil).Times(1)
+	s.clientToken.EXPECT().Execute(gomock.Any(), c, "").Return(&entity.Token{AccessToken: "access"}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+}
+
+func (s *TokenControllerSuite) TestTokenClientCredentialsInvalidClient() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=client_credentials"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("service", "wrong")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "service", "wrong").Return(nil, client.ErrInvalidClient).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.NotEmpty(resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
+
+func (s *TokenControllerSuite) TestTokenClientCredentialsInvalidScope() {
+	c := &entity.Client{ClientID: "service", Scopes: []string{"read"}}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=client_credentials&scope=write"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("service", "secret")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "service", "secret").Return(c, nil).Times(1)
+	s.clientToken.EXPECT().Execute(gomock.Any(), c, "write").Return(nil, token.ErrInvalidScope).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TokenControllerSuite) TestTokenClientCredentialsUnauthorizedClient() {
+	c := &entity.Client{ClientID: "service"}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=client_credentials"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("service", "secret")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "service", "secret").Return(c, nil).Times(1)
+	s.clientToken.EXPECT().Execute(gomock.Any(), c, "").Return(nil, token.ErrUnauthorizedClient).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrUnauthorizedClient.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenAuthorizationCodePublicClientOk() {
+	c := &entity.Client{ClientID: "spa"}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=authorization_code&client_id=spa&code=abc&redirect_uri=https%3A%2F%2Fapp%2Fcb&code_verifier=verifier"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "spa", "").Return(c, nil).Times(1)
+	s.exchangeCode.EXPECT().Execute(gomock.Any(), c, "abc", "https://app/cb", "verifier").Return(&entity.Token{AccessToken: "access", RefreshToken: "refresh"}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result model.TokenResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	s.Equal("access", result.AccessToken)
+	s.Equal("refresh", result.RefreshToken)
+}
+
+func (s *TokenControllerSuite) TestTokenAuthorizationCodeInvalidCode() {
+	c := &entity.Client{ClientID: "spa"}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=authorization_code&client_id=spa&code=abc"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "spa", "").Return(c, nil).Times(1)
+	s.exchangeCode.EXPECT().Execute(gomock.Any(), c, "abc", "", "").Return(nil, token.ErrInvalidAuthorizationCode).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Equal(token.ErrInvalidAuthorizationCode.Error(), string(b))
+}
+
+func (s *TokenControllerSuite) TestTokenAuthorizationCodeInvalidClient() {
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=authorization_code&client_id=spa&client_secret=wrong&code=abc"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "spa", "wrong").Return(nil, client.ErrInvalidClient).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *TokenControllerSuite) TestTokenExchangeOk() {
+	c := &entity.Client{ClientID: "gateway", Secret: "hash"}
+	body := "grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Atoken-exchange&subject_token=user-token" +
+		"&subject_token_type=urn%3Aietf%3Aparams%3Aoauth%3Atoken-type%3Aaccess_token&audience=orders&scope=ORDERS_READ"
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("gateway", "secret")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "gateway", "secret").Return(c, nil).Times(1)
+	s.exchangeToken.EXPECT().Execute(gomock.Any(), c, "user-token", token.TokenTypeAccessToken, "", "orders", "ORDERS_READ").
+		Return(&entity.Token{AccessToken: "delegated", ExpiresIn: 90 * time.Second, IssuedTokenType: token.TokenTypeAccessToken}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result model.TokenResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	s.Equal("delegated", result.AccessToken)
+	s.Equal(model.TokenTypeBearer, result.TokenType)
+	s.Equal(90, result.ExpiresIn)
+	s.Equal(token.TokenTypeAccessToken, result.IssuedTokenType)
+	s.Empty(result.RefreshToken)
+}
+
+func (s *TokenControllerSuite) TestTokenExchangeInvalidScope() {
+	c := &entity.Client{ClientID: "gateway", Secret: "hash"}
+	body := "grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Atoken-exchange&subject_token=user-token" +
+		"&subject_token_type=urn%3Aietf%3Aparams%3Aoauth%3Atoken-type%3Aaccess_token&scope=ADMIN"
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.SetBasicAuth("gateway", "secret")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "gateway", "secret").Return(c, nil).Times(1)
+	s.exchangeToken.EXPECT().Execute(gomock.Any(), c, "user-token", token.TokenTypeAccessToken, "", "", "ADMIN").
+		Return(nil, token.ErrInvalidScope).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *TokenControllerSuite) TestTokenDeviceCodeOk() {
+	c := &entity.Client{ClientID: "cli"}
+	r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Adevice_code&client_id=cli&device_code=abc"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	s.authClient.EXPECT().Execute(gomock.Any(), "cli", "").Return(c, nil).Times(1)
+	s.exchangeDevice.EXPECT().Execute(gomock.Any(), c, "abc").Return(&entity.Token{AccessToken: "access", RefreshToken: "refresh"}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result model.TokenResponse
+	_ = json.NewDecoder(resp.Body).Decode(&result)
+	s.Equal("access", result.AccessToken)
+	s.Equal("refresh", result.RefreshToken)
+}
+
+func (s *TokenControllerSuite) TestTokenDeviceCodePollingErrors() {
+	for _, pollErr := range []error{token.ErrAuthorizationPending, token.ErrSlowDown, token.ErrExpiredToken, token.ErrAccessDenied} {
+		c := &entity.Client{ClientID: "cli"}
+		r, _ := http.NewRequest("POST", "/token", strings.NewReader("grant_type=urn%3Aietf%3Aparams%3Aoauth%3Agrant-type%3Adevice_code&client_id=cli&device_code=abc"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+		s.authClient.EXPECT().Execute(gomock.Any(), "cli", "").Return(c, nil).Times(1)
+		s.exchangeDevice.EXPECT().Execute(gomock.Any(), c, "abc").Return(nil, pollErr).Times(1)
+
+		resp, _ := s.app.Test(r, -1)
+		s.Equal(http.StatusBadRequest, resp.StatusCode)
+		b, _ := io.ReadAll(resp.Body)
+		s.Equal(pollErr.Error(), string(b))
+	}
+}
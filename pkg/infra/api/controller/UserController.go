@@ -1,20 +1,45 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/role"
 	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/infra/api/controller/model"
 	"github.com/google/uuid"
 	"net/http"
+	"strconv"
+	"strings"
 )
 
 type UserController struct {
-	findById    user.FindUserById
-	addUserRole user.AddUserRole
+	findById     user.FindUserById
+	addUserRole  user.AddUserRole
+	changeStatus user.ChangeUserStatus
+	listUsers    user.ListUsers
+	updateUser   user.UpdateUser
+	deleteUser   user.DeleteUser
+	findRoles    user.FindUserRoles
+	removeRole   user.RemoveUserRole
+	replaceRoles user.ReplaceUserRoles
 }
 
-func NewUserController(findById user.FindUserById, addUserRole user.AddUserRole) UserController {
-	return UserController{findById: findById, addUserRole: addUserRole}
+func NewUserController(findById user.FindUserById, addUserRole user.AddUserRole, changeStatus user.ChangeUserStatus,
+	listUsers user.ListUsers, updateUser user.UpdateUser, deleteUser user.DeleteUser, findRoles user.FindUserRoles,
+	removeRole user.RemoveUserRole, replaceRoles user.ReplaceUserRoles) UserController {
+	return UserController{
+		findById:     findById,
+		addUserRole:  addUserRole,
+		changeStatus: changeStatus,
+		listUsers:    listUsers,
+		updateUser:   updateUser,
+		deleteUser:   deleteUser,
+		findRoles:    findRoles,
+		removeRole:   removeRole,
+		replaceRoles: replaceRoles,
+	}
 }
 
 func (u UserController) FindById(ctx *fiber.Ctx) error {
@@ -24,12 +49,75 @@ func (u UserController) FindById(ctx *fiber.Ctx) error {
 	}
 	data, err := u.findById.Execute(ctx.UserContext(), id)
 	if err != nil {
-		return fiber.NewError(http.StatusInternalServerError, err.Error())
+		return userError(err)
 	}
 
 	return ctx.Status(http.StatusOK).JSON(model.NewUserResponseFromEntity(data))
 }
 
+// List pages through users. sort names a field, prefixed with "-" for descending order.
+func (u UserController) List(ctx *fiber.Ctx) error {
+	filter := repository.UserFilter{
+		Username: ctx.Query("username"),
+		Email:    ctx.Query("email"),
+		Role:     ctx.Query("role"),
+		Sort:     strings.TrimPrefix(ctx.Query("sort"), "-"),
+		Desc:     strings.HasPrefix(ctx.Query("sort"), "-"),
+	}
+	if value := ctx.Query("enabled"); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fiber.NewError(http.StatusBadRequest, fmt.Sprintf("invalid enabled %s: %v", value, err))
+		}
+		filter.Enabled = &enabled
+	}
+	page, err := strconv.Atoi(ctx.Query("page", "0"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, user.ErrInvalidPage.Error())
+	}
+	size, err := strconv.Atoi(ctx.Query("size", "0"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, user.ErrInvalidPage.Error())
+	}
+
+	output, err := u.listUsers.Execute(ctx.UserContext(), filter, page, size)
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewUserPageResponse(output.Users, output.Page, output.Size, output.Total))
+}
+
+func (u UserController) Update(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	var data model.UpdateUserRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	output, err := u.updateUser.Execute(ctx.UserContext(), id, data.ToEntity())
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.Status(http.StatusOK).JSON(model.NewUserResponseFromEntity(output))
+}
+
+func (u UserController) Delete(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err = u.deleteUser.Execute(ctx.UserContext(), id)
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
 func (u UserController) AddRole(ctx *fiber.Ctx) error {
 	var userRole model.UserRoleRequest
 	if err := ctx.BodyParser(&userRole); err != nil {
@@ -42,3 +130,100 @@ func (u UserController) AddRole(ctx *fiber.Ctx) error {
 
 	return ctx.SendStatus(http.StatusCreated)
 }
+
+func (u UserController) Roles(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	roles, err := u.findRoles.Execute(ctx.UserContext(), id)
+	if err != nil {
+		return userError(err)
+	}
+
+	result := make([]*model.RoleResponse, 0, len(roles))
+	for _, r := range roles {
+		result = append(result, model.NewRoleResponseFromEntity(r))
+	}
+	return ctx.Status(http.StatusOK).JSON(result)
+}
+
+func (u UserController) RemoveRole(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	roleId, err := uuid.Parse(ctx.Params("roleId"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err = u.removeRole.Execute(ctx.UserContext(), id, roleId, ctx.QueryBool("revoke_tokens"))
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (u UserController) ReplaceRoles(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	var data model.UserRolesRequest
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	err = u.replaceRoles.Execute(ctx.UserContext(), id, data.RoleIDs, data.RevokeTokens)
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func (u UserController) ChangeStatus(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	}
+	var data model.UserChangeStatus
+	if err := ctx.BodyParser(&data); err != nil {
+		return fiber.NewError(http.StatusInternalServerError, err.Error())
+	}
+	err = u.changeStatus.Execute(ctx.UserContext(), id, data.Enabled, data.RevokeTokens)
+	if err != nil {
+		return userError(err)
+	}
+
+	return ctx.SendStatus(http.StatusNoContent)
+}
+
+func userError(err error) error {
+	switch {
+	case errors.Is(err, user.ErrUserNotFound), errors.Is(err, role.ErrRoleNotFound), errors.Is(err, user.ErrRoleNotAssigned):
+		return fiber.NewError(http.StatusNotFound, err.Error())
+	case errors.Is(err, user.ErrUserAlreadyExists):
+		return fiber.NewError(http.StatusConflict, err.Error())
+	case errors.Is(err, user.ErrInvalidSort), errors.Is(err, user.ErrInvalidPage), errors.Is(err, user.ErrPasswordRequired),
+		errors.Is(err, user.ErrInvalidResetToken), errors.Is(err, user.ErrInvalidVerificationToken), errors.Is(err, user.ErrPasswordPolicy):
+		return fiber.NewError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, user.ErrInvalidPassword):
+		return fiber.NewError(http.StatusForbidden, err.Error())
+	}
+	return fiber.NewError(http.StatusInternalServerError, err.Error())
+}
+
+// passwordError answers a password the policy rejects with 400 Bad Request and every broken rule in the body,
+// so clients can show them all at once. Other errors are answered like userError.
+func passwordError(ctx *fiber.Ctx, err error) error {
+	var policyErr *user.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		return userError(err)
+	}
+	response := model.PasswordPolicyErrorResponse{Error: user.ErrPasswordPolicy.Error()}
+	for _, v := range policyErr.Violations {
+		response.Violations = append(response.Violations, model.PasswordViolationResponse{Rule: v.Rule, Message: v.Message})
+	}
+	return ctx.Status(http.StatusBadRequest).JSON(response)
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/role"
+	userApp "github.com/golauth/golauth/pkg/application/user"
 	"github.com/golauth/golauth/pkg/application/user/mock"
 	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/infra/api/controller/model"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -25,6 +28,13 @@ type UserControllerSuite struct {
 	ctrl         *gomock.Controller
 	findUserById *mock.MockFindUserById
 	addUserRole  *mock.MockAddUserRole
+	changeStatus *mock.MockChangeUserStatus
+	listUsers    *mock.MockListUsers
+	updateUser   *mock.MockUpdateUser
+	deleteUser   *mock.MockDeleteUser
+	findRoles    *mock.MockFindUserRoles
+	removeRole   *mock.MockRemoveUserRole
+	replaceRoles *mock.MockReplaceUserRoles
 	uc           UserController
 	app          *fiber.App
 }
@@ -40,10 +50,27 @@ func (s *UserControllerSuite) SetupTest() {
 	s.findUserById = mock.NewMockFindUserById(s.ctrl)
 	s.addUserRole = mock.NewMockAddUserRole(s.ctrl)
 
-	s.uc = NewUserController(s.findUserById, s.addUserRole)
+	s.changeStatus = mock.NewMockChangeUserStatus(s.ctrl)
+
+	s.listUsers = mock.NewMockListUsers(s.ctrl)
+	s.updateUser = mock.NewMockUpdateUser(s.ctrl)
+	s.deleteUser = mock.NewMockDeleteUser(s.ctrl)
+	s.findRoles = mock.NewMockFindUserRoles(s.ctrl)
+	s.removeRole = mock.NewMockRemoveUserRole(s.ctrl)
+	s.replaceRoles = mock.NewMockReplaceUserRoles(s.ctrl)
+
+	s.uc = NewUserController(s.findUserById, s.addUserRole, s.changeStatus, s.listUsers, s.updateUser, s.deleteUser,
+		s.findRoles, s.removeRole, s.replaceRoles)
 	s.app = fiber.New()
+	s.app.Get("/users", s.uc.List)
 	s.app.Get("/users/:id", s.uc.FindById)
+	s.app.Put("/users/:id", s.uc.Update)
+	s.app.Delete("/users/:id", s.uc.Delete)
 	s.app.Post("/users/:id/add-role", s.uc.AddRole)
+	s.app.Patch("/users/:id/change-status", s.uc.ChangeStatus)
+	s.app.Get("/users/:id/roles", s.uc.Roles)
+	s.app.Put("/users/:id/roles", s.uc.ReplaceRoles)
+	s.app.Delete("/users/:id/roles/:roleId", s.uc.RemoveRole)
 }
 
 func (s *UserControllerSuite) TearDownTest() {
@@ -136,3 +163,237 @@ func (s *UserControllerSuite) TestAddRoleErrSvc() {
 	b, _ := io.ReadAll(resp.Body)
 	s.Contains(string(b), errMessage)
 }
+
+func (s *UserControllerSuite) TestChangeStatusOk() {
+	userId := uuid.New()
+	body, _ := json.Marshal(model.UserChangeStatus{Enabled: false, RevokeTokens: true})
+
+	r, _ := http.NewRequest("PATCH", fmt.Sprintf("/users/%s/change-status", userId), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.changeStatus.EXPECT().Execute(r.Context(), userId, false, true).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestChangeStatusErrParseUUID() {
+	body, _ := json.Marshal(model.UserChangeStatus{Enabled: false})
+
+	r, _ := http.NewRequest("PATCH", "/users/abc/change-status", strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestChangeStatusErrSvc() {
+	userId := uuid.New()
+	errMessage := "could not change status for user"
+	body, _ := json.Marshal(model.UserChangeStatus{Enabled: true})
+
+	r, _ := http.NewRequest("PATCH", fmt.Sprintf("/users/%s/change-status", userId), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.changeStatus.EXPECT().Execute(r.Context(), userId, true, false).Return(errors.New(errMessage)).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusInternalServerError, resp.StatusCode)
+	b, _ := io.ReadAll(resp.Body)
+	s.Contains(string(b), errMessage)
+}
+
+func (s *UserControllerSuite) TestFindByIDNotFound() {
+	id := uuid.New()
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/users/%s", id), nil)
+
+	s.findUserById.EXPECT().Execute(r.Context(), id).Return(nil, userApp.ErrUserNotFound).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestListOk() {
+	users := []*entity.User{{ID: uuid.New(), Username: "admin", Enabled: true}}
+	enabled := true
+	filter := repository.UserFilter{Username: "adm", Email: "goauth", Enabled: &enabled, Role: "ADMIN", Sort: repository.UserSortCreationDate, Desc: true}
+	r, _ := http.NewRequest("GET", "/users?username=adm&email=goauth&enabled=true&role=ADMIN&sort=-creationDate&page=1&size=5", nil)
+
+	s.listUsers.EXPECT().Execute(r.Context(), filter, 1, 5).Return(&userApp.UserPage{Users: users, Page: 1, Size: 5, Total: 6}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result model.UserPageResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Len(result.Content, 1)
+	s.Equal("admin", result.Content[0].Username)
+	s.Equal(1, result.Page)
+	s.Equal(5, result.Size)
+	s.Equal(6, result.Total)
+}
+
+func (s *UserControllerSuite) TestListInvalidEnabled() {
+	r, _ := http.NewRequest("GET", "/users?enabled=maybe", nil)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestListInvalidSort() {
+	r, _ := http.NewRequest("GET", "/users?sort=password", nil)
+
+	s.listUsers.EXPECT().Execute(r.Context(), repository.UserFilter{Sort: "password"}, 0, 0).Return(nil, userApp.ErrInvalidSort).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestUpdateOk() {
+	id := uuid.New()
+	input := model.UpdateUserRequest{FirstName: "New", LastName: "Name", Email: "new@goauth.org", Document: "999"}
+	body, _ := json.Marshal(input)
+	r, _ := http.NewRequest("PUT", fmt.Sprintf("/users/%s", id), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.updateUser.EXPECT().Execute(r.Context(), id, input.ToEntity()).
+		Return(&entity.User{ID: id, Username: "admin", FirstName: "New", LastName: "Name", Email: "new@goauth.org", Document: "999"}, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result model.UserResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Equal("admin", result.Username)
+	s.Equal("new@goauth.org", result.Email)
+}
+
+func (s *UserControllerSuite) TestUpdateErrors() {
+	for expected, svcErr := range map[int]error{http.StatusNotFound: userApp.ErrUserNotFound, http.StatusConflict: userApp.ErrUserAlreadyExists} {
+		id := uuid.New()
+		r, _ := http.NewRequest("PUT", fmt.Sprintf("/users/%s", id), strings.NewReader(`{"email": "admin2@goauth.org"}`))
+		r.Header.Set("Content-Type", "application/json")
+
+		s.updateUser.EXPECT().Execute(r.Context(), id, gomock.Any()).Return(nil, svcErr).Times(1)
+
+		resp, _ := s.app.Test(r, -1)
+		s.Equal(expected, resp.StatusCode)
+	}
+}
+
+func (s *UserControllerSuite) TestDeleteOk() {
+	id := uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/users/%s", id), nil)
+
+	s.deleteUser.EXPECT().Execute(r.Context(), id).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestDeleteNotFound() {
+	id := uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/users/%s", id), nil)
+
+	s.deleteUser.EXPECT().Execute(r.Context(), id).Return(userApp.ErrUserNotFound).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestRolesOk() {
+	id := uuid.New()
+	roles := []*entity.Role{{ID: uuid.New(), Name: "ADMIN", Enabled: true}}
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/users/%s/roles", id), nil)
+
+	s.findRoles.EXPECT().Execute(r.Context(), id).Return(roles, nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusOK, resp.StatusCode)
+	var result []model.RoleResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Len(result, 1)
+	s.Equal("ADMIN", result[0].Name)
+}
+
+func (s *UserControllerSuite) TestRolesUserNotFound() {
+	id := uuid.New()
+	r, _ := http.NewRequest("GET", fmt.Sprintf("/users/%s/roles", id), nil)
+
+	s.findRoles.EXPECT().Execute(r.Context(), id).Return(nil, userApp.ErrUserNotFound).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestRemoveRoleOk() {
+	id, roleId := uuid.New(), uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/users/%s/roles/%s", id, roleId), nil)
+
+	s.removeRole.EXPECT().Execute(r.Context(), id, roleId, false).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestRemoveRoleRevokeTokens() {
+	id, roleId := uuid.New(), uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/users/%s/roles/%s?revoke_tokens=true", id, roleId), nil)
+
+	s.removeRole.EXPECT().Execute(r.Context(), id, roleId, true).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestRemoveRoleNotAssigned() {
+	id, roleId := uuid.New(), uuid.New()
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/users/%s/roles/%s", id, roleId), nil)
+
+	s.removeRole.EXPECT().Execute(r.Context(), id, roleId, false).Return(userApp.ErrRoleNotAssigned).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestRemoveRoleErrParseUUID() {
+	r, _ := http.NewRequest("DELETE", fmt.Sprintf("/users/%s/roles/abc", uuid.New()), nil)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestReplaceRolesOk() {
+	id := uuid.New()
+	roleIds := []uuid.UUID{uuid.New(), uuid.New()}
+	body, _ := json.Marshal(model.UserRolesRequest{RoleIDs: roleIds})
+	r, _ := http.NewRequest("PUT", fmt.Sprintf("/users/%s/roles", id), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.replaceRoles.EXPECT().Execute(r.Context(), id, roleIds, false).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestReplaceRolesRevokeTokens() {
+	id := uuid.New()
+	roleIds := []uuid.UUID{uuid.New()}
+	body, _ := json.Marshal(model.UserRolesRequest{RoleIDs: roleIds, RevokeTokens: true})
+	r, _ := http.NewRequest("PUT", fmt.Sprintf("/users/%s/roles", id), strings.NewReader(string(body)))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.replaceRoles.EXPECT().Execute(r.Context(), id, roleIds, true).Return(nil).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *UserControllerSuite) TestReplaceRolesRoleNotFound() {
+	id := uuid.New()
+	r, _ := http.NewRequest("PUT", fmt.Sprintf("/users/%s/roles", id), strings.NewReader(fmt.Sprintf(`{"roleIds": ["%s"]}`, uuid.New())))
+	r.Header.Set("Content-Type", "application/json")
+
+	s.replaceRoles.EXPECT().Execute(r.Context(), id, gomock.Any(), false).Return(role.ErrRoleNotFound).Times(1)
+
+	resp, _ := s.app.Test(r, -1)
+	s.Equal(http.StatusNotFound, resp.StatusCode)
+}
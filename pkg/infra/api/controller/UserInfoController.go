@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/user"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/google/uuid"
+	"net/http"
+)
+
+const bearerInvalidToken = `Bearer error="invalid_token"`
+
+type UserInfoController interface {
+	UserInfo(ctx *fiber.Ctx) error
+}
+
+type userInfoController struct {
+	findById user.FindUserById
+}
+
+func NewUserInfoController(findById user.FindUserById) UserInfoController {
+	return userInfoController{findById: findById}
+}
+
+// UserInfo returns the OpenID Connect claims of the user owning the bearer access token. Client tokens
+// have no user and are rejected.
+func (c userInfoController) UserInfo(ctx *fiber.Ctx) error {
+	p, ok := principal.FromContext(ctx)
+	if !ok || p.Username == "" {
+		ctx.Set(fiber.HeaderWWWAuthenticate, bearerInvalidToken)
+		return fiber.NewError(http.StatusUnauthorized)
+	}
+	id, err := uuid.Parse(p.Subject)
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, bearerInvalidToken)
+		return fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+	data, err := c.findById.Execute(ctx.UserContext(), id)
+	if err != nil {
+		ctx.Set(fiber.HeaderWWWAuthenticate, bearerInvalidToken)
+		return fiber.NewError(http.StatusUnauthorized, err.Error())
+	}
+	return ctx.Status(http.StatusOK).JSON(model.NewUserInfoResponseFromEntity(data))
+}
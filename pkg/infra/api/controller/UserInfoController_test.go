@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/application/user/mock"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/infra/api/controller/model"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"testing"
+)
+
+type UserInfoControllerSuite struct {
+	suite.Suite
+	*require.Assertions
+	ctrl         *gomock.Controller
+	findUserById *mock.MockFindUserById
+	principal    *entity.Principal
+	app          *fiber.App
+	user         *entity.User
+}
+
+func TestUserInfoControllerSuite(t *testing.T) {
+	suite.Run(t, new(UserInfoControllerSuite))
+}
+
+func (s *UserInfoControllerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctrl = gomock.NewController(s.T())
+	s.findUserById = mock.NewMockFindUserById(s.ctrl)
+	s.user = &entity.User{
+		ID:        uuid.New(),
+		Username:  "admin",
+		FirstName: "User",
+		LastName:  "Name",
+		Email:     "em@il.com",
+	}
+	s.principal = &entity.Principal{Subject: s.user.ID.String(), Username: s.user.Username}
+
+	s.app = fiber.New()
+	s.app.Use(func(ctx *fiber.Ctx) error {
+		if s.principal != nil {
+			principal.Store(ctx, s.principal)
+		}
+		return ctx.Next()
+	})
+	s.app.Get("/auth/userinfo", NewUserInfoController(s.findUserById).UserInfo)
+}
+
+func (s *UserInfoControllerSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func (s *UserInfoControllerSuite) TestUserInfoOk() {
+	s.findUserById.EXPECT().Execute(gomock.Any(), s.user.ID).Return(s.user, nil).Times(1)
+
+	r, _ := http.NewRequest("GET", "/auth/userinfo", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusOK, resp.StatusCode)
+
+	var result model.UserInfoResponse
+	s.NoError(json.NewDecoder(resp.Body).Decode(&result))
+	s.Equal(s.user.ID.String(), result.Sub)
+	s.Equal("User Name", result.Name)
+	s.Equal("User", result.GivenName)
+	s.Equal("Name", result.FamilyName)
+	s.Equal("admin", result.PreferredUsername)
+	s.Equal("em@il.com", result.Email)
+}
+
+func (s *UserInfoControllerSuite) TestUserInfoClientToken() {
+	s.principal = &entity.Principal{Subject: "service", ClientID: "service"}
+
+	r, _ := http.NewRequest("GET", "/auth/userinfo", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+	s.Equal(bearerInvalidToken, resp.Header.Get(fiber.HeaderWWWAuthenticate))
+}
+
+func (s *UserInfoControllerSuite) TestUserInfoWithoutPrincipal() {
+	s.principal = nil
+
+	r, _ := http.NewRequest("GET", "/auth/userinfo", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *UserInfoControllerSuite) TestUserInfoUserNotFound() {
+	s.findUserById.EXPECT().Execute(gomock.Any(), s.user.ID).Return(nil, errors.New("user not found")).Times(1)
+
+	r, _ := http.NewRequest("GET", "/auth/userinfo", nil)
+	resp, err := s.app.Test(r, -1)
+	s.NoError(err)
+	s.Equal(http.StatusUnauthorized, resp.StatusCode)
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+// AuthorizeRequest is the authorization request of the client, read from the query of the authorize
+// endpoint and posted back with the credentials by the login page.
+type AuthorizeRequest struct {
+	ResponseType        string `query:"response_type" form:"response_type"`
+	ClientID            string `query:"client_id" form:"client_id"`
+	RedirectURI         string `query:"redirect_uri" form:"redirect_uri"`
+	Scope               string `query:"scope" form:"scope"`
+	State               string `query:"state" form:"state"`
+	Nonce               string `query:"nonce" form:"nonce"`
+	CodeChallenge       string `query:"code_challenge" form:"code_challenge"`
+	CodeChallengeMethod string `query:"code_challenge_method" form:"code_challenge_method"`
+	Username            string `query:"-" form:"username"`
+	Password            string `query:"-" form:"password"`
+	CSRFToken           string `query:"-" form:"csrf_token"`
+}
+
+func (r AuthorizeRequest) ToEntity() *entity.AuthorizationCode {
+	return &entity.AuthorizationCode{
+		ClientID:            r.ClientID,
+		RedirectURI:         r.RedirectURI,
+		Scope:               r.Scope,
+		CodeChallenge:       r.CodeChallenge,
+		CodeChallengeMethod: r.CodeChallengeMethod,
+		Nonce:               r.Nonce,
+	}
+}
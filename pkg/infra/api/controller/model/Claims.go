@@ -4,10 +4,22 @@ import (
 	"github.com/cristalhq/jwt/v3"
 )
 
+// Claims are the claims of golauth access tokens. EmailVerified is only set on tokens issued to users.
 type Claims struct {
-	Username    string   `json:"username"`
-	FirstName   string   `json:"firstName"`
-	LastName    string   `json:"lastName"`
-	Authorities []string `json:"authorities,omitempty"`
+	Username      string   `json:"username,omitempty"`
+	FirstName     string   `json:"firstName,omitempty"`
+	LastName      string   `json:"lastName,omitempty"`
+	EmailVerified *bool    `json:"email_verified,omitempty"`
+	Authorities   []string `json:"authorities,omitempty"`
+	ClientID      string   `json:"client_id,omitempty"`
+	Scope         string   `json:"scope,omitempty"`
+	Act           *Actor   `json:"act,omitempty"`
 	jwt.StandardClaims
 }
+
+// Actor is the RFC 8693 act claim naming who acts on behalf of the subject. A token exchanged
+// again keeps the previous actor nested in its own act claim.
+type Actor struct {
+	Subject string `json:"sub"`
+	Act     *Actor `json:"act,omitempty"`
+}
@@ -0,0 +1,5 @@
+package model
+
+type CloseAccountRequest struct {
+	Password string `json:"password"`
+}
@@ -0,0 +1,22 @@
+package model
+
+type DeviceAuthorizationRequest struct {
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+	Scope        string `json:"scope" form:"scope"`
+}
+
+type DeviceVerificationRequest struct {
+	UserCode string `json:"user_code" form:"user_code"`
+	Action   string `json:"action" form:"action"`
+}
+
+// DeviceVerificationForm is posted by the device verification page, which signs the user in and approves or
+// denies the user code in one go.
+type DeviceVerificationForm struct {
+	UserCode  string `query:"user_code" form:"user_code"`
+	Action    string `query:"-" form:"action"`
+	Username  string `query:"-" form:"username"`
+	Password  string `query:"-" form:"password"`
+	CSRFToken string `query:"-" form:"csrf_token"`
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"net/url"
+	"time"
+)
+
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// NewDeviceAuthorizationResponse shows the user code formatted for people, and builds the complete verification
+// uri carrying it so devices can render it as a QR code.
+func NewDeviceAuthorizationResponse(deviceCode string, userCode string, code *entity.DeviceCode, verificationURI string, now time.Time) DeviceAuthorizationResponse {
+	complete := verificationURI
+	if u, err := url.Parse(verificationURI); err == nil {
+		query := u.Query()
+		query.Set("user_code", userCode)
+		u.RawQuery = query.Encode()
+		complete = u.String()
+	}
+	return DeviceAuthorizationResponse{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         verificationURI,
+		VerificationURIComplete: complete,
+		ExpiresIn:               int(code.ExpiresAt.Sub(now).Seconds()),
+		Interval:                code.Interval,
+	}
+}
@@ -0,0 +1,9 @@
+package model
+
+type EmailVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+type EmailVerificationConfirmRequest struct {
+	Token string `json:"token"`
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"github.com/cristalhq/jwt/v3"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"strings"
+)
+
+// ProfileClaims are the OpenID Connect standard claims built from the user, shared by the
+// id token and the userinfo response.
+type ProfileClaims struct {
+	Name              string `json:"name,omitempty"`
+	GivenName         string `json:"given_name,omitempty"`
+	FamilyName        string `json:"family_name,omitempty"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     bool   `json:"email_verified"`
+}
+
+type IDTokenClaims struct {
+	AuthTime int64  `json:"auth_time,omitempty"`
+	Nonce    string `json:"nonce,omitempty"`
+	AtHash   string `json:"at_hash,omitempty"`
+	ProfileClaims
+	jwt.StandardClaims
+}
+
+func NewProfileClaimsFromEntity(e *entity.User) ProfileClaims {
+	return ProfileClaims{
+		Name:              strings.TrimSpace(e.FirstName + " " + e.LastName),
+		GivenName:         e.FirstName,
+		FamilyName:        e.LastName,
+		PreferredUsername: e.Username,
+		Email:             e.Email,
+		EmailVerified:     e.EmailVerified,
+	}
+}
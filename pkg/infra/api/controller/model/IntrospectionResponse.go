@@ -0,0 +1,40 @@
+package model
+
+const tokenTypeBearer = "Bearer"
+
+type IntrospectionResponse struct {
+	Active      bool     `json:"active"`
+	Sub         string   `json:"sub,omitempty"`
+	Username    string   `json:"username,omitempty"`
+	Scope       string   `json:"scope,omitempty"`
+	Authorities []string `json:"authorities,omitempty"`
+	Exp         int64    `json:"exp,omitempty"`
+	Iat         int64    `json:"iat,omitempty"`
+	Iss         string   `json:"iss,omitempty"`
+	Aud         []string `json:"aud,omitempty"`
+	Jti         string   `json:"jti,omitempty"`
+	ClientID    string   `json:"client_id,omitempty"`
+	TokenType   string   `json:"token_type,omitempty"`
+}
+
+func NewIntrospectionResponseFromClaims(c *Claims) *IntrospectionResponse {
+	output := &IntrospectionResponse{
+		Active:      true,
+		Sub:         c.Subject,
+		Username:    c.Username,
+		Scope:       c.Scope,
+		Authorities: c.Authorities,
+		Iss:         c.Issuer,
+		Aud:         c.Audience,
+		Jti:         c.ID,
+		ClientID:    c.ClientID,
+		TokenType:   tokenTypeBearer,
+	}
+	if c.ExpiresAt != nil {
+		output.Exp = c.ExpiresAt.Unix()
+	}
+	if c.IssuedAt != nil {
+		output.Iat = c.IssuedAt.Unix()
+	}
+	return output
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"math/big"
+)
+
+const jwkUseSignature = "sig"
+
+type JwkResponse struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	Modulus   string `json:"n,omitempty"`
+	Exponent  string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JwksResponse struct {
+	Keys []JwkResponse `json:"keys"`
+}
+
+// NewJwkResponseFromEntity returns the public part of an asymmetric key, and false for HMAC secrets.
+func NewJwkResponseFromEntity(e *entity.SigningKey) (JwkResponse, bool) {
+	jwk := JwkResponse{
+		KeyID:     e.ID,
+		Algorithm: e.Algorithm,
+		Use:       jwkUseSignature,
+	}
+	switch key := e.PrivateKey.(type) {
+	case *rsa.PrivateKey:
+		jwk.KeyType = "RSA"
+		jwk.Modulus = encodeJwkValue(key.N.Bytes())
+		jwk.Exponent = encodeJwkValue(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PrivateKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = key.Curve.Params().Name
+		jwk.X = encodeJwkValue(key.X.FillBytes(make([]byte, size)))
+		jwk.Y = encodeJwkValue(key.Y.FillBytes(make([]byte, size)))
+	case ed25519.PrivateKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encodeJwkValue(key.Public().(ed25519.PublicKey))
+	default:
+		return JwkResponse{}, false
+	}
+	return jwk, true
+}
+
+func NewJwksResponseFromEntities(keys []*entity.SigningKey) *JwksResponse {
+	result := &JwksResponse{Keys: make([]JwkResponse, 0, len(keys))}
+	for _, k := range keys {
+		if jwk, ok := NewJwkResponseFromEntity(k); ok {
+			result.Keys = append(result.Keys, jwk)
+		}
+	}
+	return result
+}
+
+func encodeJwkValue(value []byte) string {
+	return base64.RawURLEncoding.EncodeToString(value)
+}
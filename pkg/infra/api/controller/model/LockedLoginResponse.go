@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"time"
+)
+
+type LockedLoginResponse struct {
+	Kind        string    `json:"kind"`
+	Subject     string    `json:"subject"`
+	Failures    int       `json:"failures"`
+	LastFailure time.Time `json:"lastFailure"`
+	LockedUntil time.Time `json:"lockedUntil"`
+}
+
+func NewLockedLoginResponses(failures []*entity.LoginFailure) []*LockedLoginResponse {
+	result := make([]*LockedLoginResponse, 0, len(failures))
+	for _, f := range failures {
+		response := &LockedLoginResponse{
+			Kind:        f.Kind,
+			Subject:     f.Subject,
+			Failures:    f.Failures,
+			LastFailure: f.LastFailure,
+		}
+		if f.LockedUntil != nil {
+			response.LockedUntil = *f.LockedUntil
+		}
+		result = append(result, response)
+	}
+	return result
+}
@@ -0,0 +1,11 @@
+package model
+
+type PasswordViolationResponse struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type PasswordPolicyErrorResponse struct {
+	Error      string                      `json:"error"`
+	Violations []PasswordViolationResponse `json:"violations"`
+}
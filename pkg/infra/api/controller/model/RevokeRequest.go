@@ -0,0 +1,7 @@
+package model
+
+type RevokeRequest struct {
+	Token        string `json:"token" form:"token"`
+	ClientID     string `json:"client_id" form:"client_id"`
+	ClientSecret string `json:"client_secret" form:"client_secret"`
+}
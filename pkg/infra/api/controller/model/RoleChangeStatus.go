@@ -1,5 +1,6 @@
 package model
 
 type RoleChangeStatus struct {
-	Enabled bool `json:"enabled"`
+	Enabled      bool `json:"enabled"`
+	RevokeTokens bool `json:"revoke_tokens"`
 }
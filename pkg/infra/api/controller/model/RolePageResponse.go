@@ -0,0 +1,20 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+type RolePageResponse struct {
+	Content []*RoleResponse `json:"content"`
+	Page    int             `json:"page"`
+	Size    int             `json:"size"`
+	Total   int             `json:"total"`
+}
+
+func NewRolePageResponse(roles []*entity.Role, page int, size int, total int) *RolePageResponse {
+	content := make([]*RoleResponse, 0, len(roles))
+	for _, r := range roles {
+		content = append(content, NewRoleResponseFromEntity(r))
+	}
+	return &RolePageResponse{Content: content, Page: page, Size: size, Total: total}
+}
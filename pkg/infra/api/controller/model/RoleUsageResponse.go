@@ -0,0 +1,32 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/google/uuid"
+)
+
+type RoleUsageResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Enabled     bool      `json:"enabled"`
+	Users       int       `json:"users"`
+	Authorities []string  `json:"authorities"`
+}
+
+func NewRoleUsageResponses(usage []*entity.RoleUsage) []*RoleUsageResponse {
+	result := make([]*RoleUsageResponse, 0, len(usage))
+	for _, u := range usage {
+		authorities := u.Authorities
+		if authorities == nil {
+			authorities = []string{}
+		}
+		result = append(result, &RoleUsageResponse{
+			ID:          u.Role.ID,
+			Name:        u.Role.Name,
+			Enabled:     u.Role.Enabled,
+			Users:       u.Users,
+			Authorities: authorities,
+		})
+	}
+	return result
+}
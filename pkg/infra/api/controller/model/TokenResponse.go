@@ -2,14 +2,28 @@ package model
 
 import (
 	"github.com/golauth/golauth/pkg/domain/entity"
-	"github.com/google/uuid"
+	"time"
 )
 
+// TokenTypeBearer is the type of every access token golauth issues.
+const TokenTypeBearer = "Bearer"
+
 type TokenResponse struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken uuid.UUID `json:"refresh_token"`
+	AccessToken     string `json:"access_token"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int    `json:"expires_in"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+	IDToken         string `json:"id_token,omitempty"`
+	IssuedTokenType string `json:"issued_token_type,omitempty"`
 }
 
 func NewTokenResponseFromEntity(e *entity.Token) *TokenResponse {
-	return &TokenResponse{AccessToken: e.AccessToken}
+	return &TokenResponse{
+		AccessToken:     e.AccessToken,
+		TokenType:       TokenTypeBearer,
+		ExpiresIn:       int(e.ExpiresIn / time.Second),
+		RefreshToken:    e.RefreshToken,
+		IDToken:         e.IDToken,
+		IssuedTokenType: e.IssuedTokenType,
+	}
 }
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+// UpdateProfileRequest carries the profile fields a user changes on their own account. Absent fields keep
+// their current value.
+type UpdateProfileRequest struct {
+	FirstName *string `json:"firstName"`
+	LastName  *string `json:"lastName"`
+	Email     *string `json:"email"`
+	Document  *string `json:"document"`
+}
+
+func (u UpdateProfileRequest) Apply(user *entity.User) *entity.User {
+	if u.FirstName != nil {
+		user.FirstName = *u.FirstName
+	}
+	if u.LastName != nil {
+		user.LastName = *u.LastName
+	}
+	if u.Email != nil {
+		user.Email = *u.Email
+	}
+	if u.Document != nil {
+		user.Document = *u.Document
+	}
+	return user
+}
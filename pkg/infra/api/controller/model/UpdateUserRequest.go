@@ -0,0 +1,21 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+type UpdateUserRequest struct {
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+	Document  string `json:"document"`
+}
+
+func (u UpdateUserRequest) ToEntity() *entity.User {
+	return &entity.User{
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		Email:     u.Email,
+		Document:  u.Document,
+	}
+}
@@ -0,0 +1,6 @@
+package model
+
+type UserChangeStatus struct {
+	Enabled      bool `json:"enabled"`
+	RevokeTokens bool `json:"revoke_tokens"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+type UserInfoResponse struct {
+	Sub string `json:"sub"`
+	ProfileClaims
+}
+
+func NewUserInfoResponseFromEntity(e *entity.User) *UserInfoResponse {
+	return &UserInfoResponse{
+		Sub:           e.ID.String(),
+		ProfileClaims: NewProfileClaimsFromEntity(e),
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+type UserPageResponse struct {
+	Content []*UserResponse `json:"content"`
+	Page    int             `json:"page"`
+	Size    int             `json:"size"`
+	Total   int             `json:"total"`
+}
+
+func NewUserPageResponse(users []*entity.User, page int, size int, total int) *UserPageResponse {
+	content := make([]*UserResponse, 0, len(users))
+	for _, u := range users {
+		content = append(content, NewUserResponseFromEntity(u))
+	}
+	return &UserPageResponse{Content: content, Page: page, Size: size, Total: total}
+}
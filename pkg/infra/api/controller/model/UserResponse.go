@@ -7,25 +7,27 @@ import (
 )
 
 type UserResponse struct {
-	ID           uuid.UUID `json:"id"`
-	Username     string    `json:"username"`
-	FirstName    string    `json:"firstName"`
-	LastName     string    `json:"lastName"`
-	Email        string    `json:"email"`
-	Document     string    `json:"document"`
-	Enabled      bool      `json:"enabled"`
-	CreationDate time.Time `json:"creationDate"`
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	FirstName     string    `json:"firstName"`
+	LastName      string    `json:"lastName"`
+	Email         string    `json:"email"`
+	Document      string    `json:"document"`
+	Enabled       bool      `json:"enabled"`
+	EmailVerified bool      `json:"emailVerified"`
+	CreationDate  time.Time `json:"creationDate"`
 }
 
 func NewUserResponseFromEntity(e *entity.User) *UserResponse {
 	return &UserResponse{
-		ID:           e.ID,
-		Username:     e.Username,
-		FirstName:    e.FirstName,
-		LastName:     e.LastName,
-		Email:        e.Email,
-		Document:     e.Document,
-		Enabled:      e.Enabled,
-		CreationDate: e.CreationDate,
+		ID:            e.ID,
+		Username:      e.Username,
+		FirstName:     e.FirstName,
+		LastName:      e.LastName,
+		Email:         e.Email,
+		Document:      e.Document,
+		Enabled:       e.Enabled,
+		EmailVerified: e.EmailVerified,
+		CreationDate:  e.CreationDate,
 	}
 }
@@ -0,0 +1,10 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+type UserRolesRequest struct {
+	RoleIDs      []uuid.UUID `json:"roleIds"`
+	RevokeTokens bool        `json:"revoke_tokens"`
+}
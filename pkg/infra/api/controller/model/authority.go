@@ -1,6 +1,7 @@
 package model
 
 import (
+	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/google/uuid"
 	"time"
 )
@@ -11,6 +12,14 @@ type AuthorityRequest struct {
 	Description string    `json:"description"`
 }
 
+func (a AuthorityRequest) ToEntity() *entity.Authority {
+	return &entity.Authority{
+		ID:          a.ID,
+		Name:        a.Name,
+		Description: a.Description,
+	}
+}
+
 type AuthorityResponse struct {
 	ID           uuid.UUID `json:"id"`
 	Name         string    `json:"name"`
@@ -18,3 +27,25 @@ type AuthorityResponse struct {
 	Enabled      bool      `json:"enabled"`
 	CreationDate time.Time `json:"creationDate"`
 }
+
+func NewAuthorityResponseFromEntity(e *entity.Authority) *AuthorityResponse {
+	return &AuthorityResponse{
+		ID:           e.ID,
+		Name:         e.Name,
+		Description:  e.Description,
+		Enabled:      e.Enabled,
+		CreationDate: e.CreationDate,
+	}
+}
+
+func NewAuthorityResponses(authorities []*entity.Authority) []*AuthorityResponse {
+	result := make([]*AuthorityResponse, 0, len(authorities))
+	for _, a := range authorities {
+		result = append(result, NewAuthorityResponseFromEntity(a))
+	}
+	return result
+}
+
+type AuthorityChangeStatus struct {
+	Enabled bool `json:"enabled"`
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/google/uuid"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+// RolePrefix marks the entries of a rule naming a role instead of an authority, such as "role:ADMIN".
+const RolePrefix = "role:"
+
+// AuthorizationRules maps a route name to the authorities and roles allowed to call it. The caller needs any
+// one of them; routes without a rule only require a valid token.
+type AuthorizationRules map[string][]string
+
+func DefaultAuthorizationRules() AuthorizationRules {
+	return AuthorizationRules{
+		"listUsers":               {"ADMIN"},
+		"getUser":                 {"ADMIN"},
+		"updateUser":              {"ADMIN"},
+		"deleteUser":              {"ADMIN"},
+		"addRoleToUser":           {"ADMIN"},
+		"getUserRoles":            {"ADMIN"},
+		"replaceUserRoles":        {"ADMIN"},
+		"removeUserRole":          {"ADMIN"},
+		"changeUserStatus":        {"ADMIN"},
+		"listRoles":               {"ADMIN"},
+		"addRole":                 {"ADMIN"},
+		"findRoleByName":          {"ADMIN"},
+		"editRole":                {"ADMIN"},
+		"changeStatus":            {"ADMIN"},
+		"deleteRole":              {"ADMIN"},
+		"roleUsage":               {"ADMIN"},
+		"getRoleAuthorities":      {"ADMIN"},
+		"addAuthorityToRole":      {"ADMIN"},
+		"removeAuthorityFromRole": {"ADMIN"},
+		"listAuthorities":         {"ADMIN"},
+		"addAuthority":            {"ADMIN"},
+		"editAuthority":           {"ADMIN"},
+		"changeAuthorityStatus":   {"ADMIN"},
+		"listLockedLogins":        {"ADMIN"},
+		"unlockLogin":             {"ADMIN"},
+	}
+}
+
+// LoadAuthorizationRules reads a json object of route names to authorities and roles from path and applies it over base.
+func LoadAuthorizationRules(path string, base AuthorizationRules) (AuthorizationRules, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read authorization rules: %w", err)
+	}
+	var overrides AuthorizationRules
+	err = json.Unmarshal(content, &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse authorization rules: %w", err)
+	}
+	rules := make(AuthorizationRules, len(base)+len(overrides))
+	for name, authorities := range base {
+		rules[name] = authorities
+	}
+	for name, authorities := range overrides {
+		rules[name] = authorities
+	}
+	return rules, nil
+}
+
+// AuthorizationMiddleware enforces the rules of the routes. Authorities are read from the access token, while
+// roles are looked up for the user on each call, so they only cost a query on routes whose rule names one.
+type AuthorizationMiddleware struct {
+	rules              AuthorizationRules
+	userRoleRepository repository.UserRoleRepository
+}
+
+func NewAuthorizationMiddleware(rules AuthorizationRules, userRoleRepository repository.UserRoleRepository) *AuthorizationMiddleware {
+	return &AuthorizationMiddleware{rules: rules, userRoleRepository: userRoleRepository}
+}
+
+// Require returns a handler enforcing the rule configured for routeName.
+func (m *AuthorizationMiddleware) Require(routeName string) fiber.Handler {
+	rule := m.rules[routeName]
+	authorities := make([]string, 0, len(rule))
+	roles := make([]string, 0)
+	for _, entry := range rule {
+		if name, ok := strings.CutPrefix(entry, RolePrefix); ok {
+			roles = append(roles, name)
+		} else {
+			authorities = append(authorities, entry)
+		}
+	}
+	return func(ctx *fiber.Ctx) error {
+		p, ok := principal.FromContext(ctx)
+		if !ok {
+			return fiber.NewError(http.StatusUnauthorized)
+		}
+		if len(rule) == 0 || p.HasAnyAuthority(authorities...) {
+			return ctx.Next()
+		}
+		granted, err := m.hasAnyRole(ctx, p, roles)
+		if err != nil {
+			return fiber.NewError(http.StatusInternalServerError, err.Error())
+		}
+		if !granted {
+			return fiber.NewError(http.StatusForbidden)
+		}
+		return ctx.Next()
+	}
+}
+
+// hasAnyRole reports whether the user behind the principal holds one of the enabled roles. Client tokens have
+// no user and hold no role.
+func (m *AuthorizationMiddleware) hasAnyRole(ctx *fiber.Ctx, p *entity.Principal, roles []string) (bool, error) {
+	if len(roles) == 0 || p.Username == "" {
+		return false, nil
+	}
+	userID, err := uuid.Parse(p.Subject)
+	if err != nil {
+		return false, nil
+	}
+	userRoles, err := m.userRoleRepository.FindRolesByUserID(ctx.UserContext(), userID)
+	if err != nil {
+		return false, err
+	}
+	for _, r := range userRoles {
+		if r.Enabled && slices.Contains(roles, r.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository/mock"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuthorizationMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	userRoleRepository := mock.NewMockUserRoleRepository(ctrl)
+	m := NewAuthorizationMiddleware(AuthorizationRules{
+		"addRole":  {"ADMIN", "ROLE_MANAGER"},
+		"editRole": {"ADMIN", "role:SUPPORT"},
+	}, userRoleRepository)
+	ok := func(ctx *fiber.Ctx) error { return ctx.SendStatus(http.StatusOK) }
+
+	newApp := func(p *entity.Principal) *fiber.App {
+		app := fiber.New()
+		app.Use(func(ctx *fiber.Ctx) error {
+			if p != nil {
+				principal.Store(ctx, p)
+			}
+			return ctx.Next()
+		})
+		app.Post("/roles", m.Require("addRole"), ok)
+		app.Put("/roles", m.Require("editRole"), ok)
+		app.Get("/open", m.Require("open"), ok)
+		return app
+	}
+	call := func(app *fiber.App, method string, path string) int {
+		req, _ := http.NewRequest(method, path, nil)
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	t.Run("authority granted", func(t *testing.T) {
+		app := newApp(&entity.Principal{Authorities: []string{"USER", "ROLE_MANAGER"}})
+		assert.Equal(t, http.StatusOK, call(app, "POST", "/roles"))
+	})
+
+	t.Run("authority missing", func(t *testing.T) {
+		app := newApp(&entity.Principal{Authorities: []string{"USER"}})
+		assert.Equal(t, http.StatusForbidden, call(app, "POST", "/roles"))
+	})
+
+	t.Run("authority rule does not look up roles", func(t *testing.T) {
+		app := newApp(&entity.Principal{Subject: uuid.NewString(), Username: "user", Authorities: []string{"USER"}})
+		assert.Equal(t, http.StatusForbidden, call(app, "POST", "/roles"))
+	})
+
+	t.Run("role granted", func(t *testing.T) {
+		userID := uuid.New()
+		userRoleRepository.EXPECT().FindRolesByUserID(gomock.Any(), userID).
+			Return([]*entity.Role{{Name: "USER", Enabled: true}, {Name: "SUPPORT", Enabled: true}}, nil).Times(1)
+		app := newApp(&entity.Principal{Subject: userID.String(), Username: "support"})
+		assert.Equal(t, http.StatusOK, call(app, "PUT", "/roles"))
+	})
+
+	t.Run("role disabled", func(t *testing.T) {
+		userID := uuid.New()
+		userRoleRepository.EXPECT().FindRolesByUserID(gomock.Any(), userID).
+			Return([]*entity.Role{{Name: "SUPPORT", Enabled: false}}, nil).Times(1)
+		app := newApp(&entity.Principal{Subject: userID.String(), Username: "support"})
+		assert.Equal(t, http.StatusForbidden, call(app, "PUT", "/roles"))
+	})
+
+	t.Run("role lookup fails", func(t *testing.T) {
+		userID := uuid.New()
+		userRoleRepository.EXPECT().FindRolesByUserID(gomock.Any(), userID).Return(nil, errors.New("db down")).Times(1)
+		app := newApp(&entity.Principal{Subject: userID.String(), Username: "support"})
+		assert.Equal(t, http.StatusInternalServerError, call(app, "PUT", "/roles"))
+	})
+
+	t.Run("client token holds no role", func(t *testing.T) {
+		app := newApp(&entity.Principal{Subject: "service", ClientID: "service"})
+		assert.Equal(t, http.StatusForbidden, call(app, "PUT", "/roles"))
+	})
+
+	t.Run("route without rule", func(t *testing.T) {
+		app := newApp(&entity.Principal{Authorities: []string{"USER"}})
+		assert.Equal(t, http.StatusOK, call(app, "GET", "/open"))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		app := newApp(nil)
+		assert.Equal(t, http.StatusUnauthorized, call(app, "POST", "/roles"))
+	})
+}
+
+func TestLoadAuthorizationRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`{"addRole": ["SUPER_ADMIN"], "getUser": []}`), 0600))
+
+	rules, err := LoadAuthorizationRules(path, DefaultAuthorizationRules())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SUPER_ADMIN"}, rules["addRole"])
+	assert.Empty(t, rules["getUser"])
+	assert.Equal(t, []string{"ADMIN"}, rules["editRole"])
+}
+
+func TestLoadAuthorizationRulesInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`["ADMIN"]`), 0600))
+
+	_, err := LoadAuthorizationRules(path, DefaultAuthorizationRules())
+	assert.Error(t, err)
+
+	_, err = LoadAuthorizationRules(filepath.Join(t.TempDir(), "missing.json"), DefaultAuthorizationRules())
+	assert.Error(t, err)
+}
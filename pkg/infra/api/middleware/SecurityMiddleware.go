@@ -1,9 +1,9 @@
 package middleware
 
 import (
-	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golauth/golauth/pkg/application/token"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
 	"net/http"
 )
 
@@ -16,27 +16,36 @@ func NewSecurityMiddleware(validateToken token.ValidateToken, pathPrefix string)
 	return &SecurityMiddleware{
 		validateToken: validateToken,
 		publicURI: map[string]bool{
-			pathPrefix + "/token":       true,
-			pathPrefix + "/check_token": true,
-			pathPrefix + "/signup":      true,
+			pathPrefix + "/authorize":              true,
+			pathPrefix + "/token":                  true,
+			pathPrefix + "/check_token":            true,
+			pathPrefix + "/revoke":                 true,
+			pathPrefix + "/introspect":             true,
+			pathPrefix + "/signup":                 true,
+			pathPrefix + "/device_authorization":   true,
+			pathPrefix + "/device/verify":          true,
+			pathPrefix + "/password-reset":         true,
+			pathPrefix + "/password-reset/confirm": true,
+			pathPrefix + "/verify-email":           true,
+			pathPrefix + "/verify-email/confirm":   true,
+			"/.well-known/jwks.json":               true,
+			"/.well-known/openid-configuration":    true,
 		},
 	}
 }
 
 func (s *SecurityMiddleware) Apply() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
-		requestURI := ctx.Request().URI().String()
-		if s.isPrivateURI(requestURI) {
-			fmt.Println(ctx.GetReqHeaders())
-			bearerTk := ctx.Get("Authorization", "")
-			t, err := token.ExtractToken(bearerTk)
+		if s.isPrivateURI(ctx.Path()) {
+			t, err := token.ExtractToken(ctx.Get("Authorization", ""))
 			if err != nil {
-				return fiber.NewError(http.StatusInternalServerError, err.Error())
+				return fiber.NewError(http.StatusUnauthorized, err.Error())
 			}
-			err = s.validateToken.Execute(t)
+			p, err := s.validateToken.Execute(t)
 			if err != nil {
 				return fiber.NewError(http.StatusUnauthorized, err.Error())
 			}
+			principal.Store(ctx, p)
 		}
 		return ctx.Next()
 	}
@@ -5,14 +5,17 @@ import (
 	"fmt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golauth/golauth/pkg/application/token"
+	tokenMock "github.com/golauth/golauth/pkg/application/token/mock"
 	"github.com/golauth/golauth/pkg/application/user/mock"
 	"github.com/golauth/golauth/pkg/domain/entity"
 	mock2 "github.com/golauth/golauth/pkg/domain/factory/mock"
 	mock3 "github.com/golauth/golauth/pkg/domain/repository/mock"
 	"github.com/golauth/golauth/pkg/infra/api/controller"
+	"github.com/golauth/golauth/pkg/infra/api/principal"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"io"
 	"net/http"
 	"testing"
 )
@@ -24,12 +27,19 @@ func TestSecurityMiddleware(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	findUserById := mock.NewMockFindUserById(ctrl)
 	addUserRole := mock.NewMockAddUserRole(ctrl)
-	userController := controller.NewUserController(findUserById, addUserRole)
+	userController := controller.NewUserController(findUserById, addUserRole, mock.NewMockChangeUserStatus(ctrl),
+		mock.NewMockListUsers(ctrl), mock.NewMockUpdateUser(ctrl), mock.NewMockDeleteUser(ctrl),
+		mock.NewMockFindUserRoles(ctrl), mock.NewMockRemoveUserRole(ctrl), mock.NewMockReplaceUserRoles(ctrl))
 
-	key := token.GeneratePrivateKey()
+	keyRepository := mock3.NewMockSigningKeyRepository(ctrl)
+	key, err := token.GenerateSigningKey(token.DefaultAlgorithm)
+	assert.NoError(t, err)
+	keyRepository.EXPECT().FindAll(gomock.Any()).Return([]*entity.SigningKey{key}, nil).AnyTimes()
+	keyStore := token.NewKeyStore(keyRepository, token.KeyStoreConfig{})
+	assert.NoError(t, keyStore.Load(context.Background()))
 
 	app := fiber.New()
-	app.Use(NewSecurityMiddleware(token.NewValidateToken(key), "/").Apply())
+	app.Use(NewSecurityMiddleware(token.NewValidateToken(keyStore, token.NewDenylist(mock3.NewMockRevokedTokenRepository(ctrl), mock3.NewMockRevokedSubjectRepository(ctrl)), token.TokenConfig{}), "/").Apply())
 	app.Get("/users/:id", userController.FindById)
 
 	t.Run("valid token", func(t *testing.T) {
@@ -37,20 +47,26 @@ func TestSecurityMiddleware(t *testing.T) {
 		userAuthorityRepository := mock3.NewMockUserAuthorityRepository(ctrl)
 		roleRepository := mock3.NewMockRoleRepository(ctrl)
 		userRoleRepository := mock3.NewMockUserRoleRepository(ctrl)
+		refreshTokenRepository := mock3.NewMockRefreshTokenRepository(ctrl)
 
 		repoFactory := mock2.NewMockRepositoryFactory(ctrl)
 		repoFactory.EXPECT().NewUserRepository().Return(userRepository)
 		repoFactory.EXPECT().NewUserAuthorityRepository().Return(userAuthorityRepository)
 		repoFactory.EXPECT().NewRoleRepository().Return(roleRepository)
 		repoFactory.EXPECT().NewUserRoleRepository().Return(userRoleRepository)
+		repoFactory.EXPECT().NewRefreshTokenRepository().Return(refreshTokenRepository)
 
-		userRepository.EXPECT().FindByUsername(gomock.Any(), "admin").Return(&entity.User{Username: username, Password: passwordEncoded}, nil)
+		userRepository.EXPECT().FindByUsername(gomock.Any(), "admin").Return(&entity.User{Username: username, Password: passwordEncoded, Enabled: true}, nil)
 		userAuthorityRepository.EXPECT().FindAuthoritiesByUserID(gomock.Any(), gomock.Any()).Return([]string{"ADMIN"}, nil)
+		refreshTokenRepository.EXPECT().Create(gomock.Any(), gomock.Any()).Return(&entity.RefreshToken{}, nil)
 
-		generateJwtToken := token.NewGenerateJwtToken(key)
-		generateToken := token.NewGenerateToken(repoFactory, generateJwtToken)
+		generateJwtToken := token.NewGenerateJwtToken(keyStore, token.TokenConfig{})
+		loginThrottle := tokenMock.NewMockLoginThrottle(ctrl)
+		loginThrottle.EXPECT().Attempt(gomock.Any(), username, "").Return(nil)
+		loginThrottle.EXPECT().Succeed(gomock.Any(), username, "").Return(nil)
+		generateToken := token.NewGenerateToken(repoFactory, generateJwtToken, loginThrottle, token.LoginConfig{})
 
-		tk, err := generateToken.Execute(context.Background(), username, password)
+		tk, err := generateToken.Execute(context.Background(), username, password, "")
 		assert.NoError(t, err)
 
 		req, err := http.NewRequest("GET", "/users/37fe41b4-24bf-4da9-9124-615cc72865a5", nil)
@@ -77,3 +93,51 @@ func TestSecurityMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
 	})
 }
+
+func TestSecurityMiddlewarePrincipal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	validateToken := tokenMock.NewMockValidateToken(ctrl)
+
+	app := fiber.New()
+	app.Use(NewSecurityMiddleware(validateToken, "/auth").Apply())
+	app.Get("/auth/me", func(ctx *fiber.Ctx) error {
+		p, ok := principal.FromContext(ctx)
+		if !ok {
+			return ctx.SendStatus(http.StatusInternalServerError)
+		}
+		return ctx.SendString(p.Username)
+	})
+	app.Post("/auth/token", func(ctx *fiber.Ctx) error {
+		_, ok := principal.FromContext(ctx)
+		assert.False(t, ok)
+		return ctx.SendStatus(http.StatusOK)
+	})
+
+	t.Run("principal stored in locals", func(t *testing.T) {
+		validateToken.EXPECT().Execute("abc").Return(&entity.Principal{Username: "admin"}, nil)
+		req, _ := http.NewRequest("GET", "/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer abc")
+
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		b, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "admin", string(b))
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		req, _ := http.NewRequest("GET", "/auth/me", nil)
+
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("public path with query string", func(t *testing.T) {
+		req, _ := http.NewRequest("POST", "/auth/token?foo=bar", nil)
+
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
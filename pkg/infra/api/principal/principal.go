@@ -0,0 +1,19 @@
+package principal
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/golauth/golauth/pkg/domain/entity"
+)
+
+const localsKey = "golauth.principal"
+
+// Store keeps the authenticated principal in the request locals for the handlers down the chain.
+func Store(ctx *fiber.Ctx, p *entity.Principal) {
+	ctx.Locals(localsKey, p)
+}
+
+// FromContext returns the principal stored by the security middleware, if the request was authenticated.
+func FromContext(ctx *fiber.Ctx) (*entity.Principal, bool) {
+	p, ok := ctx.Locals(localsKey).(*entity.Principal)
+	return p, ok && p != nil
+}
@@ -1,18 +1,41 @@
 package api
 
 import (
+	"context"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/golauth/golauth/pkg/application/client"
 	"github.com/golauth/golauth/pkg/application/token"
 	"github.com/golauth/golauth/pkg/application/user"
 	"github.com/golauth/golauth/pkg/domain/factory"
+	"github.com/golauth/golauth/pkg/domain/mail"
 	"github.com/golauth/golauth/pkg/infra/api/controller"
 	"github.com/golauth/golauth/pkg/infra/api/middleware"
+	infraAudit "github.com/golauth/golauth/pkg/infra/audit"
+	infraMail "github.com/golauth/golauth/pkg/infra/mail"
+	"github.com/golauth/golauth/pkg/infra/repository/file"
+	"github.com/sirupsen/logrus"
 	"os"
+	"strings"
+	"time"
 )
 
-const pathPrefix = "/auth"
+const (
+	pathPrefix                = "/auth"
+	jwksPath                  = "/.well-known/jwks.json"
+	openIDConfigurationPath   = "/.well-known/openid-configuration"
+	defaultKeyGracePeriod     = 24 * time.Hour
+	defaultKeyRefreshInterval = time.Minute
+	defaultDenylistInterval   = 30 * time.Second
+	defaultPort               = "8080"
+	deviceVerificationPath    = "/device"
+	deviceVerificationPage    = "/device/verify"
+	passwordResetPath         = "/password-reset"
+	verifyEmailPath           = "/verify-email"
+	defaultMailFrom           = "golauth@localhost"
+	defaultMailOutboxDir      = "outbox"
+)
 
 type Router interface {
 	Config() *fiber.App
@@ -21,62 +44,351 @@ type Router interface {
 type router struct {
 	signupController     controller.SignupController
 	tokenController      controller.TokenController
+	authorizeController  controller.AuthorizeController
 	checkTokenController controller.CheckTokenController
+	revokeController     controller.RevokeController
+	introspectController controller.IntrospectController
+	deviceController     controller.DeviceAuthorizationController
+	userInfoController   controller.UserInfoController
 	userController       controller.UserController
+	accountController    controller.AccountController
+	resetController      controller.PasswordResetController
+	verifyController     controller.EmailVerificationController
+	loginLockController  controller.LoginLockController
 	roleController       controller.RoleController
+	authorityController  controller.AuthorityController
+	jwksController       controller.JwksController
+	discoveryController  controller.DiscoveryController
 	validateToken        token.ValidateToken
+	authorization        *middleware.AuthorizationMiddleware
 }
 
 func NewRouter(repoFactory factory.RepositoryFactory) Router {
 	uRepo := repoFactory.NewUserRepository()
 	urRepo := repoFactory.NewUserRoleRepository()
 	uaRepo := repoFactory.NewUserAuthorityRepository()
-	key := token.GeneratePrivateKey()
-	jwtToken := token.NewGenerateJwtToken(key)
+	keyStore := newKeyStore(repoFactory)
+	denylist := newDenylist(repoFactory)
+	tokenConfig := newTokenConfig()
+	mailer := newMailer()
+	auditor := infraAudit.NewLogAuditor(logrus.StandardLogger())
+	loginConfig := newLoginConfig()
+	jwtToken := token.NewGenerateJwtToken(keyStore, tokenConfig)
 
-	createUser := user.NewCreateUser(repoFactory)
+	passwordPolicy := user.NewPasswordPolicy(repoFactory, newPasswordPolicyConfig())
+	createUser := user.NewCreateUser(repoFactory, passwordPolicy)
 	findUserById := user.NewFindUserById(uRepo)
 	addUserRole := user.NewAddUserRole(urRepo)
-	generateToken := token.NewGenerateToken(repoFactory, jwtToken)
-	validateToken := token.NewValidateToken(key)
+	revokeUserTokens := token.NewRevokeUserTokens(repoFactory, denylist, tokenConfig)
+	changeUserStatus := user.NewChangeUserStatus(uRepo, revokeUserTokens)
+	listUsers := user.NewListUsers(uRepo)
+	updateUser := user.NewUpdateUser(uRepo)
+	deleteUser := user.NewDeleteUser(uRepo, revokeUserTokens)
+	findUserRoles := user.NewFindUserRoles(repoFactory)
+	removeUserRole := user.NewRemoveUserRole(repoFactory, revokeUserTokens)
+	replaceUserRoles := user.NewReplaceUserRoles(repoFactory, revokeUserTokens)
+	requestPasswordReset := user.NewRequestPasswordReset(repoFactory, mailer, newPasswordResetConfig())
+	resetPassword := user.NewResetPassword(repoFactory, revokeUserTokens, passwordPolicy)
+	requestEmailVerification := user.NewRequestEmailVerification(repoFactory, mailer, newEmailVerificationConfig())
+	verifyEmail := user.NewVerifyEmail(repoFactory)
+	mailThrottle := user.NewMailThrottle(repoFactory, newMailThrottleConfig())
+	loginThrottle := token.NewLoginThrottle(repoFactory, auditor, newLoginThrottleConfig())
+	changePassword := user.NewChangePassword(uRepo, loginThrottle, revokeUserTokens, passwordPolicy)
+	closeAccount := user.NewCloseAccount(uRepo, loginThrottle, deleteUser)
+	listLockedLogins := token.NewListLockedLogins(repoFactory)
+	unlockLogin := token.NewUnlockLogin(repoFactory, auditor)
+	generateToken := token.NewGenerateToken(repoFactory, jwtToken, loginThrottle, loginConfig)
+	validateToken := token.NewValidateToken(keyStore, denylist, tokenConfig)
+	revokeToken := token.NewRevokeToken(repoFactory, keyStore, denylist)
+	introspectToken := token.NewIntrospectToken(keyStore, denylist, tokenConfig)
+	refreshToken := token.NewRefreshToken(repoFactory, jwtToken)
+	authenticateClient := client.NewAuthenticateClient(repoFactory.NewClientRepository(), keyStore)
+	generateClientToken := token.NewGenerateClientToken(jwtToken)
+	generateAuthorizationCode := token.NewGenerateAuthorizationCode(repoFactory, loginThrottle, loginConfig)
+	exchangeAuthorizationCode := token.NewExchangeAuthorizationCode(repoFactory, jwtToken)
+	exchangeToken := token.NewExchangeToken(repoFactory, introspectToken, jwtToken)
+	generateDeviceCode := token.NewGenerateDeviceCode(repoFactory)
+	verifyDeviceCode := token.NewVerifyDeviceCode(repoFactory, loginThrottle, loginConfig)
+	exchangeDeviceCode := token.NewExchangeDeviceCode(repoFactory, jwtToken)
 
 	return &router{
-		signupController:     controller.NewSignupController(createUser),
-		tokenController:      controller.NewTokenController(uRepo, uaRepo, generateToken),
+		signupController:     controller.NewSignupController(createUser, requestEmailVerification),
+		tokenController:      controller.NewTokenController(uRepo, uaRepo, generateToken, refreshToken, authenticateClient, generateClientToken, exchangeAuthorizationCode, exchangeToken, exchangeDeviceCode),
+		authorizeController:  controller.NewAuthorizeController(generateAuthorizationCode),
 		checkTokenController: controller.NewCheckTokenController(validateToken),
-		userController:       controller.NewUserController(findUserById, addUserRole),
-		roleController:       controller.NewRoleController(repoFactory),
-		validateToken:        validateToken,
+		revokeController:     controller.NewRevokeController(authenticateClient, revokeToken),
+		introspectController: controller.NewIntrospectController(authenticateClient, introspectToken),
+		deviceController:     controller.NewDeviceAuthorizationController(authenticateClient, generateDeviceCode, verifyDeviceCode, newDeviceVerificationURI(tokenConfig)),
+		userInfoController:   controller.NewUserInfoController(findUserById),
+		userController:       controller.NewUserController(findUserById, addUserRole, changeUserStatus, listUsers, updateUser, deleteUser, findUserRoles, removeUserRole, replaceUserRoles),
+		accountController:    controller.NewAccountController(findUserById, updateUser, changePassword, closeAccount),
+		resetController:      controller.NewPasswordResetController(requestPasswordReset, resetPassword, mailThrottle),
+		verifyController:     controller.NewEmailVerificationController(requestEmailVerification, verifyEmail, mailThrottle),
+		loginLockController:  controller.NewLoginLockController(listLockedLogins, unlockLogin),
+		roleController:       controller.NewRoleController(repoFactory, revokeUserTokens),
+		authorityController:  controller.NewAuthorityController(repoFactory),
+		jwksController:       controller.NewJwksController(keyStore),
+		discoveryController: controller.NewDiscoveryController(keyStore, controller.DiscoveryConfig{
+			Issuer:     tokenConfig.Issuer,
+			GrantTypes: []string{token.GrantTypePassword, token.GrantTypeRefreshToken, token.GrantTypeClientCredentials, token.GrantTypeAuthorizationCode, token.GrantTypeTokenExchange, token.GrantTypeDeviceCode},
+			Scopes:     []string{token.ScopeOpenID},
+		}),
+		validateToken: validateToken,
+		authorization: middleware.NewAuthorizationMiddleware(newAuthorizationRules(), urRepo),
+	}
+}
+
+func newKeyStore(repoFactory factory.RepositoryFactory) token.KeyStore {
+	algorithms := getEnvList("SIGNING_ALGORITHMS", []string{token.DefaultAlgorithm})
+	for _, algorithm := range algorithms {
+		if err := token.ValidateAlgorithm(algorithm); err != nil {
+			logrus.Fatalf("invalid SIGNING_ALGORITHMS, supported are %v: %v", token.SupportedAlgorithms(), err)
+		}
+	}
+	repo := repoFactory.NewSigningKeyRepository()
+	if dir := os.Getenv("SIGNING_KEYS_DIR"); dir != "" {
+		repo = file.NewSigningKeyRepository(dir, algorithms[0])
+	}
+	keyStore := token.NewKeyStore(repo, token.KeyStoreConfig{
+		Algorithms:       algorithms,
+		RotationInterval: getEnvDuration("KEY_ROTATION_INTERVAL", 0),
+		GracePeriod:      getEnvDuration("KEY_GRACE_PERIOD", defaultKeyGracePeriod),
+	})
+	if err := keyStore.Load(context.Background()); err != nil {
+		logrus.Fatal(err)
+	}
+	token.ScheduleKeyRotation(context.Background(), keyStore, getEnvDuration("KEY_REFRESH_INTERVAL", defaultKeyRefreshInterval))
+	return keyStore
+}
+
+// newTokenConfig reads the issuer from ISSUER_URL, falling back to the local address, and the audience
+// golauth accepts on its own routes from TOKEN_AUDIENCE, falling back to the issuer.
+func newTokenConfig() token.TokenConfig {
+	issuer := strings.TrimSuffix(os.Getenv("ISSUER_URL"), "/")
+	if issuer == "" {
+		port := os.Getenv("PORT")
+		if port == "" {
+			port = defaultPort
+		}
+		issuer = "http://localhost:" + port
+	}
+	audience := os.Getenv("TOKEN_AUDIENCE")
+	if audience == "" {
+		audience = issuer
+	}
+	return token.TokenConfig{
+		Issuer:   issuer,
+		Audience: audience,
+		Leeway:   getEnvDuration("TOKEN_LEEWAY", 0),
+	}
+}
+
+// newDeviceVerificationURI reads the page where users type device user codes from DEVICE_VERIFICATION_URI,
+// falling back to golauth's own verification page.
+func newDeviceVerificationURI(tokenConfig token.TokenConfig) string {
+	if uri := os.Getenv("DEVICE_VERIFICATION_URI"); uri != "" {
+		return uri
+	}
+	return tokenConfig.Issuer + pathPrefix + deviceVerificationPage
+}
+
+// newMailer sends emails through SMTP_ADDR when it is set. Otherwise they are written to MAIL_OUTBOX_DIR,
+// which is meant for development only.
+func newMailer() mail.Mailer {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = defaultMailFrom
+	}
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		return infraMail.NewSMTPMailer(infraMail.SMTPConfig{
+			Addr:     addr,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     from,
+		})
+	}
+	dir := os.Getenv("MAIL_OUTBOX_DIR")
+	if dir == "" {
+		dir = defaultMailOutboxDir
+	}
+	logrus.Warnf("SMTP_ADDR not set, emails are written to %s", dir)
+	return infraMail.NewFileMailer(dir, from)
+}
+
+func newPasswordResetConfig() user.PasswordResetConfig {
+	return user.PasswordResetConfig{
+		TTL: getEnvDuration("PASSWORD_RESET_TTL", user.DefaultPasswordResetTTL),
+		URL: os.Getenv("PASSWORD_RESET_URL"),
+	}
+}
+
+func newEmailVerificationConfig() user.EmailVerificationConfig {
+	return user.EmailVerificationConfig{
+		TTL: getEnvDuration("EMAIL_VERIFICATION_TTL", user.DefaultEmailVerificationTTL),
+		URL: os.Getenv("EMAIL_VERIFICATION_URL"),
+	}
+}
+
+// newPasswordPolicyConfig reads the password rules. PASSWORD_REQUIRED_CLASSES lists the character classes every
+// password needs, among upper, lower, digit and symbol.
+func newPasswordPolicyConfig() user.PasswordPolicyConfig {
+	config := user.PasswordPolicyConfig{
+		MinLength:   getEnvInt("PASSWORD_MIN_LENGTH", user.DefaultPasswordMinLength),
+		MaxLength:   getEnvInt("PASSWORD_MAX_LENGTH", user.MaxPasswordLength),
+		HistorySize: getEnvInt("PASSWORD_HISTORY_SIZE", 0),
+	}
+	for _, class := range getEnvList("PASSWORD_REQUIRED_CLASSES", nil) {
+		switch class {
+		case "upper":
+			config.RequireUpper = true
+		case "lower":
+			config.RequireLower = true
+		case "digit":
+			config.RequireDigit = true
+		case "symbol":
+			config.RequireSymbol = true
+		default:
+			logrus.Fatalf("invalid PASSWORD_REQUIRED_CLASSES %q, supported are upper, lower, digit and symbol", class)
+		}
+	}
+	if path := os.Getenv("PASSWORD_BREACHED_FILE"); path != "" {
+		breached, err := user.LoadBreachedPasswords(path)
+		if err != nil {
+			logrus.Fatal(err)
+		}
+		config.Breached = breached
+	}
+	return config
+}
+
+// newMailThrottleConfig reads how many password reset and email verification requests an email and a client IP
+// may make. A cap of zero turns it off.
+func newMailThrottleConfig() user.MailThrottleConfig {
+	return user.MailThrottleConfig{
+		PerEmail: getEnvInt("MAIL_REQUESTS_PER_EMAIL", user.DefaultMailRequestsPerEmail),
+		PerIP:    getEnvInt("MAIL_REQUESTS_PER_IP", user.DefaultMailRequestsPerIP),
+		Window:   getEnvDuration("MAIL_REQUEST_WINDOW", user.DefaultMailRequestWindow),
 	}
 }
 
+func newLoginConfig() token.LoginConfig {
+	return token.LoginConfig{RequireVerifiedEmail: getEnvBool("REQUIRE_VERIFIED_EMAIL", false)}
+}
+
+// newLoginThrottleConfig reads the thresholds of the login throttle. Usernames and client IPs have their own, as
+// an IP is often shared by many users. A threshold of zero turns that step off.
+func newLoginThrottleConfig() token.LoginThrottleConfig {
+	return token.LoginThrottleConfig{
+		Username: token.LoginThrottleRule{
+			DelayAfter:   getEnvInt("LOGIN_DELAY_AFTER", token.DefaultLoginDelayAfter),
+			LockoutAfter: getEnvInt("LOGIN_LOCKOUT_AFTER", token.DefaultLoginLockoutAfter),
+		},
+		IP: token.LoginThrottleRule{
+			DelayAfter:   getEnvInt("LOGIN_IP_DELAY_AFTER", token.DefaultLoginIPDelayAfter),
+			LockoutAfter: getEnvInt("LOGIN_IP_LOCKOUT_AFTER", token.DefaultLoginIPLockoutAfter),
+		},
+		Delay:           getEnvDuration("LOGIN_DELAY", token.DefaultLoginDelay),
+		LockoutDuration: getEnvDuration("LOGIN_LOCKOUT_DURATION", token.DefaultLoginLockoutDuration),
+		Window:          getEnvDuration("LOGIN_FAILURE_WINDOW", token.DefaultLoginFailureWindow),
+	}
+}
+
+func newAuthorizationRules() middleware.AuthorizationRules {
+	rules := middleware.DefaultAuthorizationRules()
+	path := os.Getenv("AUTHORIZATION_RULES_FILE")
+	if path == "" {
+		return rules
+	}
+	rules, err := middleware.LoadAuthorizationRules(path, rules)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	return rules
+}
+
+func newDenylist(repoFactory factory.RepositoryFactory) token.Denylist {
+	denylist := token.NewDenylist(repoFactory.NewRevokedTokenRepository(), repoFactory.NewRevokedSubjectRepository())
+	if err := denylist.Load(context.Background()); err != nil {
+		logrus.Fatal(err)
+	}
+	token.ScheduleDenylistRefresh(context.Background(), denylist, getEnvDuration("DENYLIST_REFRESH_INTERVAL", defaultDenylistInterval))
+	return denylist
+}
+
 func (r *router) Config() *fiber.App {
 	app := fiber.New(fiber.Config{
 		AppName:               os.Getenv("APP_NAME"),
 		DisableStartupMessage: true,
+		// behind a reverse proxy the client IP the login throttle counts comes from this header
+		ProxyHeader: os.Getenv("PROXY_HEADER"),
 	})
 
+	app.Use(recover.New())
+	app.Use(cors.New(cors.Config{
+		AllowOrigins: "*",
+		AllowMethods: "POST, GET, OPTIONS, PUT, PATCH, DELETE",
+		AllowHeaders: "access-control-allow-headers,access-control-allow-methods,access-control-allow-origin,authorization",
+	}))
+	app.Use(middleware.NewSecurityMiddleware(r.validateToken, pathPrefix).Apply())
+
+	app.Get(jwksPath, r.jwksController.Jwks).Name("jwks")
+	app.Get(openIDConfigurationPath, r.discoveryController.OpenIDConfiguration).Name("openIDConfiguration")
+
 	auth := app.Group(pathPrefix)
 
 	auth.Get("/signup", r.signupController.CreateUser).Name("signup")
+	auth.Get("/authorize", r.authorizeController.Authorize).Name("authorize")
+	auth.Post("/authorize", r.authorizeController.Login).Name("authorizeLogin")
 	auth.Post("/token", r.tokenController.Token).Name("token")
 	auth.Get("/check_token", r.checkTokenController.CheckToken).Name("checkToken")
+	auth.Post("/revoke", r.revokeController.Revoke).Name("revoke")
+	auth.Post("/introspect", r.introspectController.Introspect).Name("introspect")
+	auth.Get("/userinfo", r.userInfoController.UserInfo).Name("userinfo")
+	auth.Post("/userinfo", r.userInfoController.UserInfo)
+	auth.Post("/device_authorization", r.deviceController.DeviceAuthorization).Name("deviceAuthorization")
+	auth.Post(deviceVerificationPath, r.deviceController.Verify).Name("deviceVerification")
+	auth.Get(deviceVerificationPage, r.deviceController.VerificationPage).Name("deviceVerificationPage")
+	auth.Post(deviceVerificationPage, r.deviceController.VerificationLogin).Name("deviceVerificationLogin")
 
-	auth.Get("/users/:id", r.userController.FindById).Name("getUser")
-	auth.Post("/users/:id/add-role", r.userController.AddRole).Name("addRoleToUser")
+	auth.Post(passwordResetPath, r.resetController.Request).Name("requestPasswordReset")
+	auth.Post(passwordResetPath+"/confirm", r.resetController.Confirm).Name("confirmPasswordReset")
+	auth.Post(verifyEmailPath, r.verifyController.Request).Name("requestEmailVerification")
+	auth.Post(verifyEmailPath+"/confirm", r.verifyController.Confirm).Name("confirmEmailVerification")
 
-	auth.Post("/roles", r.roleController.Create).Name("addRole")
-	auth.Get("/roles/:name", r.roleController.FindByName).Name("findRoleByName")
-	auth.Put("/roles/:id", r.roleController.Edit).Name("editRole")
-	auth.Patch("/roles/:id/change-status", r.roleController.ChangeStatus).Name("changeStatus")
+	auth.Get("/me", r.accountController.Profile).Name("me")
+	auth.Patch("/me", r.accountController.UpdateProfile).Name("updateMe")
+	auth.Post("/me/password", r.accountController.ChangePassword).Name("changeMyPassword")
+	auth.Delete("/me", r.accountController.Close).Name("closeMe")
 
-	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowMethods: "POST, GET, OPTIONS, PUT, PATCH, DELETE",
-		AllowHeaders: "access-control-allow-headers,access-control-allow-methods,access-control-allow-origin,authorization",
-	}))
-	app.Use(middleware.NewSecurityMiddleware(r.validateToken, pathPrefix).Apply())
-	app.Use(recover.New())
+	auth.Get("/users", r.authorization.Require("listUsers"), r.userController.List).Name("listUsers")
+	auth.Get("/users/:id", r.authorization.Require("getUser"), r.userController.FindById).Name("getUser")
+	auth.Put("/users/:id", r.authorization.Require("updateUser"), r.userController.Update).Name("updateUser")
+	auth.Delete("/users/:id", r.authorization.Require("deleteUser"), r.userController.Delete).Name("deleteUser")
+	auth.Post("/users/:id/add-role", r.authorization.Require("addRoleToUser"), r.userController.AddRole).Name("addRoleToUser")
+	auth.Get("/users/:id/roles", r.authorization.Require("getUserRoles"), r.userController.Roles).Name("getUserRoles")
+	auth.Put("/users/:id/roles", r.authorization.Require("replaceUserRoles"), r.userController.ReplaceRoles).Name("replaceUserRoles")
+	auth.Delete("/users/:id/roles/:roleId", r.authorization.Require("removeUserRole"), r.userController.RemoveRole).Name("removeUserRole")
+	auth.Patch("/users/:id/change-status", r.authorization.Require("changeUserStatus"), r.userController.ChangeStatus).Name("changeUserStatus")
+
+	auth.Get("/roles", r.authorization.Require("listRoles"), r.roleController.List).Name("listRoles")
+	auth.Post("/roles", r.authorization.Require("addRole"), r.roleController.Create).Name("addRole")
+	auth.Get("/roles/:name", r.authorization.Require("findRoleByName"), r.roleController.FindByName).Name("findRoleByName")
+	auth.Put("/roles/:id", r.authorization.Require("editRole"), r.roleController.Edit).Name("editRole")
+	auth.Patch("/roles/:id/change-status", r.authorization.Require("changeStatus"), r.roleController.ChangeStatus).Name("changeStatus")
+	auth.Delete("/roles/:id", r.authorization.Require("deleteRole"), r.roleController.Delete).Name("deleteRole")
+	auth.Get("/roles/:id/authorities", r.authorization.Require("getRoleAuthorities"), r.roleController.Authorities).Name("getRoleAuthorities")
+	auth.Post("/roles/:id/authorities/:authorityId", r.authorization.Require("addAuthorityToRole"), r.roleController.AddAuthority).Name("addAuthorityToRole")
+	auth.Delete("/roles/:id/authorities/:authorityId", r.authorization.Require("removeAuthorityFromRole"), r.roleController.RemoveAuthority).Name("removeAuthorityFromRole")
+
+	auth.Get("/authorities", r.authorization.Require("listAuthorities"), r.authorityController.List).Name("listAuthorities")
+	auth.Post("/authorities", r.authorization.Require("addAuthority"), r.authorityController.Create).Name("addAuthority")
+	auth.Put("/authorities/:id", r.authorization.Require("editAuthority"), r.authorityController.Edit).Name("editAuthority")
+	auth.Patch("/authorities/:id/change-status", r.authorization.Require("changeAuthorityStatus"), r.authorityController.ChangeStatus).Name("changeAuthorityStatus")
+
+	auth.Get("/lockouts", r.authorization.Require("listLockedLogins"), r.loginLockController.List).Name("listLockedLogins")
+	auth.Delete("/lockouts/:kind/:subject", r.authorization.Require("unlockLogin"), r.loginLockController.Unlock).Name("unlockLogin")
+
+	auth.Get("/reports/role-usage", r.authorization.Require("roleUsage"), r.roleController.Usage).Name("roleUsage")
 
 	return app
 }
@@ -0,0 +1,33 @@
+package audit
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/audit"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+// LogAuditor writes audit events as structured log entries tagged audit=true, so they can be told apart from the
+// rest of the log and shipped to whatever collects the logs.
+type LogAuditor struct {
+	logger *logrus.Logger
+}
+
+func NewLogAuditor(logger *logrus.Logger) audit.Auditor {
+	return &LogAuditor{logger: logger}
+}
+
+func (a *LogAuditor) Record(_ context.Context, event audit.Event) error {
+	fields := logrus.Fields{}
+	for key, value := range event.Attributes {
+		fields[key] = value
+	}
+	fields["audit"] = true
+	fields["event"] = event.Type
+	fields["subject"] = event.Subject
+	if event.Actor != "" {
+		fields["actor"] = event.Actor
+	}
+	a.logger.WithFields(fields).WithTime(event.Time.UTC().Truncate(time.Millisecond)).Warn(event.Type)
+	return nil
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/audit"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+type LogAuditorSuite struct {
+	suite.Suite
+	*require.Assertions
+	hook    *test.Hook
+	auditor audit.Auditor
+}
+
+func TestLogAuditor(t *testing.T) {
+	suite.Run(t, new(LogAuditorSuite))
+}
+
+func (s *LogAuditorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	logger, hook := test.NewNullLogger()
+	s.hook = hook
+	s.auditor = NewLogAuditor(logger)
+}
+
+func (s *LogAuditorSuite) TestRecordWritesEntry() {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	err := s.auditor.Record(context.Background(), audit.Event{
+		Type:       audit.EventLoginUnlocked,
+		Time:       now,
+		Actor:      "admin",
+		Subject:    "jdoe",
+		Attributes: map[string]string{"kind": "username", "subject": "ignored"},
+	})
+	s.NoError(err)
+
+	entry := s.hook.LastEntry()
+	s.NotNil(entry)
+	s.Equal(logrus.WarnLevel, entry.Level)
+	s.Equal(audit.EventLoginUnlocked, entry.Message)
+	s.Equal(now, entry.Time)
+	s.Equal(logrus.Fields{"audit": true, "event": audit.EventLoginUnlocked, "subject": "jdoe", "actor": "admin", "kind": "username"}, entry.Data)
+}
+
+func (s *LogAuditorSuite) TestRecordWithoutActor() {
+	err := s.auditor.Record(context.Background(), audit.Event{Type: audit.EventLoginLocked, Time: time.Now(), Subject: "10.0.0.1"})
+	s.NoError(err)
+
+	entry := s.hook.LastEntry()
+	s.NotNil(entry)
+	s.NotContains(entry.Data, "actor")
+}
@@ -15,10 +15,62 @@ func NewPostgresRepositoryFactory(db database.Database) factory.RepositoryFactor
 	return PostgresRepositoryFactory{db: db}
 }
 
+func (p PostgresRepositoryFactory) NewAuthorityRepository() repository.AuthorityRepository {
+	return postgres.NewAuthorityRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewAuthorizationCodeRepository() repository.AuthorizationCodeRepository {
+	return postgres.NewAuthorizationCodeRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewClientRepository() repository.ClientRepository {
+	return postgres.NewClientRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewDeviceCodeRepository() repository.DeviceCodeRepository {
+	return postgres.NewDeviceCodeRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewEmailVerificationTokenRepository() repository.EmailVerificationTokenRepository {
+	return postgres.NewEmailVerificationTokenRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewLoginFailureRepository() repository.LoginFailureRepository {
+	return postgres.NewLoginFailureRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewPasswordHistoryRepository() repository.PasswordHistoryRepository {
+	return postgres.NewPasswordHistoryRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewPasswordResetTokenRepository() repository.PasswordResetTokenRepository {
+	return postgres.NewPasswordResetTokenRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewRefreshTokenRepository() repository.RefreshTokenRepository {
+	return postgres.NewRefreshTokenRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewRevokedSubjectRepository() repository.RevokedSubjectRepository {
+	return postgres.NewRevokedSubjectRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewRevokedTokenRepository() repository.RevokedTokenRepository {
+	return postgres.NewRevokedTokenRepository(p.db)
+}
+
+func (p PostgresRepositoryFactory) NewRoleAuthorityRepository() repository.RoleAuthorityRepository {
+	return postgres.NewRoleAuthorityRepository(p.db)
+}
+
 func (p PostgresRepositoryFactory) NewRoleRepository() repository.RoleRepository {
 	return postgres.NewRoleRepository(p.db)
 }
 
+func (p PostgresRepositoryFactory) NewSigningKeyRepository() repository.SigningKeyRepository {
+	return postgres.NewSigningKeyRepository(p.db)
+}
+
 func (p PostgresRepositoryFactory) NewUserAuthorityRepository() repository.UserAuthorityRepository {
 	return postgres.NewUserAuthorityRepository(p.db)
 }
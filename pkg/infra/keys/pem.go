@@ -0,0 +1,75 @@
+package keys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+const (
+	privateKeyType  = "PRIVATE KEY"
+	secretKeyType   = "SECRET KEY"
+	algorithmHeader = "Algorithm"
+)
+
+var (
+	ErrInvalidPEM         = errors.New("could not decode pem block")
+	ErrUnsupportedKeyType = errors.New("unsupported private key type")
+)
+
+// EncodePrivateKey encodes an asymmetric key as PKCS #8 and an HMAC secret as a "SECRET KEY" block.
+// The algorithm, when given, is kept in the block headers.
+func EncodePrivateKey(key crypto.PrivateKey, algorithm string) ([]byte, error) {
+	block := &pem.Block{Type: privateKeyType}
+	if algorithm != "" {
+		block.Headers = map[string]string{algorithmHeader: algorithm}
+	}
+	if secret, ok := key.([]byte); ok {
+		block.Type = secretKeyType
+		block.Bytes = secret
+		return pem.EncodeToMemory(block), nil
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal private key: %w", err)
+	}
+	block.Bytes = der
+	return pem.EncodeToMemory(block), nil
+}
+
+// DecodePrivateKey returns the key and the algorithm from the block headers, empty when absent.
+func DecodePrivateKey(data []byte) (crypto.PrivateKey, string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, "", ErrInvalidPEM
+	}
+	algorithm := block.Headers[algorithmHeader]
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		return key, algorithm, err
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		return key, algorithm, err
+	case secretKeyType:
+		return block.Bytes, algorithm, nil
+	case privateKeyType:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, "", fmt.Errorf("could not parse private key: %w", err)
+		}
+		switch key.(type) {
+		case *rsa.PrivateKey, *ecdsa.PrivateKey, ed25519.PrivateKey:
+			return key, algorithm, nil
+		default:
+			return nil, "", ErrUnsupportedKeyType
+		}
+	default:
+		return nil, "", fmt.Errorf("%w: %s", ErrUnsupportedKeyType, block.Type)
+	}
+}
@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	"github.com/google/uuid"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer writes each email as an .eml file to an outbox directory instead of delivering it. It suits
+// development and tests, where no SMTP server is around.
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir string, from string) mail.Mailer {
+	return &FileMailer{dir: dir, from: from}
+}
+
+func (m *FileMailer) Send(_ context.Context, message mail.Message) error {
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return fmt.Errorf("could not create outbox %s: %w", m.dir, err)
+	}
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405Z"), uuid.NewString())
+	err := os.WriteFile(filepath.Join(m.dir, name), format(m.from, message, now), 0o600)
+	if err != nil {
+		return fmt.Errorf("could not write email to outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package mail
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type FileMailerSuite struct {
+	suite.Suite
+	*require.Assertions
+	dir    string
+	mailer mail.Mailer
+}
+
+func TestFileMailer(t *testing.T) {
+	suite.Run(t, new(FileMailerSuite))
+}
+
+func (s *FileMailerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.dir = filepath.Join(s.T().TempDir(), "outbox")
+	s.mailer = NewFileMailer(s.dir, "golauth@example.com")
+}
+
+func (s *FileMailerSuite) TestSendWritesEml() {
+	err := s.mailer.Send(context.Background(), mail.Message{To: "admin@example.com", Subject: "Hello", Body: "line 1\nline 2"})
+	s.NoError(err)
+
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.eml"))
+	s.NoError(err)
+	s.Len(files, 1)
+	content, err := os.ReadFile(files[0])
+	s.NoError(err)
+	s.Contains(string(content), "From: golauth@example.com\r\n")
+	s.Contains(string(content), "To: admin@example.com\r\n")
+	s.Contains(string(content), "Subject: Hello\r\n")
+	s.Contains(string(content), "\r\n\r\nline 1\r\nline 2\r\n")
+}
+
+func (s *FileMailerSuite) TestSendStripsHeaderInjection() {
+	err := s.mailer.Send(context.Background(), mail.Message{To: "admin@example.com", Subject: "Hi\r\nBcc: evil@example.com"})
+	s.NoError(err)
+
+	files, _ := filepath.Glob(filepath.Join(s.dir, "*.eml"))
+	content, err := os.ReadFile(files[0])
+	s.NoError(err)
+	s.Contains(string(content), "Subject: HiBcc: evil@example.com\r\n")
+	s.NotContains(string(content), "\r\nBcc:")
+}
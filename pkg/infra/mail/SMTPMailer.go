@@ -0,0 +1,76 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+type SMTPConfig struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPMailer sends emails through an SMTP server, upgrading to TLS when the server offers STARTTLS and
+// authenticating when a username is set.
+type SMTPMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) mail.Mailer {
+	return &SMTPMailer{config: config}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message mail.Message) error {
+	host, _, err := net.SplitHostPort(m.config.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid smtp address %s: %w", m.config.Addr, err)
+	}
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", m.config.Addr)
+	if err != nil {
+		return fmt.Errorf("could not connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("could not connect to smtp server: %w", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("could not start tls: %w", err)
+		}
+	}
+	if m.config.Username != "" {
+		if err = c.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, host)); err != nil {
+			return fmt.Errorf("could not authenticate on smtp server: %w", err)
+		}
+	}
+	if err = c.Mail(m.config.From); err != nil {
+		return fmt.Errorf("smtp sender rejected: %w", err)
+	}
+	if err = c.Rcpt(message.To); err != nil {
+		return fmt.Errorf("smtp recipient rejected: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("could not send email: %w", err)
+	}
+	if _, err = w.Write(format(m.config.From, message, time.Now())); err != nil {
+		return fmt.Errorf("could not send email: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("could not send email: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpStandIn is a minimal SMTP server that accepts a single session and records what it received.
+type smtpStandIn struct {
+	listener net.Listener
+	commands []string
+	data     string
+	done     chan struct{}
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpStandIn{listener: listener, done: make(chan struct{})}
+	go s.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return s
+}
+
+func (s *smtpStandIn) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 localhost ESMTP stand-in")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		s.commands = append(s.commands, line)
+		switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250-localhost\r\n250 AUTH PLAIN")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			lines, _ := tp.ReadDotLines()
+			s.data = strings.Join(lines, "\n")
+			_ = tp.PrintfLine("250 queued")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		case "AUTH":
+			_ = tp.PrintfLine("235 authenticated")
+		default:
+			_ = tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+type SMTPMailerSuite struct {
+	suite.Suite
+	*require.Assertions
+	server *smtpStandIn
+}
+
+func TestSMTPMailer(t *testing.T) {
+	suite.Run(t, new(SMTPMailerSuite))
+}
+
+func (s *SMTPMailerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.server = newSMTPStandIn(s.T())
+}
+
+func (s *SMTPMailerSuite) send(config SMTPConfig) error {
+	config.Addr = s.server.listener.Addr().String()
+	config.From = "golauth@example.com"
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := NewSMTPMailer(config).Send(ctx, mail.Message{To: "admin@example.com", Subject: "Reset", Body: "token"})
+	<-s.server.done
+	return err
+}
+
+func (s *SMTPMailerSuite) TestSend() {
+	s.NoError(s.send(SMTPConfig{}))
+
+	s.Contains(s.server.commands, "MAIL FROM:<golauth@example.com>")
+	s.Contains(s.server.commands, "RCPT TO:<admin@example.com>")
+	s.Contains(s.server.data, "Subject: Reset")
+	s.Contains(s.server.data, "\n\ntoken")
+}
+
+func (s *SMTPMailerSuite) TestSendAuthenticates() {
+	s.NoError(s.send(SMTPConfig{Username: "user", Password: "secret"}))
+
+	s.True(strings.HasPrefix(s.server.commands[1], "AUTH PLAIN"))
+}
+
+func (s *SMTPMailerSuite) TestSendInvalidAddress() {
+	err := NewSMTPMailer(SMTPConfig{Addr: "localhost"}).Send(context.Background(), mail.Message{To: "admin@example.com"})
+	s.ErrorContains(err, "invalid smtp address")
+}
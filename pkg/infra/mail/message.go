@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/mail"
+	"strings"
+	"time"
+)
+
+var headerSanitizer = strings.NewReplacer("\r", "", "\n", "")
+
+// format renders message as an RFC 5322 email. Header values are stripped of line breaks so they cannot
+// smuggle extra headers.
+func format(from string, message mail.Message, now time.Time) []byte {
+	var b bytes.Buffer
+	_, _ = fmt.Fprintf(&b, "From: %s\r\n", headerSanitizer.Replace(from))
+	_, _ = fmt.Fprintf(&b, "To: %s\r\n", headerSanitizer.Replace(message.To))
+	_, _ = fmt.Fprintf(&b, "Subject: %s\r\n", headerSanitizer.Replace(message.Subject))
+	_, _ = fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n"))
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
@@ -0,0 +1,153 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/keys"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	activeKeySuffix  = ".pem"
+	retiredKeySuffix = ".pem.retired"
+)
+
+// SigningKeyRepositoryFile keeps one PEM encoded private key per file inside dir.
+// The file name (without extension) is used as the key id. Retired keys are renamed
+// with the ".retired" suffix and their modification time marks the retirement date.
+// Keys without an "Algorithm" PEM header are used with the repository algorithm.
+type SigningKeyRepositoryFile struct {
+	dir       string
+	algorithm string
+}
+
+func NewSigningKeyRepository(dir string, algorithm string) repository.SigningKeyRepository {
+	return &SigningKeyRepositoryFile{dir: dir, algorithm: algorithm}
+}
+
+func (r SigningKeyRepositoryFile) FindAll(_ context.Context) ([]*entity.SigningKey, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read signing keys directory %s: %w", r.dir, err)
+	}
+
+	var result []*entity.SigningKey
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		name := e.Name()
+		var id string
+		retired := false
+		switch {
+		case strings.HasSuffix(name, retiredKeySuffix):
+			id = strings.TrimSuffix(name, retiredKeySuffix)
+			retired = true
+		case strings.HasSuffix(name, activeKeySuffix):
+			id = strings.TrimSuffix(name, activeKeySuffix)
+		default:
+			continue
+		}
+
+		key, err := r.load(id, name)
+		if err != nil {
+			return nil, err
+		}
+		if retired {
+			retiredAt := key.CreationDate
+			key.RetiredAt = &retiredAt
+		}
+		result = append(result, key)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreationDate.Before(result[j].CreationDate)
+	})
+	return result, nil
+}
+
+func (r SigningKeyRepositoryFile) Create(_ context.Context, key *entity.SigningKey) (*entity.SigningKey, error) {
+	encoded, err := keys.EncodePrivateKey(key.PrivateKey, key.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode signing key %s: %w", key.ID, err)
+	}
+	path := filepath.Join(r.dir, key.ID+activeKeySuffix)
+	err = os.WriteFile(path, encoded, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("could not create signing key %s: %w", key.ID, err)
+	}
+	key.CreationDate = time.Now()
+	return key, nil
+}
+
+func (r SigningKeyRepositoryFile) Retire(_ context.Context, id string) error {
+	activePath := filepath.Join(r.dir, id+activeKeySuffix)
+	retiredPath := filepath.Join(r.dir, id+retiredKeySuffix)
+	err := os.Rename(activePath, retiredPath)
+	if err != nil {
+		return fmt.Errorf("could not retire signing key %s: %w", id, err)
+	}
+	now := time.Now()
+	err = os.Chtimes(retiredPath, now, now)
+	if err != nil {
+		return fmt.Errorf("could not retire signing key %s: %w", id, err)
+	}
+	return nil
+}
+
+// Rotate is meant for a single instance owning the keys directory, and does not guard against other instances
+// rotating the same keys.
+func (r SigningKeyRepositoryFile) Rotate(ctx context.Context, key *entity.SigningKey, interval time.Duration) (bool, error) {
+	stored, err := r.FindAll(ctx)
+	if err != nil {
+		return false, err
+	}
+	var active []*entity.SigningKey
+	for _, k := range stored {
+		if k.Algorithm != key.Algorithm || k.IsRetired() {
+			continue
+		}
+		if interval == 0 || time.Since(k.CreationDate) < interval {
+			return false, nil
+		}
+		active = append(active, k)
+	}
+	_, err = r.Create(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	for _, k := range active {
+		err = r.Retire(ctx, k.ID)
+		if err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (r SigningKeyRepositoryFile) load(id string, name string) (*entity.SigningKey, error) {
+	path := filepath.Join(r.dir, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read signing key %s: %w", id, err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read signing key %s: %w", id, err)
+	}
+	privateKey, algorithm, err := keys.DecodePrivateKey(content)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode signing key %s: %w", id, err)
+	}
+	if algorithm == "" {
+		algorithm = r.algorithm
+	}
+	key := entity.NewSigningKey(id, algorithm, privateKey)
+	key.CreationDate = info.ModTime()
+	return key, nil
+}
@@ -0,0 +1,147 @@
+package file
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type SigningKeyRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	dir  string
+	repo repository.SigningKeyRepository
+}
+
+func TestSigningKeyRepository(t *testing.T) {
+	suite.Run(t, new(SigningKeyRepositorySuite))
+}
+
+func (s *SigningKeyRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.dir = s.T().TempDir()
+	s.repo = NewSigningKeyRepository(s.dir, "RS512")
+}
+
+func (s *SigningKeyRepositorySuite) newKey(id string) *entity.SigningKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.NoError(err)
+	return entity.NewSigningKey(id, "RS512", privateKey)
+}
+
+func (s *SigningKeyRepositorySuite) TestCreateAndFindAll() {
+	key := s.newKey("key-1")
+	_, err := s.repo.Create(context.Background(), key)
+	s.NoError(err)
+	s.FileExists(filepath.Join(s.dir, "key-1.pem"))
+
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(keys, 1)
+	s.Equal("key-1", keys[0].ID)
+	s.Equal("RS512", keys[0].Algorithm)
+	s.False(keys[0].IsRetired())
+	s.True(key.PrivateKey.(*rsa.PrivateKey).Equal(keys[0].PrivateKey))
+}
+
+func (s *SigningKeyRepositorySuite) TestCreateAndFindAllKeepsAlgorithm() {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.NoError(err)
+	_, err = s.repo.Create(context.Background(), entity.NewSigningKey("key-ec", "ES256", ecKey))
+	s.NoError(err)
+	_, err = s.repo.Create(context.Background(), entity.NewSigningKey("key-hs", "HS256", []byte("0123456789abcdef0123456789abcdef")))
+	s.NoError(err)
+
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(keys, 2)
+	for _, k := range keys {
+		switch k.ID {
+		case "key-ec":
+			s.Equal("ES256", k.Algorithm)
+			s.True(ecKey.Equal(k.PrivateKey))
+		case "key-hs":
+			s.Equal("HS256", k.Algorithm)
+			s.True(k.IsSymmetric())
+			s.Equal([]byte("0123456789abcdef0123456789abcdef"), k.PrivateKey)
+		}
+	}
+}
+
+func (s *SigningKeyRepositorySuite) TestFindAllWithoutAlgorithmHeader() {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.NoError(err)
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+	s.NoError(os.WriteFile(filepath.Join(s.dir, "legacy.pem"), encoded, 0600))
+
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(keys, 1)
+	s.Equal("RS512", keys[0].Algorithm)
+}
+
+func (s *SigningKeyRepositorySuite) TestRetire() {
+	_, err := s.repo.Create(context.Background(), s.newKey("key-1"))
+	s.NoError(err)
+	_, err = s.repo.Create(context.Background(), s.newKey("key-2"))
+	s.NoError(err)
+
+	err = s.repo.Retire(context.Background(), "key-1")
+	s.NoError(err)
+	s.FileExists(filepath.Join(s.dir, "key-1.pem.retired"))
+
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(keys, 2)
+	for _, k := range keys {
+		s.Equal(k.ID == "key-1", k.IsRetired())
+	}
+}
+
+func (s *SigningKeyRepositorySuite) TestRetireNotFound() {
+	err := s.repo.Retire(context.Background(), "unknown")
+	s.Error(err)
+}
+
+func (s *SigningKeyRepositorySuite) TestFindAllIgnoresOtherFiles() {
+	s.NoError(os.WriteFile(filepath.Join(s.dir, "README"), []byte("keys"), 0600))
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Empty(keys)
+}
+
+func (s *SigningKeyRepositorySuite) TestFindAllInvalidPem() {
+	s.NoError(os.WriteFile(filepath.Join(s.dir, "broken.pem"), []byte("not a pem"), 0600))
+	_, err := s.repo.FindAll(context.Background())
+	s.Error(err)
+}
+
+func (s *SigningKeyRepositorySuite) TestRotate() {
+	_, err := s.repo.Create(context.Background(), s.newKey("key-1"))
+	s.NoError(err)
+
+	rotated, err := s.repo.Rotate(context.Background(), s.newKey("key-2"), time.Hour)
+	s.NoError(err)
+	s.False(rotated)
+
+	old := time.Now().Add(-2 * time.Hour)
+	s.NoError(os.Chtimes(filepath.Join(s.dir, "key-1.pem"), old, old))
+	rotated, err = s.repo.Rotate(context.Background(), s.newKey("key-3"), time.Hour)
+	s.NoError(err)
+	s.True(rotated)
+	s.FileExists(filepath.Join(s.dir, "key-1.pem.retired"))
+	s.FileExists(filepath.Join(s.dir, "key-3.pem"))
+	s.NoFileExists(filepath.Join(s.dir, "key-2.pem"))
+}
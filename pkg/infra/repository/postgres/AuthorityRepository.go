@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/google/uuid"
+)
+
+type AuthorityRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewAuthorityRepository(db database.Database) repository.AuthorityRepository {
+	return &AuthorityRepositoryPostgres{db: db}
+}
+
+func (r AuthorityRepositoryPostgres) FindAll(ctx context.Context) ([]*entity.Authority, error) {
+	rows, err := r.db.Many(ctx, "SELECT id, name, description, enabled, creation_date FROM golauth_authority ORDER BY name")
+	if err != nil {
+		return nil, fmt.Errorf("could not list authorities: %w", err)
+	}
+	defer rows.Close()
+	return scanAuthorities(rows)
+}
+
+func (r AuthorityRepositoryPostgres) Create(ctx context.Context, authority *entity.Authority) (*entity.Authority, error) {
+	err := r.db.One(ctx, "INSERT INTO golauth_authority (name, description, enabled) VALUES ($1, $2, $3) RETURNING id, creation_date;",
+		authority.Name, authority.Description, authority.Enabled).Scan(&authority.ID, &authority.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not create authority %s: %w", authority.Name, translateError(err))
+	}
+	return authority, nil
+}
+
+func (r AuthorityRepositoryPostgres) Edit(ctx context.Context, authority *entity.Authority) error {
+	updateStatement := `
+		UPDATE golauth_authority
+		SET name = $2, description = $3
+		WHERE id = $1
+	`
+	res, err := r.db.Exec(ctx, updateStatement, authority.ID, authority.Name, authority.Description)
+	if err != nil {
+		return fmt.Errorf("could not edit authority %s: %w", authority.Name, translateError(err))
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not edit authority %s", authority.ID))
+}
+
+func (r AuthorityRepositoryPostgres) ChangeStatus(ctx context.Context, id uuid.UUID, enabled bool) error {
+	res, err := r.db.Exec(ctx, "UPDATE golauth_authority SET enabled = $2 WHERE id = $1", id, enabled)
+	if err != nil {
+		return fmt.Errorf("could not change status of authority %s: %w", id, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not change status of authority %s", id))
+}
+
+func (r AuthorityRepositoryPostgres) ExistsById(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	err := r.db.One(ctx, "SELECT EXISTS (SELECT 1 FROM golauth_authority WHERE id = $1)", id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("could not check authority %s: %w", id, err)
+	}
+	return exists, nil
+}
+
+func scanAuthorities(rows *sql.Rows) ([]*entity.Authority, error) {
+	result := make([]*entity.Authority, 0)
+	for rows.Next() {
+		var authority entity.Authority
+		err := rows.Scan(&authority.ID, &authority.Name, &authority.Description, &authority.Enabled, &authority.CreationDate)
+		if err != nil {
+			return nil, fmt.Errorf("could not transform result in slice: %w", err)
+		}
+		result = append(result, &authority)
+	}
+	return result, rows.Err()
+}
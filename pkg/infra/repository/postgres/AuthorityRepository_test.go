@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type AuthorityRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.AuthorityRepository
+}
+
+func TestAuthorityRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(AuthorityRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *AuthorityRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+
+	s.repo = NewAuthorityRepository(s.db)
+}
+
+func (s *AuthorityRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *AuthorityRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *AuthorityRepositorySuite) TestFindAll() {
+	s.prepareDatabase(true, "add-users.sql")
+	authorities, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(authorities, 2)
+	s.Equal("ADMIN", authorities[0].Name)
+	s.Equal("USER", authorities[1].Name)
+}
+
+func (s *AuthorityRepositorySuite) TestCreate() {
+	s.prepareDatabase(true)
+	saved, err := s.repo.Create(context.Background(), &entity.Authority{Name: "ORDERS_READ", Description: "Read orders", Enabled: true})
+	s.NoError(err)
+	s.NotEqual(uuid.Nil, saved.ID)
+	s.NotZero(saved.CreationDate)
+}
+
+func (s *AuthorityRepositorySuite) TestCreateDuplicated() {
+	s.prepareDatabase(true, "add-users.sql")
+	_, err := s.repo.Create(context.Background(), &entity.Authority{Name: "ADMIN", Description: "Again", Enabled: true})
+	s.ErrorIs(err, repository.ErrDuplicate)
+}
+
+func (s *AuthorityRepositorySuite) TestEditAndChangeStatus() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("df30f1c0-9a0c-4095-a14b-13d44d39ec15")
+
+	s.NoError(s.repo.Edit(context.Background(), &entity.Authority{ID: id, Name: "USER", Description: "Common user"}))
+	s.NoError(s.repo.ChangeStatus(context.Background(), id, false))
+
+	authorities, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Equal("Common user", authorities[1].Description)
+	s.False(authorities[1].Enabled)
+}
+
+func (s *AuthorityRepositorySuite) TestEditNotFound() {
+	s.prepareDatabase(true)
+	err := s.repo.Edit(context.Background(), &entity.Authority{ID: uuid.New(), Name: "NONE"})
+	s.ErrorIs(err, repository.ErrNotFound)
+	err = s.repo.ChangeStatus(context.Background(), uuid.New(), false)
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *AuthorityRepositorySuite) TestExistsById() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("df30f1c0-9a0c-4095-a14b-13d44d39ec15")
+
+	exists, err := s.repo.ExistsById(context.Background(), id)
+	s.NoError(err)
+	s.True(exists)
+
+	exists, err = s.repo.ExistsById(context.Background(), uuid.New())
+	s.NoError(err)
+	s.False(exists)
+}
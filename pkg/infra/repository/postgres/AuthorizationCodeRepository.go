@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/google/uuid"
+)
+
+type AuthorizationCodeRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewAuthorizationCodeRepository(db database.Database) repository.AuthorizationCodeRepository {
+	return &AuthorizationCodeRepositoryPostgres{db: db}
+}
+
+func (r AuthorizationCodeRepositoryPostgres) Create(ctx context.Context, code *entity.AuthorizationCode) (*entity.AuthorizationCode, error) {
+	insertStatement := `
+		INSERT INTO golauth_authorization_code (code_hash, client_id, user_id, redirect_uri, scope, code_challenge, code_challenge_method, nonce, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, creation_date;`
+	err := r.db.One(ctx, insertStatement, code.CodeHash, code.ClientID, code.UserID, code.RedirectURI, code.Scope,
+		code.CodeChallenge, code.CodeChallengeMethod, code.Nonce, code.ExpiresAt).Scan(&code.ID, &code.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not create authorization code: %w", err)
+	}
+	return code, nil
+}
+
+func (r AuthorizationCodeRepositoryPostgres) FindByHash(ctx context.Context, hash string) (*entity.AuthorizationCode, error) {
+	var code entity.AuthorizationCode
+	query := `
+		SELECT id, code_hash, client_id, user_id, redirect_uri, scope, code_challenge, code_challenge_method, nonce, expires_at, used_at, creation_date
+		FROM golauth_authorization_code
+		WHERE code_hash = $1`
+	err := r.db.One(ctx, query, hash).Scan(&code.ID, &code.CodeHash, &code.ClientID, &code.UserID, &code.RedirectURI,
+		&code.Scope, &code.CodeChallenge, &code.CodeChallengeMethod, &code.Nonce, &code.ExpiresAt, &code.UsedAt, &code.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not find authorization code: %w", err)
+	}
+	return &code, nil
+}
+
+func (r AuthorizationCodeRepositoryPostgres) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	updateStatement := `
+		UPDATE golauth_authorization_code
+		SET used_at = current_timestamp
+		WHERE id = $1 AND used_at IS NULL
+	`
+	res, err := r.db.Exec(ctx, updateStatement, id)
+	if err != nil {
+		return false, fmt.Errorf("could not mark authorization code %s as used: %w", id, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not mark authorization code %s as used: %w", id, err)
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,110 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type AuthorizationCodeRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.AuthorizationCodeRepository
+}
+
+func TestAuthorizationCodeRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(AuthorizationCodeRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *AuthorizationCodeRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewAuthorizationCodeRepository(s.db)
+}
+
+func (s *AuthorizationCodeRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *AuthorizationCodeRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *AuthorizationCodeRepositorySuite) newCode() *entity.AuthorizationCode {
+	return &entity.AuthorizationCode{
+		CodeHash:            "hash",
+		ClientID:            "spa",
+		UserID:              uuid.New(),
+		RedirectURI:         "https://app/callback",
+		Scope:               "openid",
+		CodeChallenge:       "challenge",
+		CodeChallengeMethod: "S256",
+		Nonce:               "n-0S6_WzA2Mj",
+		ExpiresAt:           time.Now().Add(time.Minute),
+	}
+}
+
+func (s *AuthorizationCodeRepositorySuite) TestCreateAndFindByHash() {
+	s.prepareDatabase(true)
+	code, err := s.repo.Create(context.Background(), s.newCode())
+	s.NoError(err)
+	s.NotEqual(uuid.Nil, code.ID)
+
+	found, err := s.repo.FindByHash(context.Background(), "hash")
+	s.NoError(err)
+	s.Equal(code.ID, found.ID)
+	s.Equal("spa", found.ClientID)
+	s.Equal(code.UserID, found.UserID)
+	s.Equal("https://app/callback", found.RedirectURI)
+	s.Equal("S256", found.CodeChallengeMethod)
+	s.Equal("n-0S6_WzA2Mj", found.Nonce)
+	s.False(found.IsUsed())
+}
+
+func (s *AuthorizationCodeRepositorySuite) TestMarkUsedOnlyOnce() {
+	s.prepareDatabase(true)
+	code, err := s.repo.Create(context.Background(), s.newCode())
+	s.NoError(err)
+
+	marked, err := s.repo.MarkUsed(context.Background(), code.ID)
+	s.NoError(err)
+	s.True(marked)
+
+	marked, err = s.repo.MarkUsed(context.Background(), code.ID)
+	s.NoError(err)
+	s.False(marked)
+
+	found, err := s.repo.FindByHash(context.Background(), "hash")
+	s.NoError(err)
+	s.True(found.IsUsed())
+}
+
+func (s *AuthorizationCodeRepositorySuite) TestFindByHashNotFound() {
+	s.prepareDatabase(true)
+	found, err := s.repo.FindByHash(context.Background(), "unknown")
+	s.Error(err)
+	s.Nil(found)
+}
@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/lib/pq"
+)
+
+type ClientRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewClientRepository(db database.Database) repository.ClientRepository {
+	return &ClientRepositoryPostgres{db: db}
+}
+
+func (r ClientRepositoryPostgres) FindByClientID(ctx context.Context, clientID string) (*entity.Client, error) {
+	var client entity.Client
+	query := `
+		SELECT id, client_id, client_secret, name, grant_types, scopes, authorities, redirect_uris, audiences, signing_algorithm, token_ttl, enabled, creation_date
+		FROM golauth_client
+		WHERE client_id = $1`
+	err := r.db.One(ctx, query, clientID).Scan(&client.ID, &client.ClientID, &client.Secret, &client.Name,
+		pq.Array(&client.GrantTypes), pq.Array(&client.Scopes), pq.Array(&client.Authorities), pq.Array(&client.RedirectURIs), pq.Array(&client.Audiences),
+		&client.SigningAlgorithm, &client.TokenTTL, &client.Enabled, &client.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not find client %s: %w", clientID, err)
+	}
+	return &client, nil
+}
+
+func (r ClientRepositoryPostgres) Create(ctx context.Context, client *entity.Client) (*entity.Client, error) {
+	insertStatement := `
+		INSERT INTO golauth_client (client_id, client_secret, name, grant_types, scopes, authorities, redirect_uris, audiences, signing_algorithm, token_ttl, enabled)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, creation_date;`
+	err := r.db.One(ctx, insertStatement, client.ClientID, client.Secret, client.Name,
+		pq.Array(client.GrantTypes), pq.Array(client.Scopes), pq.Array(client.Authorities), pq.Array(client.RedirectURIs), pq.Array(client.Audiences),
+		client.SigningAlgorithm, client.TokenTTL, client.Enabled).Scan(&client.ID, &client.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not create client %s: %w", client.ClientID, err)
+	}
+	return client, nil
+}
@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type ClientRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.ClientRepository
+}
+
+func TestClientRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(ClientRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *ClientRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewClientRepository(s.db)
+}
+
+func (s *ClientRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *ClientRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *ClientRepositorySuite) TestCreateAndFindByClientID() {
+	s.prepareDatabase(true)
+	client, err := s.repo.Create(context.Background(), &entity.Client{
+		ClientID:         "service",
+		Secret:           "hash",
+		Name:             "Service",
+		GrantTypes:       []string{"client_credentials"},
+		Scopes:           []string{"read", "write"},
+		Authorities:      []string{"ADMIN"},
+		RedirectURIs:     []string{"https://app/callback"},
+		Audiences:        []string{"orders-api"},
+		SigningAlgorithm: "ES256",
+		TokenTTL:         300,
+		Enabled:          true,
+	})
+	s.NoError(err)
+	s.NotZero(client.ID)
+	s.NotZero(client.CreationDate)
+
+	found, err := s.repo.FindByClientID(context.Background(), "service")
+	s.NoError(err)
+	s.Equal(client.ID, found.ID)
+	s.Equal([]string{"client_credentials"}, found.GrantTypes)
+	s.Equal([]string{"read", "write"}, found.Scopes)
+	s.Equal([]string{"ADMIN"}, found.Authorities)
+	s.Equal([]string{"https://app/callback"}, found.RedirectURIs)
+	s.Equal([]string{"orders-api"}, found.Audiences)
+	s.Equal("ES256", found.SigningAlgorithm)
+	s.Equal(300, found.TokenTTL)
+	s.True(found.Enabled)
+}
+
+func (s *ClientRepositorySuite) TestFindByClientIDNotFound() {
+	s.prepareDatabase(true)
+	found, err := s.repo.FindByClientID(context.Background(), "unknown")
+	s.Error(err)
+	s.Nil(found)
+}
+
+func (s *ClientRepositorySuite) TestCreateDuplicatedClientID() {
+	s.prepareDatabase(true)
+	c := &entity.Client{ClientID: "service", Secret: "hash", Enabled: true}
+	_, err := s.repo.Create(context.Background(), c)
+	s.NoError(err)
+	_, err = s.repo.Create(context.Background(), &entity.Client{ClientID: "service", Secret: "hash", Enabled: true})
+	s.Error(err)
+}
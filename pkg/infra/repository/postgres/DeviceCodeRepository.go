@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/google/uuid"
+)
+
+const deviceCodeColumns = `id, device_code_hash, user_code, client_id, scope, user_id, denied, poll_interval, expires_at,
+		last_polled_at, approved_at, used_at, creation_date`
+
+type DeviceCodeRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewDeviceCodeRepository(db database.Database) repository.DeviceCodeRepository {
+	return &DeviceCodeRepositoryPostgres{db: db}
+}
+
+// Create stores the device code after purging the expired or used code holding the same user code, so user codes
+// only need to be unique among the codes still waiting for the user. A live code fails with repository.ErrDuplicate.
+func (r DeviceCodeRepositoryPostgres) Create(ctx context.Context, code *entity.DeviceCode) (*entity.DeviceCode, error) {
+	deleteStatement := `
+		DELETE FROM golauth_device_code
+		WHERE user_code = $1 AND (expires_at <= current_timestamp OR used_at IS NOT NULL)
+	`
+	_, err := r.db.Exec(ctx, deleteStatement, code.UserCode)
+	if err != nil {
+		return nil, fmt.Errorf("could not purge device code: %w", err)
+	}
+	insertStatement := `
+		INSERT INTO golauth_device_code (device_code_hash, user_code, client_id, scope, poll_interval, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, creation_date;`
+	err = r.db.One(ctx, insertStatement, code.DeviceCodeHash, code.UserCode, code.ClientID, code.Scope, code.Interval,
+		code.ExpiresAt).Scan(&code.ID, &code.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not create device code: %w", translateError(err))
+	}
+	return code, nil
+}
+
+func (r DeviceCodeRepositoryPostgres) FindByHash(ctx context.Context, hash string) (*entity.DeviceCode, error) {
+	return r.find(ctx, "device_code_hash", hash)
+}
+
+func (r DeviceCodeRepositoryPostgres) FindByUserCode(ctx context.Context, userCode string) (*entity.DeviceCode, error) {
+	return r.find(ctx, "user_code", userCode)
+}
+
+func (r DeviceCodeRepositoryPostgres) find(ctx context.Context, column string, value string) (*entity.DeviceCode, error) {
+	var code entity.DeviceCode
+	query := fmt.Sprintf(`SELECT %s FROM golauth_device_code WHERE %s = $1`, deviceCodeColumns, column)
+	err := r.db.One(ctx, query, value).Scan(&code.ID, &code.DeviceCodeHash, &code.UserCode, &code.ClientID, &code.Scope,
+		&code.UserID, &code.Denied, &code.Interval, &code.ExpiresAt, &code.LastPolledAt, &code.ApprovedAt, &code.UsedAt,
+		&code.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not find device code: %w", err)
+	}
+	return &code, nil
+}
+
+func (r DeviceCodeRepositoryPostgres) Approve(ctx context.Context, id uuid.UUID, userID uuid.UUID) (bool, error) {
+	updateStatement := `
+		UPDATE golauth_device_code
+		SET user_id = $2, approved_at = current_timestamp
+		WHERE id = $1 AND user_id IS NULL AND denied = false
+	`
+	return r.update(ctx, "approve", id, updateStatement, id, userID)
+}
+
+func (r DeviceCodeRepositoryPostgres) Deny(ctx context.Context, id uuid.UUID) (bool, error) {
+	updateStatement := `
+		UPDATE golauth_device_code
+		SET denied = true
+		WHERE id = $1 AND user_id IS NULL AND denied = false
+	`
+	return r.update(ctx, "deny", id, updateStatement, id)
+}
+
+// Poll records a poll of the device code, and reports false without recording it when the previous
+// poll happened less than the polling interval ago. The database clock is used so replicas agree.
+func (r DeviceCodeRepositoryPostgres) Poll(ctx context.Context, id uuid.UUID) (bool, error) {
+	updateStatement := `
+		UPDATE golauth_device_code
+		SET last_polled_at = current_timestamp
+		WHERE id = $1 AND (last_polled_at IS NULL OR last_polled_at + poll_interval * interval '1 second' <= current_timestamp)
+	`
+	return r.update(ctx, "poll", id, updateStatement, id)
+}
+
+func (r DeviceCodeRepositoryPostgres) SlowDown(ctx context.Context, id uuid.UUID, increment int) error {
+	updateStatement := `
+		UPDATE golauth_device_code
+		SET poll_interval = poll_interval + $2, last_polled_at = current_timestamp
+		WHERE id = $1
+	`
+	_, err := r.update(ctx, "slow down", id, updateStatement, id, increment)
+	return err
+}
+
+func (r DeviceCodeRepositoryPostgres) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	updateStatement := `
+		UPDATE golauth_device_code
+		SET used_at = current_timestamp
+		WHERE id = $1 AND used_at IS NULL
+	`
+	return r.update(ctx, "mark as used", id, updateStatement, id)
+}
+
+func (r DeviceCodeRepositoryPostgres) update(ctx context.Context, action string, id uuid.UUID, statement string, args ...interface{}) (bool, error) {
+	res, err := r.db.Exec(ctx, statement, args...)
+	if err != nil {
+		return false, fmt.Errorf("could not %s device code %s: %w", action, id, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not %s device code %s: %w", action, id, err)
+	}
+	return rows > 0, nil
+}
@@ -0,0 +1,209 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type DeviceCodeRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.DeviceCodeRepository
+}
+
+func TestDeviceCodeRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(DeviceCodeRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *DeviceCodeRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewDeviceCodeRepository(s.db)
+}
+
+func (s *DeviceCodeRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *DeviceCodeRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *DeviceCodeRepositorySuite) newCode() *entity.DeviceCode {
+	return &entity.DeviceCode{
+		DeviceCodeHash: "hash",
+		UserCode:       "BCDFGHJK",
+		ClientID:       "cli",
+		Scope:          "openid",
+		Interval:       5,
+		ExpiresAt:      time.Now().Add(time.Minute),
+	}
+}
+
+func (s *DeviceCodeRepositorySuite) TestCreateAndFind() {
+	s.prepareDatabase(true)
+	code, err := s.repo.Create(context.Background(), s.newCode())
+	s.NoError(err)
+	s.NotEqual(uuid.Nil, code.ID)
+
+	found, err := s.repo.FindByHash(context.Background(), "hash")
+	s.NoError(err)
+	s.Equal(code.ID, found.ID)
+	s.Equal("BCDFGHJK", found.UserCode)
+	s.Equal("cli", found.ClientID)
+	s.Equal("openid", found.Scope)
+	s.Equal(5, found.Interval)
+	s.True(found.IsPending())
+	s.False(found.IsUsed())
+
+	found, err = s.repo.FindByUserCode(context.Background(), "BCDFGHJK")
+	s.NoError(err)
+	s.Equal(code.ID, found.ID)
+}
+
+func (s *DeviceCodeRepositorySuite) TestCreateDuplicateUserCode() {
+	s.prepareDatabase(true)
+	_, err := s.repo.Create(context.Background(), s.newCode())
+	s.NoError(err)
+
+	duplicate := s.newCode()
+	duplicate.DeviceCodeHash = "other-hash"
+	_, err = s.repo.Create(context.Background(), duplicate)
+	s.ErrorIs(err, repository.ErrDuplicate)
+}
+
+func (s *DeviceCodeRepositorySuite) TestCreateReusesUserCodeOfExpiredAndUsedCodes() {
+	s.prepareDatabase(true)
+	expired := s.newCode()
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	_, err := s.repo.Create(context.Background(), expired)
+	s.NoError(err)
+
+	used := s.newCode()
+	used.DeviceCodeHash = "used-hash"
+	used, err = s.repo.Create(context.Background(), used)
+	s.NoError(err)
+	marked, err := s.repo.MarkUsed(context.Background(), used.ID)
+	s.NoError(err)
+	s.True(marked)
+
+	code := s.newCode()
+	code.DeviceCodeHash = "new-hash"
+	code, err = s.repo.Create(context.Background(), code)
+	s.NoError(err)
+
+	found, err := s.repo.FindByUserCode(context.Background(), "BCDFGHJK")
+	s.NoError(err)
+	s.Equal(code.ID, found.ID)
+}
+
+func (s *DeviceCodeRepositorySuite) TestFindNotFound() {
+	s.prepareDatabase(true)
+	found, err := s.repo.FindByUserCode(context.Background(), "unknown")
+	s.Error(err)
+	s.Nil(found)
+}
+
+func (s *DeviceCodeRepositorySuite) TestApproveOnlyOnce() {
+	s.prepareDatabase(true)
+	code, err := s.repo.Create(context.Background(), s.newCode())
+	s.NoError(err)
+	userID := uuid.New()
+
+	approved, err := s.repo.Approve(context.Background(), code.ID, userID)
+	s.NoError(err)
+	s.True(approved)
+
+	approved, err = s.repo.Approve(context.Background(), code.ID, uuid.New())
+	s.NoError(err)
+	s.False(approved)
+
+	denied, err := s.repo.Deny(context.Background(), code.ID)
+	s.NoError(err)
+	s.False(denied)
+
+	found, err := s.repo.FindByHash(context.Background(), "hash")
+	s.NoError(err)
+	s.True(found.IsApproved())
+	s.Equal(userID, *found.UserID)
+	s.NotNil(found.ApprovedAt)
+}
+
+func (s *DeviceCodeRepositorySuite) TestDeny() {
+	s.prepareDatabase(true)
+	code, err := s.repo.Create(context.Background(), s.newCode())
+	s.NoError(err)
+
+	denied, err := s.repo.Deny(context.Background(), code.ID)
+	s.NoError(err)
+	s.True(denied)
+
+	approved, err := s.repo.Approve(context.Background(), code.ID, uuid.New())
+	s.NoError(err)
+	s.False(approved)
+
+	found, err := s.repo.FindByHash(context.Background(), "hash")
+	s.NoError(err)
+	s.True(found.Denied)
+	s.False(found.IsPending())
+}
+
+func (s *DeviceCodeRepositorySuite) TestPollInsideIntervalAndSlowDown() {
+	s.prepareDatabase(true)
+	code, err := s.repo.Create(context.Background(), s.newCode())
+	s.NoError(err)
+
+	polled, err := s.repo.Poll(context.Background(), code.ID)
+	s.NoError(err)
+	s.True(polled)
+
+	polled, err = s.repo.Poll(context.Background(), code.ID)
+	s.NoError(err)
+	s.False(polled)
+
+	err = s.repo.SlowDown(context.Background(), code.ID, 5)
+	s.NoError(err)
+
+	found, err := s.repo.FindByHash(context.Background(), "hash")
+	s.NoError(err)
+	s.Equal(10, found.Interval)
+	s.NotNil(found.LastPolledAt)
+}
+
+func (s *DeviceCodeRepositorySuite) TestMarkUsedOnlyOnce() {
+	s.prepareDatabase(true)
+	code, err := s.repo.Create(context.Background(), s.newCode())
+	s.NoError(err)
+
+	marked, err := s.repo.MarkUsed(context.Background(), code.ID)
+	s.NoError(err)
+	s.True(marked)
+
+	marked, err = s.repo.MarkUsed(context.Background(), code.ID)
+	s.NoError(err)
+	s.False(marked)
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/google/uuid"
+	"time"
+)
+
+type EmailVerificationTokenRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewEmailVerificationTokenRepository(db database.Database) repository.EmailVerificationTokenRepository {
+	return &EmailVerificationTokenRepositoryPostgres{db: db}
+}
+
+func (r EmailVerificationTokenRepositoryPostgres) Create(ctx context.Context, token *entity.EmailVerificationToken) (*entity.EmailVerificationToken, error) {
+	insertStatement := `
+		WITH previous AS (
+			UPDATE golauth_email_verification_token
+			SET used_at = current_timestamp
+			WHERE user_id = $1 AND used_at IS NULL
+		)
+		INSERT INTO golauth_email_verification_token (user_id, email, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, creation_date
+	`
+	err := r.db.One(ctx, insertStatement, token.UserID, token.Email, token.TokenHash, token.ExpiresAt).Scan(&token.ID, &token.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not create email verification token: %w", err)
+	}
+	return token, nil
+}
+
+func (r EmailVerificationTokenRepositoryPostgres) HasPending(ctx context.Context, userID uuid.UUID, email string, now time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM golauth_email_verification_token
+			WHERE user_id = $1 AND email = $2 AND used_at IS NULL AND expires_at > $3
+		)
+	`
+	var pending bool
+	err := r.db.One(ctx, query, userID, email, now).Scan(&pending)
+	if err != nil {
+		return false, fmt.Errorf("could not find pending email verification token: %w", err)
+	}
+	return pending, nil
+}
+
+func (r EmailVerificationTokenRepositoryPostgres) Consume(ctx context.Context, hash string, now time.Time) (*entity.EmailVerificationToken, error) {
+	updateStatement := `
+		UPDATE golauth_email_verification_token
+		SET used_at = current_timestamp
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING id, user_id, email, token_hash, expires_at, used_at, creation_date
+	`
+	var token entity.EmailVerificationToken
+	err := r.db.One(ctx, updateStatement, hash, now).Scan(&token.ID, &token.UserID, &token.Email, &token.TokenHash,
+		&token.ExpiresAt, &token.UsedAt, &token.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not consume email verification token: %w", translateError(err))
+	}
+	return &token, nil
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type EmailVerificationTokenRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo        repository.EmailVerificationTokenRepository
+	userAdminId uuid.UUID
+}
+
+func TestEmailVerificationTokenRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(EmailVerificationTokenRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *EmailVerificationTokenRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewEmailVerificationTokenRepository(s.db)
+	s.userAdminId, _ = uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+}
+
+func (s *EmailVerificationTokenRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *EmailVerificationTokenRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *EmailVerificationTokenRepositorySuite) create(hash string, expiresAt time.Time) {
+	token, err := s.repo.Create(context.Background(), &entity.EmailVerificationToken{
+		UserID:    s.userAdminId,
+		Email:     "admin@goauth.org",
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+	})
+	s.NoError(err)
+	s.NotEqual(uuid.Nil, token.ID)
+}
+
+func (s *EmailVerificationTokenRepositorySuite) TestConsumeOnce() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	s.create("hash", now.Add(time.Hour))
+
+	token, err := s.repo.Consume(context.Background(), "hash", now)
+	s.NoError(err)
+	s.Equal(s.userAdminId, token.UserID)
+	s.Equal("admin@goauth.org", token.Email)
+	s.NotNil(token.UsedAt)
+
+	_, err = s.repo.Consume(context.Background(), "hash", now)
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *EmailVerificationTokenRepositorySuite) TestConsumeExpired() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	s.create("hash", now.Add(-time.Minute))
+
+	_, err := s.repo.Consume(context.Background(), "hash", now)
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *EmailVerificationTokenRepositorySuite) TestCreateInvalidatesPreviousTokens() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	s.create("first", now.Add(time.Hour))
+	s.create("second", now.Add(time.Hour))
+
+	_, err := s.repo.Consume(context.Background(), "first", now)
+	s.ErrorIs(err, repository.ErrNotFound)
+	token, err := s.repo.Consume(context.Background(), "second", now)
+	s.NoError(err)
+	s.Equal(s.userAdminId, token.UserID)
+}
+
+func (s *EmailVerificationTokenRepositorySuite) TestConsumeUnknown() {
+	s.prepareDatabase(true)
+	_, err := s.repo.Consume(context.Background(), "unknown", time.Now())
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *EmailVerificationTokenRepositorySuite) TestHasPending() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	pending, err := s.repo.HasPending(context.Background(), s.userAdminId, "admin@goauth.org", now)
+	s.NoError(err)
+	s.False(pending)
+
+	s.create("hash", now.Add(time.Hour))
+	pending, err = s.repo.HasPending(context.Background(), s.userAdminId, "admin@goauth.org", now)
+	s.NoError(err)
+	s.True(pending)
+
+	pending, err = s.repo.HasPending(context.Background(), s.userAdminId, "new@goauth.org", now)
+	s.NoError(err)
+	s.False(pending)
+
+	_, err = s.repo.Consume(context.Background(), "hash", now)
+	s.NoError(err)
+	pending, err = s.repo.HasPending(context.Background(), s.userAdminId, "admin@goauth.org", now)
+	s.NoError(err)
+	s.False(pending)
+}
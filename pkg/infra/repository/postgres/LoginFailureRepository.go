@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"time"
+)
+
+type LoginFailureRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewLoginFailureRepository(db database.Database) repository.LoginFailureRepository {
+	return &LoginFailureRepositoryPostgres{db: db}
+}
+
+func (r LoginFailureRepositoryPostgres) Find(ctx context.Context, kind string, subject string) (*entity.LoginFailure, error) {
+	query := `
+		SELECT kind, subject, failures, last_failure, locked_until FROM golauth_login_failure
+		WHERE kind = $1 AND subject = $2
+	`
+	failure, err := scanLoginFailure(r.db.One(ctx, query, kind, subject))
+	if err != nil {
+		return nil, fmt.Errorf("could not find login failures of %s %s: %w", kind, subject, translateError(err))
+	}
+	return failure, nil
+}
+
+// RecordAttempt counts and reads in a single upsert, so concurrent attempts on several replicas each get their own
+// count and none of them gets past a lock.
+func (r LoginFailureRepositoryPostgres) RecordAttempt(ctx context.Context, kind string, subject string, now time.Time, since time.Time) (*entity.LoginFailure, error) {
+	upsertStatement := `
+		INSERT INTO golauth_login_failure AS f (kind, subject, failures, last_failure) VALUES ($1, $2, 1, $3)
+		ON CONFLICT (kind, subject) DO UPDATE SET
+			failures = CASE
+				WHEN f.locked_until > $3 THEN f.failures
+				WHEN f.last_failure < $4 THEN 1
+				ELSE f.failures + 1
+			END,
+			last_failure = CASE WHEN f.locked_until > $3 THEN f.last_failure ELSE excluded.last_failure END
+		RETURNING kind, subject, failures, last_failure, locked_until
+	`
+	failure, err := scanLoginFailure(r.db.One(ctx, upsertStatement, kind, subject, now, since))
+	if err != nil {
+		return nil, fmt.Errorf("could not record login attempt of %s %s: %w", kind, subject, err)
+	}
+	return failure, nil
+}
+
+func (r LoginFailureRepositoryPostgres) ForgetAttempt(ctx context.Context, kind string, subject string) error {
+	_, err := r.db.Exec(ctx, "UPDATE golauth_login_failure SET failures = failures - 1 WHERE kind = $1 AND subject = $2 AND failures > 0", kind, subject)
+	if err != nil {
+		return fmt.Errorf("could not forget login attempt of %s %s: %w", kind, subject, err)
+	}
+	return nil
+}
+
+func (r LoginFailureRepositoryPostgres) Lock(ctx context.Context, kind string, subject string, until time.Time) error {
+	res, err := r.db.Exec(ctx, "UPDATE golauth_login_failure SET locked_until = GREATEST(locked_until, $3) WHERE kind = $1 AND subject = $2", kind, subject, until)
+	if err != nil {
+		return fmt.Errorf("could not lock logins of %s %s: %w", kind, subject, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not lock logins of %s %s", kind, subject))
+}
+
+func (r LoginFailureRepositoryPostgres) Delete(ctx context.Context, kind string, subject string) error {
+	res, err := r.db.Exec(ctx, "DELETE FROM golauth_login_failure WHERE kind = $1 AND subject = $2", kind, subject)
+	if err != nil {
+		return fmt.Errorf("could not delete login failures of %s %s: %w", kind, subject, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not delete login failures of %s %s", kind, subject))
+}
+
+func (r LoginFailureRepositoryPostgres) FindLocked(ctx context.Context, now time.Time) ([]*entity.LoginFailure, error) {
+	query := `
+		SELECT kind, subject, failures, last_failure, locked_until FROM golauth_login_failure
+		WHERE locked_until > $1
+		ORDER BY locked_until
+	`
+	rows, err := r.db.Many(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("could not find locked logins: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*entity.LoginFailure
+	for rows.Next() {
+		failure, err := scanLoginFailure(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not transform result in slice: %w", err)
+		}
+		result = append(result, failure)
+	}
+	return result, nil
+}
+
+func scanLoginFailure(row interface{ Scan(dest ...any) error }) (*entity.LoginFailure, error) {
+	var failure entity.LoginFailure
+	err := row.Scan(&failure.Kind, &failure.Subject, &failure.Failures, &failure.LastFailure, &failure.LockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	return &failure, nil
+}
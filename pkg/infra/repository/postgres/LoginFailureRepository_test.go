@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"sync"
+	"testing"
+	"time"
+)
+
+type LoginFailureRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.LoginFailureRepository
+}
+
+func TestLoginFailureRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(LoginFailureRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *LoginFailureRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewLoginFailureRepository(s.db)
+}
+
+func (s *LoginFailureRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *LoginFailureRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *LoginFailureRepositorySuite) TestRecordAttemptCounts() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	for i := 1; i <= 3; i++ {
+		failure, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, "admin", now, now.Add(-time.Hour))
+		s.NoError(err)
+		s.Equal(i, failure.Failures)
+		s.Nil(failure.LockedUntil)
+	}
+
+	failure, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindIP, "10.0.0.1", now, now.Add(-time.Hour))
+	s.NoError(err)
+	s.Equal(1, failure.Failures)
+}
+
+func (s *LoginFailureRepositorySuite) TestRecordAttemptForgetsOldFailures() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, "admin", now.Add(-2*time.Hour), now.Add(-3*time.Hour))
+	s.NoError(err)
+
+	failure, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, "admin", now, now.Add(-time.Hour))
+	s.NoError(err)
+	s.Equal(1, failure.Failures)
+}
+
+func (s *LoginFailureRepositorySuite) TestRecordAttemptConcurrent() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	const attempts = 20
+	counts := make(chan int, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			failure, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, "admin", now, now.Add(-time.Hour))
+			s.NoError(err)
+			counts <- failure.Failures
+		}()
+	}
+	wg.Wait()
+	close(counts)
+
+	seen := map[int]bool{}
+	for c := range counts {
+		seen[c] = true
+	}
+	s.Len(seen, attempts)
+}
+
+func (s *LoginFailureRepositorySuite) TestRecordAttemptWhileLocked() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, "admin", now, now.Add(-time.Hour))
+	s.NoError(err)
+	s.NoError(s.repo.Lock(context.Background(), entity.LoginFailureKindUsername, "admin", now.Add(time.Minute)))
+
+	failure, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, "admin", now, now.Add(-time.Hour))
+	s.NoError(err)
+	s.Equal(1, failure.Failures)
+	s.NotNil(failure.LockedUntil)
+}
+
+func (s *LoginFailureRepositorySuite) TestForgetAttempt() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		_, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindIP, "10.0.0.1", now, now.Add(-time.Hour))
+		s.NoError(err)
+	}
+
+	s.NoError(s.repo.ForgetAttempt(context.Background(), entity.LoginFailureKindIP, "10.0.0.1"))
+	failure, err := s.repo.Find(context.Background(), entity.LoginFailureKindIP, "10.0.0.1")
+	s.NoError(err)
+	s.Equal(1, failure.Failures)
+	s.NoError(s.repo.ForgetAttempt(context.Background(), entity.LoginFailureKindIP, "10.0.0.2"))
+}
+
+func (s *LoginFailureRepositorySuite) TestLockKeepsLongerLock() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, "admin", now, now.Add(-time.Hour))
+	s.NoError(err)
+	s.NoError(s.repo.Lock(context.Background(), entity.LoginFailureKindUsername, "admin", now.Add(time.Hour)))
+	s.NoError(s.repo.Lock(context.Background(), entity.LoginFailureKindUsername, "admin", now.Add(time.Minute)))
+
+	failure, err := s.repo.Find(context.Background(), entity.LoginFailureKindUsername, "admin")
+	s.NoError(err)
+	s.WithinDuration(now.Add(time.Hour), *failure.LockedUntil, time.Second)
+}
+
+func (s *LoginFailureRepositorySuite) TestLockAndFind() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, "admin", now, now.Add(-time.Hour))
+	s.NoError(err)
+	s.NoError(s.repo.Lock(context.Background(), entity.LoginFailureKindUsername, "admin", now.Add(time.Minute)))
+
+	failure, err := s.repo.Find(context.Background(), entity.LoginFailureKindUsername, "admin")
+	s.NoError(err)
+	s.Equal(1, failure.Failures)
+	s.NotNil(failure.LockedUntil)
+	s.WithinDuration(now.Add(time.Minute), *failure.LockedUntil, time.Second)
+}
+
+func (s *LoginFailureRepositorySuite) TestLockNotFound() {
+	s.prepareDatabase(true)
+	err := s.repo.Lock(context.Background(), entity.LoginFailureKindUsername, "unknown", time.Now())
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *LoginFailureRepositorySuite) TestFindNotFound() {
+	s.prepareDatabase(true)
+	_, err := s.repo.Find(context.Background(), entity.LoginFailureKindIP, "10.0.0.1")
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *LoginFailureRepositorySuite) TestFindLocked() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	for _, subject := range []string{"later", "sooner", "expired", "unlocked"} {
+		_, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindUsername, subject, now, now.Add(-time.Hour))
+		s.NoError(err)
+	}
+	s.NoError(s.repo.Lock(context.Background(), entity.LoginFailureKindUsername, "later", now.Add(time.Hour)))
+	s.NoError(s.repo.Lock(context.Background(), entity.LoginFailureKindUsername, "sooner", now.Add(time.Minute)))
+	s.NoError(s.repo.Lock(context.Background(), entity.LoginFailureKindUsername, "expired", now.Add(-time.Minute)))
+
+	locked, err := s.repo.FindLocked(context.Background(), now)
+	s.NoError(err)
+	s.Len(locked, 2)
+	s.Equal("sooner", locked[0].Subject)
+	s.Equal("later", locked[1].Subject)
+}
+
+func (s *LoginFailureRepositorySuite) TestDelete() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.RecordAttempt(context.Background(), entity.LoginFailureKindIP, "10.0.0.1", now, now.Add(-time.Hour))
+	s.NoError(err)
+
+	s.NoError(s.repo.Delete(context.Background(), entity.LoginFailureKindIP, "10.0.0.1"))
+	err = s.repo.Delete(context.Background(), entity.LoginFailureKindIP, "10.0.0.1")
+	s.ErrorIs(err, repository.ErrNotFound)
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/google/uuid"
+)
+
+type PasswordHistoryRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewPasswordHistoryRepository(db database.Database) repository.PasswordHistoryRepository {
+	return &PasswordHistoryRepositoryPostgres{db: db}
+}
+
+// Add prunes and inserts in one statement. The delete does not see the row being inserted, so it keeps the
+// newest keep-1 older hashes.
+func (r PasswordHistoryRepositoryPostgres) Add(ctx context.Context, userID uuid.UUID, hash string, keep int) error {
+	insertStatement := `
+		WITH added AS (
+			INSERT INTO golauth_password_history (user_id, password_hash)
+			VALUES ($1, $2)
+		)
+		DELETE FROM golauth_password_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM golauth_password_history
+			WHERE user_id = $1
+			ORDER BY creation_date DESC
+			LIMIT $3
+		)
+	`
+	_, err := r.db.Exec(ctx, insertStatement, userID, hash, max(keep-1, 0))
+	if err != nil {
+		return fmt.Errorf("could not add password history of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+func (r PasswordHistoryRepositoryPostgres) FindLatest(ctx context.Context, userID uuid.UUID, limit int) ([]string, error) {
+	query := `
+		SELECT password_hash FROM golauth_password_history
+		WHERE user_id = $1
+		ORDER BY creation_date DESC
+		LIMIT $2
+	`
+	rows, err := r.db.Many(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not find password history of user %s: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var result []string
+	for rows.Next() {
+		var hash string
+		err = rows.Scan(&hash)
+		if err != nil {
+			return nil, fmt.Errorf("could not transform result in slice: %w", err)
+		}
+		result = append(result, hash)
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type PasswordHistoryRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo        repository.PasswordHistoryRepository
+	userAdminId uuid.UUID
+}
+
+func TestPasswordHistoryRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(PasswordHistoryRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *PasswordHistoryRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewPasswordHistoryRepository(s.db)
+	s.userAdminId, _ = uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+}
+
+func (s *PasswordHistoryRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *PasswordHistoryRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *PasswordHistoryRepositorySuite) TestAddKeepsNewest() {
+	s.prepareDatabase(true, "add-users.sql")
+	for _, hash := range []string{"first", "second", "third"} {
+		s.NoError(s.repo.Add(context.Background(), s.userAdminId, hash, 2))
+	}
+
+	hashes, err := s.repo.FindLatest(context.Background(), s.userAdminId, 5)
+	s.NoError(err)
+	s.Equal([]string{"third", "second"}, hashes)
+}
+
+func (s *PasswordHistoryRepositorySuite) TestFindLatestLimit() {
+	s.prepareDatabase(true, "add-users.sql")
+	for _, hash := range []string{"first", "second", "third"} {
+		s.NoError(s.repo.Add(context.Background(), s.userAdminId, hash, 5))
+	}
+
+	hashes, err := s.repo.FindLatest(context.Background(), s.userAdminId, 1)
+	s.NoError(err)
+	s.Equal([]string{"third"}, hashes)
+}
+
+func (s *PasswordHistoryRepositorySuite) TestFindLatestEmpty() {
+	s.prepareDatabase(true)
+	hashes, err := s.repo.FindLatest(context.Background(), uuid.New(), 5)
+	s.NoError(err)
+	s.Empty(hashes)
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/google/uuid"
+	"time"
+)
+
+type PasswordResetTokenRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewPasswordResetTokenRepository(db database.Database) repository.PasswordResetTokenRepository {
+	return &PasswordResetTokenRepositoryPostgres{db: db}
+}
+
+func (r PasswordResetTokenRepositoryPostgres) Create(ctx context.Context, token *entity.PasswordResetToken) (*entity.PasswordResetToken, error) {
+	insertStatement := `
+		WITH previous AS (
+			UPDATE golauth_password_reset_token
+			SET used_at = current_timestamp
+			WHERE user_id = $1 AND used_at IS NULL
+		)
+		INSERT INTO golauth_password_reset_token (user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, creation_date
+	`
+	err := r.db.One(ctx, insertStatement, token.UserID, token.TokenHash, token.ExpiresAt).Scan(&token.ID, &token.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not create password reset token: %w", err)
+	}
+	return token, nil
+}
+
+func (r PasswordResetTokenRepositoryPostgres) HasPending(ctx context.Context, userID uuid.UUID, now time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM golauth_password_reset_token
+			WHERE user_id = $1 AND used_at IS NULL AND expires_at > $2
+		)
+	`
+	var pending bool
+	err := r.db.One(ctx, query, userID, now).Scan(&pending)
+	if err != nil {
+		return false, fmt.Errorf("could not find pending password reset token: %w", err)
+	}
+	return pending, nil
+}
+
+func (r PasswordResetTokenRepositoryPostgres) FindUserID(ctx context.Context, hash string, now time.Time) (uuid.UUID, error) {
+	query := `
+		SELECT user_id FROM golauth_password_reset_token
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+	`
+	var userID uuid.UUID
+	err := r.db.One(ctx, query, hash, now).Scan(&userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("could not find password reset token: %w", translateError(err))
+	}
+	return userID, nil
+}
+
+func (r PasswordResetTokenRepositoryPostgres) Consume(ctx context.Context, hash string, now time.Time) (uuid.UUID, error) {
+	updateStatement := `
+		UPDATE golauth_password_reset_token
+		SET used_at = current_timestamp
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > $2
+		RETURNING user_id
+	`
+	var userID uuid.UUID
+	err := r.db.One(ctx, updateStatement, hash, now).Scan(&userID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("could not consume password reset token: %w", translateError(err))
+	}
+	return userID, nil
+}
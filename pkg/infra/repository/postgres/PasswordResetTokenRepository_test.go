@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type PasswordResetTokenRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo        repository.PasswordResetTokenRepository
+	userAdminId uuid.UUID
+}
+
+func TestPasswordResetTokenRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(PasswordResetTokenRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *PasswordResetTokenRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewPasswordResetTokenRepository(s.db)
+	s.userAdminId, _ = uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+}
+
+func (s *PasswordResetTokenRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *PasswordResetTokenRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *PasswordResetTokenRepositorySuite) create(hash string, expiresAt time.Time) {
+	token, err := s.repo.Create(context.Background(), &entity.PasswordResetToken{
+		UserID:    s.userAdminId,
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+	})
+	s.NoError(err)
+	s.NotEqual(uuid.Nil, token.ID)
+}
+
+func (s *PasswordResetTokenRepositorySuite) TestConsumeOnce() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	s.create("hash", now.Add(time.Hour))
+
+	userID, err := s.repo.Consume(context.Background(), "hash", now)
+	s.NoError(err)
+	s.Equal(s.userAdminId, userID)
+
+	_, err = s.repo.Consume(context.Background(), "hash", now)
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *PasswordResetTokenRepositorySuite) TestFindUserIDKeepsToken() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	s.create("hash", now.Add(time.Hour))
+
+	userID, err := s.repo.FindUserID(context.Background(), "hash", now)
+	s.NoError(err)
+	s.Equal(s.userAdminId, userID)
+
+	userID, err = s.repo.Consume(context.Background(), "hash", now)
+	s.NoError(err)
+	s.Equal(s.userAdminId, userID)
+
+	_, err = s.repo.FindUserID(context.Background(), "hash", now)
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *PasswordResetTokenRepositorySuite) TestConsumeExpired() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	s.create("hash", now.Add(-time.Minute))
+
+	_, err := s.repo.Consume(context.Background(), "hash", now)
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *PasswordResetTokenRepositorySuite) TestCreateInvalidatesPreviousTokens() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	s.create("first", now.Add(time.Hour))
+	s.create("second", now.Add(time.Hour))
+
+	_, err := s.repo.Consume(context.Background(), "first", now)
+	s.ErrorIs(err, repository.ErrNotFound)
+	userID, err := s.repo.Consume(context.Background(), "second", now)
+	s.NoError(err)
+	s.Equal(s.userAdminId, userID)
+}
+
+func (s *PasswordResetTokenRepositorySuite) TestConsumeUnknown() {
+	s.prepareDatabase(true)
+	_, err := s.repo.Consume(context.Background(), "unknown", time.Now())
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *PasswordResetTokenRepositorySuite) TestHasPending() {
+	s.prepareDatabase(true, "add-users.sql")
+	now := time.Now()
+	pending, err := s.repo.HasPending(context.Background(), s.userAdminId, now)
+	s.NoError(err)
+	s.False(pending)
+
+	s.create("hash", now.Add(time.Hour))
+	pending, err = s.repo.HasPending(context.Background(), s.userAdminId, now)
+	s.NoError(err)
+	s.True(pending)
+
+	pending, err = s.repo.HasPending(context.Background(), s.userAdminId, now.Add(2*time.Hour))
+	s.NoError(err)
+	s.False(pending)
+
+	_, err = s.repo.Consume(context.Background(), "hash", now)
+	s.NoError(err)
+	pending, err = s.repo.HasPending(context.Background(), s.userAdminId, now)
+	s.NoError(err)
+	s.False(pending)
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/google/uuid"
+)
+
+type RefreshTokenRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewRefreshTokenRepository(db database.Database) repository.RefreshTokenRepository {
+	return &RefreshTokenRepositoryPostgres{db: db}
+}
+
+func (r RefreshTokenRepositoryPostgres) Create(ctx context.Context, token *entity.RefreshToken) (*entity.RefreshToken, error) {
+	err := r.db.One(ctx, "INSERT INTO golauth_refresh_token (family_id, user_id, token_hash, client_id, scope, expires_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, creation_date;",
+		token.FamilyID, token.UserID, token.TokenHash, token.ClientID, token.Scope, token.ExpiresAt).Scan(&token.ID, &token.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not create refresh token: %w", err)
+	}
+	return token, nil
+}
+
+func (r RefreshTokenRepositoryPostgres) FindByHash(ctx context.Context, hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	query := `
+		SELECT id, family_id, user_id, token_hash, client_id, scope, expires_at, used_at, revoked_at, creation_date
+		FROM golauth_refresh_token
+		WHERE token_hash = $1`
+	err := r.db.One(ctx, query, hash).Scan(&token.ID, &token.FamilyID, &token.UserID, &token.TokenHash, &token.ClientID, &token.Scope,
+		&token.ExpiresAt, &token.UsedAt, &token.RevokedAt, &token.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not find refresh token: %w", err)
+	}
+	return &token, nil
+}
+
+func (r RefreshTokenRepositoryPostgres) MarkUsed(ctx context.Context, id uuid.UUID) (bool, error) {
+	updateStatement := `
+		UPDATE golauth_refresh_token
+		SET used_at = current_timestamp
+		WHERE id = $1 AND used_at IS NULL
+	`
+	res, err := r.db.Exec(ctx, updateStatement, id)
+	if err != nil {
+		return false, fmt.Errorf("could not mark refresh token %s as used: %w", id, err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("could not mark refresh token %s as used: %w", id, err)
+	}
+	return rows > 0, nil
+}
+
+func (r RefreshTokenRepositoryPostgres) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	updateStatement := `
+		UPDATE golauth_refresh_token
+		SET revoked_at = current_timestamp
+		WHERE family_id = $1 AND revoked_at IS NULL
+	`
+	_, err := r.db.Exec(ctx, updateStatement, familyID)
+	if err != nil {
+		return fmt.Errorf("could not revoke refresh token family %s: %w", familyID, err)
+	}
+	return nil
+}
+
+func (r RefreshTokenRepositoryPostgres) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+	updateStatement := `
+		UPDATE golauth_refresh_token
+		SET revoked_at = current_timestamp
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`
+	_, err := r.db.Exec(ctx, updateStatement, userID)
+	if err != nil {
+		return fmt.Errorf("could not revoke refresh tokens of user %s: %w", userID, err)
+	}
+	return nil
+}
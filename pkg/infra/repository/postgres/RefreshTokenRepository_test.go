@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type RefreshTokenRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo        repository.RefreshTokenRepository
+	userAdminId uuid.UUID
+}
+
+func TestRefreshTokenRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(RefreshTokenRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *RefreshTokenRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewRefreshTokenRepository(s.db)
+	s.userAdminId, _ = uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+}
+
+func (s *RefreshTokenRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *RefreshTokenRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *RefreshTokenRepositorySuite) create(familyID uuid.UUID, hash string) *entity.RefreshToken {
+	token, err := s.repo.Create(context.Background(), &entity.RefreshToken{
+		FamilyID:  familyID,
+		UserID:    s.userAdminId,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	s.NoError(err)
+	return token
+}
+
+func (s *RefreshTokenRepositorySuite) TestCreateAndFindByHash() {
+	s.prepareDatabase(true, "add-users.sql")
+	created := s.create(uuid.New(), "hash-1")
+	s.NotEqual(uuid.Nil, created.ID)
+
+	found, err := s.repo.FindByHash(context.Background(), "hash-1")
+	s.NoError(err)
+	s.Equal(created.ID, found.ID)
+	s.False(found.IsUsed())
+	s.False(found.IsRevoked())
+	s.Nil(found.Scope)
+}
+
+func (s *RefreshTokenRepositorySuite) TestCreateWithScope() {
+	s.prepareDatabase(true, "add-users.sql")
+	scope := "openid ORDERS_READ"
+	_, err := s.repo.Create(context.Background(), &entity.RefreshToken{
+		FamilyID:  uuid.New(),
+		UserID:    s.userAdminId,
+		TokenHash: "hash-1",
+		ClientID:  "spa",
+		Scope:     &scope,
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	s.NoError(err)
+
+	found, err := s.repo.FindByHash(context.Background(), "hash-1")
+	s.NoError(err)
+	s.Equal(&scope, found.Scope)
+	s.Equal("spa", found.ClientID)
+}
+
+func (s *RefreshTokenRepositorySuite) TestFindByHashNotFound() {
+	s.prepareDatabase(true)
+	_, err := s.repo.FindByHash(context.Background(), "unknown")
+	s.Error(err)
+}
+
+func (s *RefreshTokenRepositorySuite) TestMarkUsedOnlyOnce() {
+	s.prepareDatabase(true, "add-users.sql")
+	created := s.create(uuid.New(), "hash-1")
+
+	marked, err := s.repo.MarkUsed(context.Background(), created.ID)
+	s.NoError(err)
+	s.True(marked)
+
+	marked, err = s.repo.MarkUsed(context.Background(), created.ID)
+	s.NoError(err)
+	s.False(marked)
+}
+
+func (s *RefreshTokenRepositorySuite) TestRevokeFamily() {
+	s.prepareDatabase(true, "add-users.sql")
+	familyID := uuid.New()
+	s.create(familyID, "hash-1")
+	s.create(familyID, "hash-2")
+	s.create(uuid.New(), "hash-3")
+
+	err := s.repo.RevokeFamily(context.Background(), familyID)
+	s.NoError(err)
+
+	for hash, revoked := range map[string]bool{"hash-1": true, "hash-2": true, "hash-3": false} {
+		found, err := s.repo.FindByHash(context.Background(), hash)
+		s.NoError(err)
+		s.Equal(revoked, found.IsRevoked())
+	}
+}
+
+func (s *RefreshTokenRepositorySuite) TestRevokeByUserID() {
+	s.prepareDatabase(true, "add-users.sql")
+	s.create(uuid.New(), "hash-1")
+	s.create(uuid.New(), "hash-2")
+
+	err := s.repo.RevokeByUserID(context.Background(), s.userAdminId)
+	s.NoError(err)
+
+	for _, hash := range []string{"hash-1", "hash-2"} {
+		found, err := s.repo.FindByHash(context.Background(), hash)
+		s.NoError(err)
+		s.True(found.IsRevoked())
+	}
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"time"
+)
+
+type RevokedSubjectRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewRevokedSubjectRepository(db database.Database) repository.RevokedSubjectRepository {
+	return &RevokedSubjectRepositoryPostgres{db: db}
+}
+
+func (r RevokedSubjectRepositoryPostgres) Save(ctx context.Context, subject *entity.RevokedSubject) (*entity.RevokedSubject, error) {
+	insertStatement := `
+		INSERT INTO golauth_revoked_subject (subject, revoked_at, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (subject) DO UPDATE SET revoked_at = excluded.revoked_at, expires_at = excluded.expires_at`
+	_, err := r.db.Exec(ctx, insertStatement, subject.Subject, subject.RevokedAt, subject.ExpiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("could not revoke tokens of subject %s: %w", subject.Subject, err)
+	}
+	return subject, nil
+}
+
+func (r RevokedSubjectRepositoryPostgres) FindActive(ctx context.Context, now time.Time) ([]*entity.RevokedSubject, error) {
+	rows, err := r.db.Many(ctx, "SELECT subject, revoked_at, expires_at FROM golauth_revoked_subject WHERE expires_at > $1", now)
+	if err != nil {
+		return nil, fmt.Errorf("could not find revoked subjects: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*entity.RevokedSubject
+	for rows.Next() {
+		var subject entity.RevokedSubject
+		err = rows.Scan(&subject.Subject, &subject.RevokedAt, &subject.ExpiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("could not transform result in slice: %w", err)
+		}
+		result = append(result, &subject)
+	}
+	return result, nil
+}
+
+func (r RevokedSubjectRepositoryPostgres) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM golauth_revoked_subject WHERE expires_at <= $1", now)
+	if err != nil {
+		return fmt.Errorf("could not delete expired revoked subjects: %w", err)
+	}
+	return nil
+}
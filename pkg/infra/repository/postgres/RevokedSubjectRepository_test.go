@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type RevokedSubjectRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.RevokedSubjectRepository
+}
+
+func TestRevokedSubjectRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(RevokedSubjectRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *RevokedSubjectRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewRevokedSubjectRepository(s.db)
+}
+
+func (s *RevokedSubjectRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *RevokedSubjectRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *RevokedSubjectRepositorySuite) TestSaveAndFindActive() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.Save(context.Background(), &entity.RevokedSubject{Subject: "active", RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+	s.NoError(err)
+	_, err = s.repo.Save(context.Background(), &entity.RevokedSubject{Subject: "expired", RevokedAt: now, ExpiresAt: now.Add(-time.Hour)})
+	s.NoError(err)
+
+	subjects, err := s.repo.FindActive(context.Background(), now)
+	s.NoError(err)
+	s.Len(subjects, 1)
+	s.Equal("active", subjects[0].Subject)
+}
+
+func (s *RevokedSubjectRepositorySuite) TestSaveTwiceMovesRevokedAt() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.Save(context.Background(), &entity.RevokedSubject{Subject: "user", RevokedAt: now.Add(-time.Minute), ExpiresAt: now.Add(time.Hour)})
+	s.NoError(err)
+	_, err = s.repo.Save(context.Background(), &entity.RevokedSubject{Subject: "user", RevokedAt: now, ExpiresAt: now.Add(2 * time.Hour)})
+	s.NoError(err)
+
+	subjects, err := s.repo.FindActive(context.Background(), now.Add(90*time.Minute))
+	s.NoError(err)
+	s.Len(subjects, 1)
+}
+
+func (s *RevokedSubjectRepositorySuite) TestDeleteExpired() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.Save(context.Background(), &entity.RevokedSubject{Subject: "active", RevokedAt: now, ExpiresAt: now.Add(time.Hour)})
+	s.NoError(err)
+	_, err = s.repo.Save(context.Background(), &entity.RevokedSubject{Subject: "expired", RevokedAt: now, ExpiresAt: now.Add(-time.Hour)})
+	s.NoError(err)
+
+	s.NoError(s.repo.DeleteExpired(context.Background(), now))
+
+	subjects, err := s.repo.FindActive(context.Background(), now.Add(-2*time.Hour))
+	s.NoError(err)
+	s.Len(subjects, 1)
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"time"
+)
+
+type RevokedTokenRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewRevokedTokenRepository(db database.Database) repository.RevokedTokenRepository {
+	return &RevokedTokenRepositoryPostgres{db: db}
+}
+
+func (r RevokedTokenRepositoryPostgres) Create(ctx context.Context, token *entity.RevokedToken) (*entity.RevokedToken, error) {
+	insertStatement := `
+		INSERT INTO golauth_revoked_token (jti, expires_at) VALUES ($1, $2)
+		ON CONFLICT (jti) DO UPDATE SET expires_at = excluded.expires_at
+		RETURNING creation_date;`
+	err := r.db.One(ctx, insertStatement, token.JTI, token.ExpiresAt).Scan(&token.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not revoke token %s: %w", token.JTI, err)
+	}
+	return token, nil
+}
+
+func (r RevokedTokenRepositoryPostgres) FindActive(ctx context.Context, now time.Time) ([]*entity.RevokedToken, error) {
+	rows, err := r.db.Many(ctx, "SELECT jti, expires_at, creation_date FROM golauth_revoked_token WHERE expires_at > $1", now)
+	if err != nil {
+		return nil, fmt.Errorf("could not find revoked tokens: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*entity.RevokedToken
+	for rows.Next() {
+		var token entity.RevokedToken
+		err = rows.Scan(&token.JTI, &token.ExpiresAt, &token.CreationDate)
+		if err != nil {
+			return nil, fmt.Errorf("could not transform result in slice: %w", err)
+		}
+		result = append(result, &token)
+	}
+	return result, nil
+}
+
+func (r RevokedTokenRepositoryPostgres) DeleteExpired(ctx context.Context, now time.Time) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM golauth_revoked_token WHERE expires_at <= $1", now)
+	if err != nil {
+		return fmt.Errorf("could not delete expired revoked tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+type RevokedTokenRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.RevokedTokenRepository
+}
+
+func TestRevokedTokenRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(RevokedTokenRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *RevokedTokenRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewRevokedTokenRepository(s.db)
+}
+
+func (s *RevokedTokenRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *RevokedTokenRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *RevokedTokenRepositorySuite) TestCreateAndFindActive() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.Create(context.Background(), &entity.RevokedToken{JTI: "active", ExpiresAt: now.Add(time.Hour)})
+	s.NoError(err)
+	_, err = s.repo.Create(context.Background(), &entity.RevokedToken{JTI: "expired", ExpiresAt: now.Add(-time.Hour)})
+	s.NoError(err)
+
+	tokens, err := s.repo.FindActive(context.Background(), now)
+	s.NoError(err)
+	s.Len(tokens, 1)
+	s.Equal("active", tokens[0].JTI)
+}
+
+func (s *RevokedTokenRepositorySuite) TestCreateTwice() {
+	s.prepareDatabase(true)
+	token := &entity.RevokedToken{JTI: "jti", ExpiresAt: time.Now().Add(time.Hour)}
+	_, err := s.repo.Create(context.Background(), token)
+	s.NoError(err)
+	_, err = s.repo.Create(context.Background(), token)
+	s.NoError(err)
+}
+
+func (s *RevokedTokenRepositorySuite) TestDeleteExpired() {
+	s.prepareDatabase(true)
+	now := time.Now()
+	_, err := s.repo.Create(context.Background(), &entity.RevokedToken{JTI: "active", ExpiresAt: now.Add(time.Hour)})
+	s.NoError(err)
+	_, err = s.repo.Create(context.Background(), &entity.RevokedToken{JTI: "expired", ExpiresAt: now.Add(-time.Hour)})
+	s.NoError(err)
+
+	s.NoError(s.repo.DeleteExpired(context.Background(), now))
+
+	tokens, err := s.repo.FindActive(context.Background(), now.Add(-2*time.Hour))
+	s.NoError(err)
+	s.Len(tokens, 1)
+}
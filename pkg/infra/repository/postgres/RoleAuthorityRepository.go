@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/google/uuid"
+)
+
+type RoleAuthorityRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewRoleAuthorityRepository(db database.Database) repository.RoleAuthorityRepository {
+	return &RoleAuthorityRepositoryPostgres{db: db}
+}
+
+func (r RoleAuthorityRepositoryPostgres) AddRoleAuthority(ctx context.Context, roleId uuid.UUID, authorityId uuid.UUID) error {
+	_, err := r.db.Exec(ctx, "INSERT INTO golauth_role_authority (role_id, authority_id) VALUES ($1, $2)", roleId, authorityId)
+	if err != nil {
+		return fmt.Errorf("could not add roleauthority [%s;%s]: %w", roleId, authorityId, translateError(err))
+	}
+	return nil
+}
+
+func (r RoleAuthorityRepositoryPostgres) RemoveRoleAuthority(ctx context.Context, roleId uuid.UUID, authorityId uuid.UUID) error {
+	res, err := r.db.Exec(ctx, "DELETE FROM golauth_role_authority WHERE role_id = $1 AND authority_id = $2", roleId, authorityId)
+	if err != nil {
+		return fmt.Errorf("could not remove roleauthority [%s;%s]: %w", roleId, authorityId, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not remove roleauthority [%s;%s]", roleId, authorityId))
+}
+
+func (r RoleAuthorityRepositoryPostgres) FindAuthoritiesByRoleID(ctx context.Context, roleId uuid.UUID) ([]*entity.Authority, error) {
+	query := `
+		SELECT a.id, a.name, a.description, a.enabled, a.creation_date
+		FROM golauth_authority a
+		INNER JOIN golauth_role_authority ra ON ra.authority_id = a.id
+		WHERE ra.role_id = $1
+		ORDER BY a.name
+	`
+	rows, err := r.db.Many(ctx, query, roleId)
+	if err != nil {
+		return nil, fmt.Errorf("could not find authorities of role %s: %w", roleId, err)
+	}
+	defer rows.Close()
+	return scanAuthorities(rows)
+}
@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"testing"
+)
+
+type RoleAuthorityRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.RoleAuthorityRepository
+}
+
+func TestRoleAuthorityRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(RoleAuthorityRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *RoleAuthorityRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+
+	s.repo = NewRoleAuthorityRepository(s.db)
+}
+
+func (s *RoleAuthorityRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *RoleAuthorityRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *RoleAuthorityRepositorySuite) TestAddAndFind() {
+	s.prepareDatabase(true, "add-users.sql")
+	roleId, _ := uuid.Parse("7f68301e-df80-45bd-9532-23a58733ef2c")
+	userAuthorityId, _ := uuid.Parse("df30f1c0-9a0c-4095-a14b-13d44d39ec15")
+
+	s.NoError(s.repo.AddRoleAuthority(context.Background(), roleId, userAuthorityId))
+
+	authorities, err := s.repo.FindAuthoritiesByRoleID(context.Background(), roleId)
+	s.NoError(err)
+	s.Len(authorities, 2)
+	s.Equal("ADMIN", authorities[0].Name)
+	s.Equal("USER", authorities[1].Name)
+}
+
+func (s *RoleAuthorityRepositorySuite) TestAddDuplicated() {
+	s.prepareDatabase(true, "add-users.sql")
+	roleId, _ := uuid.Parse("7f68301e-df80-45bd-9532-23a58733ef2c")
+	adminAuthorityId, _ := uuid.Parse("8ae4420b-760c-47a6-ab7a-1cb2f9f07c16")
+
+	err := s.repo.AddRoleAuthority(context.Background(), roleId, adminAuthorityId)
+	s.ErrorIs(err, repository.ErrDuplicate)
+}
+
+func (s *RoleAuthorityRepositorySuite) TestRemove() {
+	s.prepareDatabase(true, "add-users.sql")
+	roleId, _ := uuid.Parse("7f68301e-df80-45bd-9532-23a58733ef2c")
+	adminAuthorityId, _ := uuid.Parse("8ae4420b-760c-47a6-ab7a-1cb2f9f07c16")
+
+	s.NoError(s.repo.RemoveRoleAuthority(context.Background(), roleId, adminAuthorityId))
+
+	authorities, err := s.repo.FindAuthoritiesByRoleID(context.Background(), roleId)
+	s.NoError(err)
+	s.Empty(authorities)
+
+	err = s.repo.RemoveRoleAuthority(context.Background(), roleId, adminAuthorityId)
+	s.ErrorIs(err, repository.ErrNotFound)
+}
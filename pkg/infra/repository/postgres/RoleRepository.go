@@ -7,6 +7,8 @@ import (
 	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/infra/database"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"strings"
 )
 
 type RoleRepositoryPostgres struct {
@@ -82,3 +84,120 @@ func (r RoleRepositoryPostgres) ExistsById(ctx context.Context, id uuid.UUID) (b
 	}
 	return exists, nil
 }
+
+var roleSortColumns = map[string]string{
+	repository.RoleSortName:         "r.name",
+	repository.RoleSortCreationDate: "r.creation_date",
+}
+
+func (r RoleRepositoryPostgres) FindAll(ctx context.Context, filter repository.RoleFilter) ([]*entity.Role, int, error) {
+	where, params := roleFilterClause(filter)
+
+	var total int
+	err := r.db.One(ctx, "SELECT count(*) FROM golauth_role r"+where, params...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not count roles: %w", err)
+	}
+
+	column, ok := roleSortColumns[filter.Sort]
+	if !ok {
+		column = roleSortColumns[repository.RoleSortName]
+	}
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
+	}
+	query := fmt.Sprintf(`
+		SELECT r.id, r.name, r.description, r.enabled, r.creation_date
+		FROM golauth_role r%s
+		ORDER BY %s %s, r.id
+		LIMIT $%d OFFSET $%d
+	`, where, column, direction, len(params)+1, len(params)+2)
+	rows, err := r.db.Many(ctx, query, append(params, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not list roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]*entity.Role, 0)
+	for rows.Next() {
+		var role entity.Role
+		err = rows.Scan(&role.ID, &role.Name, &role.Description, &role.Enabled, &role.CreationDate)
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not scan role: %w", err)
+		}
+		roles = append(roles, &role)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("could not list roles: %w", err)
+	}
+	return roles, total, nil
+}
+
+func roleFilterClause(filter repository.RoleFilter) (string, []interface{}) {
+	var conditions []string
+	var params []interface{}
+	add := func(condition string, param interface{}) {
+		params = append(params, param)
+		conditions = append(conditions, fmt.Sprintf(condition, len(params)))
+	}
+	if filter.Name != "" {
+		add("r.name ILIKE $%d", "%"+escapeLike(filter.Name)+"%")
+	}
+	if filter.Enabled != nil {
+		add("r.enabled = $%d", *filter.Enabled)
+	}
+	if len(conditions) == 0 {
+		return "", params
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), params
+}
+
+// Delete removes the role together with its user grants and authorities in a single statement.
+func (r RoleRepositoryPostgres) Delete(ctx context.Context, id uuid.UUID) error {
+	deleteStatement := `
+		WITH users AS (DELETE FROM golauth_user_role WHERE role_id = $1),
+		     authorities AS (DELETE FROM golauth_role_authority WHERE role_id = $1)
+		DELETE FROM golauth_role
+		WHERE id = $1
+	`
+	res, err := r.db.Exec(ctx, deleteStatement, id)
+	if err != nil {
+		return fmt.Errorf("could not delete role %s: %w", id, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not delete role %s", id))
+}
+
+func (r RoleRepositoryPostgres) FindUsage(ctx context.Context) ([]*entity.RoleUsage, error) {
+	query := `
+		SELECT r.id, r.name, r.description, r.enabled, r.creation_date,
+		       (SELECT count(*) FROM golauth_user_role ur WHERE ur.role_id = r.id),
+		       COALESCE(array_agg(a.name ORDER BY a.name) FILTER (WHERE a.id IS NOT NULL), '{}')
+		FROM golauth_role r
+		    LEFT JOIN golauth_role_authority ra ON ra.role_id = r.id
+		    LEFT JOIN golauth_authority a ON a.id = ra.authority_id
+		GROUP BY r.id
+		ORDER BY r.name
+	`
+	rows, err := r.db.Many(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not find role usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := make([]*entity.RoleUsage, 0)
+	for rows.Next() {
+		var u entity.RoleUsage
+		var authorities pq.StringArray
+		err = rows.Scan(&u.Role.ID, &u.Role.Name, &u.Role.Description, &u.Role.Enabled, &u.Role.CreationDate, &u.Users, &authorities)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan role usage: %w", err)
+		}
+		u.Authorities = authorities
+		usage = append(usage, &u)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not find role usage: %w", err)
+	}
+	return usage, nil
+}
@@ -160,3 +160,69 @@ func (s *RoleRepositorySuite) TestRoleRepositoryNotExistsByID() {
 	s.NoError(err)
 	s.False(exists)
 }
+
+func (s *RoleRepositorySuite) TestRoleRepositoryFindAllFiltered() {
+	s.prepareDatabase(true, "add-users.sql")
+	enabled := true
+	roles, total, err := s.repo.FindAll(context.Background(), repository.RoleFilter{
+		Name:    "us",
+		Enabled: &enabled,
+		Limit:   10,
+	})
+	s.NoError(err)
+	s.Equal(1, total)
+	s.Len(roles, 1)
+	s.Equal("USER", roles[0].Name)
+}
+
+func (s *RoleRepositorySuite) TestRoleRepositoryFindAllSortedAndPaged() {
+	s.prepareDatabase(true, "add-users.sql")
+	roles, total, err := s.repo.FindAll(context.Background(), repository.RoleFilter{
+		Sort:   repository.RoleSortName,
+		Desc:   true,
+		Offset: 1,
+		Limit:  1,
+	})
+	s.NoError(err)
+	s.Equal(2, total)
+	s.Len(roles, 1)
+	s.Equal("ADMIN", roles[0].Name)
+}
+
+func (s *RoleRepositorySuite) TestRoleRepositoryDeleteOk() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("c12b415b-c3ad-487f-9800-f548aa18cc58")
+	err := s.repo.Delete(context.Background(), id)
+	s.NoError(err)
+
+	exists, err := s.repo.ExistsById(context.Background(), id)
+	s.NoError(err)
+	s.False(exists)
+	var grants int
+	s.NoError(s.db.One(context.Background(), "SELECT count(*) FROM golauth_user_role WHERE role_id = $1", id).Scan(&grants))
+	s.Zero(grants)
+	s.NoError(s.db.One(context.Background(), "SELECT count(*) FROM golauth_role_authority WHERE role_id = $1", id).Scan(&grants))
+	s.Zero(grants)
+}
+
+func (s *RoleRepositorySuite) TestRoleRepositoryDeleteNotFound() {
+	s.prepareDatabase(true, "add-users.sql")
+	err := s.repo.Delete(context.Background(), uuid.New())
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *RoleRepositorySuite) TestRoleRepositoryFindUsage() {
+	s.prepareDatabase(true, "add-users.sql")
+	_, err := s.repo.Create(context.Background(), entity.NewRole("UNUSED", "Role without users"))
+	s.NoError(err)
+
+	usage, err := s.repo.FindUsage(context.Background())
+	s.NoError(err)
+	s.Len(usage, 3)
+	s.Equal("ADMIN", usage[0].Role.Name)
+	s.Equal(1, usage[0].Users)
+	s.Equal([]string{"ADMIN"}, usage[0].Authorities)
+	s.Equal("UNUSED", usage[1].Role.Name)
+	s.Zero(usage[1].Users)
+	s.Empty(usage[1].Authorities)
+}
@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/pkg/infra/keys"
+	"time"
+)
+
+type SigningKeyRepositoryPostgres struct {
+	db database.Database
+}
+
+func NewSigningKeyRepository(db database.Database) repository.SigningKeyRepository {
+	return &SigningKeyRepositoryPostgres{db: db}
+}
+
+func (r SigningKeyRepositoryPostgres) FindAll(ctx context.Context) ([]*entity.SigningKey, error) {
+	query := "SELECT id, algorithm, private_key, creation_date, retired_at FROM golauth_signing_key ORDER BY creation_date"
+	rows, err := r.db.Many(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("could not find signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*entity.SigningKey
+	for rows.Next() {
+		var key entity.SigningKey
+		var encoded string
+		err = rows.Scan(&key.ID, &key.Algorithm, &encoded, &key.CreationDate, &key.RetiredAt)
+		if err != nil {
+			return nil, fmt.Errorf("could not transform result in slice: %w", err)
+		}
+		key.PrivateKey, _, err = keys.DecodePrivateKey([]byte(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode signing key %s: %w", key.ID, err)
+		}
+		result = append(result, &key)
+	}
+	return result, nil
+}
+
+func (r SigningKeyRepositoryPostgres) Create(ctx context.Context, key *entity.SigningKey) (*entity.SigningKey, error) {
+	encoded, err := keys.EncodePrivateKey(key.PrivateKey, key.Algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("could not encode signing key %s: %w", key.ID, err)
+	}
+	err = r.db.One(ctx, "INSERT INTO golauth_signing_key (id, algorithm, private_key) VALUES ($1, $2, $3) RETURNING creation_date;",
+		key.ID, key.Algorithm, string(encoded)).Scan(&key.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not create signing key %s: %w", key.ID, err)
+	}
+	return key, nil
+}
+
+func (r SigningKeyRepositoryPostgres) Retire(ctx context.Context, id string) error {
+	updateStatement := `
+		UPDATE golauth_signing_key
+		SET retired_at = current_timestamp
+		WHERE id = $1 AND retired_at IS NULL
+	`
+	res, err := r.db.Exec(ctx, updateStatement, id)
+	if err != nil {
+		return fmt.Errorf("could not retire signing key %s: %w", id, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return fmt.Errorf("no rows affected: %w", err)
+	}
+	return nil
+}
+
+// Rotate locks the active key of the algorithm while retiring it, so a replica rotating at the same time finds it
+// already retired. The insert waits for the retirement it reads, and the unique index on the active key of each
+// algorithm refuses the key of the replica that lost the race.
+func (r SigningKeyRepositoryPostgres) Rotate(ctx context.Context, key *entity.SigningKey, interval time.Duration) (bool, error) {
+	encoded, err := keys.EncodePrivateKey(key.PrivateKey, key.Algorithm)
+	if err != nil {
+		return false, fmt.Errorf("could not encode signing key %s: %w", key.ID, err)
+	}
+	rotateStatement := `
+		WITH active AS (
+			SELECT id, $4::float8 = 0 OR creation_date > current_timestamp - $4::float8 * interval '1 second' AS fresh
+			FROM golauth_signing_key
+			WHERE algorithm = $2 AND retired_at IS NULL
+			FOR UPDATE
+		), retired AS (
+			UPDATE golauth_signing_key
+			SET retired_at = current_timestamp
+			WHERE id IN (SELECT id FROM active WHERE NOT fresh)
+			RETURNING id
+		)
+		INSERT INTO golauth_signing_key (id, algorithm, private_key)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (SELECT 1 FROM active WHERE fresh)
+		  AND (EXISTS (SELECT 1 FROM retired) OR NOT EXISTS (SELECT 1 FROM active))
+		RETURNING creation_date
+	`
+	err = r.db.One(ctx, rotateStatement, key.ID, key.Algorithm, string(encoded), interval.Seconds()).Scan(&key.CreationDate)
+	err = translateError(err)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if errors.Is(err, repository.ErrDuplicate) {
+		// another replica stored its key first, which is only fine when that key is now the fresh active one
+		fresh, freshErr := r.activeKeyFresh(ctx, key.Algorithm, interval)
+		if freshErr == nil && fresh {
+			return false, nil
+		}
+	}
+	if err != nil {
+		return false, fmt.Errorf("could not rotate signing key %s: %w", key.ID, err)
+	}
+	return true, nil
+}
+
+// activeKeyFresh reports whether the algorithm has an active key younger than the rotation interval.
+func (r SigningKeyRepositoryPostgres) activeKeyFresh(ctx context.Context, algorithm string, interval time.Duration) (bool, error) {
+	query := `
+		SELECT $2::float8 = 0 OR creation_date > current_timestamp - $2::float8 * interval '1 second'
+		FROM golauth_signing_key
+		WHERE algorithm = $1 AND retired_at IS NULL
+	`
+	var fresh bool
+	err := r.db.One(ctx, query, algorithm, interval.Seconds()).Scan(&fresh)
+	if err != nil {
+		return false, fmt.Errorf("could not find active signing key %s: %w", algorithm, translateError(err))
+	}
+	return fresh, nil
+}
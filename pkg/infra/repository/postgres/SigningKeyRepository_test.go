@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"github.com/golauth/golauth/pkg/domain/entity"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/golauth/golauth/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"sync"
+	"testing"
+	"time"
+)
+
+type SigningKeyRepositorySuite struct {
+	suite.Suite
+	*require.Assertions
+	mockCtrl *gomock.Controller
+	db       database.Database
+
+	repo repository.SigningKeyRepository
+}
+
+func TestSigningKeyRepository(t *testing.T) {
+	ctxContainer, err := tests.ContainerDBStart("./../../../..")
+	assert.NoError(t, err)
+	s := new(SigningKeyRepositorySuite)
+	suite.Run(t, s)
+	tests.ContainerDBStop(ctxContainer)
+}
+
+func (s *SigningKeyRepositorySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.mockCtrl = gomock.NewController(s.T())
+	s.db = database.NewPGDatabase()
+	s.repo = NewSigningKeyRepository(s.db)
+}
+
+func (s *SigningKeyRepositorySuite) TearDownTest() {
+	s.db.Close()
+	s.mockCtrl.Finish()
+}
+
+func (s *SigningKeyRepositorySuite) prepareDatabase(clean bool, scripts ...string) {
+	cleanScript := ""
+	if clean {
+		cleanScript = "clear-data.sql"
+	}
+	err := tests.DatasetTest(s.db, "./../../../..", cleanScript, scripts...)
+	s.NoError(err)
+}
+
+func (s *SigningKeyRepositorySuite) newKey(id string) *entity.SigningKey {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.NoError(err)
+	return entity.NewSigningKey(id, "RS512", privateKey)
+}
+
+func (s *SigningKeyRepositorySuite) TestCreateAndFindAll() {
+	s.prepareDatabase(true)
+	key, err := s.repo.Create(context.Background(), s.newKey("key-1"))
+	s.NoError(err)
+	s.NotZero(key.CreationDate)
+
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(keys, 1)
+	s.Equal("key-1", keys[0].ID)
+	s.False(keys[0].IsRetired())
+	s.True(key.PrivateKey.(*rsa.PrivateKey).Equal(keys[0].PrivateKey))
+}
+
+func (s *SigningKeyRepositorySuite) TestRetire() {
+	s.prepareDatabase(true)
+	_, err := s.repo.Create(context.Background(), s.newKey("key-1"))
+	s.NoError(err)
+
+	err = s.repo.Retire(context.Background(), "key-1")
+	s.NoError(err)
+
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(keys, 1)
+	s.True(keys[0].IsRetired())
+}
+
+func (s *SigningKeyRepositorySuite) TestRetireNotFound() {
+	s.prepareDatabase(true)
+	err := s.repo.Retire(context.Background(), "unknown")
+	s.Error(err)
+}
+
+func (s *SigningKeyRepositorySuite) TestRotate() {
+	s.prepareDatabase(true)
+	_, err := s.repo.Create(context.Background(), s.newKey("key-1"))
+	s.NoError(err)
+
+	rotated, err := s.repo.Rotate(context.Background(), s.newKey("key-2"), time.Hour)
+	s.NoError(err)
+	s.False(rotated)
+
+	_, err = s.db.Exec(context.Background(), "UPDATE golauth_signing_key SET creation_date = creation_date - interval '2 hours'")
+	s.NoError(err)
+	rotated, err = s.repo.Rotate(context.Background(), s.newKey("key-3"), time.Hour)
+	s.NoError(err)
+	s.True(rotated)
+
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(keys, 2)
+	for _, k := range keys {
+		s.Equal(k.ID == "key-1", k.IsRetired())
+	}
+}
+
+func (s *SigningKeyRepositorySuite) TestRotateWithoutIntervalKeepsActiveKey() {
+	s.prepareDatabase(true)
+	_, err := s.repo.Create(context.Background(), s.newKey("key-1"))
+	s.NoError(err)
+
+	rotated, err := s.repo.Rotate(context.Background(), s.newKey("key-2"), 0)
+	s.NoError(err)
+	s.False(rotated)
+}
+
+func (s *SigningKeyRepositorySuite) TestRotateConcurrentlyStoresOneKey() {
+	s.prepareDatabase(true)
+	candidates := []*entity.SigningKey{s.newKey("key-1"), s.newKey("key-2"), s.newKey("key-3")}
+
+	var wg sync.WaitGroup
+	results := make([]bool, len(candidates))
+	errs := make([]error, len(candidates))
+	for i, key := range candidates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = s.repo.Rotate(context.Background(), key, time.Hour)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		s.NoError(err)
+	}
+	keys, err := s.repo.FindAll(context.Background())
+	s.NoError(err)
+	s.Len(keys, 1)
+	s.Contains(results, true)
+}
@@ -20,11 +20,12 @@ func (u UserAuthorityRepositoryPostgres) FindAuthoritiesByUserID(ctx context.Con
 	var authorities []string
 	var err error
 	var query = `
-		SELECT a.name 
-		FROM golauth_authority a 
-		    INNER JOIN golauth_role_authority ra ON ra.authority_id = a.id 
-		    INNER JOIN golauth_user_role ur ON ur.role_id = ra.role_id 
-		WHERE ur.user_id = $1`
+		SELECT DISTINCT a.name
+		FROM golauth_authority a
+		    INNER JOIN golauth_role_authority ra ON ra.authority_id = a.id
+		    INNER JOIN golauth_role r ON r.id = ra.role_id
+		    INNER JOIN golauth_user_role ur ON ur.role_id = ra.role_id
+		WHERE ur.user_id = $1 AND r.enabled = true AND a.enabled = true`
 
 	rows, err := u.db.Many(ctx, query, userId)
 	if err != nil {
@@ -68,3 +68,23 @@ func (s *UserAuthorityRepositorySuite) TestFindAuthoritiesByUserIDUserNotExists(
 	s.NoError(err)
 	s.Nil(a)
 }
+
+func (s *UserAuthorityRepositorySuite) TestFindAuthoritiesByUserIDSkipsDisabledRole() {
+	s.prepareDatabase(true, "add-users.sql")
+	_, err := s.db.Exec(context.Background(), "UPDATE golauth_role SET enabled = false WHERE name = 'ADMIN'")
+	s.NoError(err)
+
+	a, err := s.repo.FindAuthoritiesByUserID(context.Background(), s.userAdminId)
+	s.NoError(err)
+	s.Equal([]string{"USER"}, a)
+}
+
+func (s *UserAuthorityRepositorySuite) TestFindAuthoritiesByUserIDSkipsDisabledAuthority() {
+	s.prepareDatabase(true, "add-users.sql")
+	_, err := s.db.Exec(context.Background(), "UPDATE golauth_authority SET enabled = false WHERE name = 'USER'")
+	s.NoError(err)
+
+	a, err := s.repo.FindAuthoritiesByUserID(context.Background(), s.userAdminId)
+	s.NoError(err)
+	s.Equal([]string{"ADMIN"}, a)
+}
@@ -7,8 +7,17 @@ import (
 	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/infra/database"
 	"github.com/google/uuid"
+	"strings"
 )
 
+var userSortColumns = map[string]string{
+	repository.UserSortUsername:     "u.username",
+	repository.UserSortFirstName:    "u.first_name",
+	repository.UserSortLastName:     "u.last_name",
+	repository.UserSortEmail:        "u.email",
+	repository.UserSortCreationDate: "u.creation_date",
+}
+
 type UserRepositoryPostgres struct {
 	db database.Database
 }
@@ -19,8 +28,11 @@ func NewUserRepository(db database.Database) repository.UserRepository {
 
 func (ur UserRepositoryPostgres) FindByUsername(ctx context.Context, username string) (*entity.User, error) {
 	var user entity.User
-	row := ur.db.One(ctx, "SELECT * FROM golauth_user WHERE username = $1", username)
-	err := row.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.Email, &user.Document, &user.Password, &user.Enabled, &user.CreationDate)
+	row := ur.db.One(ctx, `
+		SELECT id, username, first_name, last_name, email, document, password, enabled, email_verified, creation_date
+		FROM golauth_user WHERE username = $1
+	`, username)
+	err := row.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.Email, &user.Document, &user.Password, &user.Enabled, &user.EmailVerified, &user.CreationDate)
 	if err != nil {
 		return nil, fmt.Errorf("could not find user by username [%s]: %w", username, err)
 	}
@@ -29,11 +41,26 @@ func (ur UserRepositoryPostgres) FindByUsername(ctx context.Context, username st
 
 func (ur UserRepositoryPostgres) FindByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
 	var user entity.User
-	var phantomZone string
-	row := ur.db.One(ctx, "SELECT * FROM golauth_user WHERE id = $1", id)
-	err := row.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.Email, &user.Document, &phantomZone, &user.Enabled, &user.CreationDate)
+	row := ur.db.One(ctx, `
+		SELECT id, username, first_name, last_name, email, document, enabled, email_verified, creation_date
+		FROM golauth_user WHERE id = $1
+	`, id)
+	err := row.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.Email, &user.Document, &user.Enabled, &user.EmailVerified, &user.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not find user by id [%s]: %w", id, translateError(err))
+	}
+	return &user, nil
+}
+
+func (ur UserRepositoryPostgres) FindByEmail(ctx context.Context, email string) (*entity.User, error) {
+	var user entity.User
+	row := ur.db.One(ctx, `
+		SELECT id, username, first_name, last_name, email, document, enabled, email_verified, creation_date
+		FROM golauth_user WHERE email = $1
+	`, email)
+	err := row.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.Email, &user.Document, &user.Enabled, &user.EmailVerified, &user.CreationDate)
 	if err != nil {
-		return nil, fmt.Errorf("could not find user by id [%d]: %w", id, err)
+		return nil, fmt.Errorf("could not find user by email [%s]: %w", email, translateError(err))
 	}
 	return &user, nil
 }
@@ -42,7 +69,174 @@ func (ur UserRepositoryPostgres) Create(ctx context.Context, user *entity.User)
 	err := ur.db.One(ctx, "INSERT INTO golauth_user (username, first_name, last_name, email, document, password) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;",
 		user.Username, user.FirstName, user.LastName, user.Email, user.Document, user.Password).Scan(&user.ID)
 	if err != nil {
-		return nil, fmt.Errorf("could not create user %s: %w", user.Username, err)
+		return nil, fmt.Errorf("could not create user %s: %w", user.Username, translateError(err))
+	}
+	return user, nil
+}
+
+func (ur UserRepositoryPostgres) ChangeStatus(ctx context.Context, id uuid.UUID, enabled bool) error {
+	updateStatement := `
+		UPDATE golauth_user
+		SET enabled = $2
+		WHERE id = $1
+	`
+	res, err := ur.db.Exec(ctx, updateStatement, id, enabled)
+	if err != nil {
+		return fmt.Errorf("could not edit user %s: %w", id, err)
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil || rows == 0 {
+		return fmt.Errorf("no rows affected: %w", err)
+	}
+	return nil
+}
+
+func (ur UserRepositoryPostgres) ExistsById(ctx context.Context, id uuid.UUID) (bool, error) {
+	var exists bool
+	row := ur.db.One(ctx, "SELECT EXISTS (SELECT 1 FROM golauth_user WHERE id = $1)", id)
+	err := row.Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("could not check user %s: %w", id, err)
+	}
+	return exists, nil
+}
+
+func (ur UserRepositoryPostgres) FindAll(ctx context.Context, filter repository.UserFilter) ([]*entity.User, int, error) {
+	where, params := userFilterClause(filter)
+
+	var total int
+	err := ur.db.One(ctx, "SELECT count(*) FROM golauth_user u"+where, params...).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not count users: %w", err)
+	}
+
+	column, ok := userSortColumns[filter.Sort]
+	if !ok {
+		column = userSortColumns[repository.UserSortUsername]
+	}
+	direction := "ASC"
+	if filter.Desc {
+		direction = "DESC"
+	}
+	query := fmt.Sprintf(`
+		SELECT u.id, u.username, u.first_name, u.last_name, u.email, u.document, u.enabled, u.email_verified, u.creation_date
+		FROM golauth_user u%s
+		ORDER BY %s %s, u.id
+		LIMIT $%d OFFSET $%d
+	`, where, column, direction, len(params)+1, len(params)+2)
+	rows, err := ur.db.Many(ctx, query, append(params, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not list users: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0)
+	for rows.Next() {
+		var user entity.User
+		err = rows.Scan(&user.ID, &user.Username, &user.FirstName, &user.LastName, &user.Email, &user.Document, &user.Enabled, &user.EmailVerified, &user.CreationDate)
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not scan user: %w", err)
+		}
+		users = append(users, &user)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("could not list users: %w", err)
+	}
+	return users, total, nil
+}
+
+func userFilterClause(filter repository.UserFilter) (string, []interface{}) {
+	var conditions []string
+	var params []interface{}
+	add := func(condition string, param interface{}) {
+		params = append(params, param)
+		conditions = append(conditions, fmt.Sprintf(condition, len(params)))
+	}
+	if filter.Username != "" {
+		add("u.username ILIKE $%d", "%"+escapeLike(filter.Username)+"%")
+	}
+	if filter.Email != "" {
+		add("u.email ILIKE $%d", "%"+escapeLike(filter.Email)+"%")
+	}
+	if filter.Enabled != nil {
+		add("u.enabled = $%d", *filter.Enabled)
+	}
+	if filter.Role != "" {
+		add(`EXISTS (SELECT 1 FROM golauth_user_role ur INNER JOIN golauth_role r ON r.id = ur.role_id
+			WHERE ur.user_id = u.id AND r.name = $%d)`, filter.Role)
+	}
+	if len(conditions) == 0 {
+		return "", params
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), params
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+func (ur UserRepositoryPostgres) Update(ctx context.Context, user *entity.User) (*entity.User, error) {
+	updateStatement := `
+		UPDATE golauth_user
+		SET first_name = $2, last_name = $3, email = $4, document = $5, email_verified = email_verified AND email = $4
+		WHERE id = $1
+		RETURNING username, enabled, email_verified, creation_date
+	`
+	err := ur.db.One(ctx, updateStatement, user.ID, user.FirstName, user.LastName, user.Email, user.Document).
+		Scan(&user.Username, &user.Enabled, &user.EmailVerified, &user.CreationDate)
+	if err != nil {
+		return nil, fmt.Errorf("could not update user %s: %w", user.ID, translateError(err))
 	}
 	return user, nil
 }
+
+// Delete removes the user with every row kept about it: roles, tokens and codes issued to it, password history and
+// the failed logins counted for its username.
+func (ur UserRepositoryPostgres) Delete(ctx context.Context, id uuid.UUID) error {
+	deleteStatement := `
+		WITH roles AS (DELETE FROM golauth_user_role WHERE user_id = $1),
+		     tokens AS (DELETE FROM golauth_refresh_token WHERE user_id = $1),
+		     history AS (DELETE FROM golauth_password_history WHERE user_id = $1),
+		     verification_tokens AS (DELETE FROM golauth_email_verification_token WHERE user_id = $1),
+		     reset_tokens AS (DELETE FROM golauth_password_reset_token WHERE user_id = $1),
+		     authorization_codes AS (DELETE FROM golauth_authorization_code WHERE user_id = $1),
+		     device_codes AS (DELETE FROM golauth_device_code WHERE user_id = $1),
+		     login_failures AS (
+		         DELETE FROM golauth_login_failure
+		         WHERE kind = $2 AND subject = (SELECT username FROM golauth_user WHERE id = $1)
+		     )
+		DELETE FROM golauth_user
+		WHERE id = $1
+	`
+	res, err := ur.db.Exec(ctx, deleteStatement, id, entity.LoginFailureKindUsername)
+	if err != nil {
+		return fmt.Errorf("could not delete user %s: %w", id, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not delete user %s", id))
+}
+
+func (ur UserRepositoryPostgres) FindPasswordByID(ctx context.Context, id uuid.UUID) (string, error) {
+	var password string
+	err := ur.db.One(ctx, "SELECT password FROM golauth_user WHERE id = $1", id).Scan(&password)
+	if err != nil {
+		return "", fmt.Errorf("could not find password of user %s: %w", id, translateError(err))
+	}
+	return password, nil
+}
+
+func (ur UserRepositoryPostgres) ChangePassword(ctx context.Context, id uuid.UUID, password string) error {
+	res, err := ur.db.Exec(ctx, "UPDATE golauth_user SET password = $2 WHERE id = $1", id, password)
+	if err != nil {
+		return fmt.Errorf("could not change password of user %s: %w", id, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not change password of user %s", id))
+}
+
+func (ur UserRepositoryPostgres) MarkEmailVerified(ctx context.Context, id uuid.UUID, email string) error {
+	res, err := ur.db.Exec(ctx, "UPDATE golauth_user SET email_verified = true WHERE id = $1 AND email = $2", id, email)
+	if err != nil {
+		return fmt.Errorf("could not verify email of user %s: %w", id, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not verify email of user %s", id))
+}
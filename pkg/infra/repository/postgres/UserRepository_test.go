@@ -100,3 +100,244 @@ func (s *UserRepositorySuite) TestCreateNewUserOk() {
 	s.NoError(err)
 	s.NotEmpty(user.ID)
 }
+
+func (s *UserRepositorySuite) TestChangeStatus() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+
+	err := s.repo.ChangeStatus(context.Background(), id, false)
+	s.NoError(err)
+
+	u, err := s.repo.FindByID(context.Background(), id)
+	s.NoError(err)
+	s.False(u.Enabled)
+}
+
+func (s *UserRepositorySuite) TestChangeStatusNotFound() {
+	s.prepareDatabase(true)
+	err := s.repo.ChangeStatus(context.Background(), uuid.New(), false)
+	s.Error(err)
+}
+
+func (s *UserRepositorySuite) TestExistsById() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+
+	exists, err := s.repo.ExistsById(context.Background(), id)
+	s.NoError(err)
+	s.True(exists)
+
+	exists, err = s.repo.ExistsById(context.Background(), uuid.New())
+	s.NoError(err)
+	s.False(exists)
+}
+
+func (s *UserRepositorySuite) TestFindByIDNotFound() {
+	s.prepareDatabase(true)
+	_, err := s.repo.FindByID(context.Background(), uuid.New())
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *UserRepositorySuite) TestCreateDuplicateUsername() {
+	s.prepareDatabase(true, "add-users.sql")
+	u := &entity.User{Username: "admin", FirstName: "Other", LastName: "Admin", Email: "other@goauth.org", Document: "002", Password: "secret"}
+
+	_, err := s.repo.Create(context.Background(), u)
+	s.ErrorIs(err, repository.ErrDuplicate)
+}
+
+func (s *UserRepositorySuite) TestFindAll() {
+	s.prepareDatabase(true, "add-users.sql")
+
+	users, total, err := s.repo.FindAll(context.Background(), repository.UserFilter{Limit: 10})
+	s.NoError(err)
+	s.Equal(2, total)
+	s.Len(users, 2)
+	s.Equal("admin", users[0].Username)
+	s.Equal("admin2", users[1].Username)
+	s.Empty(users[0].Password)
+}
+
+func (s *UserRepositorySuite) TestFindAllSortedAndPaged() {
+	s.prepareDatabase(true, "add-users.sql")
+
+	users, total, err := s.repo.FindAll(context.Background(), repository.UserFilter{Sort: repository.UserSortEmail, Desc: true, Limit: 1, Offset: 1})
+	s.NoError(err)
+	s.Equal(2, total)
+	s.Len(users, 1)
+	s.Equal("admin", users[0].Username)
+}
+
+func (s *UserRepositorySuite) TestFindAllFiltered() {
+	s.prepareDatabase(true, "add-users.sql")
+	ctx := context.Background()
+	enabled := true
+
+	users, total, err := s.repo.FindAll(ctx, repository.UserFilter{Username: "MIN2", Enabled: &enabled, Limit: 10})
+	s.NoError(err)
+	s.Equal(1, total)
+	s.Equal("admin2", users[0].Username)
+
+	users, total, err = s.repo.FindAll(ctx, repository.UserFilter{Role: "ADMIN", Limit: 10})
+	s.NoError(err)
+	s.Equal(1, total)
+	s.Equal("admin", users[0].Username)
+
+	users, total, err = s.repo.FindAll(ctx, repository.UserFilter{Email: "%", Limit: 10})
+	s.NoError(err)
+	s.Zero(total)
+	s.Empty(users)
+}
+
+func (s *UserRepositorySuite) TestUpdate() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+
+	u, err := s.repo.Update(context.Background(), &entity.User{ID: id, FirstName: "New", LastName: "Name", Email: "new@goauth.org", Document: "999"})
+	s.NoError(err)
+	s.Equal("admin", u.Username)
+	s.True(u.Enabled)
+	s.NotZero(u.CreationDate)
+
+	u, err = s.repo.FindByID(context.Background(), id)
+	s.NoError(err)
+	s.Equal("New", u.FirstName)
+	s.Equal("new@goauth.org", u.Email)
+}
+
+func (s *UserRepositorySuite) TestUpdateNotFound() {
+	s.prepareDatabase(true)
+	_, err := s.repo.Update(context.Background(), &entity.User{ID: uuid.New(), Email: "new@goauth.org"})
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *UserRepositorySuite) TestUpdateDuplicateEmail() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+
+	_, err := s.repo.Update(context.Background(), &entity.User{ID: id, FirstName: "Admin", LastName: "Admin", Email: "admin2@goauth.org", Document: "000"})
+	s.ErrorIs(err, repository.ErrDuplicate)
+}
+
+func (s *UserRepositorySuite) TestDelete() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+
+	ctx := context.Background()
+	for _, statement := range []string{
+		`INSERT INTO golauth_email_verification_token (user_id, email, token_hash, expires_at) VALUES ($1, 'admin@goauth.org', 'verify', now())`,
+		`INSERT INTO golauth_password_reset_token (user_id, token_hash, expires_at) VALUES ($1, 'reset', now())`,
+		`INSERT INTO golauth_authorization_code (code_hash, client_id, user_id, redirect_uri, code_challenge, code_challenge_method, expires_at)
+		 VALUES ('code', 'spa', $1, 'https://app/callback', 'challenge', 'plain', now())`,
+		`INSERT INTO golauth_device_code (device_code_hash, user_code, client_id, user_id, poll_interval, expires_at)
+		 VALUES ('device', 'BCDFGHJK', 'cli', $1, 5, now())`,
+	} {
+		_, err := s.db.Exec(ctx, statement, id)
+		s.NoError(err)
+	}
+	_, err := s.db.Exec(ctx, `INSERT INTO golauth_login_failure (kind, subject, failures, last_failure) VALUES ('username', 'admin', 1, now())`)
+	s.NoError(err)
+
+	s.NoError(s.repo.Delete(ctx, id))
+
+	exists, err := s.repo.ExistsById(ctx, id)
+	s.NoError(err)
+	s.False(exists)
+	roleIDs, err := NewUserRoleRepository(s.db).FindUserIDsByRoleID(ctx, uuid.MustParse("7f68301e-df80-45bd-9532-23a58733ef2c"))
+	s.NoError(err)
+	s.Empty(roleIDs)
+	for _, table := range []string{"golauth_email_verification_token", "golauth_password_reset_token",
+		"golauth_authorization_code", "golauth_device_code", "golauth_login_failure"} {
+		var count int
+		s.NoError(s.db.One(ctx, "SELECT count(*) FROM "+table).Scan(&count))
+		s.Zero(count, table)
+	}
+}
+
+func (s *UserRepositorySuite) TestDeleteNotFound() {
+	s.prepareDatabase(true)
+	err := s.repo.Delete(context.Background(), uuid.New())
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *UserRepositorySuite) TestChangePassword() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+
+	s.NoError(s.repo.ChangePassword(context.Background(), id, "new-hash"))
+
+	password, err := s.repo.FindPasswordByID(context.Background(), id)
+	s.NoError(err)
+	s.Equal("new-hash", password)
+}
+
+func (s *UserRepositorySuite) TestChangePasswordNotFound() {
+	s.prepareDatabase(true)
+	err := s.repo.ChangePassword(context.Background(), uuid.New(), "new-hash")
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *UserRepositorySuite) TestFindPasswordByIDNotFound() {
+	s.prepareDatabase(true)
+	_, err := s.repo.FindPasswordByID(context.Background(), uuid.New())
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *UserRepositorySuite) TestFindByEmail() {
+	s.prepareDatabase(true, "add-users.sql")
+	user, err := s.repo.FindByEmail(context.Background(), "admin@goauth.org")
+	s.NoError(err)
+	s.Equal("admin", user.Username)
+	s.Empty(user.Password)
+}
+
+func (s *UserRepositorySuite) TestFindByEmailNotFound() {
+	s.prepareDatabase(true)
+	_, err := s.repo.FindByEmail(context.Background(), "nobody@goauth.org")
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *UserRepositorySuite) TestMarkEmailVerified() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+
+	s.NoError(s.repo.MarkEmailVerified(context.Background(), id, "admin@goauth.org"))
+
+	u, err := s.repo.FindByID(context.Background(), id)
+	s.NoError(err)
+	s.True(u.EmailVerified)
+}
+
+func (s *UserRepositorySuite) TestMarkEmailVerifiedNotFound() {
+	s.prepareDatabase(true)
+	err := s.repo.MarkEmailVerified(context.Background(), uuid.New(), "admin@goauth.org")
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *UserRepositorySuite) TestMarkEmailVerifiedAfterEmailChanged() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+	_, err := s.repo.Update(context.Background(), &entity.User{ID: id, FirstName: "Admin", LastName: "Admin", Email: "not-mine@goauth.org", Document: "000"})
+	s.NoError(err)
+
+	err = s.repo.MarkEmailVerified(context.Background(), id, "admin@goauth.org")
+	s.ErrorIs(err, repository.ErrNotFound)
+
+	u, err := s.repo.FindByID(context.Background(), id)
+	s.NoError(err)
+	s.False(u.EmailVerified)
+}
+
+func (s *UserRepositorySuite) TestUpdateEmailClearsVerification() {
+	s.prepareDatabase(true, "add-users.sql")
+	id, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+	s.NoError(s.repo.MarkEmailVerified(context.Background(), id, "admin@goauth.org"))
+
+	u, err := s.repo.Update(context.Background(), &entity.User{ID: id, FirstName: "Admin", LastName: "Admin", Email: "admin@goauth.org", Document: "000"})
+	s.NoError(err)
+	s.True(u.EmailVerified)
+
+	u, err = s.repo.Update(context.Background(), &entity.User{ID: id, FirstName: "Admin", LastName: "Admin", Email: "new@goauth.org", Document: "000"})
+	s.NoError(err)
+	s.False(u.EmailVerified)
+}
@@ -3,9 +3,11 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"github.com/golauth/golauth/pkg/domain/entity"
 	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/infra/database"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type UserRoleRepositoryPostgres struct {
@@ -24,3 +26,76 @@ func (urr UserRoleRepositoryPostgres) AddUserRole(ctx context.Context, userId uu
 	}
 	return nil
 }
+
+func (urr UserRoleRepositoryPostgres) FindUserIDsByRoleID(ctx context.Context, roleId uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := urr.db.Many(ctx, "SELECT user_id FROM golauth_user_role WHERE role_id = $1", roleId)
+	if err != nil {
+		return nil, fmt.Errorf("could not find users of role %s: %w", roleId, err)
+	}
+	defer rows.Close()
+
+	var result []uuid.UUID
+	for rows.Next() {
+		var userId uuid.UUID
+		err = rows.Scan(&userId)
+		if err != nil {
+			return nil, fmt.Errorf("could not transform result in slice: %w", err)
+		}
+		result = append(result, userId)
+	}
+	return result, nil
+}
+
+func (urr UserRoleRepositoryPostgres) FindRolesByUserID(ctx context.Context, userId uuid.UUID) ([]*entity.Role, error) {
+	query := `
+		SELECT r.id, r.name, r.description, r.enabled, r.creation_date
+		FROM golauth_role r
+		INNER JOIN golauth_user_role ur ON ur.role_id = r.id
+		WHERE ur.user_id = $1
+		ORDER BY r.name
+	`
+	rows, err := urr.db.Many(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("could not find roles of user %s: %w", userId, err)
+	}
+	defer rows.Close()
+
+	result := make([]*entity.Role, 0)
+	for rows.Next() {
+		var role entity.Role
+		err = rows.Scan(&role.ID, &role.Name, &role.Description, &role.Enabled, &role.CreationDate)
+		if err != nil {
+			return nil, fmt.Errorf("could not transform result in slice: %w", err)
+		}
+		result = append(result, &role)
+	}
+	return result, nil
+}
+
+func (urr UserRoleRepositoryPostgres) RemoveUserRole(ctx context.Context, userId uuid.UUID, roleId uuid.UUID) error {
+	res, err := urr.db.Exec(ctx, "DELETE FROM golauth_user_role WHERE user_id = $1 AND role_id = $2", userId, roleId)
+	if err != nil {
+		return fmt.Errorf("could not remove userrole [%s;%s]: %w", userId, roleId, err)
+	}
+	return requireRowsAffected(res, fmt.Sprintf("could not remove userrole [%s;%s]", userId, roleId))
+}
+
+// ReplaceUserRoles sets the user's roles to exactly roleIds in a single statement, keeping the creation date
+// of the roles the user already had.
+func (urr UserRoleRepositoryPostgres) ReplaceUserRoles(ctx context.Context, userId uuid.UUID, roleIds []uuid.UUID) error {
+	ids := make([]string, 0, len(roleIds))
+	for _, id := range roleIds {
+		ids = append(ids, id.String())
+	}
+	replaceStatement := `
+		WITH removed AS (DELETE FROM golauth_user_role WHERE user_id = $1 AND NOT role_id = ANY ($2::uuid[]))
+		INSERT INTO golauth_user_role (user_id, role_id)
+		SELECT $1, unnest($2::uuid[])
+		ON CONFLICT DO NOTHING
+	`
+	_, err := urr.db.Exec(ctx, replaceStatement, userId, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("could not replace roles of user %s: %w", userId, err)
+	}
+	return nil
+}
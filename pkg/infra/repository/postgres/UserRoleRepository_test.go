@@ -6,6 +6,7 @@ import (
 	"github.com/golauth/golauth/pkg/domain/repository"
 	"github.com/golauth/golauth/pkg/infra/database"
 	"github.com/golauth/golauth/tests"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -73,3 +74,61 @@ func (s *UserRoleRepositorySuite) TestAddUserRole() {
 	err = s.repo.AddUserRole(context.Background(), user.ID, role.ID)
 	s.NoError(err)
 }
+
+func (s *UserRoleRepositorySuite) TestFindUserIDsByRoleID() {
+	s.prepareDatabase(true, "add-users.sql")
+	roleId, _ := uuid.Parse("7f68301e-df80-45bd-9532-23a58733ef2c")
+
+	ids, err := s.repo.FindUserIDsByRoleID(context.Background(), roleId)
+	s.NoError(err)
+	s.Len(ids, 1)
+	s.Equal("8c61f220-8bb8-48b9-b225-d54dfa6503db", ids[0].String())
+}
+
+func (s *UserRoleRepositorySuite) TestFindRolesByUserID() {
+	s.prepareDatabase(true, "add-users.sql")
+	userId, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+
+	roles, err := s.repo.FindRolesByUserID(context.Background(), userId)
+	s.NoError(err)
+	s.Len(roles, 2)
+	s.Equal("ADMIN", roles[0].Name)
+	s.Equal("USER", roles[1].Name)
+
+	roles, err = s.repo.FindRolesByUserID(context.Background(), uuid.New())
+	s.NoError(err)
+	s.Empty(roles)
+}
+
+func (s *UserRoleRepositorySuite) TestRemoveUserRole() {
+	s.prepareDatabase(true, "add-users.sql")
+	userId, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+	roleId, _ := uuid.Parse("7f68301e-df80-45bd-9532-23a58733ef2c")
+
+	s.NoError(s.repo.RemoveUserRole(context.Background(), userId, roleId))
+
+	roles, err := s.repo.FindRolesByUserID(context.Background(), userId)
+	s.NoError(err)
+	s.Len(roles, 1)
+	s.Equal("USER", roles[0].Name)
+
+	err = s.repo.RemoveUserRole(context.Background(), userId, roleId)
+	s.ErrorIs(err, repository.ErrNotFound)
+}
+
+func (s *UserRoleRepositorySuite) TestReplaceUserRoles() {
+	s.prepareDatabase(true, "add-users.sql")
+	userId, _ := uuid.Parse("8c61f220-8bb8-48b9-b225-d54dfa6503db")
+	userRoleId, _ := uuid.Parse("c12b415b-c3ad-487f-9800-f548aa18cc58")
+
+	s.NoError(s.repo.ReplaceUserRoles(context.Background(), userId, []uuid.UUID{userRoleId}))
+	roles, err := s.repo.FindRolesByUserID(context.Background(), userId)
+	s.NoError(err)
+	s.Len(roles, 1)
+	s.Equal(userRoleId, roles[0].ID)
+
+	s.NoError(s.repo.ReplaceUserRoles(context.Background(), userId, nil))
+	roles, err = s.repo.FindRolesByUserID(context.Background(), userId)
+	s.NoError(err)
+	s.Empty(roles)
+}
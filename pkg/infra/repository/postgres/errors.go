@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/golauth/golauth/pkg/domain/repository"
+	"github.com/golauth/golauth/pkg/infra/database"
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+// translateError tags the driver errors use cases act on with the matching repository error.
+func translateError(err error) error {
+	var pqErr *pq.Error
+	switch {
+	case errors.Is(err, database.ErrNoRows):
+		return fmt.Errorf("%w: %w", repository.ErrNotFound, err)
+	case errors.As(err, &pqErr) && pqErr.Code == uniqueViolation:
+		return fmt.Errorf("%w: %w", repository.ErrDuplicate, err)
+	}
+	return err
+}
+
+// requireRowsAffected fails with repository.ErrNotFound when the statement behind res matched no row.
+func requireRowsAffected(res sql.Result, action string) error {
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("%s: %w", action, repository.ErrNotFound)
+	}
+	return nil
+}
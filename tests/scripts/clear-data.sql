@@ -3,3 +3,14 @@ delete from golauth_user;
 delete from golauth_role_authority;
 delete from golauth_role;
 delete from golauth_authority;
+delete from golauth_signing_key;
+delete from golauth_refresh_token;
+delete from golauth_client;
+delete from golauth_authorization_code;
+delete from golauth_revoked_token;
+delete from golauth_device_code;
+delete from golauth_revoked_subject;
+delete from golauth_password_reset_token;
+delete from golauth_email_verification_token;
+delete from golauth_password_history;
+delete from golauth_login_failure;